@@ -12,13 +12,24 @@ import (
 	"github.com/ApollosProject/pgstream-wal2json/pkg/stream"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/tls"
 	kafkacheckpoint "github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer/kafka"
+	fileprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/file"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
+	grpcprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc"
 	kafkaprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/kafka"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/livefeed"
+	natsprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/nats"
+	parquetprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/parquet"
+	pgprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/postgres"
+	redisprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/redis"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/router"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search/store"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/translator"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/wasm"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/notifier"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/server"
 	pgreplication "github.com/ApollosProject/pgstream-wal2json/pkg/wal/replication/postgres"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 )
 
@@ -77,6 +88,7 @@ func parsePostgresListenerConfig() *stream.PostgresListenerConfig {
 			PostgresURL:    pgURL,
 			Wal2JsonConfig: wal2jsonConfig,
 		},
+		LosslessNumbers: viper.GetBool("PGSTREAM_POSTGRES_LISTENER_LOSSLESS_NUMBERS"),
 	}
 }
 
@@ -89,8 +101,9 @@ func parseKafkaListenerConfig() *stream.KafkaListenerConfig {
 	}
 
 	return &stream.KafkaListenerConfig{
-		Reader:       parseKafkaReaderConfig(kafkaServers, kafkaTopic, consumerGroupID),
-		Checkpointer: parseKafkaCheckpointConfig(),
+		Reader:          parseKafkaReaderConfig(kafkaServers, kafkaTopic, consumerGroupID),
+		Checkpointer:    parseKafkaCheckpointConfig(),
+		LosslessNumbers: viper.GetBool("PGSTREAM_KAFKA_READER_LOSSLESS_NUMBERS"),
 	}
 }
 
@@ -121,7 +134,17 @@ func parseProcessorConfig() stream.ProcessorConfig {
 		Kafka:      parseKafkaProcessorConfig(),
 		Search:     parseSearchProcessorConfig(),
 		Webhook:    parseWebhookProcessorConfig(),
+		Postgres:   parsePostgresProcessorConfig(),
+		File:       parseFileProcessorConfig(),
+		Parquet:    parseParquetProcessorConfig(),
+		NATS:       parseNATSProcessorConfig(),
+		Redis:      parseRedisProcessorConfig(),
+		LiveFeed:   parseLiveFeedProcessorConfig(),
+		GRPC:       parseGRPCProcessorConfig(),
 		Translator: parseTranslatorConfig(),
+		WASM:       parseWASMTransformerConfig(),
+		Filter:     parseFilterConfig(),
+		Router:     parseRouterConfig(),
 	}
 }
 
@@ -154,6 +177,24 @@ func parseKafkaWriterConfig(kafkaServers []string, kafkaTopic string) *kafkaproc
 		BatchBytes:    viper.GetInt64("PGSTREAM_KAFKA_WRITER_BATCH_BYTES"),
 		BatchSize:     viper.GetInt("PGSTREAM_KAFKA_WRITER_BATCH_SIZE"),
 		MaxQueueBytes: viper.GetInt64("PGSTREAM_KAFKA_WRITER_MAX_QUEUE_BYTES"),
+		TopicRoutes:   parseKafkaTopicRoutes(),
+	}
+}
+
+// parseKafkaTopicRoutes parses the numbered topic routes, starting from 1,
+// until a route expression is not found.
+func parseKafkaTopicRoutes() []kafkaprocessor.TopicRoute {
+	routes := []kafkaprocessor.TopicRoute{}
+	for i := 1; ; i++ {
+		prefix := fmt.Sprintf("PGSTREAM_KAFKA_WRITER_TOPIC_ROUTE_%d", i)
+		expression := viper.GetString(prefix + "_EXPRESSION")
+		if expression == "" {
+			return routes
+		}
+		routes = append(routes, kafkaprocessor.TopicRoute{
+			Expression: expression,
+			Topic:      viper.GetString(prefix + "_TOPIC"),
+		})
 	}
 }
 
@@ -172,8 +213,17 @@ func parseSearchProcessorConfig() *stream.SearchProcessorConfig {
 			CleanupBackoff: parseBackoffConfig("PGSTREAM_SEARCH_INDEXER_CLEANUP"),
 		},
 		Store: store.Config{
-			OpenSearchURL:    opensearchStore,
-			ElasticsearchURL: elasticsearchStore,
+			OpenSearchURL:       opensearchStore,
+			ElasticsearchURL:    elasticsearchStore,
+			ColumnNameAliases:   viper.GetBool("PGSTREAM_SEARCH_STORE_COLUMN_NAME_ALIASES"),
+			MappingOverrides:    parseSearchMappingOverrides(),
+			JSONMappingMode:     viper.GetString("PGSTREAM_SEARCH_STORE_JSON_MAPPING_MODE"),
+			IndexSettings:       viper.GetStringMap("PGSTREAM_SEARCH_STORE_INDEX_SETTINGS"),
+			SchemaIndexSettings: parseNestedStringMap("PGSTREAM_SEARCH_STORE_SCHEMA_INDEX_SETTINGS"),
+			IndexTemplate:       viper.GetStringMap("PGSTREAM_SEARCH_STORE_INDEX_TEMPLATE"),
+			IndexTemplateName:   viper.GetString("PGSTREAM_SEARCH_STORE_INDEX_TEMPLATE_NAME"),
+			Auth:                parseSearchAuthConfig(),
+			TLS:                 parseTLSConfig("PGSTREAM_SEARCH_STORE"),
 		},
 		Retrier: search.StoreRetryConfig{
 			Backoff: parseBackoffConfig("PGSTREAM_SEARCH_STORE"),
@@ -181,6 +231,46 @@ func parseSearchProcessorConfig() *stream.SearchProcessorConfig {
 	}
 }
 
+func parseSearchAuthConfig() store.AuthConfig {
+	authCfg := store.AuthConfig{
+		Username:    viper.GetString("PGSTREAM_SEARCH_STORE_USERNAME"),
+		Password:    viper.GetString("PGSTREAM_SEARCH_STORE_PASSWORD"),
+		APIKey:      viper.GetString("PGSTREAM_SEARCH_STORE_API_KEY"),
+		BearerToken: viper.GetString("PGSTREAM_SEARCH_STORE_BEARER_TOKEN"),
+	}
+	if viper.GetBool("PGSTREAM_SEARCH_STORE_AWS_SIGV4_ENABLED") {
+		authCfg.AWSSigV4 = &store.AWSSigV4Config{
+			Region:          viper.GetString("PGSTREAM_SEARCH_STORE_AWS_SIGV4_REGION"),
+			Service:         viper.GetString("PGSTREAM_SEARCH_STORE_AWS_SIGV4_SERVICE"),
+			AccessKeyID:     viper.GetString("PGSTREAM_SEARCH_STORE_AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: viper.GetString("PGSTREAM_SEARCH_STORE_AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    viper.GetString("PGSTREAM_SEARCH_STORE_AWS_SESSION_TOKEN"),
+		}
+	}
+	return authCfg
+}
+
+// parseSearchMappingOverrides parses the search mapping overrides, keyed by
+// `<schema>.<table>.<column>`.
+func parseSearchMappingOverrides() map[string]map[string]any {
+	return parseNestedStringMap("PGSTREAM_SEARCH_STORE_MAPPING_OVERRIDES")
+}
+
+// parseNestedStringMap parses a map of maps, provided either as a JSON string
+// (environment variable) or a map (config file).
+func parseNestedStringMap(key string) map[string]map[string]any {
+	rawMap := viper.GetStringMap(key)
+	if len(rawMap) == 0 {
+		return nil
+	}
+
+	nestedMap := make(map[string]map[string]any, len(rawMap))
+	for k, v := range rawMap {
+		nestedMap[k] = cast.ToStringMap(v)
+	}
+	return nestedMap
+}
+
 func parseWebhookProcessorConfig() *stream.WebhookProcessorConfig {
 	subscriptionStore := viper.GetString("PGSTREAM_WEBHOOK_SUBSCRIPTION_STORE_URL")
 	if subscriptionStore == "" {
@@ -192,6 +282,7 @@ func parseWebhookProcessorConfig() *stream.WebhookProcessorConfig {
 			URL:                  subscriptionStore,
 			CacheEnabled:         viper.GetBool("PGSTREAM_WEBHOOK_SUBSCRIPTION_STORE_CACHE_ENABLED"),
 			CacheRefreshInterval: viper.GetDuration("PGSTREAM_WEBHOOK_SUBSCRIPTION_STORE_CACHE_REFRESH_INTERVAL"),
+			EncryptionKey:        viper.GetString("PGSTREAM_WEBHOOK_SUBSCRIPTION_STORE_ENCRYPTION_KEY"),
 		},
 		Notifier: notifier.Config{
 			MaxQueueBytes:  viper.GetInt64("PGSTREAM_WEBHOOK_NOTIFIER_MAX_QUEUE_BYTES"),
@@ -202,10 +293,244 @@ func parseWebhookProcessorConfig() *stream.WebhookProcessorConfig {
 			Address:      viper.GetString("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_ADDRESS"),
 			ReadTimeout:  viper.GetDuration("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_READ_TIMEOUT"),
 			WriteTimeout: viper.GetDuration("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_WRITE_TIMEOUT"),
+			APIKeys:      viper.GetStringSlice("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_API_KEYS"),
+			TLS:          parseSubscriptionServerTLSConfig(),
+		},
+		Outbox: parseWebhookOutboxConfig(),
+		// the outbox is enabled unless explicitly disabled, so that failed
+		// deliveries are not lost by default
+		DisableOutbox: viper.IsSet("PGSTREAM_WEBHOOK_OUTBOX_ENABLED") && !viper.GetBool("PGSTREAM_WEBHOOK_OUTBOX_ENABLED"),
+	}
+}
+
+func parseSubscriptionServerTLSConfig() *tls.ServerConfig {
+	certFile := viper.GetString("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_TLS_CERT_FILE")
+	if certFile == "" {
+		return nil
+	}
+
+	return &tls.ServerConfig{
+		CertFile:     certFile,
+		KeyFile:      viper.GetString("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_TLS_KEY_FILE"),
+		ClientCAFile: viper.GetString("PGSTREAM_WEBHOOK_SUBSCRIPTION_SERVER_TLS_CLIENT_CA_FILE"),
+	}
+}
+
+func parseWebhookOutboxConfig() *notifier.RetrierConfig {
+	return &notifier.RetrierConfig{
+		Backoff:          parseBackoffConfig("PGSTREAM_WEBHOOK_OUTBOX"),
+		PollInterval:     viper.GetDuration("PGSTREAM_WEBHOOK_OUTBOX_POLL_INTERVAL"),
+		WorkerCount:      viper.GetUint("PGSTREAM_WEBHOOK_OUTBOX_WORKER_COUNT"),
+		DisableThreshold: viper.GetUint("PGSTREAM_WEBHOOK_OUTBOX_DISABLE_THRESHOLD"),
+		DisableWindow:    viper.GetDuration("PGSTREAM_WEBHOOK_OUTBOX_DISABLE_WINDOW"),
+		ClientTimeout:    viper.GetDuration("PGSTREAM_WEBHOOK_NOTIFIER_CLIENT_TIMEOUT"),
+	}
+}
+
+func parsePostgresProcessorConfig() *stream.PostgresProcessorConfig {
+	targetURL := viper.GetString("PGSTREAM_POSTGRES_WRITER_TARGET_URL")
+	if targetURL == "" {
+		return nil
+	}
+
+	return &stream.PostgresProcessorConfig{
+		BatchWriter: pgprocessor.Config{
+			URL:           targetURL,
+			BatchSize:     viper.GetInt("PGSTREAM_POSTGRES_WRITER_BATCH_SIZE"),
+			BatchTime:     viper.GetDuration("PGSTREAM_POSTGRES_WRITER_BATCH_TIMEOUT"),
+			MaxQueueBytes: viper.GetInt64("PGSTREAM_POSTGRES_WRITER_MAX_QUEUE_BYTES"),
+			IncludeTables: viper.GetStringSlice("PGSTREAM_POSTGRES_WRITER_INCLUDE_TABLES"),
+		},
+	}
+}
+
+func parseFileProcessorConfig() *stream.FileProcessorConfig {
+	stdout := viper.GetBool("PGSTREAM_FILE_WRITER_STDOUT")
+	dir := viper.GetString("PGSTREAM_FILE_WRITER_DIR")
+	if !stdout && dir == "" {
+		return nil
+	}
+
+	return &stream.FileProcessorConfig{
+		Writer: fileprocessor.Config{
+			Stdout:           stdout,
+			Dir:              dir,
+			FilenameTemplate: viper.GetString("PGSTREAM_FILE_WRITER_FILENAME_TEMPLATE"),
+			MaxFileBytes:     viper.GetInt64("PGSTREAM_FILE_WRITER_MAX_FILE_BYTES"),
+			MaxFileAge:       viper.GetDuration("PGSTREAM_FILE_WRITER_MAX_FILE_AGE"),
+			Compression:      viper.GetString("PGSTREAM_FILE_WRITER_COMPRESSION"),
+			BatchSize:        viper.GetInt("PGSTREAM_FILE_WRITER_BATCH_SIZE"),
+			BatchTime:        viper.GetDuration("PGSTREAM_FILE_WRITER_BATCH_TIMEOUT"),
+			MaxQueueBytes:    viper.GetInt64("PGSTREAM_FILE_WRITER_MAX_QUEUE_BYTES"),
+		},
+	}
+}
+
+func parseParquetProcessorConfig() *stream.ParquetProcessorConfig {
+	localDir := viper.GetString("PGSTREAM_PARQUET_WRITER_LOCAL_DIR")
+	bucket := viper.GetString("PGSTREAM_PARQUET_WRITER_S3_BUCKET")
+	if localDir == "" && bucket == "" {
+		return nil
+	}
+
+	cfg := &stream.ParquetProcessorConfig{
+		Writer: parquetprocessor.Config{
+			LocalDir:      localDir,
+			MaxFileBytes:  viper.GetInt64("PGSTREAM_PARQUET_WRITER_MAX_FILE_BYTES"),
+			MaxFileAge:    viper.GetDuration("PGSTREAM_PARQUET_WRITER_MAX_FILE_AGE"),
+			Compression:   viper.GetString("PGSTREAM_PARQUET_WRITER_COMPRESSION"),
+			MaxQueueBytes: viper.GetInt64("PGSTREAM_PARQUET_WRITER_MAX_QUEUE_BYTES"),
+		},
+	}
+
+	if bucket != "" {
+		cfg.Writer.S3 = &parquetprocessor.S3Config{
+			Bucket:          bucket,
+			Prefix:          viper.GetString("PGSTREAM_PARQUET_WRITER_S3_PREFIX"),
+			Region:          viper.GetString("PGSTREAM_PARQUET_WRITER_S3_REGION"),
+			Endpoint:        viper.GetString("PGSTREAM_PARQUET_WRITER_S3_ENDPOINT"),
+			UsePathStyle:    viper.GetBool("PGSTREAM_PARQUET_WRITER_S3_USE_PATH_STYLE"),
+			AccessKeyID:     viper.GetString("PGSTREAM_PARQUET_WRITER_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: viper.GetString("PGSTREAM_PARQUET_WRITER_S3_SECRET_ACCESS_KEY"),
+			SessionToken:    viper.GetString("PGSTREAM_PARQUET_WRITER_S3_SESSION_TOKEN"),
+		}
+	}
+
+	// the schema log store is used to retrieve the table definitions when
+	// available
+	if pgURL := viper.GetString("PGSTREAM_TRANSLATOR_STORE_POSTGRES_URL"); pgURL != "" {
+		cfg.Writer.SchemaLogStore = &pgschemalog.Config{URL: pgURL}
+	}
+
+	return cfg
+}
+
+func parseNATSProcessorConfig() *stream.NATSProcessorConfig {
+	natsURL := viper.GetString("PGSTREAM_NATS_URL")
+	if natsURL == "" {
+		return nil
+	}
+
+	return &stream.NATSProcessorConfig{
+		Publisher: natsprocessor.Config{
+			URL:             natsURL,
+			SubjectTemplate: viper.GetString("PGSTREAM_NATS_PUBLISHER_SUBJECT_TEMPLATE"),
+			CredentialsFile: viper.GetString("PGSTREAM_NATS_CREDENTIALS_FILE"),
+			TLS:             parseTLSConfig("PGSTREAM_NATS"),
+			MaxPendingAcks:  viper.GetInt("PGSTREAM_NATS_PUBLISHER_MAX_PENDING_ACKS"),
+			AckTimeout:      viper.GetDuration("PGSTREAM_NATS_PUBLISHER_ACK_TIMEOUT"),
+			MaxQueueBytes:   viper.GetInt64("PGSTREAM_NATS_PUBLISHER_MAX_QUEUE_BYTES"),
+		},
+	}
+}
+
+func parseRedisProcessorConfig() *stream.RedisProcessorConfig {
+	redisURL := viper.GetString("PGSTREAM_REDIS_URL")
+	if redisURL == "" {
+		return nil
+	}
+
+	return &stream.RedisProcessorConfig{
+		Writer: redisprocessor.Config{
+			URL:  redisURL,
+			Mode: viper.GetString("PGSTREAM_REDIS_WRITER_MODE"),
+			Stream: redisprocessor.StreamConfig{
+				KeyTemplate: viper.GetString("PGSTREAM_REDIS_WRITER_STREAM_KEY_TEMPLATE"),
+				MaxLen:      viper.GetInt64("PGSTREAM_REDIS_WRITER_STREAM_MAX_LEN"),
+			},
+			Cache: redisprocessor.CacheConfig{
+				KeyTemplates: parseRedisCacheKeyTemplates(viper.GetStringSlice("PGSTREAM_REDIS_WRITER_CACHE_KEY_TEMPLATES")),
+				Action:       viper.GetString("PGSTREAM_REDIS_WRITER_CACHE_ACTION"),
+				TTL:          viper.GetDuration("PGSTREAM_REDIS_WRITER_CACHE_TTL"),
+			},
+			BatchSize:     viper.GetInt("PGSTREAM_REDIS_WRITER_BATCH_SIZE"),
+			BatchTime:     viper.GetDuration("PGSTREAM_REDIS_WRITER_BATCH_TIMEOUT"),
+			MaxQueueBytes: viper.GetInt64("PGSTREAM_REDIS_WRITER_MAX_QUEUE_BYTES"),
+		},
+	}
+}
+
+// parseRedisCacheKeyTemplates parses the `<schema>.<table>=<template>` entries
+// on input into a map of table to key template.
+func parseRedisCacheKeyTemplates(entries []string) map[string]string {
+	if len(entries) == 0 {
+		return nil
+	}
+	templates := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		table, template, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		templates[table] = template
+	}
+	return templates
+}
+
+func parseLiveFeedProcessorConfig() *stream.LiveFeedProcessorConfig {
+	address := viper.GetString("PGSTREAM_LIVE_FEED_ADDRESS")
+	if address == "" {
+		return nil
+	}
+
+	return &stream.LiveFeedProcessorConfig{
+		Server: livefeed.Config{
+			Address:            address,
+			BufferSize:         viper.GetInt("PGSTREAM_LIVE_FEED_BUFFER_SIZE"),
+			ClientBufferSize:   viper.GetInt("PGSTREAM_LIVE_FEED_CLIENT_BUFFER_SIZE"),
+			KeepAliveInterval:  viper.GetDuration("PGSTREAM_LIVE_FEED_KEEP_ALIVE_INTERVAL"),
+			CheckpointInterval: viper.GetDuration("PGSTREAM_LIVE_FEED_CHECKPOINT_INTERVAL"),
+			AllowedOrigins:     viper.GetStringSlice("PGSTREAM_LIVE_FEED_ALLOWED_ORIGINS"),
+			APIKeys:            viper.GetStringSlice("PGSTREAM_LIVE_FEED_API_KEYS"),
+			TLS:                parseLiveFeedTLSConfig(),
+		},
+	}
+}
+
+func parseLiveFeedTLSConfig() *tls.ServerConfig {
+	certFile := viper.GetString("PGSTREAM_LIVE_FEED_TLS_CERT_FILE")
+	if certFile == "" {
+		return nil
+	}
+
+	return &tls.ServerConfig{
+		CertFile:     certFile,
+		KeyFile:      viper.GetString("PGSTREAM_LIVE_FEED_TLS_KEY_FILE"),
+		ClientCAFile: viper.GetString("PGSTREAM_LIVE_FEED_TLS_CLIENT_CA_FILE"),
+	}
+}
+
+func parseGRPCProcessorConfig() *stream.GRPCProcessorConfig {
+	address := viper.GetString("PGSTREAM_GRPC_SERVER_ADDRESS")
+	if address == "" {
+		return nil
+	}
+
+	return &stream.GRPCProcessorConfig{
+		Server: grpcprocessor.Config{
+			Address:            address,
+			BufferSize:         viper.GetInt("PGSTREAM_GRPC_SERVER_BUFFER_SIZE"),
+			ClientBufferSize:   viper.GetInt("PGSTREAM_GRPC_SERVER_CLIENT_BUFFER_SIZE"),
+			CheckpointInterval: viper.GetDuration("PGSTREAM_GRPC_SERVER_CHECKPOINT_INTERVAL"),
+			APIKeys:            viper.GetStringSlice("PGSTREAM_GRPC_SERVER_API_KEYS"),
+			TLS:                parseGRPCServerTLSConfig(),
 		},
 	}
 }
 
+func parseGRPCServerTLSConfig() *tls.ServerConfig {
+	certFile := viper.GetString("PGSTREAM_GRPC_SERVER_TLS_CERT_FILE")
+	if certFile == "" {
+		return nil
+	}
+
+	return &tls.ServerConfig{
+		CertFile:     certFile,
+		KeyFile:      viper.GetString("PGSTREAM_GRPC_SERVER_TLS_KEY_FILE"),
+		ClientCAFile: viper.GetString("PGSTREAM_GRPC_SERVER_TLS_CLIENT_CA_FILE"),
+	}
+}
+
 func parseBackoffConfig(prefix string) backoff.Config {
 	return backoff.Config{
 		Exponential: parseExponentialBackoffConfig(prefix),
@@ -248,7 +573,79 @@ func parseTranslatorConfig() *translator.Config {
 		Store: pgschemalog.Config{
 			URL: pgURL,
 		},
+		EmitSchemaChangeEvents: viper.GetBool("PGSTREAM_TRANSLATOR_SCHEMA_CHANGE_EVENTS_ENABLED"),
+	}
+}
+
+func parseWASMTransformerConfig() *wasm.Config {
+	modules := parseWASMModules(viper.GetStringSlice("PGSTREAM_WASM_TRANSFORMER_MODULES"))
+	if len(modules) == 0 {
+		return nil
+	}
+	return &wasm.Config{
+		Modules:        modules,
+		Timeout:        viper.GetDuration("PGSTREAM_WASM_TRANSFORMER_TIMEOUT"),
+		MaxMemoryBytes: viper.GetInt64("PGSTREAM_WASM_TRANSFORMER_MAX_MEMORY_BYTES"),
+		ReloadInterval: viper.GetDuration("PGSTREAM_WASM_TRANSFORMER_RELOAD_INTERVAL"),
+	}
+}
+
+func parseFilterConfig() *filter.Config {
+	expression := viper.GetString("PGSTREAM_FILTER_EXPRESSION")
+	if expression == "" {
+		return nil
+	}
+	return &filter.Config{
+		Expression: expression,
+	}
+}
+
+// parseRouterConfig parses the numbered routes, starting from 1, until a
+// route expression is not found.
+func parseRouterConfig() *router.Config {
+	routes := []router.Route{}
+	for i := 1; ; i++ {
+		prefix := fmt.Sprintf("PGSTREAM_ROUTER_ROUTE_%d", i)
+		expression := viper.GetString(prefix + "_EXPRESSION")
+		if expression == "" {
+			break
+		}
+		routes = append(routes, router.Route{
+			Expression: expression,
+			Processors: viper.GetStringSlice(prefix + "_PROCESSORS"),
+		})
+	}
+
+	defaultProcessors := viper.GetStringSlice("PGSTREAM_ROUTER_DEFAULT_PROCESSORS")
+	if len(routes) == 0 && len(defaultProcessors) == 0 {
+		return nil
+	}
+	return &router.Config{
+		Routes:            routes,
+		DefaultProcessors: defaultProcessors,
+	}
+}
+
+// parseWASMModules parses the `<schema>.<table>=<path>` entries on input into
+// the list of modules, grouping the tables of the same module path in the
+// order they're first defined.
+func parseWASMModules(entries []string) []wasm.ModuleConfig {
+	modules := []wasm.ModuleConfig{}
+	modulesByPath := map[string]int{}
+	for _, entry := range entries {
+		table, path, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		i, found := modulesByPath[path]
+		if !found {
+			i = len(modules)
+			modulesByPath[path] = i
+			modules = append(modules, wasm.ModuleConfig{Path: path})
+		}
+		modules[i].Tables = append(modules[i].Tables, table)
 	}
+	return modules
 }
 
 func parseTLSConfig(prefix string) tls.Config {
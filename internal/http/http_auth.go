@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth returns a middleware that rejects the requests that don't
+// provide one of the keys on input, either as a bearer token or in the api
+// key header. Additional lookups (i.e, "query:api_key") can be provided for
+// clients that can't set headers.
+func APIKeyAuth(keys []string, extraLookups ...string) echo.MiddlewareFunc {
+	lookups := append([]string{
+		fmt.Sprintf("header:%s:Bearer ", echo.HeaderAuthorization),
+		fmt.Sprintf("header:%s", APIKeyHeader),
+	}, extraLookups...)
+	return middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		KeyLookup: strings.Join(lookups, ","),
+		Validator: func(key string, _ echo.Context) (bool, error) {
+			return IsValidAPIKey(keys, key), nil
+		},
+	})
+}
+
+// IsValidAPIKey returns true if the key on input is one of the keys, using a
+// constant time comparison.
+func IsValidAPIKey(keys []string, key string) bool {
+	valid := false
+	// compare against all the keys to avoid leaking which one matched
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
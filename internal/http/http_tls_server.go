@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"fmt"
+
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+	"github.com/labstack/echo/v4"
+)
+
+// TLSServer serves the echo routes over HTTPS.
+type TLSServer struct {
+	*echo.Echo
+	Config *tlslib.ServerConfig
+}
+
+func (s *TLSServer) Start(address string) error {
+	tlsConfig, err := tlslib.NewServerConfig(s.Config)
+	if err != nil {
+		return fmt.Errorf("configuring server tls: %w", err)
+	}
+	s.TLSServer.Addr = address
+	s.TLSServer.TLSConfig = tlsConfig
+	return s.StartServer(s.TLSServer)
+}
@@ -53,6 +53,12 @@ func (c *Conn) Exec(ctx context.Context, query string, args ...any) (CommandTag,
 	return CommandTag{tag}, err
 }
 
+func (c *Conn) ExecInTx(ctx context.Context, fn func(tx Tx) error) error {
+	return pgx.BeginFunc(ctx, c.conn, func(tx pgx.Tx) error {
+		return fn(&Txn{Tx: tx})
+	})
+}
+
 func (c *Conn) Close(ctx context.Context) error {
 	return mapError(c.conn.Close(ctx))
 }
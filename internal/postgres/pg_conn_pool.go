@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -40,6 +41,12 @@ func (c *Pool) Exec(ctx context.Context, query string, args ...any) (CommandTag,
 	return CommandTag{tag}, err
 }
 
+func (c *Pool) ExecInTx(ctx context.Context, fn func(tx Tx) error) error {
+	return pgx.BeginFunc(ctx, c.Pool, func(tx pgx.Tx) error {
+		return fn(&Txn{Tx: tx})
+	})
+}
+
 func (c *Pool) Close(_ context.Context) error {
 	c.Pool.Close()
 	return nil
@@ -8,5 +8,6 @@ type Querier interface {
 	Query(ctx context.Context, query string, args ...any) (Rows, error)
 	QueryRow(ctx context.Context, query string, args ...any) Row
 	Exec(ctx context.Context, query string, args ...any) (CommandTag, error)
+	ExecInTx(ctx context.Context, fn func(tx Tx) error) error
 	Close(ctx context.Context) error
 }
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Tx is a postgres transaction. It is committed if the function it is passed
+// to returns no error, and rolled back otherwise.
+type Tx interface {
+	Query(ctx context.Context, query string, args ...any) (Rows, error)
+	QueryRow(ctx context.Context, query string, args ...any) Row
+	Exec(ctx context.Context, query string, args ...any) (CommandTag, error)
+}
+
+type Txn struct {
+	pgx.Tx
+}
+
+func (t *Txn) QueryRow(ctx context.Context, query string, args ...any) Row {
+	return t.Tx.QueryRow(ctx, query, args...)
+}
+
+func (t *Txn) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	return t.Tx.Query(ctx, query, args...)
+}
+
+func (t *Txn) Exec(ctx context.Context, query string, args ...any) (CommandTag, error) {
+	tag, err := t.Tx.Exec(ctx, query, args...)
+	return CommandTag{tag}, err
+}
@@ -23,8 +23,8 @@ type Client struct {
 
 var errInvalidSearchEnvelope = errors.New("invalid search response")
 
-func NewClient(url string) (*Client, error) {
-	es, err := newClient(url)
+func NewClient(cfg searchstore.ClientConfig) (*Client, error) {
+	es, err := newClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("create elasticsearch client: %w", err)
 	}
@@ -362,6 +362,27 @@ func (ec *Client) PutIndexSettings(ctx context.Context, index string, settings m
 	return nil
 }
 
+func (ec *Client) PutIndexTemplate(ctx context.Context, name string, body map[string]any) error {
+	reader, err := searchstore.CreateReader(body)
+	if err != nil {
+		return err
+	}
+	res, err := ec.client.Indices.PutIndexTemplate(
+		name,
+		reader,
+		ec.client.Indices.PutIndexTemplate.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("[PutIndexTemplate] error from Elasticsearch: %w", err)
+	}
+	defer res.Body.Close()
+
+	if err := ec.isErrResponse(res); err != nil {
+		return fmt.Errorf("[PutIndexTemplate] error response from Elasticsearch: %w", err)
+	}
+
+	return nil
+}
+
 func (ec *Client) RefreshIndex(ctx context.Context, index string) error {
 	res, err := ec.client.Indices.Refresh(
 		ec.client.Indices.Refresh.WithIndex(index),
@@ -402,6 +423,34 @@ func (ec *Client) Search(ctx context.Context, req *searchstore.SearchRequest) (*
 	return &response, nil
 }
 
+// UpdateByQuery updates all the documents matching the query on input. The
+// request query is expected to contain the script to be applied.
+func (ec *Client) UpdateByQuery(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+	reader, err := searchstore.CreateReader(req.Query)
+	if err != nil {
+		return err
+	}
+
+	res, err := ec.client.UpdateByQuery(req.Index,
+		ec.client.UpdateByQuery.WithBody(reader),
+		ec.client.UpdateByQuery.WithContext(ctx),
+		ec.client.UpdateByQuery.WithSlices("auto"),
+		ec.client.UpdateByQuery.WithConflicts("proceed"),
+		ec.client.UpdateByQuery.WithWaitForCompletion(false),
+		ec.client.UpdateByQuery.WithRefresh(req.Refresh),
+	)
+	if err != nil {
+		return fmt.Errorf("[UpdateByQuery] error from Elasticsearch: %w", err)
+	}
+	defer res.Body.Close()
+
+	if err := ec.isErrResponse(res); err != nil {
+		return fmt.Errorf("[UpdateByQuery] error response from Elasticsearch: %w", err)
+	}
+
+	return nil
+}
+
 // SendBulkRequest can perform multiple indexing or delete operations in a single call
 func (ec *Client) SendBulkRequest(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error) {
 	buffer := new(bytes.Buffer)
@@ -466,19 +515,29 @@ func (ec *Client) isErrResponse(res *esapi.Response) error {
 	return searchstore.IsErrResponse(newAPIResponse(res))
 }
 
-func newClient(address string) (*elasticsearch.Client, error) {
-	if address == "" {
-		return nil, errors.New("no address provided")
+func newClient(cfg searchstore.ClientConfig) (*elasticsearch.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.AWSSigV4 != nil {
+		return nil, fmt.Errorf("%w: aws sigv4 signing is not supported by Elasticsearch", searchstore.ErrUnsupportedAuthMethod)
 	}
 
-	cfg := elasticsearch.Config{
-		Addresses: []string{
-			address,
-		},
-		Transport: http.DefaultTransport,
+	transport, err := cfg.Transport()
+	if err != nil {
+		return nil, err
 	}
 
-	return elasticsearch.NewClient(cfg)
+	return elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{
+			cfg.URL,
+		},
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+		APIKey:    cfg.APIKey,
+		Header:    cfg.Header(),
+		Transport: transport,
+	})
 }
 
 type apiResponse struct {
@@ -26,9 +26,11 @@ type Client struct {
 	PutIndexAliasFn    func(ctx context.Context, index []string, name string) error
 	PutIndexMappingsFn func(ctx context.Context, index string, body map[string]any) error
 	PutIndexSettingsFn func(ctx context.Context, index string, body map[string]any) error
+	PutIndexTemplateFn func(ctx context.Context, name string, body map[string]any) error
 	RefreshIndexFn     func(ctx context.Context, index string) error
 	SearchFn           func(ctx context.Context, req *searchstore.SearchRequest) (*searchstore.SearchResponse, error)
 	SendBulkRequestFn  func(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error)
+	UpdateByQueryFn    func(ctx context.Context, req *searchstore.UpdateByQueryRequest) error
 	GetMapperFn        func() searchstore.Mapper
 }
 
@@ -96,6 +98,10 @@ func (m *Client) PutIndexSettings(ctx context.Context, index string, body map[st
 	return m.PutIndexSettingsFn(ctx, index, body)
 }
 
+func (m *Client) PutIndexTemplate(ctx context.Context, name string, body map[string]any) error {
+	return m.PutIndexTemplateFn(ctx, name, body)
+}
+
 func (m *Client) RefreshIndex(ctx context.Context, index string) error {
 	return m.RefreshIndexFn(ctx, index)
 }
@@ -111,3 +117,7 @@ func (m *Client) SendBulkRequest(ctx context.Context, items []searchstore.BulkIt
 func (m *Client) GetMapper() searchstore.Mapper {
 	return m.GetMapperFn()
 }
+
+func (m *Client) UpdateByQuery(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+	return m.UpdateByQueryFn(ctx, req)
+}
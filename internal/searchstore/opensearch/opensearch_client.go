@@ -23,8 +23,8 @@ type Client struct {
 
 var errInvalidSearchEnvelope = errors.New("invalid search response")
 
-func NewClient(url string) (*Client, error) {
-	os, err := newClient(url)
+func NewClient(cfg searchstore.ClientConfig) (*Client, error) {
+	os, err := newClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("create opensearch client: %w", err)
 	}
@@ -362,6 +362,27 @@ func (c *Client) PutIndexSettings(ctx context.Context, index string, settings ma
 	return nil
 }
 
+func (c *Client) PutIndexTemplate(ctx context.Context, name string, body map[string]any) error {
+	reader, err := searchstore.CreateReader(body)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Indices.PutIndexTemplate(
+		name,
+		reader,
+		c.client.Indices.PutIndexTemplate.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("[PutIndexTemplate] error from OpenSearch: %w", err)
+	}
+	defer res.Body.Close()
+
+	if err := c.isErrResponse(res); err != nil {
+		return fmt.Errorf("[PutIndexTemplate] error response from OpenSearch: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Client) RefreshIndex(ctx context.Context, index string) error {
 	res, err := c.client.Indices.Refresh(
 		c.client.Indices.Refresh.WithIndex(index),
@@ -402,6 +423,34 @@ func (c *Client) Search(ctx context.Context, req *searchstore.SearchRequest) (*s
 	return &response, nil
 }
 
+// UpdateByQuery updates all the documents matching the query on input. The
+// request query is expected to contain the script to be applied.
+func (c *Client) UpdateByQuery(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+	reader, err := searchstore.CreateReader(req.Query)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.UpdateByQuery(req.Index,
+		c.client.UpdateByQuery.WithBody(reader),
+		c.client.UpdateByQuery.WithContext(ctx),
+		c.client.UpdateByQuery.WithSlices("auto"),
+		c.client.UpdateByQuery.WithConflicts("proceed"),
+		c.client.UpdateByQuery.WithWaitForCompletion(false),
+		c.client.UpdateByQuery.WithRefresh(req.Refresh),
+	)
+	if err != nil {
+		return fmt.Errorf("[UpdateByQuery] error from OpenSearch: %w", err)
+	}
+	defer res.Body.Close()
+
+	if err := c.isErrResponse(res); err != nil {
+		return fmt.Errorf("[UpdateByQuery] error response from OpenSearch: %w", err)
+	}
+
+	return nil
+}
+
 // SendBulkRequest can perform multiple indexing or delete operations in a single call
 func (c *Client) SendBulkRequest(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error) {
 	buffer := new(bytes.Buffer)
@@ -466,19 +515,37 @@ func (c *Client) isErrResponse(res *opensearchapi.Response) error {
 	return searchstore.IsErrResponse(newAPIResponse(res))
 }
 
-func newClient(address string) (*opensearch.Client, error) {
-	if address == "" {
-		return nil, errors.New("no address provided")
+func newClient(cfg searchstore.ClientConfig) (*opensearch.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.APIKey != "" {
+		return nil, fmt.Errorf("%w: api keys are not supported by OpenSearch", searchstore.ErrUnsupportedAuthMethod)
+	}
+
+	transport, err := cfg.Transport()
+	if err != nil {
+		return nil, err
 	}
 
-	cfg := opensearch.Config{
+	osCfg := opensearch.Config{
 		Addresses: []string{
-			address,
+			cfg.URL,
 		},
-		Transport: http.DefaultTransport,
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+		Header:    cfg.Header(),
+		Transport: transport,
+	}
+
+	if cfg.AWSSigV4 != nil {
+		osCfg.Signer, err = searchstore.NewAWSSigV4Signer(context.Background(), cfg.AWSSigV4)
+		if err != nil {
+			return nil, fmt.Errorf("creating aws sigv4 signer: %w", err)
+		}
 	}
 
-	return opensearch.NewClient(cfg)
+	return opensearch.NewClient(osCfg)
 }
 
 type apiResponse struct {
@@ -17,6 +17,10 @@ const (
 	termByteLengthLimit = 32766
 )
 
+// dateTimeFormat is the format of timestamp fields, shared with the bounds of
+// timestamp ranges.
+const dateTimeFormat = "yyyy-MM-dd HH:mm:ss[.SSS][x]||yyyy-MM-dd HH:mm:ss[.SS][x]||yyyy-MM-dd HH:mm:ss[.S][x]||yyyy-MM-dd'T'HH:mm:ss[.SSS][X]"
+
 func NewMapper() *Mapper {
 	return &Mapper{}
 }
@@ -39,8 +43,10 @@ func (m *Mapper) FieldMapping(field *searchstore.Field) (map[string]any, error)
 		return map[string]any{"type": "double"}, nil
 	case searchstore.BoolType:
 		return map[string]any{"type": "boolean"}, nil
-	case searchstore.TextType, searchstore.JSONType:
+	case searchstore.TextType:
 		return map[string]any{"type": "text"}, nil
+	case searchstore.JSONType:
+		return jsonFieldMapping(field.Metadata.JSONMode), nil
 	case searchstore.StringType:
 		return map[string]any{
 			"type":         "keyword",
@@ -64,7 +70,7 @@ func (m *Mapper) FieldMapping(field *searchstore.Field) (map[string]any, error)
 	case searchstore.DateTimeType, searchstore.DateTimeTZType:
 		return map[string]any{
 			"type":   "date",
-			"format": "yyyy-MM-dd HH:mm:ss[.SSS][x]||yyyy-MM-dd HH:mm:ss[.SS][x]||yyyy-MM-dd HH:mm:ss[.S][x]||yyyy-MM-dd'T'HH:mm:ss[.SSS][X]",
+			"format": dateTimeFormat,
 		}, nil
 	case searchstore.PGVectorType:
 		vectorSettings := map[string]any{
@@ -72,7 +78,62 @@ func (m *Mapper) FieldMapping(field *searchstore.Field) (map[string]any, error)
 			"dimension": field.Metadata.VectorDimension,
 		}
 		return vectorSettings, nil
+	case searchstore.GeoPointType:
+		return map[string]any{"type": "geo_point"}, nil
+	case searchstore.GeoShapeType:
+		return map[string]any{"type": "geo_shape"}, nil
+	case searchstore.KeywordType:
+		return map[string]any{"type": "keyword"}, nil
+	case searchstore.RangeType:
+		return rangeFieldMapping(field.Metadata.RangeSubtype)
+	case searchstore.ObjectType:
+		return m.objectFieldMapping(field.Metadata.Properties)
 	default:
 		return nil, searchstore.ErrUnsupportedSearchFieldType
 	}
 }
+
+func jsonFieldMapping(mode searchstore.JSONMode) map[string]any {
+	switch mode {
+	case searchstore.JSONModeObject:
+		// the index mapping is strict, so sub-fields need to be explicitly
+		// allowed
+		return map[string]any{"type": "object", "dynamic": true}
+	case searchstore.JSONModeFlattened:
+		return map[string]any{"type": "flat_object"}
+	case searchstore.JSONModeNested:
+		return map[string]any{"type": "nested", "dynamic": true}
+	default:
+		return map[string]any{"type": "text"}
+	}
+}
+
+func rangeFieldMapping(subtype searchstore.Type) (map[string]any, error) {
+	switch subtype {
+	case searchstore.IntegerType:
+		return map[string]any{"type": "long_range"}, nil
+	case searchstore.FloatType:
+		return map[string]any{"type": "double_range"}, nil
+	case searchstore.DateType:
+		return map[string]any{"type": "date_range", "format": "date"}, nil
+	case searchstore.DateTimeType, searchstore.DateTimeTZType:
+		return map[string]any{"type": "date_range", "format": dateTimeFormat}, nil
+	default:
+		return nil, searchstore.ErrUnsupportedSearchFieldType
+	}
+}
+
+func (m *Mapper) objectFieldMapping(fields map[string]*searchstore.Field) (map[string]any, error) {
+	properties := make(map[string]any, len(fields))
+	for name, field := range fields {
+		mapping, err := m.FieldMapping(field)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = mapping
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}, nil
+}
@@ -25,6 +25,12 @@ type DeleteByQueryRequest struct {
 	Refresh bool
 }
 
+type UpdateByQueryRequest struct {
+	Index   []string
+	Query   map[string]any
+	Refresh bool
+}
+
 type IndexRequest struct {
 	Index   string
 	Body    []byte
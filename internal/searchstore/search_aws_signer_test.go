@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package searchstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccessKeyID     = "AKIDEXAMPLE"
+	testSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion          = "eu-west-1"
+)
+
+// newSigV4VerifierServer returns a stand-in for an AWS service endpoint that
+// verifies the SigV4 signature of the requests it receives, by recomputing it
+// with the known secret key.
+func newSigV4VerifierServer(t *testing.T, service string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifySigV4(r, service); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func verifySigV4(r *http.Request, service string) error {
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "AWS4-HMAC-SHA256 ") {
+		return fmt.Errorf("missing sigv4 authorization header: %q", authHeader)
+	}
+
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(authHeader, "AWS4-HMAC-SHA256 "), ", ") {
+		key, value, _ := strings.Cut(part, "=")
+		fields[key] = value
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if len(amzDate) < 8 {
+		return fmt.Errorf("invalid X-Amz-Date header: %q", amzDate)
+	}
+	wantCredential := fmt.Sprintf("%s/%s/%s/%s/aws4_request", testAccessKeyID, amzDate[:8], testRegion, service)
+	if fields["Credential"] != wantCredential {
+		return fmt.Errorf("unexpected credential scope: %s", fields["Credential"])
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	payloadHash := sha256.Sum256(body)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+	if r.Header.Get(amzContentSHA256Header) != payloadHashHex {
+		return fmt.Errorf("payload hash mismatch")
+	}
+
+	signingTime, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return err
+	}
+
+	// rebuild the request with only the signed headers, and sign it again
+	verifyReq, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	if err != nil {
+		return err
+	}
+	verifyReq.ContentLength = r.ContentLength
+	for _, header := range strings.Split(fields["SignedHeaders"], ";") {
+		if header == "host" {
+			continue
+		}
+		verifyReq.Header[http.CanonicalHeaderKey(header)] = r.Header.Values(header)
+	}
+	verifyReq.Header.Del("X-Amz-Date")
+
+	creds := aws.Credentials{AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretAccessKey}
+	if err := v4.NewSigner().SignHTTP(context.Background(), creds, verifyReq, payloadHashHex, service, testRegion, signingTime); err != nil {
+		return err
+	}
+
+	if verifyReq.Header.Get("Authorization") != authHeader {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func TestAWSSigV4Signer_SignRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		service         string
+		secretAccessKey string
+		body            string
+
+		wantStatus int
+	}{
+		{
+			name:            "ok - request with body",
+			secretAccessKey: testSecretAccessKey,
+			body:            `{"query":{"match_all":{}}}`,
+
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "ok - request without body",
+			secretAccessKey: testSecretAccessKey,
+
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "ok - serverless service",
+			service:         "aoss",
+			secretAccessKey: testSecretAccessKey,
+			body:            `{}`,
+
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "error - invalid signature",
+			secretAccessKey: "invalid",
+			body:            `{}`,
+
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantService := tc.service
+			if wantService == "" {
+				wantService = defaultAWSSigningService
+			}
+			server := newSigV4VerifierServer(t, wantService)
+			defer server.Close()
+
+			signer, err := NewAWSSigV4Signer(context.Background(), &AWSSigV4Config{
+				Region:          testRegion,
+				Service:         tc.service,
+				AccessKeyID:     testAccessKeyID,
+				SecretAccessKey: tc.secretAccessKey,
+			})
+			require.NoError(t, err)
+
+			var body io.Reader = http.NoBody
+			if tc.body != "" {
+				body = strings.NewReader(tc.body)
+			}
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/index/_search?size=10", body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			err = signer.SignRequest(req)
+			require.NoError(t, err)
+
+			// the body must still be readable after signing
+			if tc.body != "" {
+				bodyBytes, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.Equal(t, tc.body, string(bodyBytes))
+				req.Body = io.NopCloser(strings.NewReader(tc.body))
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Equal(t, tc.wantStatus, resp.StatusCode, string(respBody))
+		})
+	}
+}
+
+func TestNewAWSSigV4Signer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAWSSigV4Signer(context.Background(), &AWSSigV4Config{})
+	require.ErrorIs(t, err, errAWSRegionMissing)
+}
@@ -27,9 +27,11 @@ type Client interface {
 	PutIndexAlias(ctx context.Context, index []string, name string) error
 	PutIndexMappings(ctx context.Context, index string, body map[string]any) error
 	PutIndexSettings(ctx context.Context, index string, body map[string]any) error
+	PutIndexTemplate(ctx context.Context, name string, body map[string]any) error
 	RefreshIndex(ctx context.Context, index string) error
 	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
 	SendBulkRequest(ctx context.Context, items []BulkItem) ([]BulkItem, error)
+	UpdateByQuery(ctx context.Context, req *UpdateByQueryRequest) error
 	GetMapper() Mapper
 }
 
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package searchstore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+)
+
+type ClientConfig struct {
+	URL string
+	// Username and Password are used for basic authentication.
+	Username string
+	Password string
+	// APIKey is the base64 encoded Elasticsearch API key. Not supported by
+	// OpenSearch.
+	APIKey string
+	// BearerToken is sent in the Authorization header of every request.
+	BearerToken string
+	// AWSSigV4 enables AWS Signature Version 4 request signing, for Amazon
+	// OpenSearch Service. Not supported by Elasticsearch.
+	AWSSigV4 *AWSSigV4Config
+	TLS      tls.Config
+}
+
+var (
+	errNoAddress             = errors.New("no address provided")
+	errMultipleAuthMethods   = errors.New("only one authentication method can be configured")
+	ErrUnsupportedAuthMethod = errors.New("unsupported authentication method")
+)
+
+// Validate checks the client configuration is valid. Only one authentication
+// method can be configured.
+func (c *ClientConfig) Validate() error {
+	if c.URL == "" {
+		return errNoAddress
+	}
+
+	authMethods := 0
+	for _, configured := range []bool{
+		c.Username != "" || c.Password != "",
+		c.APIKey != "",
+		c.BearerToken != "",
+		c.AWSSigV4 != nil,
+	} {
+		if configured {
+			authMethods++
+		}
+	}
+	if authMethods > 1 {
+		return errMultipleAuthMethods
+	}
+	return nil
+}
+
+// Header returns the global headers to be sent with every request.
+func (c *ClientConfig) Header() http.Header {
+	if c.BearerToken == "" {
+		return nil
+	}
+	return http.Header{
+		"Authorization": []string{"Bearer " + c.BearerToken},
+	}
+}
+
+// Transport returns the http transport for the client, using the TLS
+// configuration when enabled.
+func (c *ClientConfig) Transport() (http.RoundTripper, error) {
+	tlsConfig, err := tls.NewConfig(&c.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("building tls config: %w", err)
+	}
+	if tlsConfig == nil {
+		return http.DefaultTransport, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -2,6 +2,8 @@
 
 package searchstore
 
+import "fmt"
+
 type Mapper interface {
 	GetDefaultIndexSettings() map[string]any
 	FieldMapping(*Field) (map[string]any, error)
@@ -15,6 +17,45 @@ type Field struct {
 
 type Metadata struct {
 	VectorDimension int
+	JSONMode        JSONMode
+	// RangeSubtype is the type of the bounds of a range field
+	RangeSubtype Type
+	// Properties contains the sub-fields of an object field, by name
+	Properties map[string]*Field
+}
+
+// JSONMode determines how JSON fields are mapped in the search store.
+type JSONMode string
+
+const (
+	// JSONModeText indexes the JSON value as an analysed text string.
+	JSONModeText JSONMode = "text"
+	// JSONModeObject indexes the JSON value as an object with dynamic
+	// sub-field mappings.
+	JSONModeObject JSONMode = "object"
+	// JSONModeFlattened indexes the whole JSON object as a single field
+	// (`flattened` in Elasticsearch, `flat_object` in OpenSearch).
+	JSONModeFlattened JSONMode = "flattened"
+	// JSONModeNested indexes arrays of JSON objects as independent nested
+	// documents.
+	JSONModeNested JSONMode = "nested"
+)
+
+// ParseJSONMode returns the JSON mode for the string on input. It defaults to
+// text mode when the input is empty.
+func ParseJSONMode(mode string) (JSONMode, error) {
+	switch mode {
+	case "", string(JSONModeText):
+		return JSONModeText, nil
+	case string(JSONModeObject):
+		return JSONModeObject, nil
+	case string(JSONModeFlattened), "flat_object":
+		return JSONModeFlattened, nil
+	case string(JSONModeNested):
+		return JSONModeNested, nil
+	default:
+		return "", fmt.Errorf("unsupported json mapping mode: %s", mode)
+	}
 }
 
 type Type uint
@@ -31,4 +72,9 @@ const (
 	JSONType
 	TextType
 	PGVectorType
+	GeoPointType
+	GeoShapeType
+	KeywordType
+	RangeType
+	ObjectType
 )
@@ -16,6 +16,10 @@
 // migrations/postgres/6_create_pgstream_refresh_schema_function.up.sql
 // migrations/postgres/7_create_pgstream_event_triggers.down.sql
 // migrations/postgres/7_create_pgstream_event_triggers.up.sql
+// migrations/postgres/8_add_pgstream_get_schema_type_info.down.sql
+// migrations/postgres/8_add_pgstream_get_schema_type_info.up.sql
+// migrations/postgres/9_add_pgstream_get_schema_replica_identity.down.sql
+// migrations/postgres/9_add_pgstream_get_schema_replica_identity.up.sql
 package pgmigrations
 
 import (
@@ -92,7 +96,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __1_create_pgstream_xidDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\xab\xc8\x4c\x89\x4f\xce\x2f\xcd\x2b\x49\x2d\xb2\xe6\x22\x4a\x75\x41\x66\x0a\x91\x2a\x73\x13\x93\x33\x32\xf3\x52\x89\x54\x5d\x92\x99\x4b\xa4\x52\x22\x0d\x4c\x49\x4d\xce\x4f\x21\xd6\xf6\xd4\x3c\x22\x15\xc7\x23\xf9\x2c\x1e\xee\x96\x60\xd7\xc0\x50\x57\x3f\x67\x57\x5c\xc6\x17\xa7\x16\x65\x26\xe6\x28\x40\x55\xbb\xf8\xfb\x3a\x7a\xe2\xf6\x1d\x20\x00\x00\xff\xff\x93\x5b\x45\xc7\xb5\x01\x00\x00")
+var __1_create_pgstream_xidDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\xab\xc8\x4c\x89\x4f\xce\x2f\xcd\x2b\x49\x2d\xb2\xe6\x22\x4a\x75\x41\x66\x0a\x91\x2a\x73\x13\x93\x33\x32\xf3\x52\x89\x54\x5d\x92\x99\x4b\xa4\x52\x22\x0d\x4c\x49\x4d\xce\x4f\x21\xd6\xf6\xd4\x3c\x22\x15\xc7\x23\xf9\x2c\x1e\xee\x96\x60\xd7\xc0\x50\x57\x3f\x67\x57\x5c\xc6\x17\xa7\x16\x65\x26\xe6\x28\x40\x55\xbb\xf8\xfb\x3a\x7a\xe2\x72\x8a\x35\x17\x60\x00\x93\x5b\x45\xc7\xb5\x01\x00\x00")
 
 func _1_create_pgstream_xidDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -107,12 +111,12 @@ func _1_create_pgstream_xidDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "1_create_pgstream_xid.down.sql", size: 437, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "1_create_pgstream_xid.down.sql", size: 437, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __1_create_pgstream_xidUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x58\x6b\x73\xe2\x46\xd6\xfe\xce\xaf\x78\x6a\xca\x15\xa3\xf7\x6d\xcf\x20\x71\xf3\x65\x92\x2a\x59\xb4\x8d\x2a\x20\xb1\x92\x88\xc7\xeb\xf2\x52\x42\x6a\x40\x89\x2e\x44\x6a\x66\xc6\x95\xc9\xfe\xf6\xad\x6e\x81\x0d\x1e\x06\x1b\xb2\xb5\x9b\xaa\x8d\x3f\xc8\xa2\xfb\x9c\xe7\x5c\x9e\xd3\xa7\xd5\x7d\x72\x02\x23\x9b\x3f\xe4\xd1\x74\xc6\xa1\xd5\x34\x0d\x8e\x5f\x24\x8b\x02\xdd\x2c\x4e\x2a\x27\x27\x18\xb0\x3c\x89\x8a\x22\xca\x52\x44\x05\x66\x2c\x67\xe3\x07\x4c\x73\x3f\xe5\x2c\x24\x98\xe4\x8c\x21\x9b\x20\x98\xf9\xf9\x94\x11\xf0\x0c\x7e\xfa\x80\x39\xcb\x8b\x2c\x45\x36\xe6\x7e\x94\x46\xe9\x14\x3e\x82\x6c\xfe\x20\x24\xf9\x2c\x2a\x50\x64\x13\xfe\xc9\xcf\x19\xfc\x34\x84\x5f\x14\x59\x10\xf9\x9c\x85\x08\xb3\x60\x91\xb0\x94\xfb\x5c\xd8\x9b\x44\x31\x2b\x50\xe5\x33\x86\x37\xee\x52\xe3\x8d\x22\x8d\x84\xcc\x8f\x11\xa5\x10\x73\xab\x29\x7c\x8a\xf8\x2c\x5b\x70\xe4\xac\xe0\x79\x14\x08\x0c\x82\x28\x0d\xe2\x45\x28\x7c\x58\x4d\xc7\x51\x12\x2d\x2d\x08\x75\x19\x7a\x21\x40\x17\x05\x23\xd2\x4f\x82\x24\x0b\xa3\x89\xf8\xcf\x64\x58\xf3\xc5\x38\x8e\x8a\x19\x41\x18\x09\xe8\xf1\x82\x33\x82\x42\x0c\x06\x2c\x15\x5a\x7e\x1a\xbe\xcb\x72\x14\x2c\x8e\x05\x42\xc4\x8a\x32\xd6\x27\xef\xa4\x8c\xb0\x32\x17\x09\xe5\xcb\x14\x49\xbb\x9f\x66\x59\xb2\x19\x49\x54\x60\xb2\xc8\xd3\xa8\x98\x31\xa9\x13\x66\x28\x32\x69\xf1\x67\x16\x70\x31\x22\xc4\x27\x59\x1c\x67\x9f\x44\x68\x41\x96\x86\x91\x88\xa8\x38\x17\x9c\x79\x33\x06\x7f\x9c\x7d\x64\x32\x9a\x92\xdb\x34\xe3\x51\x50\x26\x5c\x52\x30\x7f\xe2\x75\x39\x55\xcc\xfc\x38\xc6\x98\x2d\x53\xc6\x42\x91\x60\x7f\x2d\xa0\x5c\x38\x50\x70\x3f\xe5\x91\x1f\x63\x9e\xe5\xd2\xe2\xf3\x40\xdf\x4a\x0f\xba\x14\xae\x7d\xe5\xdd\xe8\x0e\x85\xe9\x62\xe0\xd8\x3f\x99\x1d\xda\xc1\x1b\xdd\x85\xe9\xbe\x21\xb8\x31\xbd\xae\x3d\xf4\x70\xa3\x3b\x8e\x6e\x79\xb7\xb0\xaf\xa0\x5b\xb7\xf8\xd1\xb4\x3a\x04\xf4\xc3\xc0\xa1\xae\x0b\xdb\x81\xd9\x1f\xf4\x4c\xda\x21\x30\x2d\xa3\x37\xec\x98\xd6\x35\x2e\x87\x1e\x2c\xdb\x43\xcf\xec\x9b\x1e\xed\xc0\xb3\xa5\xc1\x25\x94\x49\x5d\x01\xd6\xa7\x8e\xd1\xd5\x2d\x4f\xbf\x34\x7b\xa6\x77\x4b\x70\x65\x7a\x96\xc0\xbc\xb2\x1d\xe8\x18\xe8\x8e\x67\x1a\xc3\x9e\xee\x60\x30\x74\x06\xb6\x4b\xa1\x5b\x1d\x58\xb6\x65\x5a\x57\x8e\x69\x5d\xd3\x3e\xb5\xbc\xb7\x30\x2d\x58\x36\xe8\x4f\xd4\xf2\xe0\x76\xf5\x5e\x4f\x9a\xd2\x87\x5e\xd7\x76\xa4\x7f\x86\x3d\xb8\x75\xcc\xeb\xae\x87\xae\xdd\xeb\x50\xc7\xc5\x25\x45\xcf\xd4\x2f\x7b\xb4\x34\x65\xdd\xc2\xe8\xe9\x66\x9f\xa0\xa3\xf7\xf5\x6b\x2a\xb5\x6c\xaf\x4b\x1d\x29\xb6\xf4\xee\xa6\x4b\xe5\x90\x69\x41\xb7\xa0\x1b\x9e\x69\x5b\x22\x0c\xc3\xb6\x3c\x47\x37\x3c\x02\xcf\x76\xbc\x47\xd5\x1b\xd3\xa5\x04\xba\x63\xba\x22\x21\x57\x8e\xdd\x27\x10\xe9\xb4\xaf\x64\xce\x2c\xa1\x67\xd1\x12\x45\xa4\x7a\x93\x11\xdb\x91\xbf\x87\x2e\x7d\xf2\xa5\x43\xf5\x9e\x69\x5d\xbb\x42\x79\x5d\xf8\x6d\x45\x10\xfa\x39\x0a\x31\x59\xa4\x41\x49\xf9\x24\xcf\x12\xcc\x38\x9f\x17\xe7\xef\xde\x4d\x23\x3e\x5b\x8c\xdf\x06\x59\xf2\x2e\xc9\xc2\x49\x94\xbe\x9b\x4f\x4f\x3e\x47\x21\xc1\x78\xc1\x91\xfa\x09\x2b\xe6\x7e\xc0\x44\x8f\x48\xa7\x2c\x94\xca\x02\x52\x2e\xaa\x40\x2e\x89\x69\xc1\x73\xe6\x27\x95\x4a\xc7\xc6\xd1\x11\x2e\xe9\xb5\x69\x55\x00\xc0\x70\xa8\xee\x51\x74\xec\xbe\x6e\x5a\x8f\x72\x6f\x85\x37\xba\x0b\xa3\xab\x3b\x55\xad\xa6\xc0\xe8\x52\xe3\x47\x54\x7f\xd2\x7b\x43\x8a\x7f\xe2\xf8\x1f\x77\xfe\xc9\xc7\xda\xc9\xd9\xfd\x6f\x5a\xed\xf7\xa3\x63\xe5\xa2\x42\x3f\x18\x74\x20\xb2\x21\x61\x6f\xba\xd4\x42\xb8\x98\xc7\x51\xe0\x73\x36\xca\xca\x65\xe5\x89\xd1\x74\x11\xc7\x17\x15\x6a\x75\x70\x74\x74\x51\xa9\x2c\x1d\x70\xe9\xdf\x86\xd4\x32\x28\xcc\x2b\x59\x79\xf4\x83\xe9\x7a\xee\x86\x43\xa3\x82\xe5\x62\x5d\xf4\x4d\xab\xf4\xa3\x86\xbe\xfe\xa1\x7c\x55\x5b\xed\x76\x5b\x53\x9b\x30\x6e\x8d\x1e\xbd\xc0\xc9\x09\x50\xad\x6a\xcd\xe6\xfb\xf7\x6a\x4b\xc1\xff\xa3\x7c\x3f\x15\xaf\x5a\xb3\xa9\x28\x95\x8a\x4b\x7b\xd4\xf0\x50\x30\xfe\xd1\x8f\xab\xc7\x5b\x2c\x1d\x13\x54\x73\x3f\x0d\xb3\xa4\xaa\xe0\xff\x1e\x6d\x28\xe7\xe7\xa6\xe5\x29\xaf\x32\xb2\x8c\xce\x76\xe0\xd0\x41\x4f\x37\x28\xae\x86\x56\x59\x34\x8f\x06\x47\xc2\x62\xe2\x07\xb3\x28\x65\xa3\x28\xac\x2a\x32\x83\x0e\xf5\x86\x8e\x25\x8a\xc5\x93\xbf\x7b\xba\x75\x3d\xd4\xaf\x29\xe6\xf1\x7c\x5a\xfc\x1a\xcb\x41\xb3\xdf\x1f\x7a\x62\x21\x54\x74\xb7\x72\x74\x54\xe9\x50\xa3\xa7\x3b\xb4\xf2\xc4\x6f\x09\x83\xea\x2a\xda\x87\x82\xb3\x64\x14\x85\x2c\xe5\xd1\x24\x62\x39\xbe\x7b\xca\x9d\x28\x72\xcc\xa7\xa3\x20\x4b\x79\x9e\xc5\xa3\x52\xb8\xaa\x28\x92\xae\xca\x3a\x5d\x3b\x03\x12\xf1\xb0\x34\xc8\x42\x56\x1d\x45\xa2\xc9\xf1\xbb\xfb\xcd\xa0\xd6\x45\xb7\x47\xb7\x19\x8f\x10\x29\x21\x45\x33\x96\x55\xa9\x2a\x77\xf7\xf8\x1e\xc7\xbf\xd5\x08\x54\x02\x8d\xa0\x4e\xd0\x20\x68\x12\xb4\x08\xda\x04\xa7\x04\x67\x04\x3e\xc1\x98\x20\x20\x08\x09\x18\xc1\x84\x60\x4a\x30\x23\x88\x08\x7e\x26\xf8\x85\x20\x26\x48\x08\x52\x82\x8c\x60\x4e\xf0\x2b\x41\x4e\x50\x10\x70\x82\x05\xc1\xc7\xdf\x8f\x2f\xbe\xce\xe6\xa3\x2b\x77\xaa\x60\x7d\x14\x85\x77\xea\x3d\x7e\xf8\x01\x75\xe5\x5e\xca\xad\xfd\x7d\xf9\xf2\x5c\x5c\xca\x6b\x52\xbe\xa5\xe0\x3b\xd4\x55\x7c\x79\x04\x79\xff\x1e\x5a\x39\xb8\x27\x94\xba\xa7\x56\x5d\x6a\x35\x36\x1d\xd0\xa4\x03\x8d\xd7\x43\x09\xa5\x86\x44\x6a\xaf\x30\xea\x12\x63\x5f\x77\x4a\x90\x3d\x42\x17\x4a\x4d\xa9\xd4\x5c\x59\x6e\x48\xcb\xf5\xbd\x31\xf6\x11\x6f\xed\xc9\x73\x7b\x1b\xcf\xad\x83\x78\x6e\x1f\xc4\xf3\xe9\x36\x9e\xdb\x87\xf0\x7c\xb6\xc9\xf3\xe9\x41\x3c\x9f\xed\xcb\x73\xb9\x30\x6a\x25\xd3\xca\xca\xf8\xd9\x21\x54\x0b\x94\xbd\xe4\xf7\x5d\xd4\xea\xf6\x55\x7d\xd8\xb2\x56\xbf\xb5\xae\x77\xc8\xaf\x93\x2a\x9b\xf6\xc5\x7e\x5d\x3b\x64\x65\xd7\x16\xdb\xff\xfa\xc4\x66\xf7\x96\x0d\xfd\xd5\x6d\x3b\x64\x41\xa9\x21\xbb\xb5\xd6\x6c\x12\xfc\x6f\x3c\x76\x6f\x4c\xff\x7d\xff\xfe\xf0\x43\x15\x11\xaa\x04\xaa\x46\xa0\xd6\x09\xd4\x06\x81\x2a\xc6\x5b\x04\x6a\x9b\x40\x3d\x25\x50\x45\xa8\x35\x02\x4d\xa4\x42\x23\xd0\xea\x04\x5a\x43\x00\x10\x68\x2d\x02\xad\x4d\xa0\x9d\x12\x68\x67\x04\xf5\x1a\x41\x5d\xfd\x33\x44\xf6\xd7\xe3\xaf\xc7\xab\x1e\xe2\xf3\x50\x76\xb9\x31\x80\xf1\x03\x67\xfe\xfa\xe7\xe2\x68\x8c\xf3\xef\x21\xba\xe9\xf9\xf9\xe5\xad\x47\xf5\x52\x38\x67\x7c\x91\xa7\x10\x87\xe9\x5b\xdc\x6d\x6c\x07\xd5\xaa\xe8\x97\x77\x53\xc6\x47\x02\xad\x3a\x1a\x13\xd4\x94\xd5\x66\x27\x36\x94\xaf\xa6\x55\x65\xb9\xa5\x8a\xc6\x2f\xfc\x7a\x11\x50\x2d\x01\x5b\xdf\x00\xd4\x94\xd5\xce\xbe\x75\xba\xae\x2c\xbf\x28\x5e\x6d\xaf\xae\xac\x76\xa6\xad\x80\x0d\x65\xb9\xd1\xbd\x1a\xb0\x51\x02\xb6\xbf\x01\xd8\x54\x56\x1b\xee\xd6\xe9\x96\xb2\xdc\xd6\x5f\x6d\xaf\x55\x02\x36\xbf\x01\xd8\x56\xee\x5f\x8f\x75\xba\x9b\xcd\xb3\xbd\xd9\x3c\xdb\xcd\xa6\x5a\xdb\x4d\xa7\xaa\xee\xcd\xa7\xaa\xee\x26\x54\xd5\xf6\x66\x54\xd5\x76\x53\xaa\xd6\x77\x73\xaa\x36\xf6\x26\x55\x6d\xec\x66\x55\x6d\xee\x43\xab\xda\x7a\x61\x95\xb6\xf7\x5f\xa6\xed\x17\x98\x3d\x7d\x81\xd9\xb3\x67\xcc\x3e\x5a\x3c\xe4\xdb\xb0\x3a\xf2\x39\x3c\xb3\x4f\x5d\x4f\xef\x0f\xbc\xbf\xa3\x43\xaf\xf4\x61\xcf\x83\x31\x74\x1c\x6a\x79\xa3\xc7\xb9\x7f\xc3\x51\x9f\xc3\xb4\xbc\x65\x67\x4d\xd6\xde\xe7\x6b\xef\x41\xf9\xbe\xd6\x6d\xb9\xe8\xb6\x93\x38\xcb\xf2\x2a\xfd\x20\xef\xf4\xaa\x6c\x9e\x05\xb3\xf2\x62\x63\xe4\x73\x45\x79\xc4\x3c\xff\x7e\xc7\x05\xcc\xa3\x35\x29\x35\x1a\xfb\xc1\x2f\x2c\x0d\x47\xf3\xb5\xb9\x40\xcc\xa5\xec\xf3\xb7\x2f\x8f\xca\x6b\xa2\x8b\xca\x7a\xcb\xdf\x76\x45\xb2\x75\x1b\x18\x71\x59\x2c\x8d\x55\xb1\xac\x46\xd4\xd6\xf3\x91\xd3\xe5\x00\x88\x48\xc0\xb6\xd2\x1a\x25\x5f\x69\x26\xcf\x35\x93\xed\x9a\xf3\x75\x39\x22\x32\xb2\x55\x2c\xf8\xca\x40\xf0\xdc\x40\xf0\xbc\x04\x95\x43\xce\x27\x3c\x4a\x5e\x3c\x9d\xac\xd5\xe8\xab\xeb\x6d\x75\x4b\xb5\x51\x4e\x51\xb8\x51\x25\xcf\xce\x47\xca\xc6\x5e\xce\x33\xe9\x5b\xc1\xfd\x64\x5e\x5d\xbf\xcb\x69\x28\xe7\xe7\x97\xe6\xb5\x69\x79\xab\x53\x65\x79\x4c\x5b\x5e\x18\x3e\x5d\x98\x9c\x2a\x4f\xf7\x29\xca\x41\xc9\x59\x56\xf0\x4b\xf9\x31\x2d\x6f\x8f\xd3\xdb\x1f\xce\xcc\xb2\xbc\xcb\xab\x16\x82\xf2\xfa\xa3\xfc\xdf\xbe\x3f\xe8\x90\x2a\x96\xe1\xcb\x31\xfe\xe7\x22\x5c\xbb\x0d\x79\x22\xf1\xec\xb0\x02\x0f\xb2\x45\xca\x59\xfe\xe7\x8b\x4f\xad\x3d\x2f\xdb\xe5\xb5\xc6\x53\xc8\xaa\xb6\x11\xf3\xbf\x02\x00\x00\xff\xff\xe6\x0a\xf8\x30\xf9\x1c\x00\x00")
+var __1_create_pgstream_xidUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x58\x6b\x6f\xe2\x48\x16\xfd\xce\xaf\x38\x6a\x45\x13\xbc\x5b\xe9\xc6\xe6\x95\x57\x8f\xe4\x98\x4a\xb0\x06\xec\xac\x6d\x26\x9d\x8d\x58\x64\xec\x02\x3c\xe3\xd7\xd8\x45\xf7\x44\xd3\xb3\xbf\x7d\x55\x36\x10\x48\x13\x12\xd0\x6a\x34\xab\x1d\x45\x72\x4c\xd5\xbd\xe7\x3e\xce\xad\x5b\xae\x3a\x39\x81\x96\xa4\x8f\x59\x30\x9d\x71\x28\x35\x45\x81\xe5\xe6\xd1\x3c\x47\x37\x09\xa3\xca\xc9\x09\x6e\x59\x16\x05\x79\x1e\x24\x31\x82\x1c\x33\x96\xb1\xf1\x23\xa6\x99\x1b\x73\xe6\x13\x4c\x32\xc6\x90\x4c\xe0\xcd\xdc\x6c\xca\x08\x78\x02\x37\x7e\x44\xca\xb2\x3c\x89\x91\x8c\xb9\x1b\xc4\x41\x3c\x85\x0b\x2f\x49\x1f\x85\x24\x9f\x05\x39\xf2\x64\xc2\xbf\xb8\x19\x83\x1b\xfb\x70\xf3\x3c\xf1\x02\x97\x33\x1f\x7e\xe2\xcd\x23\x16\x73\x97\x0b\x7b\x93\x20\x64\x39\xaa\x7c\xc6\xf0\xce\x5e\x68\xbc\x93\x0a\x23\x3e\x73\x43\x04\x31\xc4\xdc\x72\x0a\x5f\x02\x3e\x4b\xe6\x1c\x19\xcb\x79\x16\x78\x02\x83\x20\x88\xbd\x70\xee\x0b\x1f\x96\xd3\x61\x10\x05\x0b\x0b\x42\xbd\x08\x3d\x17\xa0\xf3\x9c\x91\xc2\x4f\x82\x28\xf1\x83\xc9\x23\x41\xc4\x8a\xb0\xd2\xf9\x38\x0c\xf2\x19\x81\x1f\x08\xe8\xf1\x9c\x33\x82\x5c\x0c\x7a\x2c\x16\x5a\x6e\xec\x7f\x48\x32\xe4\x2c\x0c\x05\x42\xc0\xf2\x32\xd6\x27\xef\x0a\x19\x61\x25\x15\x09\xe5\x8b\x14\x15\x76\xbf\xcc\x92\x68\x33\x92\x20\xc7\x64\x9e\xc5\x41\x3e\x63\xbe\x90\xf0\x13\xe4\x49\x61\xf1\x27\xe6\x71\x31\x22\xc4\x27\x49\x18\x26\x5f\x44\x68\x5e\x12\xfb\x81\x88\x28\x3f\x17\x9c\x39\x33\x06\x77\x9c\x7c\x66\xf0\x56\xdc\xc6\x09\x0f\xbc\x32\xe1\x05\x05\xe9\x13\xaf\x8b\xa9\x7c\xe6\x86\x21\xc6\x6c\x91\x32\xe6\x8b\x04\xbb\x6b\x01\x65\xc2\x81\x9c\xbb\x31\x0f\xdc\x10\x69\x92\x15\x16\x9f\x07\xfa\xbe\xf0\xa0\x4b\x61\x9b\xd7\xce\x9d\x6a\x51\xe8\x36\x6e\x2d\xf3\x47\xbd\x43\x3b\x78\xa7\xda\xd0\xed\x77\x04\x77\xba\xd3\x35\x07\x0e\xee\x54\xcb\x52\x0d\xe7\x1e\xe6\x35\x54\xe3\x1e\x3f\xe8\x46\x87\x80\x7e\xba\xb5\xa8\x6d\xc3\xb4\xa0\xf7\x6f\x7b\x3a\xed\x10\xe8\x86\xd6\x1b\x74\x74\xe3\x06\x57\x03\x07\x86\xe9\xa0\xa7\xf7\x75\x87\x76\xe0\x98\x70\xba\x74\x09\xa5\x53\x5b\x80\xf5\xa9\xa5\x75\x55\xc3\x51\xaf\xf4\x9e\xee\xdc\x13\x5c\xeb\x8e\x21\x30\xaf\x4d\x0b\x2a\x6e\x55\xcb\xd1\xb5\x41\x4f\xb5\x70\x3b\xb0\x6e\x4d\x9b\x42\x35\x3a\x30\x4c\x43\x37\xae\x2d\xdd\xb8\xa1\x7d\x6a\x38\xef\xa1\x1b\x30\x4c\xd0\x1f\xa9\xe1\xc0\xee\xaa\xbd\x5e\x61\x4a\x1d\x38\x5d\xd3\x2a\xfc\xd3\xcc\xdb\x7b\x4b\xbf\xe9\x3a\xe8\x9a\xbd\x0e\xb5\x6c\x5c\x51\xf4\x74\xf5\xaa\x47\x4b\x53\xc6\x3d\xb4\x9e\xaa\xf7\x09\x3a\x6a\x5f\xbd\x11\xde\x59\x30\x9d\x2e\xb5\x0a\xb1\x85\x77\x77\x5d\x5a\x0c\xe9\x06\x54\x03\xaa\xe6\xe8\xa6\x21\xc2\xd0\x4c\xc3\xb1\x54\xcd\x21\x70\x4c\xcb\x59\xa9\xde\xe9\x36\x25\x50\x2d\xdd\x16\x09\xb9\xb6\xcc\x3e\x81\x48\xa7\x79\x2d\x44\x74\x03\x9a\x69\x18\xb4\x44\x11\xa9\xde\x64\xc4\xb4\x8a\xdf\x03\x9b\x3e\xf9\xd2\xa1\x6a\x4f\x37\x6e\x6c\x11\xf1\xba\xf0\xfb\x8a\x20\xf4\xd7\xc0\xc7\x64\x1e\x17\x8b\x2a\xc7\x24\x4b\x22\xcc\x38\x4f\xf3\xf3\x0f\x1f\xa6\x01\x9f\xcd\xc7\xef\xbd\x24\xfa\x10\x25\xfe\x24\x88\x3f\xa4\xd3\x93\x5f\x03\x9f\x60\x3c\xe7\x88\xdd\x88\xe5\xa9\xeb\x31\xd1\x23\xe2\x29\xf3\x0b\x65\x01\x59\x2c\x2a\x4f\x14\x73\x3a\xcd\x79\xc6\xdc\xa8\x52\xe9\x98\x38\x3a\xc2\x15\xbd\xd1\x8d\x0a\x00\x68\x16\x55\x1d\x8a\x8e\xd9\x57\x75\x63\x25\xf7\x5e\x78\xa3\xda\xd0\xba\xaa\x55\x55\x6a\x12\xb4\x2e\xd5\x7e\x40\xf5\x47\xb5\x37\xa0\xf8\x37\x8e\xff\xf5\xe0\x9e\x7c\xae\x9d\x9c\x0d\x7f\x53\x6a\xbf\x1f\x1d\x4b\x17\x15\xfa\x49\xa3\xb7\x22\x1b\x05\xec\x5d\x97\x1a\xf0\xe7\x69\x18\x78\x2e\x67\xa3\xa4\x5c\x56\x8e\x18\x8d\xe7\x61\x78\x51\xa1\x46\x07\x47\x47\x17\x95\xca\xc2\x01\x9b\xfe\x63\x40\x0d\x8d\x42\xbf\x2e\x2a\x8f\x7e\xd2\x6d\xc7\xde\x70\x68\x94\xb3\x4c\xac\x8b\xbe\x6e\x94\x7e\xd4\xd0\x57\x3f\x95\xaf\x72\xab\xdd\x6e\x2b\x72\x13\xda\xbd\xd6\xa3\x17\x38\x39\x01\xaa\x55\xa5\xd9\xbc\xbc\x94\x5b\x12\xfe\x8e\xf2\xfd\x54\xbc\x2a\xcd\xa6\x24\x55\x2a\x36\xed\x51\xcd\x41\xce\xf8\x67\x37\xac\x1e\x6f\xb1\x74\x4c\x50\xcd\xdc\xd8\x4f\xa2\xaa\x84\xbf\xad\x6c\x48\xe7\xe7\xba\xe1\x48\x6f\x32\xb2\x88\xce\xb4\x60\xd1\xdb\x9e\xaa\x51\x5c\x0f\x8c\xb2\x68\x56\x06\x47\x22\xb6\xc8\xf5\x66\x41\xcc\x46\x81\x5f\x95\x8a\x0c\x5a\xd4\x19\x58\x86\x28\x16\xa7\xf8\xdd\x53\x8d\x9b\x81\x7a\x43\x91\x86\xe9\x34\xff\x25\x2c\x06\xf5\x7e\x7f\xe0\x88\x85\x50\x51\xed\xca\xd1\x51\xa5\x43\xb5\x9e\x6a\xd1\xca\x13\xbf\x25\x0c\xaa\xcb\x68\x1f\x73\xce\xa2\x51\xe0\xb3\x98\x07\x93\x80\x65\xf8\xee\x29\x77\xa2\xc8\x91\x4e\x47\x5e\x12\xf3\x2c\x09\x47\xa5\x70\x55\x12\xec\x1a\x9d\xca\x3a\x5d\x3b\x03\x12\xf1\xb0\xd8\x4b\x7c\x56\x1d\x05\xa2\xc9\xf1\x87\xe1\x66\x50\xeb\xa2\xdb\xa3\xdb\x8c\x47\x88\x94\x90\xa2\x19\x17\x55\x29\x4b\x0f\x43\x7c\xc4\xf1\x6f\x35\x02\x99\x40\x21\xa8\x13\x34\x08\x9a\x04\x2d\x82\x36\xc1\x29\xc1\x19\x81\x4b\x30\x26\xf0\x08\x7c\x02\x46\x30\x21\x98\x12\xcc\x08\x02\x82\x9f\x08\x7e\x26\x08\x09\x22\x82\x98\x20\x21\x48\x09\x7e\x21\xc8\x08\x72\x02\x4e\x30\x27\xf8\xfc\xfb\xf1\xc5\xb7\xd9\x5c\xb9\xf2\x20\x0b\xd6\x47\x81\xff\x20\x0f\xf1\xfd\xf7\xa8\x4b\xc3\x42\x6e\xed\xef\xeb\xd7\xe7\xe2\x22\x29\x0f\x4a\x21\xdf\x92\xf0\x1d\xea\x32\xbe\xae\x40\x2e\x2f\xa1\x94\x83\x7b\x42\xc9\x7b\x6a\xd5\x0b\xad\xc6\xa6\x03\xca\x10\x97\x97\x68\xbc\x1d\x4a\x28\x35\x0a\xa4\xf6\x12\xa3\x5e\x60\xec\xeb\x4e\x09\xb2\x47\xe8\x42\xa9\x59\x28\x35\x97\x96\x1b\x85\xe5\xfa\xde\x18\xfb\x88\xb7\xf6\xe4\xb9\xbd\x8d\xe7\xd6\x41\x3c\xb7\x0f\xe2\xf9\x74\x1b\xcf\xed\x43\x78\x3e\xdb\xe4\xf9\xf4\x20\x9e\xcf\xf6\xe5\xb9\xd0\x92\x6b\x85\x5a\x53\x5a\x1a\x3f\x3b\x84\x6a\x81\xb2\x97\xfc\xbe\x8b\x5a\xde\xbe\xaa\x0f\x5b\xd6\xb2\xf2\x02\xdf\x3b\xe4\xd7\x49\x2d\x9a\xf6\xc5\x7e\x5d\xdb\x67\x65\xd7\x16\xdb\xff\xfa\xc4\x66\xf7\x2e\x1a\xfa\x9b\xdb\xb6\xcf\xbc\x72\x0b\x28\xba\xb5\xd2\x6c\x12\xfc\x7f\x3c\x76\x6f\x4c\x2f\xaa\xfd\xef\x3c\x64\x11\xa1\x4c\x20\x2b\x04\x72\x9d\x40\x6e\x10\xc8\x4d\x02\xb9\x45\x20\xb7\x09\xe4\x53\x02\xf9\x8c\x40\xa9\x11\x28\x62\x8f\x56\x08\x94\x3a\x81\xd2\x10\x28\x04\x4a\x8b\x40\x69\x13\x28\xa7\x04\xca\x19\x41\xbd\x46\x50\x97\xb7\xdb\xfa\xeb\xf1\xd7\xe3\x4f\xf8\x10\x9f\x87\x45\x97\x1b\x03\x18\x3f\x72\xe6\xae\x7f\x2e\x8e\xc6\x38\xff\x08\xf1\x7d\x7f\x7e\x7e\x75\xef\x50\xb5\x14\xce\x18\x9f\x67\x31\xc4\x61\xfa\x1e\x0f\x1b\xdb\x41\xb5\x3a\xf2\x99\xf7\x30\x65\x7c\x24\xd0\xaa\xa3\x31\x41\x4d\x5a\x6e\x76\x5f\xb1\x65\x5a\x96\x16\x5b\xaa\x68\xfc\xc2\xb9\x57\x01\xe5\x12\xb0\xf5\x02\xa0\x22\x2d\x77\xf6\xad\xd3\x75\x69\xf1\x45\xf1\x66\x7b\xf5\xd2\x5e\xe3\x05\xc0\x86\xb4\xd8\xe8\xde\x0c\xd8\x28\x01\xdb\x2f\x00\x36\xa5\xe5\x86\xbb\x75\xba\x25\x2d\xb6\xf5\x37\xdb\x6b\x95\xf6\x9a\x2f\x00\xb6\xa5\xe1\xdb\xb1\x4e\x77\xb3\x79\xb6\x37\x9b\x67\xbb\xd9\x94\x6b\xbb\xe9\x94\xe5\xbd\xf9\x94\xe5\xdd\x84\xca\xca\xde\x8c\xca\xca\x6e\x4a\xe5\xfa\x6e\x4e\xe5\xc6\xde\xa4\xca\x8d\xdd\xac\xca\xcd\x7d\x68\x95\x5b\xbb\x79\x95\xdb\xfb\x2f\xd3\xf6\x2b\xcc\x9e\xbe\xc2\xec\xd9\x33\x66\x57\x16\x0f\xf9\x36\xac\x8e\x5c\x0e\x47\xef\x53\xdb\x51\xfb\xb7\xce\x3f\xd1\xa1\xd7\xea\xa0\xe7\x40\x1b\x58\x16\x35\x9c\xd1\x6a\xee\xbf\x70\xd4\xe7\xe2\xda\x63\xd1\x59\xa3\xb5\xf7\x74\xed\xdd\x2b\xdf\xd7\xba\x2d\x17\xdd\x76\x12\x26\x49\x56\xa5\x9f\x8a\x3b\xbd\x2a\x4b\x13\x6f\x56\x5e\x6c\x8c\x5c\x2e\x49\x2b\xcc\xf3\x8f\x3b\x2e\x60\x16\x52\x69\x29\x35\x1a\xbb\xde\xcf\x2c\xf6\x47\xe9\xda\x9c\x27\xe6\x62\xf6\xeb\xcb\x97\x47\xe5\x35\xd1\x45\x65\xbd\xe5\x6f\xbb\x22\xd9\xba\x0d\x8c\x78\x51\x2c\x8d\x65\xb1\x2c\x47\xe4\xd6\xf3\x91\xd3\xc5\x00\x08\x46\x7c\x31\xf7\x0c\x2b\xfa\x46\x33\x7a\xae\x19\x6d\xd7\x4c\xd7\xe5\x08\x46\xe9\x76\x31\xef\x1b\x03\xde\x73\x03\xde\xf3\x12\x94\x0e\x39\x9f\xf0\x20\x7a\xf5\x74\xb2\x56\xa3\x6f\xae\xb7\xe5\x2d\xd5\x46\x39\x05\x3e\xce\x3f\xbe\x78\x3e\x92\x2e\xd6\x89\xe5\x49\xe1\x5b\xce\xdd\x28\x5d\x1c\xc7\x8a\xb5\xa9\x34\xa4\xf3\xf3\x2b\xfd\x46\x37\x9c\xe5\xa9\xb2\x3c\xa6\x2d\x2e\x0c\x9f\x2e\x4c\x4e\x57\xbf\x1b\x43\xe9\xa0\xe4\x2c\x2a\xf8\xb5\xfc\xe8\x86\xf3\x30\xfc\xe3\x32\xb3\x28\x6f\x11\x58\x73\x48\x84\xee\x43\x6b\xf1\xbf\x3d\x3c\xe8\x90\x2a\x96\xe1\xeb\x31\xfe\x71\x11\xae\xdd\x86\x3c\x91\x78\x76\x58\x81\x7b\xc9\x3c\xe6\x2c\xfb\xf3\xc5\x27\xd7\x9e\x97\xad\x2c\x3f\x0b\x59\x56\x36\x62\xfe\xcf\x00\xe6\x0a\xf8\x30\xf9\x1c\x00\x00")
 
 func _1_create_pgstream_xidUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -127,12 +131,12 @@ func _1_create_pgstream_xidUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "1_create_pgstream_xid.up.sql", size: 7417, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "1_create_pgstream_xid.up.sql", size: 7417, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __2_create_pgstream_schemalog_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\x2b\x4e\xce\x48\xcd\x4d\x8c\xcf\xc9\x4f\xb7\xe6\x02\x04\x00\x00\xff\xff\xea\x95\xfd\x5d\x2a\x00\x00\x00")
+var __2_create_pgstream_schemalog_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x2a\x00\xd5\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x73\x63\x68\x65\x6d\x61\x5f\x6c\x6f\x67\x3b\x0a\x03\x00\xea\x95\xfd\x5d\x2a\x00\x00\x00")
 
 func _2_create_pgstream_schemalog_tableDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -147,12 +151,12 @@ func _2_create_pgstream_schemalog_tableDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "2_create_pgstream_schemalog_table.down.sql", size: 42, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "2_create_pgstream_schemalog_table.down.sql", size: 42, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __2_create_pgstream_schemalog_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\x41\x6f\xe2\x30\x14\x84\xef\xf9\x15\x73\x83\x48\x61\xa5\xbd\x2e\xda\x83\x43\x5e\x76\xdd\x3a\x0e\x4d\x1c\x01\xa7\x10\x11\x97\x5a\x10\x4c\x49\xa8\xfa\xf3\x2b\x42\x80\x42\xa9\x7a\xf2\x61\xe6\xbd\x6f\xfc\x66\x94\x10\x53\x04\xc5\x7c\x41\xe0\x21\x64\xac\x40\x53\x9e\xaa\x14\xdb\x65\xdd\xec\x74\x51\xfd\xaa\x17\x2f\xba\x2a\xf2\xb5\x5d\xa2\xef\x00\x80\x29\x2f\xe2\xbb\x29\x31\x4e\x78\xc4\x92\x19\x1e\x69\x86\x80\x42\x96\x09\x75\x65\xe8\xbb\x5e\x3b\xf7\xa6\x77\xb5\xb1\x1b\xf8\xfc\x1f\x97\xaa\x65\xc9\x4c\x88\xa3\xd8\x51\x36\x45\xa5\xa1\x68\x7a\x5f\xc6\x43\x1a\x4b\xff\x46\x5a\xec\x74\xd1\xe8\x32\x2f\x1a\x28\x1e\x51\xaa\x58\x34\x3e\x5b\xce\x81\x64\x3c\x39\xe5\x28\x16\x2b\x5d\xc2\x8f\x63\x41\x4c\x7e\x75\x86\x4c\xa4\xe4\xb8\x43\xc7\x19\x0c\x50\xd9\xba\xc1\xda\xda\xd5\x7e\x5b\xb7\x2f\xd6\x66\xa5\xff\x1c\xa4\x79\x4a\x82\x46\x0a\xa6\xf4\x4e\xf1\xc2\x24\x8e\xee\x1e\x6e\xf2\x9f\x12\xba\xfa\xe3\x5f\xf4\x9e\xad\xed\x81\xc9\xa0\x8d\x70\x0c\x15\x27\x01\x25\xf0\x67\x87\x1b\x07\x94\x8e\x20\x78\xc4\x15\x7e\xcf\x9d\xae\x29\x2e\x03\x9a\xde\x34\x75\xe1\xb4\xab\xf3\x6e\x95\xbc\x5f\xe1\xa7\x10\xde\x91\xea\xc1\x94\xee\xf0\x44\xc8\x24\x7f\xca\x7e\x04\x75\x5d\xe6\xfb\x8d\x79\xfd\x06\x75\x4d\xea\x06\xdc\xa1\xf3\x11\x00\x00\xff\xff\x97\x55\xe8\xae\x74\x02\x00\x00")
+var __2_create_pgstream_schemalog_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\xd1\xaf\x9a\x30\x18\xc5\xdf\xf9\x2b\xce\x9b\x92\xe0\x92\xbd\xce\xec\xa1\xc8\xc7\xd6\xad\x14\x07\x25\xea\x13\x12\xe9\x5c\xa3\x50\x07\x68\xf6\xe7\x2f\x22\x6a\xf4\x7a\x73\x9f\xda\xe4\x9c\x7e\xbf\xd3\xef\xcc\x12\x62\x8a\xa0\x98\x2f\x08\x3c\x84\x8c\x15\x68\xc9\x53\x95\xe2\xb0\x6d\xbb\x46\x17\xd5\xa7\x76\xf3\x47\x57\x45\xbe\xb7\x5b\x8c\x1d\x00\x30\xe5\x5d\xfc\x67\x4a\xcc\x13\x1e\xb1\x64\x85\x9f\xb4\x42\x40\x21\xcb\x84\x7a\x30\x8c\x5d\xaf\x7f\x77\xd2\x4d\x6b\x6c\x0d\x9f\x7f\xe3\x52\xf5\x2c\x99\x09\x71\x11\x07\x4a\x5d\x54\x1a\x8a\x96\xaf\x65\xfc\x48\x63\xe9\x3f\x49\x9b\x46\x17\x9d\x2e\xf3\xa2\x83\xe2\x11\xa5\x8a\x45\xf3\x9b\xe5\x16\x48\xc6\x8b\x6b\x8e\x62\xb3\xd3\x25\xfc\x38\x16\xc4\xe4\x5b\x67\xc8\x44\x4a\x8e\x3b\x75\x9c\xc9\x04\x95\x6d\x3b\xec\xad\xdd\x1d\x0f\x6d\x7f\x62\x6f\x76\xfa\xcb\x59\x5a\xa7\x24\x68\xa6\x60\x4a\xef\x1a\x2f\x4c\xe2\xe8\xe5\xe2\x16\xdf\x29\xa1\xc1\x75\xf9\xe3\x57\x8c\x7e\x5b\x3b\x02\x93\x41\x1f\xe1\x12\x2a\x4e\x02\x4a\xe0\xaf\xce\x3b\x0e\x28\x9d\x41\xf0\x88\x2b\x7c\x5e\x3b\x43\x53\x5c\x06\xb4\x7c\x6a\xea\xce\xe9\x47\xe7\xc3\x28\xf9\x32\xc9\x78\xb8\x9f\x9d\x1e\x7a\xab\x07\x53\xba\xd3\x2b\x21\x93\xfc\x57\xf6\x21\x68\xe8\x32\x3f\xd6\xe6\xef\x3b\xa8\x47\xd2\x49\x37\xad\xb1\xb5\x3b\x75\xfe\x0f\x00\x97\x55\xe8\xae\x74\x02\x00\x00")
 
 func _2_create_pgstream_schemalog_tableUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -167,12 +171,12 @@ func _2_create_pgstream_schemalog_tableUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "2_create_pgstream_schemalog_table.up.sql", size: 628, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "2_create_pgstream_schemalog_table.up.sql", size: 628, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __3_create_pgstream_tableids_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\x4b\x2e\x4a\x4d\x2c\x49\x8d\x2f\x49\x4c\xca\x49\x8d\xcf\x4d\x2c\x28\xc8\xcc\x4b\xb7\xe6\x02\x6b\x0b\x71\x74\xf2\x71\xc5\xa6\x07\xa2\x38\x33\xa5\xd8\x9a\x0b\x10\x00\x00\xff\xff\x89\x55\x03\x71\x60\x00\x00\x00")
+var __3_create_pgstream_tableids_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x60\x00\x9f\xff\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x63\x72\x65\x61\x74\x65\x5f\x74\x61\x62\x6c\x65\x5f\x6d\x61\x70\x70\x69\x6e\x67\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x74\x61\x62\x6c\x65\x5f\x69\x64\x73\x3b\x0a\x03\x00\x89\x55\x03\x71\x60\x00\x00\x00")
 
 func _3_create_pgstream_tableids_tableDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -187,12 +191,12 @@ func _3_create_pgstream_tableids_tableDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "3_create_pgstream_tableids_table.down.sql", size: 96, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "3_create_pgstream_tableids_table.down.sql", size: 96, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __3_create_pgstream_tableids_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\xcd\x6e\xea\x30\x14\x84\xf7\x79\x8a\x59\x64\x01\x12\xdc\x17\x40\x77\x61\xb8\x87\xc8\xba\xae\x03\xfe\xa9\xca\x2a\x32\xd8\x0a\x96\x80\x44\x89\xa5\xf6\xf1\x2b\x4c\x01\x21\x75\x67\x9f\x39\x33\xf3\xd9\xf3\x39\x92\xdb\x9f\x42\x13\xfd\x88\x31\x75\x43\x18\x91\x8e\x01\x67\xd7\xf7\xf1\xd2\x62\x1f\xd2\x67\x08\x97\x3c\xeb\xdb\x31\x0d\xc1\x9d\x6f\x0e\x44\x0f\x77\xf1\x59\xd9\x74\x63\x6a\xb3\x35\x2b\x5d\xf4\xc5\x4a\x11\x33\x04\xc3\x96\x82\xc0\xd7\x90\xb5\x01\x7d\x70\x6d\xf4\x23\xe7\xcf\xb3\x79\x52\x00\xb8\x26\x3e\xb4\xaf\xe8\xb1\x51\xfc\x8d\xa9\x1d\xfe\xd3\x0e\xff\x68\xcd\xac\x30\x2f\x0b\x93\xe9\x2c\xfb\xba\xe8\xb1\xe4\x15\x97\x26\xd7\x48\x2b\x04\xac\xe4\x5b\x4b\xc5\x74\x51\xdc\x51\x6a\x05\x45\x1b\xc1\x56\x84\xb5\x95\x2b\xc3\x6b\xf9\x4c\x3b\x0c\xc1\xa5\xd0\xdc\x88\x7e\x1e\x3f\xb9\xdd\xae\xe9\x5d\xf4\x53\x28\x32\x56\x49\xfd\x82\x90\xfb\x05\x93\x95\x65\x15\x41\x6f\x45\x1e\x68\x32\x18\x83\x1b\x0e\xc7\xa6\x77\xe9\x88\xbf\xe8\xdb\xe6\xe0\x92\x3b\x75\xed\xac\x6f\x9b\x14\xce\x7d\x5e\x64\x1a\x65\x99\x4f\x5c\x6a\x52\x06\x5c\x9a\xfa\xd7\x0f\xca\x04\xef\x4c\x58\xd2\x78\x82\xdd\xa1\xb8\xac\x10\xfd\xa2\x28\xcb\x45\xf1\x1d\x00\x00\xff\xff\xe5\x65\xe2\xd8\xd3\x01\x00\x00")
+var __3_create_pgstream_tableids_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\xc1\x8e\x22\x21\x18\x84\xef\xfd\x14\x75\xe8\x83\x26\xba\x2f\x60\xf6\x80\xee\x6f\x87\x2c\x4b\x2b\x0d\x9b\xf1\xd4\x41\x21\x2d\x89\xda\xa4\x9b\x64\x7c\xfc\x89\x38\xea\x4c\x32\x37\xf8\xeb\xaf\xaa\x0f\xe6\x73\x24\xbb\x3f\xf9\x36\xb8\x11\x63\xea\x07\x3f\x22\x1d\x3d\xce\x36\xc6\x70\xe9\xb0\xf7\xe9\xdd\xfb\x4b\x9e\xc5\x6e\x4c\x83\xb7\xe7\xbb\x03\xc1\xc1\x5e\x5c\x56\x36\xfd\x98\xba\x6c\xcd\x4a\x1f\x5c\xb1\x52\xc4\x34\x41\xb3\xa5\x20\xf0\x35\x64\xad\x41\x6f\xbc\xd1\xcd\x33\xe7\xd7\xab\x79\x52\x00\xb8\x25\x3e\xb5\x6b\x70\xd8\x28\xfe\x8f\xa9\x1d\xfe\xd2\x0e\x7f\x68\xcd\x8c\xd0\xdf\x16\x26\xd3\x59\xf6\xf5\xc1\x61\xc9\x2b\x2e\x75\xae\x91\x46\x08\x18\xc9\xb7\x86\x8a\xe9\xa2\x78\xa0\xd4\x0a\x8a\x36\x82\xad\x08\x6b\x23\x57\x9a\xd7\xf2\x95\x76\x18\xbc\x4d\xbe\xbd\x13\x7d\x3e\x7e\x72\xbf\xdd\xd2\xfb\xe0\xa6\x50\xa4\x8d\x92\x5f\xf8\xaf\xc1\xe5\x7e\xc1\x64\x65\x58\x45\x68\xb6\x22\x0f\x1a\xd2\x18\xbd\x1d\x0e\xc7\x36\xda\x74\xc4\x6f\xc4\xae\x3d\xd8\x64\x4f\x7d\x37\x8b\x5d\x9b\xfc\x39\xe6\x45\xd6\xa0\x2c\xf3\x89\xcb\x86\x94\x06\x97\xba\xfe\xf1\x83\x32\xc1\x7f\x26\x0c\x35\x78\x81\x3d\xa0\xb8\xac\x10\xdc\xa2\x28\xcb\x45\xf1\x31\x00\xe5\x65\xe2\xd8\xd3\x01\x00\x00")
 
 func _3_create_pgstream_tableids_tableUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -207,12 +211,12 @@ func _3_create_pgstream_tableids_tableUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "3_create_pgstream_tableids_table.up.sql", size: 467, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "3_create_pgstream_tableids_table.up.sql", size: 467, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __4_create_pgstream_get_schema_functionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\x4b\x4f\x2d\x89\x2f\x4e\xce\x48\xcd\x4d\xb4\xe6\x02\x04\x00\x00\xff\xff\x99\x21\xb8\x23\x2d\x00\x00\x00")
+var __4_create_pgstream_get_schema_functionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x2d\x00\xd2\xff\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x67\x65\x74\x5f\x73\x63\x68\x65\x6d\x61\x3b\x0a\x03\x00\x99\x21\xb8\x23\x2d\x00\x00\x00")
 
 func _4_create_pgstream_get_schema_functionDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -227,12 +231,12 @@ func _4_create_pgstream_get_schema_functionDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "4_create_pgstream_get_schema_function.down.sql", size: 45, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "4_create_pgstream_get_schema_function.down.sql", size: 45, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __4_create_pgstream_get_schema_functionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x56\x4d\x6f\xdb\x38\x13\xbe\xfb\x57\xcc\x21\x85\x6c\xc0\x16\xd0\x6b\x8a\x14\xaf\xdf\xc4\x4d\xbd\xc8\x3a\x5d\xdb\x41\x5b\x14\x85\x42\x8b\x13\x89\x8d\x4c\x6a\x49\x2a\xad\xff\xfd\x82\xa4\x24\x4b\x22\xed\xcd\x56\x87\x38\x12\xe7\x99\x6f\x3e\x33\xb3\x19\xe8\x9c\x29\x78\xaa\x78\xaa\x99\xe0\xc0\x14\xa4\xa4\x28\x90\x02\x92\x34\x07\xcd\xf6\x08\x04\xd2\x9c\xf0\x0c\x41\x0b\x20\x90\xb1\x17\xe4\xa0\xd2\x1c\xf7\xc4\x88\xef\x09\xc5\x18\x96\x1a\x7e\xb2\xa2\x00\xa5\x85\x44\xd0\x39\x82\x44\x55\x15\x1a\xc4\x93\x7d\xab\xe5\x9d\xa2\xd1\x6c\x06\x3f\x73\x96\xe6\x0e\xa3\x73\xe4\xb0\x33\x88\xb2\x60\x29\xd1\x48\x63\xd8\xe6\x08\xa2\xd2\x65\xa5\x41\x69\x59\xa5\xba\x92\xe8\xac\x95\x25\x52\x60\xdc\x6a\x4d\x05\xc5\x1d\x51\x38\x85\xb2\x40\xa2\x10\x34\x79\x46\x48\x89\x91\x7d\x02\xa4\x4c\x33\x9e\xc5\xa3\xd9\xcc\x58\xfc\x8c\x90\x93\x17\xe7\xdc\x13\x93\xca\x68\xc6\x12\x1e\x7f\x32\x9d\x83\x26\xbb\x02\x13\xc1\xa8\x02\xa2\x60\x0c\x71\x1c\xc3\xe4\xd1\xd8\x11\x92\xa2\x34\xa1\x67\x92\xec\x60\x79\xa3\x40\xe7\x44\x3b\x55\xa4\x90\x48\xe8\x01\x76\x88\x1c\x32\xe4\x28\x8d\xf7\x53\x20\x9c\x1a\x8b\x8c\x2b\x94\x1a\x74\x2e\x8c\x6b\x06\x45\x05\x8f\x34\x1c\xb0\xc6\x2f\x6f\x94\x49\x5d\xa4\xcc\x01\x42\xc1\x9e\xd1\xd5\x43\x0b\xc8\xb1\x28\xc1\xfa\x56\xa2\x7c\x12\x72\x4f\x78\x8a\xf1\xe8\x7a\xbd\x98\x6f\x17\x70\xbf\x86\xf5\xe2\xd3\xdd\xfc\x7a\x01\x1f\x1e\x56\xd7\xdb\xe5\xfd\x0a\xca\x4c\x69\x89\x64\x1f\x67\xa8\x13\x97\xf0\xb1\xfb\x49\x38\xd9\x23\x6c\x17\x5f\xb6\x13\x58\x2f\xb6\x0f\xeb\xd5\x06\x7e\x28\xc1\x77\x23\x00\x80\xbb\xf9\xea\xf6\x61\x7e\xbb\x80\xcd\x5f\x77\xf6\xc3\x66\xb1\x05\x85\x44\xa6\x79\x52\x12\x9d\xc3\x15\x94\x59\x92\x12\x4d\x0a\x91\x4d\xcb\x2c\xd1\xb8\x2f\xad\xe0\x7c\x03\x17\x17\xa3\xcf\xcb\xed\xc7\x6e\x02\xe7\x1b\x18\xdb\x63\x7b\x80\xbf\x98\x32\x65\x18\x9c\x39\x3b\x77\x8b\xeb\x2d\xdc\x2c\x37\xdb\xe5\xea\x7a\xdb\x7e\x37\x4f\x99\x59\xa7\x55\x49\x52\x8c\xb9\x2a\x6d\x04\xf3\x0d\x74\x02\x9a\x0e\x01\x69\x41\x94\x8a\x25\x16\x8d\xb0\x73\xea\x8c\xac\x60\xf4\x28\x27\x18\x6d\xa5\x3e\xac\xef\xff\xec\xf9\xd0\xc3\xdb\x67\xbd\xbc\xfd\xb8\x85\x3f\xee\x97\xab\x56\x1f\xd8\x2a\x74\x1c\x37\xfa\xaf\x3c\xd7\xec\x19\xcc\x57\x37\xbd\x93\x67\xc6\x29\x2c\x57\x30\x8e\x64\x34\x85\xa8\x8c\x26\xad\xcd\xcf\x1f\x17\xeb\x45\x38\x25\x57\xdd\x8c\x58\xc0\x64\x74\xcc\x6d\xab\xa1\x57\x85\x38\x98\xc4\xbe\x48\x28\x75\x21\x09\xc1\xe8\x51\x20\x15\xa4\x40\x95\xe2\xb8\x6d\x45\x27\x64\xc4\x19\x9d\x1e\x3b\x34\x95\x48\x34\x26\xee\xd4\xdc\x69\xc6\xb3\xf1\x09\xf5\x93\xc9\xb1\x44\x65\xa6\x92\xba\x4a\xb6\x42\x3d\x48\xbf\x44\x77\x8b\x0f\x6d\x75\x86\xce\x98\x3a\x9d\xb0\x66\xcb\xe5\x39\x6f\x5a\x63\x52\xc7\x99\x8a\xa2\xda\xf3\x41\x2b\x0f\xf3\x6d\x9f\xe3\x9d\xe8\xa4\xf3\x5c\x5f\xfa\x88\x61\x83\xfe\x9b\xbc\x4b\xd0\x30\x61\x03\x94\x65\x13\x3d\x8e\xde\xa8\xd9\x1b\x15\x4d\x4f\x69\x31\xf5\x4a\x88\xd6\x92\xed\x2a\x8d\x31\xd1\x9a\x57\x7b\x5b\x0c\x97\x82\xb0\x72\x0f\x53\xc7\x5c\x63\x02\x41\x3b\x77\x12\x7d\x28\x4d\xe3\xf4\xd1\xfa\x50\x86\x1c\xd1\x87\x72\x2f\x68\xd7\x17\x83\xf6\x3d\x31\x4c\x88\xbf\x4a\xd9\xe8\xa5\xf8\x14\x13\xba\x63\xbc\x55\xe9\xbe\x48\x2c\x58\x4f\x1d\xc5\x27\x52\x15\x7a\xa0\x71\x75\xbf\x85\xb1\x1f\xa1\xd0\xbc\x2a\x0a\xc3\xc9\x86\x1c\x0f\x25\xc6\xfa\x50\x9a\x5f\xb8\x82\x88\x46\xcd\x4d\x6f\x4e\x6a\xf9\xae\x39\xf3\x6e\x32\x3f\xb0\x37\x5e\x7c\x59\x6e\xb6\xbd\x3e\xeb\xb5\x1b\xbc\xf5\x0e\x1a\xe2\x4a\x05\x57\x5a\x12\xc6\xb5\x27\xe2\xd8\x24\x15\xdc\x46\xed\xf8\xa9\x17\x90\xfd\xee\xc1\x4c\x14\xf3\xf5\x7a\xfe\xf5\x5b\xa0\x2d\x2e\x2f\x19\xd7\xdf\xe1\x7f\xef\x8d\xde\x67\x3c\xd8\xf7\x6f\xdf\x83\x4a\x52\xc1\x9b\xe4\x54\xd1\x40\x62\x62\xb2\xf8\xfb\x51\x33\x4e\xf1\x97\x77\x1a\xe2\xe8\x23\xff\x5f\xb5\xc0\xd8\xfe\x0d\x47\xef\x92\xc6\x38\xfd\xef\x49\x63\x9c\x32\x55\x71\xf6\x77\xe5\x4f\x91\x57\x26\xb5\xeb\xe1\xa9\xec\x4e\xba\x0d\xe5\xcc\xf9\x17\xa2\x9e\xe2\x71\x2a\x8a\x84\xa2\x4a\x25\x2b\xcd\xde\x37\x0e\x11\xcf\xf4\xd4\xf5\xdf\xa3\x26\x94\x68\x32\xf2\xf2\xdf\x8a\xfb\xe3\xd2\x55\xa1\xb3\x26\xb8\x3a\xf8\x39\x84\xab\x20\x0d\x9e\xd2\x58\x5f\xac\x90\x3a\x4b\x1e\xae\x54\xf6\xee\x85\xb5\x74\x07\x45\x43\x09\xe7\x9c\xf3\x89\xa3\xb9\xe0\xc3\x64\x0d\x85\x79\xb5\x1f\x0d\x1a\x2a\x84\x7a\x7f\x05\x6f\x61\x36\x83\x02\x95\x5d\x36\x39\xbc\x35\xab\xaf\x44\x85\xf2\x05\xa9\xe1\x4b\x50\x07\xa5\x71\x6f\xbe\x89\x4a\xa6\xd8\x9b\x7d\xc6\x1b\xc3\x54\x43\xdd\x4c\x51\x29\xec\xfa\x6c\xf6\x6f\xb3\x79\xef\x10\x1e\xb5\xac\xf0\xd1\xac\xcb\xae\x73\x8c\xa5\x1d\x32\x9e\x41\x2d\xec\x34\x37\xc3\x6f\x77\x70\x43\xfb\x15\xd3\xaf\x9e\x93\xc1\x4d\x22\x20\xe0\x0f\xb7\xfe\xf9\xab\x26\x9b\x5d\x6a\x13\x92\x65\x63\xf7\xdf\xae\x62\x05\x4d\xc4\xee\x07\xa6\xda\xa7\x13\xfb\x44\xcd\xb8\x4f\x18\x8d\xa6\xad\xd1\x73\x23\xae\x85\x9a\xa0\x7c\x4c\x20\xd4\x16\x61\xfa\xd0\x47\x04\x86\x57\x8b\xa8\x27\x91\x0f\x0a\x8f\xa8\xa3\x6f\xf5\x48\x09\xf8\x17\x1e\x36\x2d\xd2\x71\x87\x8f\x0b\x72\x4a\x8b\x6a\x48\xa1\x83\xf3\x79\xa2\x79\xba\x3b\x5d\x2d\x3d\x1c\x7d\xa7\xc8\xff\xfa\x7e\x7e\xb7\xd8\x5c\x2f\x6c\x85\x6d\xa9\x43\x1b\xc7\x64\x0a\xd1\xb7\xef\xd1\xe5\xa5\x91\x9a\x9c\x9f\x17\xd3\xf3\xcc\x65\x2f\x6a\x30\xe8\xce\x34\xf0\x7a\xd9\xdc\xc2\x20\xe8\x14\xad\xfc\xbe\x06\xc7\x34\x84\x1f\xc6\x83\x31\xe1\xc7\x0d\xdd\x91\x54\x4a\xb6\x27\xf2\xe0\x4d\xe0\xf9\x06\xea\xa3\xe4\x19\x0f\x4d\x7d\x06\x64\xef\x7d\xbd\x5d\xdf\x3f\x7c\x82\xff\x7f\xed\x2e\xb7\x9d\xbd\xd5\x5f\xe0\x5b\x4e\x21\x2a\x31\x55\x7a\x05\xa5\xbc\xfe\x52\x5b\x6b\x2a\x3a\xd1\xac\xbf\x41\x13\xad\x66\x61\x69\xa2\xe1\xc1\x93\xe4\xe5\xe1\xfa\x34\x33\xc0\x9f\xa5\x99\x56\x45\x4d\x37\x03\xec\x19\xba\x69\x91\x75\xb5\x7c\x70\xf8\xf2\xf9\xce\xfb\xfd\xd0\xd5\x75\xb6\x5d\x86\xcf\x24\xd0\x96\xb6\xe9\x5e\x06\x2d\xd6\xa8\xaf\xbb\x65\x54\x13\xc0\x8b\x3b\xad\xbb\xe6\xdd\xe8\xe2\xe2\xdd\xe8\x9f\x00\x00\x00\xff\xff\x6e\x90\xca\xb8\xc8\x12\x00\x00")
+var __4_create_pgstream_get_schema_functionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x56\x5f\x6f\xdb\x38\x0c\x7f\xcf\xa7\xe0\x43\x07\x27\x40\x62\x60\xaf\x1d\x32\x5c\xae\xf5\xba\x1c\x7a\xc9\x2e\x71\xb1\x0d\xc3\xe0\x2a\x16\x63\x6b\xb5\x25\x9f\x24\x77\xcb\xb7\x3f\x48\xfe\x93\xd8\x72\xb2\x5e\x23\x03\xad\x4d\xfe\x48\x8a\xa4\x7e\xd4\x6c\x06\x3a\x65\x0a\xf6\x25\x8f\x35\x13\x1c\x98\x82\x98\x64\x19\x52\x40\x12\xa7\xa0\x59\x8e\x40\x20\x4e\x09\x4f\x10\xb4\x00\x02\x09\x7b\x46\x0e\x2a\x4e\x31\x27\x46\x3d\x27\x14\x7d\x58\x6a\xf8\xc9\xb2\x0c\x94\x16\x12\x41\xa7\x08\x12\x55\x99\x69\x10\x7b\xfb\x56\xeb\x57\x86\x46\xb3\x19\xfc\x4c\x59\x9c\x56\x18\x9d\x22\x87\x9d\x41\x14\x19\x8b\x89\x46\xea\x43\x98\x22\x88\x52\x17\xa5\x06\xa5\x65\x19\xeb\x52\x62\xe5\xad\x28\x90\x02\xe3\xd6\x6a\x2c\x28\xee\x88\xc2\x29\x14\x19\x12\x85\xa0\xc9\x13\x42\x4c\x8c\xee\x1e\x90\x32\xcd\x78\xe2\x8f\x66\x33\xe3\xf1\x33\x42\x4a\x9e\xab\xe0\xf6\x4c\x2a\x63\x19\x0b\x78\xfc\xc9\x74\x0a\x9a\xec\x32\x8c\x04\xa3\x0a\x88\x82\x31\xf8\xbe\x0f\x93\x47\xe3\x47\x48\x8a\xd2\x6c\x3d\x91\x64\x07\xcb\x5b\x05\x3a\x25\xba\x32\x45\x32\x89\x84\x1e\x60\x87\xc8\x21\x41\x8e\xd2\x44\x3f\x05\xc2\xa9\xf1\xc8\xb8\x42\xa9\x41\xa7\xc2\x84\x66\x50\x54\x70\x4f\xc3\x01\x6b\xfc\xf2\x56\x99\xd4\x79\x0a\xa8\xe0\x08\x19\x7b\x32\x7a\x4c\x19\x77\x29\x66\x05\xd8\xd8\x0a\x94\x7b\x21\x73\xc2\x63\xf4\x47\x37\x9b\x60\x11\x06\xb0\xde\xc0\x26\xf8\x74\xbf\xb8\x09\xe0\xc3\xc3\xea\x26\x5c\xae\x57\x50\x24\x4a\x4b\x24\xb9\x9f\xa0\x8e\xaa\x84\x8f\xab\x3f\x11\x27\x39\x42\x18\x7c\x09\x27\xb0\x09\xc2\x87\xcd\x6a\x0b\x3f\x94\xe0\xbb\x11\x00\xc0\xfd\x62\x75\xf7\xb0\xb8\x0b\x60\xfb\xcf\xbd\xfd\xb0\x0d\x42\x50\x48\x64\x9c\x46\x05\xd1\x29\xcc\xa1\x48\xa2\x98\x68\x92\x89\x64\x5a\x24\x91\xc6\xbc\xb0\x8a\x8b\x2d\x5c\x5d\x8d\x3e\x2f\xc3\x8f\xa7\x09\x5c\x6c\x61\x6c\xc5\x56\x80\xbf\x98\x32\x65\xe8\xc9\xcc\xb3\x0d\xee\x83\x9b\x10\x6e\x97\xdb\x70\xb9\xba\x09\xdb\xef\xe6\x29\x12\x1b\xb4\x2a\x48\x8c\x3e\x57\x85\x79\x81\xc5\xb6\x6e\x3c\x2b\x9b\xf6\x01\x71\x46\x94\xf2\x25\x66\x8d\x72\x15\xd4\x05\x5d\xc1\xe8\x51\x4f\x30\xda\x6a\x7d\xd8\xac\xff\xee\xc4\xd0\xc1\xdb\x67\xb3\xbc\xfb\x18\xc2\x5f\xeb\xe5\xaa\xb5\x07\xeb\x55\x07\x64\xed\xcf\x9d\xd0\xac\x0c\x16\xab\xdb\x8e\xe4\x89\x71\x0a\xcb\x15\x8c\x3d\xe9\x4d\xc1\x2b\xbc\x49\xeb\xf3\xf3\xc7\x60\x13\x0c\xa7\x64\x7e\x9a\x11\x0b\x98\xd4\x35\x34\xb9\x6d\x2d\x74\xaa\xe0\x0f\x26\xb1\xab\x32\x94\xba\x21\x0d\xc1\xe8\x51\x21\x16\x24\x43\x15\xe3\xb8\x6d\xc5\x4a\xc9\xa8\x33\x3a\x3d\x76\x68\x2c\x91\x68\x8c\x2a\xa9\x39\xd3\x8c\x27\xe3\x33\xe6\x27\x93\x63\x89\x8a\x44\x45\x75\x95\x6c\x85\x3a\x90\x36\x0c\xfb\xdc\x07\x1f\xda\xea\xf4\x83\x31\x75\x3a\xe3\x0d\xe6\x03\xfa\xa6\x8e\xa3\x49\xbd\xcf\x58\x64\x65\xce\x7b\xad\xdc\xcf\xb7\x5d\xad\xd1\xc6\xfe\xef\xfa\xd2\x45\xf4\x1b\xf4\x77\xfa\x55\x82\x8e\x90\xea\xbd\x87\xb2\x6c\xa2\xc7\xde\x1b\x35\x7b\xa3\xbc\xe9\x39\x2b\xa6\x5e\x11\xd1\x5a\xb2\x5d\xa9\xd1\x27\x5a\xf3\x32\xb7\xc5\xa8\x52\x30\x6c\xdc\xc1\xd4\x7b\xae\x31\x03\x9b\xae\xc2\x89\xf4\xa1\xc0\x71\x1f\xad\x0f\xc5\x50\x20\xfa\x50\xe4\x82\x9e\xc6\x62\xd0\x6e\x24\x86\x09\xf1\x57\x21\x1b\xbb\x14\xf7\x3e\xa1\x3b\xc6\x5b\x93\xd5\x17\x89\x19\xeb\x98\xa3\xb8\x27\x65\xa6\x7b\x16\x57\xeb\x10\xc6\x4e\x30\x5c\x68\x5e\x66\x99\xe1\x64\x43\x8e\x87\x02\x7d\x7d\x28\x4c\x40\x30\x07\x8f\x7a\xcd\x49\x6f\x24\xb5\xfe\xa9\x3b\x83\x37\x45\xe8\xf9\x1b\x07\x5f\x96\xdb\xb0\xd3\x67\x5d\xea\x7c\xeb\x08\x1a\xe2\x8a\x05\x57\x5a\x12\xc6\xb5\xa3\x52\xb1\x49\x2c\xb8\xdd\x35\xcc\x9d\x0d\xd9\xef\x0e\xcc\xec\x62\xb1\xd9\x2c\xbe\x7e\x73\x12\x50\xe6\xd7\xd7\x8c\xeb\xef\xf0\xc7\x7b\x88\x05\x7f\xc2\x83\x7d\xff\xf6\x7d\xd0\x48\x2c\x78\x93\x9c\xd2\xeb\x69\x4c\x4c\x16\x5f\xbf\x6b\xc6\x29\xfe\x72\xa4\x43\x1c\x7d\xe4\xff\x79\x0b\xf4\x2d\x7c\x78\xf7\x55\xd2\x18\xa7\xff\x3f\x69\x8c\x53\xa6\x4a\xce\xfe\x2d\xf1\xb5\x49\x3d\x8d\xf0\x5c\x76\x27\xa7\x0d\x55\xb9\xeb\xb5\xd3\x71\x8a\xfb\xb1\xc8\x22\x8a\x2a\x96\xac\x30\xf7\xbe\xb1\x43\x01\x86\xd7\x07\x42\xb2\x3e\x72\xd4\x84\x12\x4d\x46\x4e\xfe\x5b\xf5\xae\xe3\x63\x15\x8e\x7e\xea\x3a\x74\x1c\x34\xb9\x1d\x8a\xe6\x9c\xc5\xfa\x60\x0d\x99\xb3\xe4\x51\xf5\xb7\x69\x38\x7f\xd8\xca\xe9\xa0\x68\x28\xe1\x52\x70\x2e\x71\x34\x07\xbc\xa3\xce\xcb\xbc\xaf\xcc\xcb\x7c\x04\xce\x4c\x77\x50\xef\xe7\xf0\x16\x66\x33\xc8\x50\xd9\xcb\x26\x87\xb7\xe6\xea\x2b\x51\xa1\x7c\x46\x0a\x7b\x21\x41\x1d\x94\xc6\xdc\x5c\xb2\x45\x29\x63\x54\xa7\xfb\x32\xd1\x18\xa6\xea\xdb\x66\x8a\x4a\x61\xaf\xcf\xe6\xfe\x6d\x6e\xde\x3b\x84\x47\x2d\x4b\x7c\x34\xd7\xe5\xaa\x73\x8c\xa7\x1d\x32\x9e\x40\xad\x5c\x59\x6e\x86\xdf\xee\x50\x0d\xed\x17\x4c\xbf\x7a\x4e\xfa\x67\x87\x5d\x57\xc1\x1d\x6e\x5d\xf9\x8b\x26\x9b\xbd\xd4\x46\x24\x49\xc6\xd5\x7f\xbb\x92\x65\x34\x12\xbb\x1f\x18\x6b\x97\x4e\xec\xf2\x9a\x71\x1f\x31\xea\x4d\x5b\xa7\x97\x46\x5c\xf3\xf3\xcc\x30\x73\x31\x03\x5b\x6d\x96\x67\xfa\xd0\x45\x0c\x0c\xaf\x66\x79\xf5\x24\x72\x41\xc3\x23\xaa\xf9\x79\xcd\x48\x71\x81\x67\x86\x4d\xb3\xbc\x8a\x3b\x5c\xdc\x20\xa7\x34\xcb\x6b\x48\xe1\x04\xe7\xf2\x44\xf3\x3b\xbd\xd3\xd5\xda\x3d\xbb\x67\xc9\xff\x66\xbd\xb8\x0f\xb6\x37\x81\xad\xb0\x2d\xb5\x73\x86\x48\x8e\x93\x29\x78\xdf\xbe\x7b\xd7\xd7\x46\x6b\x32\xea\x99\xea\xce\x8b\xee\xf5\xc2\xd1\xb5\x07\xd5\xf9\x6a\x9e\x93\x69\xe0\xf4\xb2\xe1\x84\x41\xd0\x39\x5a\x79\xbd\x85\x8a\x69\x08\x3f\x8c\x7b\x63\xc2\xdd\x77\x43\x0f\x76\x24\x15\x92\xe5\x44\x1e\x7a\x4a\xb6\x32\xb5\x28\x7a\xc2\x43\x53\x9f\xa3\x9a\x4d\x9e\xf3\xf5\x6e\xb3\x7e\xf8\x04\x7f\x7e\xad\xab\x6a\x0f\x41\xfd\xbf\x39\xda\xee\x05\xbe\xe5\x14\xa2\x22\x53\xa5\x17\x50\xca\xcb\x0f\xb5\xf5\xa6\xbc\xe9\x68\x48\xfa\x1a\x9a\x68\x7e\x9e\xb0\x34\xd1\xf0\xe0\xb1\x5c\xd3\xd1\x25\x58\x9f\x66\x7a\xf8\x8b\x34\xd3\xa7\x9b\x1e\xf6\x02\xdd\x34\xcb\xab\xab\xe5\x82\x6b\xc1\xef\xf0\x03\xfd\x70\x6a\xeb\x62\xbb\xf4\xd7\x64\xa0\x2d\x6d\xd3\x3d\x1f\xbf\xdb\x16\x6b\xcc\xd7\xdd\x32\xaa\x09\xe0\xb9\x3a\xbd\x75\xd7\xbc\x1b\x5d\x5d\xbd\x1b\xfd\x37\x00\x6e\x90\xca\xb8\xc8\x12\x00\x00")
 
 func _4_create_pgstream_get_schema_functionUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -247,12 +251,12 @@ func _4_create_pgstream_get_schema_functionUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "4_create_pgstream_get_schema_function.up.sql", size: 4808, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "4_create_pgstream_get_schema_function.up.sql", size: 4808, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __5_create_pgstream_log_schema_functionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\xcb\xc9\x4f\x8f\x2f\x4e\xce\x48\xcd\x4d\xb4\xe6\x22\xa8\x38\xb3\x38\xbe\xb8\xb2\xb8\x24\x35\x17\xae\x05\x10\x00\x00\xff\xff\x85\x0a\x26\x0e\x60\x00\x00\x00")
+var __5_create_pgstream_log_schema_functionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x60\x00\x9f\xff\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x6c\x6f\x67\x5f\x73\x63\x68\x65\x6d\x61\x3b\x0a\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x69\x73\x5f\x73\x79\x73\x74\x65\x6d\x5f\x73\x63\x68\x65\x6d\x61\x3b\x0a\x03\x00\x85\x0a\x26\x0e\x60\x00\x00\x00")
 
 func _5_create_pgstream_log_schema_functionDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -267,12 +271,12 @@ func _5_create_pgstream_log_schema_functionDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "5_create_pgstream_log_schema_function.down.sql", size: 96, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "5_create_pgstream_log_schema_function.down.sql", size: 96, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __5_create_pgstream_log_schema_functionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x58\x4d\x8f\xdb\x36\x10\xbd\xfb\x57\x0c\x8c\x05\x64\xa1\x5a\x03\x39\x15\xb0\xbb\x05\x1c\x9b\x4e\x04\x38\x72\x20\xcb\x4d\x73\xd2\xd2\xd2\x58\xe6\x86\x26\x15\x91\xde\x5d\xa3\xe8\x7f\x2f\x48\x51\xf2\xd7\xba\x69\xd3\x00\x39\x34\xa7\x48\x24\x35\xf3\xde\x9b\x99\x67\x66\xc7\x31\x19\x25\x04\xe6\x31\xc4\xe4\xfd\x6c\x34\x26\x30\x5d\x46\xe3\x24\x9c\x47\x50\x16\x4a\x57\x48\xb7\x7d\xa6\x52\xb5\x57\x1a\xb7\xa9\xca\x36\xb8\xa5\xbd\xfa\x9f\x54\xd0\x2d\x82\xc6\x67\xed\x43\x4c\x92\x65\x1c\x2d\x60\x25\x25\x47\x2a\x60\xb4\x80\x9b\x9b\xce\x6b\xf2\x26\x8c\x3a\x00\xe0\xf6\xe1\xf8\xc3\x30\x82\x9e\xd7\xe4\xf0\x02\xf0\xca\xa2\x92\x9c\x7b\xfe\xb0\x43\xa2\xc9\xb0\x73\x73\x03\xb3\x51\xf4\x66\x39\x7a\x43\xa0\xe4\x65\xa1\x3e\xf3\x61\xa7\xf3\x4f\xf0\x72\x59\x34\x48\x0f\xc8\xf0\x11\x85\x4e\x75\xc5\x8a\x02\x2b\x8b\xe9\x3c\xba\x5d\x5c\x90\xf1\x32\x0e\x93\x8f\x30\x21\xd3\x30\x22\xb1\x5b\x4c\x40\x21\xad\xb2\x4d\x5a\x52\xbd\x81\x3b\x28\x8b\x34\xa3\x9a\x72\x59\x04\x65\x91\x6a\xdc\x96\xf6\x60\x4d\x7b\x42\xc6\xb3\x51\x4c\xec\x4a\x85\x59\x2a\x57\x0f\x2c\x07\xc9\xf2\x21\xdc\xde\xc2\x4e\x61\x0e\x6b\x59\x41\x8e\x1c\x35\xaa\xf6\xd8\xb9\xaa\x43\xbb\xe3\x56\x1f\xb1\x52\x4c\x0a\x58\xb1\x82\x09\xb7\x75\x5e\x97\x46\xfd\xe1\x91\xf0\xb7\xb7\xa0\x3e\xb1\x12\xb8\x2c\x0a\x26\x0a\x08\xa7\x07\x9d\xcc\x46\xca\x65\x01\x4c\x81\x42\x6d\xcf\x87\x53\xe8\x1d\xc8\xf5\xb3\x5d\x55\x19\xdd\x14\x6a\xcd\x44\x71\x28\x58\xfb\xb1\xa9\x5c\x12\x2f\x89\xe7\xc3\x5d\xfb\x94\xbc\x25\x75\xfa\x43\xed\x6b\xc8\x24\x9a\x40\x38\x1d\x76\x1a\x6c\x73\x81\x20\x4b\xac\xa8\x36\xe4\xb6\x74\x0f\x99\x14\x9a\x32\xf3\x2c\xf6\x75\xd1\xd4\x10\x50\x95\x98\x31\xca\xf9\x1e\x98\x00\xbd\x41\xc8\xa8\x42\x78\xda\xa0\x7d\xab\xd0\x53\xa0\xe9\x8a\xa3\x02\xbd\xa1\x1a\x72\x2c\x51\xe4\x20\x05\x28\xfc\xbc\x43\x91\xa1\x0a\x80\x89\x9c\x65\xa8\x60\x14\x4d\x6c\x08\xce\x3e\x61\x1f\x3e\x60\x83\x45\x57\x7b\xbb\xb7\x42\x50\x5b\x5a\x69\x30\x81\xed\x4a\x51\xd1\x95\xfd\xa4\x42\x8e\x8f\x54\x68\x57\x15\xb0\xb5\x52\x12\x9e\x0c\x20\x61\x44\x06\x29\xf8\x1e\xa4\xc8\xb0\xdf\x92\x0c\xd7\x20\x05\x02\x3e\x63\xb6\xd3\xa8\x80\xc2\xbd\x6b\xe3\x67\xa3\x77\x34\x4f\x80\xfc\x1e\x2e\x92\x05\xec\x87\xf7\x2d\xbc\x0a\x95\xdc\x55\x19\x42\x2e\x51\x19\xda\x6b\x9a\x69\xc0\x67\xa6\x74\x60\x12\x3e\x31\xce\x41\x48\x0d\x15\x16\x4c\x69\xac\xe0\xa0\x58\x1f\x92\x0d\xc2\x7d\x38\x85\x7e\xbf\x6f\xab\x01\xfd\xfe\x7d\xdb\x10\x9a\x6a\xdc\x9a\x73\x40\xf3\x87\x9d\xd2\xb6\x1d\xf5\x86\x29\x2b\x6b\xbf\x69\x04\x5d\xa4\x9a\x16\xa6\xac\x93\x78\xfe\x1e\x16\xe3\xb7\xe4\xdd\xc8\xab\x01\x16\xa9\xcd\x64\x36\xd5\x67\x9e\xe6\x95\x2c\xbd\xd3\xb2\x2f\xc8\x8c\x8c\x13\x90\xab\x07\xcc\x74\x33\xf2\xc9\xfc\xa2\xd5\xa7\xf1\xfc\x9d\x99\xa7\x93\x01\xb5\x01\x4b\xcc\xd3\xfa\x73\xd5\xf3\xe1\xc3\x5b\x12\x93\x26\x9c\xde\x97\x68\x73\xdb\x40\x1e\xcc\xc2\x77\x61\x02\xaf\x5c\x63\xbd\x38\x1c\x83\xbb\xbf\x71\xb4\x33\x50\xfe\x51\xa0\x70\x7a\x01\x39\x5c\xd8\xa2\x45\xcb\xd9\xcc\xaa\x61\x5e\x2e\xf2\x9d\x88\x71\x24\xc8\x78\x3e\x9a\x91\xc5\x98\xf4\x7a\x6e\xc1\x8d\xf6\x4f\xaf\x6a\x29\xba\x0d\xca\x6e\xbf\xeb\x92\x72\x59\x74\x1d\xff\x63\x18\x77\x17\xc0\xe6\xf1\x84\xc4\xf0\xfa\x63\x13\x13\x26\x64\x31\x6e\xc4\xf1\x03\x78\xe5\xd7\x35\x38\x35\x95\x23\xb2\xae\x43\x28\x38\xfd\x9b\x46\x37\x9d\x8d\xc2\x4c\x88\x69\x12\x29\x94\xa6\x42\x07\xb0\xa1\x0a\x84\x6c\x46\xcf\x48\x61\x56\x4c\xf7\x36\xdf\xaf\x39\x2d\x8c\xb9\x80\x96\xa0\xab\x9d\x6b\xae\x56\xdb\x68\x41\xe2\xa4\xc6\x74\x8d\x77\xcf\xa1\x0c\x8e\xb9\x37\x2f\x3e\xfc\x36\x9a\x2d\xc9\x02\x7a\xa7\x94\x82\x73\x69\x02\xf0\xfe\xe8\xd6\x38\xbb\x03\x10\x3b\xce\x03\xe8\x3a\x90\xdd\x81\x85\xf6\xa7\x37\x18\x3c\x28\x29\x56\xfe\xa5\x20\x15\x6e\xe5\x23\x02\xe5\xbc\x95\x82\xa1\x02\xb9\x06\xe7\x90\x0e\x4f\x00\x4f\x4c\x6f\xac\x02\xf8\x9c\x61\x69\x6d\x4d\xae\xc1\x73\xa9\xbc\x5a\xc5\x93\xf0\x13\x32\x23\x09\xf9\xef\xc5\x37\xf2\xf7\x9c\x0e\xb7\xbf\xb6\x19\xfd\xc1\xc0\xfc\x2e\xd8\xa6\x35\x0d\x3b\x8f\x6d\xbf\x5e\x3d\x78\x9d\xbd\xe4\x79\x5b\xd7\x46\x01\x67\xc7\x56\x59\x73\x00\x8d\x91\x50\x01\x3f\x43\x4e\xf7\xea\x2b\x79\x5e\x27\x61\x38\x66\x15\x52\x8d\x79\x4a\x35\xfc\x02\x42\x3e\xf5\x7c\xb8\x05\x26\x34\x56\x8f\x94\x83\x57\x67\xf6\x86\x6d\xee\xe6\x37\xc7\x3c\x23\x57\x6c\x7d\x6e\x6c\xc9\xe8\xf5\x8c\x7c\x85\xaf\xb1\x3c\x38\xbb\xd1\x38\x7b\x73\x7b\xdf\xd4\xe9\xac\xc2\x2f\x19\x9d\xf3\xa7\xfa\x92\x71\xec\x4c\x17\x06\x74\xad\x00\x36\x74\xca\x72\xd5\xe8\x2f\x59\xee\xfa\xcb\x46\xbd\x94\xf2\x87\xcb\x7e\x8d\xcb\x7e\x2f\xe7\x6b\x0b\x52\xa0\xbe\x56\x0a\xff\x0b\xf3\xd2\x4e\x45\x9e\xf3\x34\x93\xdb\x2d\x15\x79\x8a\x22\x3f\x1b\x8e\x93\x5b\x83\xbb\xe4\x34\xf7\x86\x6b\xa5\x72\x8d\xce\x72\x14\x9a\xe9\xfd\xbf\xba\x25\x1c\xc0\x7c\xf1\x8a\x60\xad\xc3\x01\x7f\x19\x60\x3b\x5b\x0d\xc0\x73\xb7\x70\xe7\x9d\x5f\x5c\xe3\xf3\xa2\x25\x7c\x1b\x2e\xce\x04\xae\x53\x71\xd0\xbe\xc8\x64\x34\x4b\x48\xfc\xbd\x88\xd8\xff\xf9\xd5\x54\x02\xc7\x09\x32\xc9\x77\x5b\xe1\xf9\x2f\x71\x3b\x01\x7b\x41\xed\x87\x27\xfd\xcf\x3c\xa9\x79\x76\x7f\x25\x18\x76\xfe\x0a\x00\x00\xff\xff\x48\x7f\x99\x33\xc4\x10\x00\x00")
+var __5_create_pgstream_log_schema_functionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x57\x5d\x8f\xda\x38\x14\x7d\xe7\x57\x5c\xa1\x91\x42\xb4\x4c\xa4\x3e\xad\x04\x3b\x2b\x51\x30\x6d\x24\x1a\xaa\x10\xb6\xdb\xa7\x8c\x49\x2e\xc1\x53\x63\xa7\xb6\x99\x19\xb4\xda\xff\xbe\xb2\x71\xf8\x1c\xd4\x6e\xb7\x52\x1f\xb6\x4f\x93\xf8\xe3\xde\x73\xce\xbd\xf7\x90\x19\xa6\x64\x90\x11\x98\xa6\x90\x92\xf7\x93\xc1\x90\xc0\x78\x9e\x0c\xb3\x78\x9a\x40\x5d\x69\xa3\x90\xae\x23\xa6\x73\xbd\xd5\x06\xd7\xb9\x2e\x56\xb8\xa6\x9d\xdd\x9f\x5c\xd0\x35\x82\xc1\x67\x13\x42\x4a\xb2\x79\x9a\xcc\x60\x21\x25\x47\x2a\x60\x30\x83\x9b\x9b\xd6\x6b\xf2\x26\x4e\x5a\x00\xe0\xf7\xe1\xf8\x62\x9c\x40\x27\x68\x72\x04\x5d\x08\xea\x4a\x49\xce\x83\xb0\xdf\x22\xc9\xa8\xdf\xba\xb9\x81\xc9\x20\x79\x33\x1f\xbc\x21\x50\xf3\xba\xd2\x9f\x79\xbf\xd5\xfa\x1a\xbc\x5c\x56\x0d\xd2\x03\x32\x7c\x44\x61\x72\xa3\x58\x55\xa1\x72\x98\xce\xa3\xbb\xc5\x19\x19\xce\xd3\x38\xfb\x08\x23\x32\x8e\x13\x92\xfa\xc5\x0c\x34\x52\x55\xac\xf2\x9a\x9a\x15\xdc\x41\x5d\xe5\x05\x35\x94\xcb\xaa\x5b\x57\xb9\xc1\x75\xed\x0e\xee\x68\x8f\xc8\x70\x32\x48\x89\x5b\x51\x58\xe4\x72\xf1\xc0\x4a\x90\xac\xec\xc3\xed\x2d\x6c\x34\x96\xb0\x94\x0a\x4a\xe4\x68\x50\xef\x8f\x9d\xab\xda\x77\x3b\x7e\xf5\x11\x95\x66\x52\xc0\x82\x55\x4c\xf8\xad\xf3\xba\x34\xea\xf7\x8f\x84\xbf\xbd\x05\xfd\x89\xd5\xc0\x65\x55\x31\x51\x41\x3c\x3e\xe8\x64\x37\x72\x2e\x2b\x60\x1a\x34\x1a\x77\x3e\x1e\x43\xe7\x40\x2e\x2a\x36\x4a\x59\xdd\x34\x1a\xc3\x44\xd5\x09\x2e\x2e\xdb\xca\x65\xe9\x9c\x04\x21\xdc\xed\x9f\xb2\xb7\x64\x57\xf7\x43\xed\x77\x90\x49\x32\x82\x78\xdc\x6f\x35\xd8\xa6\x02\x41\xd6\xa8\xa8\xb1\xe4\xd6\x74\x0b\x85\x14\x86\x32\xfb\x2c\xb6\xbb\xa2\xe9\x3e\xa0\xae\xb1\x60\x94\xf3\x2d\x30\x01\x66\x85\x50\x50\x8d\xf0\xb4\x42\xf7\xa6\x30\xd0\x60\xe8\x82\xa3\x06\xb3\xa2\x06\x4a\xac\x51\x94\x20\x05\x68\xfc\xbc\x41\x51\xa0\xee\x02\x13\x25\x2b\x50\xc3\x20\x19\xd9\x4b\xc0\xd9\x27\x8c\xe0\x03\x36\x58\x8c\xda\xba\xbd\x05\x82\x5e\x53\x65\x60\x85\x0a\xdd\x4a\xa5\xe8\xc2\x5d\x51\xc8\xf1\x91\x0a\xe3\x1b\x19\xdc\x04\x68\x09\x4f\x16\x90\xb0\x22\x83\x14\x7c\x0b\x52\x14\x18\xed\x49\xc6\x4b\x90\x02\x01\x9f\xb1\xd8\x18\xd4\x40\xe1\xde\xb7\xf1\xb3\xad\x47\x32\xcd\x80\xfc\x19\xcf\xb2\x19\x6c\xfb\xf7\x7b\x78\x0a\xb5\xdc\xa8\x02\xa1\x94\xa8\x2d\xed\x25\x2d\x0c\xe0\x33\xd3\xa6\x6b\x13\x3e\x31\xce\x41\x48\x03\x0a\x2b\xa6\x0d\x2a\x38\x28\x16\x41\xb6\x42\xb8\x8f\xc7\x10\x45\x91\xab\x06\x44\xd1\x7d\x83\x47\x1b\x6a\x70\x6d\xcf\x01\x2d\x1f\x36\xda\xb8\x76\x34\x2b\xa6\x9d\xac\x51\xd3\x08\xa6\xca\x0d\xad\x6c\x59\x47\xe9\xf4\x3d\xcc\x86\x6f\xc9\xbb\x41\xb0\x03\x58\xe5\x2e\x93\xdd\xd4\x9f\x79\x5e\x2a\x59\x07\xa7\x65\x9f\x91\x09\x19\x66\x20\x17\x0f\x58\x98\x66\xe4\xb3\xe9\x45\xab\x8f\xd3\xe9\x3b\x3b\x4f\x27\x03\xea\x02\xd6\x58\xda\xd9\xc1\xc2\xe8\x4e\x08\x1f\xde\x92\x94\x34\xe1\xcc\xb6\x46\x97\xdb\x05\x0a\x60\x12\xbf\x8b\x33\x78\xe5\x1b\xeb\xc5\xe1\xe8\xdd\x1d\x3a\xff\x7c\xb3\x73\x06\x2a\x3c\x0a\x14\x8f\x2f\x20\xc7\x33\x57\xb4\x64\x3e\x99\x38\x35\xec\xcb\x45\xbe\x13\x31\x8e\x04\x19\x4e\x07\x13\x32\x1b\x92\x4e\xc7\x2f\xf8\xd1\xfe\xe5\xd5\x4e\x8a\x76\x83\xb2\x1d\xb5\x7d\x52\x2e\xab\xb6\xe7\x7f\x0c\xe3\xee\x02\xd8\x34\x1d\x91\x14\x5e\x7f\x84\xc6\x2e\x46\x64\x36\x6c\xc4\x09\xbb\xf0\x2a\xdc\xd5\xc0\xdf\xf1\xa7\x8e\xc8\xfa\x0e\xa1\xe0\xf5\x6f\x1a\xdd\x76\x36\x0a\x3b\x21\xb6\x49\xa4\xd0\x86\x0a\xd3\x85\x15\xd5\x20\x64\x33\x7a\x56\x0a\xbb\x62\x27\xa5\xb9\xbf\xe4\xb4\xb2\xe6\x02\x46\x82\x51\x1b\x8c\x4e\x52\xc5\xc9\x8c\xa4\xd9\x0e\xd3\x35\xde\x1d\x8f\xb2\xeb\xb1\x38\xee\xcd\x4b\x08\x7f\x0c\x26\x73\x32\x83\xce\x29\xa5\xee\xb9\x34\x5d\x08\xfe\x6a\xef\x70\xb6\x7b\x20\x36\x9c\x77\xa1\xed\x41\xb6\x7b\x0e\xda\xdf\x41\xaf\xf7\xa0\xa5\x58\x84\x97\x82\x28\x5c\xcb\x47\x04\xca\xf9\x5e\x0a\x86\x1a\xe4\x12\xbc\x43\x7a\x3c\x5d\x78\x62\x66\xe5\xe6\x17\x9f\x0b\xac\x9d\xad\xc9\x25\x04\x3e\x55\xe0\xae\x6e\x4f\xc2\x8f\xc8\x84\x64\xe4\xbf\x17\xdf\xca\xdf\xf1\x3a\xdc\xfe\xbe\xcf\x18\xf6\x7a\xf6\x77\xc1\x35\xad\x6d\xd8\x69\xea\x9a\xf7\xea\xc1\xeb\xec\x25\x2f\xf7\x75\x6d\x14\xf0\x76\xec\x94\x05\xc9\x4b\xb4\x46\x42\x05\xfc\x0a\x25\xdd\xea\x6f\xe4\x79\x9d\x84\xe5\x58\x28\xa4\x06\xcb\x9c\x1a\xf8\x0d\x84\x7c\xea\x84\x70\x0b\x4c\x18\x54\x8f\x94\x43\xb0\xcb\x1c\xf4\xf7\xb9\x9b\xdf\x1c\xfb\x8c\x5c\xb3\xe5\xb9\xb1\x65\x83\xd7\x13\xf2\x0d\xbe\xc6\xca\x93\x9e\x3c\xd8\x9b\xdf\xfb\xae\x4e\xe7\x14\x7e\xc9\xe8\xbc\x3f\xb9\x9c\x27\xce\x74\x61\x40\xd7\x0a\xe0\x42\xe7\xac\xd4\x8d\xfe\x92\x95\xbe\xbf\x5c\xd4\x4b\x29\xf7\x0b\x3f\x5d\xf6\x5f\xb8\xec\x8f\x72\xbe\x26\x7c\x54\xa1\xb9\x56\x8a\xf0\x0b\xf3\xb2\x9f\x8a\xb2\xe4\x79\x21\xd7\x6b\x2a\xca\x1c\x45\x79\x36\x1c\x27\x5f\x0d\xfe\x23\xa7\xf9\x6e\xb8\x56\x2a\xdf\xe8\xac\x44\x61\x98\xd9\x1e\xc6\xe8\x2b\x66\xe7\x00\xe6\x8b\x9f\x08\xb6\x85\x1a\xe0\x2f\x03\xdc\xcf\x56\x03\xf0\xdc\x2d\xfc\x79\xef\x17\xd7\xf8\xbc\x68\x09\xdf\x87\x8b\x37\x81\xeb\x54\x3c\xb4\x2f\x32\x19\x4c\x32\x92\xfe\x28\x22\xee\x3f\xbf\x1d\x95\xae\xe7\x04\x85\xe4\x9b\xb5\x08\xc2\x97\xb8\x9d\x80\xbd\xa0\xf6\xd3\x93\xfe\x67\x9e\xd4\x3c\x93\x64\xd4\x6f\xdd\xdc\xf4\x5b\xff\x0c\x00\x48\x7f\x99\x33\xc4\x10\x00\x00")
 
 func _5_create_pgstream_log_schema_functionUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -287,12 +291,12 @@ func _5_create_pgstream_log_schema_functionUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "5_create_pgstream_log_schema_function.up.sql", size: 4292, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "5_create_pgstream_log_schema_function.up.sql", size: 4292, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __6_create_pgstream_refresh_schema_functionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0b\xf5\x73\x0e\xf1\xf4\xf7\x53\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\xd5\x2b\x4a\x4d\x2b\x4a\x2d\xce\x88\x2f\x4e\xce\x48\xcd\x4d\xe4\x02\x04\x00\x00\xff\xff\xd8\xa3\x85\xae\x30\x00\x00\x00")
+var __6_create_pgstream_refresh_schema_functionDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x30\x00\xcf\xff\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x70\x67\x73\x74\x72\x65\x61\x6d\x2e\x72\x65\x66\x72\x65\x73\x68\x5f\x73\x63\x68\x65\x6d\x61\x0a\x03\x00\xd8\xa3\x85\xae\x30\x00\x00\x00")
 
 func _6_create_pgstream_refresh_schema_functionDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -307,12 +311,12 @@ func _6_create_pgstream_refresh_schema_functionDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "6_create_pgstream_refresh_schema_function.down.sql", size: 48, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "6_create_pgstream_refresh_schema_function.down.sql", size: 48, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __6_create_pgstream_refresh_schema_functionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x92\x4f\x8f\xd3\x30\x10\xc5\xef\xf9\x14\x4f\x55\x0f\x0d\x54\x2b\x71\x25\xea\x21\xa4\x93\xae\xa5\xd4\x41\xb6\x83\xb4\x27\xcb\xdb\x35\x49\x44\xfe\x11\x5b\x0b\x7c\x7b\xd4\xac\xcb\xc2\x96\x9e\xa2\x99\x79\x33\xf3\x7b\x13\x67\x82\x52\x45\x28\x05\x04\x7d\x2e\xd2\x8c\x90\x57\x3c\x53\xac\xe4\x98\x6a\xe7\x67\x6b\xfa\xbb\xd9\x7e\x9d\xad\x6b\xb4\x3b\x35\xb6\x37\x9b\x97\x8f\xf6\xa3\x0e\x05\x78\xfb\xd3\xc7\x10\xa4\x2a\xc1\x25\x9e\xc7\xf6\x29\x02\x80\x22\xe5\x87\x2a\x3d\x10\xa6\x6e\xaa\xdd\xf7\x6e\x49\x4a\xca\x2a\xc1\xd4\x03\xf6\x94\x33\x4e\x22\x24\x15\x9c\x35\xf3\xa9\xd1\x93\xf1\x0d\x76\x98\x6a\x7d\x32\xde\x74\x63\xbd\x9d\x6a\xed\x6d\x3f\x2d\xc2\x54\x62\xbd\x8e\xf6\x94\x15\xa9\xa0\x25\x13\x68\x9e\xed\xec\xda\x71\xc0\x63\x5b\xb7\x83\x4f\x96\x52\xeb\xb4\xfb\xe5\xbc\xed\x03\x39\x1e\xc7\xb1\xb3\x66\x48\xa2\x4f\x74\x60\x3c\xfa\x8f\xe2\xe3\xee\xd5\xf6\xdb\xe2\xb5\xf1\x38\x89\x22\x80\xe5\xb8\x3e\x09\x93\xe0\xa5\x02\xaf\x8a\x02\x66\x78\x5a\x02\x26\xb5\x7c\x90\x8a\x8e\x5a\x66\xf7\x74\x4c\xa1\xee\x89\x07\xff\x05\x65\x0a\x59\x59\x71\xb5\x79\x17\xbf\xff\x00\xc6\x55\xf9\xc6\xdb\xa2\x04\x72\x51\x1e\xb1\xba\x50\xae\xee\x56\x41\xd5\x8d\xf5\x2a\x48\x7e\x34\x76\xb6\x97\xee\xc1\xf4\x16\xbb\x6b\xc4\x85\x1d\x60\x5c\x92\x50\x2f\xfb\x6e\x4c\xc5\x26\x10\x6c\xff\x9e\x79\x09\xe2\xb0\xf4\x4b\x5a\x54\x24\xb1\xf9\x17\x7a\x7b\xbd\x78\xfb\x7a\xe2\xda\xfa\xdb\xc7\x8d\x03\xa1\xa0\xf3\xf3\xf8\xd3\xe3\xbe\xb5\xd3\x19\xeb\xfc\x8f\x89\xef\xc1\xf2\x24\x22\xbe\x4f\xa2\xf5\x3a\x89\x7e\x07\x00\x00\xff\xff\x22\x78\x5d\x70\xcf\x02\x00\x00")
+var __6_create_pgstream_refresh_schema_functionUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\xcd\x6e\xdb\x30\x10\x84\xef\x7a\x8a\x81\xe1\x83\xd5\x1a\x01\x7a\xad\xe0\x83\x2a\xaf\x1c\x02\x32\x55\x90\x54\x81\x9c\x08\xc6\x61\x25\xa1\xfa\xab\x48\xa4\xed\xdb\x17\x51\x68\xb8\x8d\xe3\x93\xc0\x9d\xd9\xdd\x6f\x56\x99\xa0\x54\x11\x4a\x01\x41\x5f\x8b\x34\x23\xe4\x15\xcf\x14\x2b\x39\xa6\xda\xf9\xd9\x9a\xfe\x6e\xb6\xdf\x67\xeb\x1a\xed\x4e\x8d\xed\xcd\xe6\xf5\xa3\xfd\xa8\x83\x00\x6f\x7f\xfb\x18\x82\x54\x25\xb8\xc4\xf3\xd8\x3e\x45\x00\x50\xa4\xfc\x50\xa5\x07\xc2\xd4\x4d\xb5\xfb\xd9\x2d\x45\x49\x59\x25\x98\x7a\xc0\x9e\x72\xc6\x49\x84\xa2\x82\xb3\x66\x3e\x35\x7a\x32\xbe\xc1\x0e\x53\xad\x4f\xc6\x9b\x6e\xac\xb7\x53\xad\xbd\xed\xa7\xc5\x98\x4a\xac\xd7\xd1\x9e\xb2\x22\x15\xb4\x54\x02\xcd\xb3\x9d\x5d\x3b\x0e\x78\x6c\xeb\x76\xf0\xc9\x22\xb5\x4e\xbb\x3f\xce\xdb\x3e\x90\xe3\x71\x1c\x3b\x6b\x86\x24\xfa\x42\x07\xc6\xa3\x77\x1c\x9f\x77\x97\xd8\x6f\xc5\xeb\xe0\x71\x12\x45\x00\xcb\x71\xa5\x80\x49\xf0\x52\x81\x57\x45\x01\x33\x3c\x2d\x0f\x26\xb5\x7c\x90\x8a\x8e\x5a\x66\xf7\x74\x4c\xa1\xee\x89\x87\xfc\x05\x65\x0a\x59\x59\x71\xb5\xf9\x10\x7f\xfc\x04\xc6\x55\xf9\x26\xdb\xe2\x04\x72\x51\x1e\xb1\x3a\x53\xae\xee\x56\xc1\xd5\x8d\xf5\x2a\x58\x7e\x35\x76\xb6\xe7\xee\xc1\xf4\x16\xbb\x6b\xc4\x85\x1d\x60\x5c\x92\x50\xaf\xfb\x6e\x4c\xc5\x26\x10\x6c\xff\x9d\x79\x7e\xc4\x61\xe9\xb7\xb4\xa8\x48\x62\xf3\x3f\xf4\xf6\x7a\xf1\xf6\x72\xe2\xda\xfa\xdb\xc7\x8d\x03\xa1\x20\x49\xea\xd2\xe3\x7e\xb4\xd3\x0b\xd6\xcb\x3f\x26\xbe\x07\xcb\x93\x88\xf8\x3e\x89\xd6\xeb\x24\xfa\x3b\x00\x22\x78\x5d\x70\xcf\x02\x00\x00")
 
 func _6_create_pgstream_refresh_schema_functionUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -327,12 +331,12 @@ func _6_create_pgstream_refresh_schema_functionUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "6_create_pgstream_refresh_schema_function.up.sql", size: 719, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "6_create_pgstream_refresh_schema_function.up.sql", size: 719, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __7_create_pgstream_event_triggersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0d\x73\xf5\x0b\x51\x08\x09\xf2\x74\x77\x77\x0d\x52\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\x8d\xcf\xc9\x4f\x8f\x2f\x4e\xce\x48\xcd\x4d\x8c\x4f\x2e\x4a\x4d\x2c\x49\x8d\x4f\xcc\x29\x49\x2d\x8a\x2f\x49\x4c\xca\x49\xb5\xe6\x22\xd9\x90\x94\xa2\xfc\x02\x18\x1b\x6a\x06\x20\x00\x00\xff\xff\xa4\x32\xde\xb7\x89\x00\x00\x00")
+var __7_create_pgstream_event_triggersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x70\x0d\x73\xf5\x0b\x51\x08\x09\xf2\x74\x77\x77\x0d\x52\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x2f\x2e\x29\x4a\x4d\xcc\x8d\xcf\xc9\x4f\x8f\x2f\x4e\xce\x48\xcd\x4d\x8c\x4f\x2e\x4a\x4d\x2c\x49\x8d\x4f\xcc\x29\x49\x2d\x8a\x2f\x49\x4c\xca\x49\xb5\xe6\x22\xd9\x90\x94\xa2\xfc\x02\x18\xbb\x24\x31\x29\x27\xd5\x9a\x0b\x30\x00\xa4\x32\xde\xb7\x89\x00\x00\x00")
 
 func _7_create_pgstream_event_triggersDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -347,12 +351,12 @@ func _7_create_pgstream_event_triggersDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "7_create_pgstream_event_triggers.down.sql", size: 137, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "7_create_pgstream_event_triggers.down.sql", size: 137, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __7_create_pgstream_event_triggersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8e\xc1\x0e\x82\x30\x10\x44\xef\x7e\xc5\xde\x80\xc4\xf8\x03\x9e\xb0\xae\x40\xa2\xc5\xd4\xa2\xde\x9a\x95\x6e\xf0\xd0\x02\x96\xfe\x7f\x4c\x34\xe2\x95\xdb\x4c\x26\x79\x6f\x84\xc2\x5c\x23\xe0\x15\xa5\x06\xad\xaa\xa2\x40\x05\x63\x37\xc5\xc0\xe4\x8d\x1b\x3a\x33\xb5\x4f\xf6\x64\xda\xc0\x14\xd9\x90\x8b\x1c\x4c\xa4\x87\x63\xa8\x25\x58\xeb\x4c\x3b\x78\x4f\xbd\x35\xdc\x5b\xc0\x3b\x8a\x46\x23\x1c\x1a\x29\x74\x55\xcb\x19\xb5\xf9\xa3\xd2\x6c\xbb\x5a\xac\xb5\x61\x18\x7f\x79\xb6\x4e\x2f\xf7\x19\xe0\x56\xa2\x84\x48\x1d\x54\x12\xd2\x64\xaf\xea\x33\xe8\x7c\x77\xc4\x64\x0d\xdf\x76\x11\x25\x9e\xf2\x24\x5b\x7c\xec\x1d\x00\x00\xff\xff\xc9\xeb\xd8\xd5\x10\x01\x00\x00")
+var __7_create_pgstream_event_triggersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8e\xc1\x0e\x82\x30\x10\x44\xef\x7e\xc5\xde\x80\xc4\xf8\x03\x9e\xb0\xae\x40\xa2\xc5\xd4\xa2\xde\x9a\x95\x6e\xf0\xd0\x02\x96\xfe\x7f\x4c\x34\xe2\x95\xdb\x4c\x26\x79\x6f\x84\xc2\x5c\x23\xe0\x15\xa5\x06\xad\xaa\xa2\x40\x05\x63\x37\xc5\xc0\xe4\x8d\x1b\x3a\x33\xb5\x4f\xf6\x64\xda\xc0\x14\xd9\x90\x8b\x1c\x4c\xa4\x87\x63\xa8\x25\x58\xeb\x4c\x3b\x78\x4f\xbd\x35\xdc\x5b\xc0\x3b\x8a\x46\x23\x1c\x1a\x29\x74\x55\xcb\x19\xb5\xf9\xa3\xd2\x6c\xbb\x5a\xac\xb5\x61\x18\x7f\x79\xb6\x4e\x2f\xf7\x19\xe0\x56\xa2\x84\x48\x1d\x54\x12\xd2\x64\xaf\xea\x33\xe8\x7c\x77\xc4\x64\x0d\xdf\x76\x11\x25\x9e\xf2\x24\x5b\x7c\xec\x3d\x00\xc9\xeb\xd8\xd5\x10\x01\x00\x00")
 
 func _7_create_pgstream_event_triggersUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -367,7 +371,87 @@ func _7_create_pgstream_event_triggersUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "7_create_pgstream_event_triggers.up.sql", size: 272, mode: os.FileMode(420), modTime: time.Unix(1720000959, 0)}
+	info := bindataFileInfo{name: "7_create_pgstream_event_triggers.up.sql", size: 272, mode: os.FileMode(436), modTime: time.Unix(1726161379, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __8_add_pgstream_get_schema_type_infoDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x56\x5f\x6f\xdb\x38\x0c\x7f\xcf\xa7\xe0\x43\x07\x27\x40\x62\x60\xaf\x1d\x32\x5c\xae\xf5\xba\x1c\x7a\xc9\x2e\x71\xb1\x0d\xc3\xe0\x2a\x16\x63\x6b\xb5\x25\x9f\x24\x77\xcb\xb7\x3f\x48\xfe\x93\xd8\x72\xb2\x5e\x23\x03\xad\x4d\xfe\x48\x8a\xa4\x7e\xd4\x6c\x06\x3a\x65\x0a\xf6\x25\x8f\x35\x13\x1c\x98\x82\x98\x64\x19\x52\x40\x12\xa7\xa0\x59\x8e\x40\x20\x4e\x09\x4f\x10\xb4\x00\x02\x09\x7b\x46\x0e\x2a\x4e\x31\x27\x46\x3d\x27\x14\x7d\x58\x6a\xf8\xc9\xb2\x0c\x94\x16\x12\x41\xa7\x08\x12\x55\x99\x69\x10\x7b\xfb\x56\xeb\x57\x86\x46\xb3\x19\xfc\x4c\x59\x9c\x56\x18\x9d\x22\x87\x9d\x41\x14\x19\x8b\x89\x46\xea\x43\x98\x22\x88\x52\x17\xa5\x06\xa5\x65\x19\xeb\x52\x62\xe5\xad\x28\x90\x02\xe3\xd6\x6a\x2c\x28\xee\x88\xc2\x29\x14\x19\x12\x85\xa0\xc9\x13\x42\x4c\x8c\xee\x1e\x90\x32\xcd\x78\xe2\x8f\x66\x33\xe3\xf1\x33\x42\x4a\x9e\xab\xe0\xf6\x4c\x2a\x63\x19\x0b\x78\xfc\xc9\x74\x0a\x9a\xec\x32\x8c\x04\xa3\x0a\x88\x82\x31\xf8\xbe\x0f\x93\x47\xe3\x47\x48\x8a\xd2\x6c\x3d\x91\x64\x07\xcb\x5b\x05\x3a\x25\xba\x32\x45\x32\x89\x84\x1e\x60\x87\xc8\x21\x41\x8e\xd2\x44\x3f\x05\xc2\xa9\xf1\xc8\xb8\x42\xa9\x41\xa7\xc2\x84\x66\x50\x54\x70\x4f\xc3\x01\x6b\xfc\xf2\x56\x99\xd4\x79\x0a\xa8\xe0\x08\x19\x7b\x32\x7a\x4c\x19\x77\x29\x66\x05\xd8\xd8\x0a\x94\x7b\x21\x73\xc2\x63\xf4\x47\x37\x9b\x60\x11\x06\xb0\xde\xc0\x26\xf8\x74\xbf\xb8\x09\xe0\xc3\xc3\xea\x26\x5c\xae\x57\x50\x24\x4a\x4b\x24\xb9\x9f\xa0\x8e\xaa\x84\x8f\xab\x3f\x11\x27\x39\x42\x18\x7c\x09\x27\xb0\x09\xc2\x87\xcd\x6a\x0b\x3f\x94\xe0\xbb\x11\x00\xc0\xfd\x62\x75\xf7\xb0\xb8\x0b\x60\xfb\xcf\xbd\xfd\xb0\x0d\x42\x50\x48\x64\x9c\x46\x05\xd1\x29\xcc\xa1\x48\xa2\x98\x68\x92\x89\x64\x5a\x24\x91\xc6\xbc\xb0\x8a\x8b\x2d\x5c\x5d\x8d\x3e\x2f\xc3\x8f\xa7\x09\x5c\x6c\x61\x6c\xc5\x56\x80\xbf\x98\x32\x65\xe8\xc9\xcc\xb3\x0d\xee\x83\x9b\x10\x6e\x97\xdb\x70\xb9\xba\x09\xdb\xef\xe6\x29\x12\x1b\xb4\x2a\x48\x8c\x3e\x57\x85\x79\x81\xc5\xb6\x6e\x3c\x2b\x9b\xf6\x01\x71\x46\x94\xf2\x25\x66\x8d\x72\x15\xd4\x05\x5d\xc1\xe8\x51\x4f\x30\xda\x6a\x7d\xd8\xac\xff\xee\xc4\xd0\xc1\xdb\x67\xb3\xbc\xfb\x18\xc2\x5f\xeb\xe5\xaa\xb5\x07\xeb\x55\x07\x64\xed\xcf\x9d\xd0\xac\x0c\x16\xab\xdb\x8e\xe4\x89\x71\x0a\xcb\x15\x8c\x3d\xe9\x4d\xc1\x2b\xbc\x49\xeb\xf3\xf3\xc7\x60\x13\x0c\xa7\x64\x7e\x9a\x11\x0b\x98\xd4\x35\x34\xb9\x6d\x2d\x74\xaa\xe0\x0f\x26\xb1\xab\x32\x94\xba\x21\x0d\xc1\xe8\x51\x21\x16\x24\x43\x15\xe3\xb8\x6d\xc5\x4a\xc9\xa8\x33\x3a\x3d\x76\x68\x2c\x91\x68\x8c\x2a\xa9\x39\xd3\x8c\x27\xe3\x33\xe6\x27\x93\x63\x89\x8a\x44\x45\x75\x95\x6c\x85\x3a\x90\x36\x0c\xfb\xdc\x07\x1f\xda\xea\xf4\x83\x31\x75\x3a\xe3\x0d\xe6\x03\xfa\xa6\x8e\xa3\x49\xbd\xcf\x58\x64\x65\xce\x7b\xad\xdc\xcf\xb7\x5d\xad\xd1\xc6\xfe\xef\xfa\xd2\x45\xf4\x1b\xf4\x77\xfa\x55\x82\x8e\x90\xea\xbd\x87\xb2\x6c\xa2\xc7\xde\x1b\x35\x7b\xa3\xbc\xe9\x39\x2b\xa6\x5e\x11\xd1\x5a\xb2\x5d\xa9\xd1\x27\x5a\xf3\x32\xb7\xc5\xa8\x52\x30\x6c\xdc\xc1\xd4\x7b\xae\x31\x03\x9b\xae\xc2\x89\xf4\xa1\xc0\x71\x1f\xad\x0f\xc5\x50\x20\xfa\x50\xe4\x82\x9e\xc6\x62\xd0\x6e\x24\x86\x09\xf1\x57\x21\x1b\xbb\x14\xf7\x3e\xa1\x3b\xc6\x5b\x93\xd5\x17\x89\x19\xeb\x98\xa3\xb8\x27\x65\xa6\x7b\x16\x57\xeb\x10\xc6\x4e\x30\x5c\x68\x5e\x66\x99\xe1\x64\x43\x8e\x87\x02\x7d\x7d\x28\x4c\x40\x30\x07\x8f\x7a\xcd\x49\x6f\x24\xb5\xfe\xa9\x3b\x83\x37\x45\xe8\xf9\x1b\x07\x5f\x96\xdb\xb0\xd3\x67\x5d\xea\x7c\xeb\x08\x1a\xe2\x8a\x05\x57\x5a\x12\xc6\xb5\xa3\x52\xb1\x49\x2c\xb8\xdd\x35\xcc\x9d\x0d\xd9\xef\x0e\xcc\xec\x62\xb1\xd9\x2c\xbe\x7e\x73\x12\x50\xe6\xd7\xd7\x8c\xeb\xef\xf0\xc7\x7b\x88\x05\x7f\xc2\x83\x7d\xff\xf6\x7d\xd0\x48\x2c\x78\x93\x9c\xd2\xeb\x69\x4c\x4c\x16\x5f\xbf\x6b\xc6\x29\xfe\x72\xa4\x43\x1c\x7d\xe4\xff\x79\x0b\xf4\x2d\x7c\x78\xf7\x55\xd2\x18\xa7\xff\x3f\x69\x8c\x53\xa6\x4a\xce\xfe\x2d\xf1\xb5\x49\x3d\x8d\xf0\x5c\x76\x27\xa7\x0d\x55\xb9\xeb\xb5\xd3\x71\x8a\xfb\xb1\xc8\x22\x8a\x2a\x96\xac\x30\xf7\xbe\xb1\x43\x01\x86\xd7\x07\x42\xb2\x3e\x72\xd4\x84\x12\x4d\x46\x4e\xfe\x5b\xf5\xae\xe3\x63\x15\x8e\x7e\xea\x3a\x74\x1c\x34\xb9\x1d\x8a\xe6\x9c\xc5\xfa\x60\x0d\x99\xb3\xe4\x51\xf5\xb7\x69\x38\x7f\xd8\xca\xe9\xa0\x68\x28\xe1\x52\x70\x2e\x71\x34\x07\xbc\xa3\xce\xcb\xbc\xaf\xcc\xcb\x7c\x04\xce\x4c\x77\x50\xef\xe7\xf0\x16\x66\x33\xc8\x50\xd9\xcb\x26\x87\xb7\xe6\xea\x2b\x51\xa1\x7c\x46\x0a\x7b\x21\x41\x1d\x94\xc6\xdc\x5c\xb2\x45\x29\x63\x54\xa7\xfb\x32\xd1\x18\xa6\xea\xdb\x66\x8a\x4a\x61\xaf\xcf\xe6\xfe\x6d\x6e\xde\x3b\x84\x47\x2d\x4b\x7c\x34\xd7\xe5\xaa\x73\x8c\xa7\x1d\x32\x9e\x40\xad\x5c\x59\x6e\x86\xdf\xee\x50\x0d\xed\x17\x4c\xbf\x7a\x4e\xfa\x67\x87\x5d\x57\xc1\x1d\x6e\x5d\xf9\x8b\x26\x9b\xbd\xd4\x46\x24\x49\xc6\xd5\x7f\xbb\x92\x65\x34\x12\xbb\x1f\x18\x6b\x97\x4e\xec\xf2\x9a\x71\x1f\x31\xea\x4d\x5b\xa7\x97\x46\x5c\xf3\xf3\xcc\x30\x73\x31\x03\x5b\x6d\x96\x67\xfa\xd0\x45\x0c\x0c\xaf\x66\x79\xf5\x24\x72\x41\xc3\x23\xaa\xf9\x79\xcd\x48\x71\x81\x67\x86\x4d\xb3\xbc\x8a\x3b\x5c\xdc\x20\xa7\x34\xcb\x6b\x48\xe1\x04\xe7\xf2\x44\xf3\x3b\xbd\xd3\xd5\xda\x3d\xbb\x67\xc9\xff\x66\xbd\xb8\x0f\xb6\x37\x81\xad\xb0\x2d\xb5\x73\x86\x48\x8e\x93\x29\x78\xdf\xbe\x7b\xd7\xd7\x46\x6b\x32\xea\x99\xea\xce\x8b\xee\xf5\xc2\xd1\xb5\x07\xd5\xf9\x6a\x9e\x93\x69\xe0\xf4\xb2\xe1\x84\x41\xd0\x39\x5a\x79\xbd\x85\x8a\x69\x08\x3f\x8c\x7b\x63\xc2\xdd\x77\x43\x0f\x76\x24\x15\x92\xe5\x44\x1e\x7a\x4a\xb6\x32\xb5\x28\x7a\xc2\x43\x53\x9f\xa3\x9a\x4d\x9e\xf3\xf5\x6e\xb3\x7e\xf8\x04\x7f\x7e\xad\xab\x6a\x0f\x41\xfd\xbf\x39\xda\xee\x05\xbe\xe5\x14\xa2\x22\x53\xa5\x17\x50\xca\xcb\x0f\xb5\xf5\xa6\xbc\xe9\x68\x48\xfa\x1a\x9a\x68\x7e\x9e\xb0\x34\xd1\xf0\xe0\xb1\x5c\xd3\xd1\x25\x58\x9f\x66\x7a\xf8\x8b\x34\xd3\xa7\x9b\x1e\xf6\x02\xdd\x34\xcb\xab\xab\xe5\x82\x6b\xc1\xef\xf0\x03\xfd\x70\x6a\xeb\x62\xbb\xf4\xd7\x64\xa0\x2d\x6d\xd3\x3d\x1f\xbf\xdb\x16\x6b\xcc\xd7\xdd\x32\xaa\x09\xe0\xb9\x3a\xbd\x75\xd7\xbc\x1b\x5d\x5d\xbd\x1b\xfd\x37\x00\x6e\x90\xca\xb8\xc8\x12\x00\x00")
+
+func _8_add_pgstream_get_schema_type_infoDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__8_add_pgstream_get_schema_type_infoDownSql,
+		"8_add_pgstream_get_schema_type_info.down.sql",
+	)
+}
+
+func _8_add_pgstream_get_schema_type_infoDownSql() (*asset, error) {
+	bytes, err := _8_add_pgstream_get_schema_type_infoDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "8_add_pgstream_get_schema_type_info.down.sql", size: 4808, mode: os.FileMode(420), modTime: time.Unix(1792328049, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __8_add_pgstream_get_schema_type_infoUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x58\x6d\x6f\xdb\x38\x12\xfe\xae\x5f\x31\x1f\xba\x90\x0d\x38\xc2\xed\xd7\xee\xa5\x38\x5f\xa2\xb6\x3e\xe4\xec\x3d\xdb\x45\x77\x51\x14\x0a\x2d\x8e\x65\x6e\x24\x52\x47\x52\x69\xfd\xef\x0f\x43\xbd\x59\x6f\x49\x9a\xe2\x4c\xa3\xa9\xc9\x99\x87\xc3\xe1\xf0\xe1\x0c\xaf\xae\xc0\x9e\x84\x81\x63\x21\x63\x2b\x94\x04\x61\x20\x66\x69\x8a\x1c\x90\xc5\x27\xb0\x22\x43\x60\x10\x9f\x98\x4c\x10\xac\x02\x06\x89\x78\x44\x09\x26\x3e\x61\xc6\x48\x3c\x63\x1c\x03\x58\x59\xf8\x26\xd2\x14\x8c\x55\x1a\xc1\x9e\x10\x34\x9a\x22\xb5\xa0\x8e\xee\x57\x25\x5f\x02\x79\x57\x57\xf0\xed\x24\xe2\x53\xa9\x63\x4f\x28\xe1\x40\x1a\x79\x2a\x62\x66\x91\x07\xb0\x3f\x21\xa8\xc2\xe6\x85\x05\x63\x75\x11\xdb\x42\x63\x39\x5b\x9e\x23\x07\x21\x1d\x6a\xac\x38\x1e\x98\xc1\x05\xe4\x29\x32\x83\x60\xd9\x03\x42\xcc\x48\xf6\x08\xc8\x85\x15\x32\x09\xbc\xab\x2b\x9a\x91\x20\x63\x95\x16\x99\x84\x7b\x7b\xce\x31\x12\xf2\xa8\xee\x81\xa3\x89\xb5\x38\xa0\x81\xc2\xa0\x06\x8e\x47\x21\x91\x03\x49\x18\x98\xa1\x2c\x32\xb3\x00\xae\x32\x26\xa4\x59\x80\x26\xfb\x0d\x30\xc9\x21\x56\x59\xae\x8c\xb0\x58\xca\xce\x17\x50\x18\x21\x13\x67\x18\xa6\x98\xa1\xb4\x34\x2d\x0d\xc2\x51\x69\x60\x5a\xb3\xb3\x21\x57\xf9\x06\x64\x91\xa6\x65\xaf\x3c\x83\xb2\x27\xd4\x25\x4a\x6d\xec\x67\x84\x13\x7b\x2c\x3d\x79\x14\xda\x90\x1b\x30\x87\xfb\x6f\xc2\x9e\xc0\xb2\x43\x8a\x91\x12\xdc\x00\x33\x30\x83\x20\x08\x60\x7e\x4f\x4e\x51\x9a\x13\x92\x82\x44\xb3\x03\xac\x6e\x0d\xd8\x13\xb3\x25\x14\x4b\x35\x32\x7e\x86\x03\xa2\x84\x04\x25\x6a\x72\xf5\x82\xd6\x42\x33\x0a\x69\x50\x5b\xb0\x27\x45\x7e\x24\x2d\xae\xa4\x6f\xe1\x8c\x95\xfe\xea\xb6\x36\x9e\x2b\x89\x90\x8a\x07\x92\x13\x86\xa6\x3b\x61\x9a\x83\xb3\x2d\x47\x7d\x54\x3a\x63\x32\xc6\xc0\xbb\xd9\x86\xcb\x7d\x08\x9b\x2d\x6c\xc3\xdf\xef\x96\x37\x21\xbc\xff\xb4\xbe\xd9\xaf\x36\x6b\xc8\x13\x63\x35\xb2\x2c\x48\xd0\x46\x65\x74\xcc\xca\x3f\x91\x64\x19\xc2\x3e\xfc\x63\x3f\x87\x6d\xb8\xff\xb4\x5d\xef\xe0\x2f\xa3\xe4\xc1\x03\x00\xb8\x5b\xae\x3f\x7c\x5a\x7e\x08\x61\xf7\x9f\x3b\xd7\xb1\x0b\xf7\x60\x90\xe9\xf8\x14\xe5\xcc\x9e\xe0\x1a\xf2\x24\x8a\x99\x65\xa9\x4a\x16\x79\x12\x59\xcc\x72\x27\xb8\xdc\xc1\x9b\x37\xde\xe7\xd5\xfe\xe3\xa5\x03\x97\x3b\x98\xb9\x61\x37\x80\xdf\x85\xa1\x98\xe9\x8d\xd1\x77\x17\xde\x85\x37\x7b\xb8\x5d\xed\xf6\xab\xf5\xcd\xbe\xe9\xa7\x6f\x9e\x38\xa3\x4d\xce\x62\x0c\xa4\xc9\xe9\x07\x2c\x77\xd5\x29\x71\x63\x8b\xbe\x42\x9c\x32\x63\x02\x8d\x69\x2d\x5c\x1a\xf5\x84\xac\x12\xbc\x95\x53\x82\x37\x52\xef\xb7\x9b\x7f\x77\x6c\xe8\xe8\xbb\xef\x76\xf5\xe1\xe3\x1e\xfe\xb5\x59\xad\x1b\x3c\xd8\xac\x3b\x4a\x0e\xff\x7a\x60\x9a\x1b\x83\xe5\xfa\xb6\x33\xf2\x20\x24\x87\xd5\x1a\x66\xbe\xf6\x17\xe0\xe7\xfe\xbc\x99\xf3\xf3\xc7\x70\x1b\x8e\xbb\xe4\xfa\xd2\x23\x4e\x61\x5e\xed\x21\xf9\xb6\x41\xe8\xec\x42\x30\xea\xc4\xae\xc8\x98\xeb\xc6\x24\x94\xe0\xad\x40\xac\x58\x8a\x26\xc6\x59\x13\x8a\xa5\x10\x89\x0b\xbe\x68\x23\x34\xd6\xc8\x2c\x46\xe5\x28\x11\x90\x90\xc9\x6c\x02\x7e\x3e\x6f\xb7\x28\x4f\x4c\x54\xed\x92\xdb\xa1\x8e\x4a\x63\x86\xfb\xde\x85\xef\x9b\xdd\xe9\x1b\x43\xfb\x34\x31\x1b\x5c\x8f\xc8\xd3\x3e\x7a\xf3\x6a\x9d\x25\xe1\xf5\x42\xb9\xef\x6f\xd7\x1a\xd0\x1a\xff\xb9\xb8\x1c\x6a\xf4\x03\xf4\x39\xf9\xd2\x41\xad\x4a\xf9\xbb\xa7\xe5\xd8\xc4\xce\xfc\x5f\xcc\xd5\x2f\xc6\x5f\x4c\xa1\xd0\x7e\x45\xcc\x5a\x2d\x0e\x85\xc5\x80\x59\x2b\x8b\xcc\x6d\x46\xe9\x82\x71\xf0\x81\x4e\xb5\xe6\x4a\x67\x64\xd1\xa5\x39\x11\x91\xf5\xac\xaf\x6d\xcf\xf9\x98\x21\xf6\x9c\x67\x8a\x5f\xda\x42\xda\x43\x4b\x88\x09\xf1\x7b\xae\x6b\x5c\x8e\xc7\x80\xf1\x83\x90\x0d\x64\xd9\xa3\x31\x15\x1d\x38\x8e\x47\x56\xa4\xb6\x87\xb8\xde\xec\x61\x36\x30\x46\x2a\xeb\xee\x9e\xcd\x96\x86\xc8\x90\xc0\x9e\x73\xfa\x0b\xd7\xe0\x73\xbf\x3e\xe9\xf5\x48\x25\x7f\x39\x1d\xe9\xd3\x26\xf4\xe6\x9b\x85\x7f\xac\x76\xfb\x4e\x9c\x75\xa9\xf3\xd7\xc1\x40\x4d\x5c\xb1\x92\xc6\x6a\x26\xa4\x1d\x88\x94\x6c\x12\x2b\xe9\x56\x0d\xd7\x83\x05\xb9\xfe\x81\x1a\xad\x62\xb9\xdd\x2e\xff\xfc\x32\x70\x40\x91\xbd\x7d\x2b\xa4\xfd\x0a\xff\x78\x07\xb1\x92\x0f\x78\x76\xbf\xbf\x7c\x1d\x05\x89\x95\xac\x9d\x53\xf8\x3d\x89\x39\xdd\x6c\xaf\x5f\xb5\x90\x1c\xbf\x0f\x46\xc7\x38\xba\xe5\xff\xeb\x46\x31\x70\xea\xe3\xab\x2f\x9d\x26\x24\xff\x71\xa7\x09\xc9\x85\x29\xa4\xf8\x6f\x81\xaf\x75\xea\xa5\x85\x53\xde\x9d\x5f\x06\x54\x39\x5d\x2f\x9c\xda\x5b\x3c\x88\x55\x1a\x95\x69\x5a\x4e\x49\xea\x6c\x40\x01\xc4\xeb\x23\x26\xb9\x39\x32\xb4\x8c\x33\xcb\x7a\xf0\x37\xcb\x5d\xe8\xd2\xb4\xce\x19\xf0\x60\xc4\x95\x6b\xf0\xd1\x87\xfd\xc7\x70\x5d\xe6\x21\xd1\xa1\x10\x29\x8f\xd4\xe1\x2f\x8c\xed\x70\xdf\x5d\xf3\x29\x8f\x4d\x94\x3e\xd3\xed\x48\x49\xa4\xbf\xf0\xc6\xe4\xca\xc1\xe8\x91\xa5\x05\x12\xbb\x4d\xc0\xb5\xe1\x54\x5a\xc0\x92\x84\x58\x82\x74\x03\xfa\x27\x65\x07\x4c\x61\xb3\xbd\x0d\xb7\xf0\xcf\x3f\xe1\x72\xc8\x28\x6d\x5d\x5a\x38\xf7\x46\x71\x2f\x02\x92\x74\x26\x85\x9a\x6b\xbd\x41\x76\x74\x07\xd7\x17\x5e\xbc\x4c\x4a\x3a\x9f\xb9\xf7\x92\xae\xd2\xd7\xfc\xa7\x7c\x5d\x66\xea\x93\xde\xa6\x6a\xc1\xed\xb8\xbf\xe8\x50\x79\x27\x12\x48\x88\x7a\x17\x83\x00\x21\x1e\xff\x81\xa5\xe8\x9f\x5a\x8a\xab\x35\x26\x57\xe2\x46\x23\x53\x1c\xaa\xd5\x3c\x1b\x39\xbd\x9b\xcb\xe9\x07\x5a\x26\x15\xc4\x02\xd6\x9f\xee\xee\xe6\x93\x28\x75\x90\x38\xbd\xe7\xa3\xa4\x81\xff\x3f\x45\x49\xfc\x53\xae\x6d\x6a\xb7\x49\xf7\x1e\x05\xa6\xfc\x07\x4f\xe4\x8b\x6d\xa9\x9a\x4f\xf9\x85\xbf\x68\x2b\xc9\x1e\x83\x0d\xb3\x8f\xde\xc7\x1f\x89\xe4\x09\xb0\x2a\x37\x99\x1e\x1d\x0f\xed\xea\x33\x6f\xb9\x65\x02\x81\xe8\xf6\xd9\xd8\x69\x34\x88\x98\x47\x80\x26\x01\xea\x2c\x60\x74\xea\xfa\x92\xeb\x1c\xd6\xf1\x1b\xae\xfd\xd0\x5d\x37\x81\x27\x8b\x0c\xde\x5d\x8f\x5c\xdc\x6d\x23\x6d\xca\xaf\x26\x10\x84\xe1\x5a\xd1\x43\xc5\x38\xc4\xdc\x7b\xae\x2b\xa4\x9b\x76\x07\xcd\x33\x85\x37\xed\xca\xae\x62\x9b\x42\xb4\x97\x64\x95\x44\x8c\xfa\x6c\xec\x2a\x9d\x42\xac\xb2\xc2\x31\xb8\xfa\x90\xe7\xc9\x53\x47\xbc\x83\xb2\xdc\xb5\x1b\x46\x90\xed\xee\x95\x79\xce\xd4\xd1\x71\x17\xb7\xbb\x29\xea\xc9\xec\x39\xaf\xcf\x36\xa5\x69\xcb\x41\x0e\x4b\xd0\xf0\xf7\x77\xf0\x37\xd8\xf7\xf4\xdc\x48\x78\xb7\x0b\x9b\x4e\x9a\x3c\x5c\xdf\x7a\xf0\xdc\x16\x0d\xea\xb6\x3a\x43\x7f\xca\xdd\xc3\x3c\xbe\xb6\xb5\x23\x4e\x11\xd8\x13\xee\x5c\xcd\x0d\xcb\x0e\xb4\x28\x6e\xe1\xea\x0a\x52\x34\xee\xed\x47\xc2\xaf\xf4\x6c\xa6\xd1\xa0\x7e\x44\x4e\x4c\x01\xe6\x6c\x2c\x66\xf4\x40\xa7\x0a\x1d\xa3\xf1\x46\x02\x3b\x4f\x26\x22\x9a\xc0\xdd\xab\xdd\x01\xe1\xde\xea\x02\xef\xe9\xa9\xad\x7a\x57\x13\x06\x0e\x48\x6f\x60\x9d\xf0\xaf\x6b\xd1\xc3\xb9\xac\xa1\x5f\x50\x8c\x56\x65\x6b\x30\x59\x7b\x76\x05\x86\xb5\x66\x77\xfc\x45\x85\xe6\x2b\x78\xdc\xaf\xab\xef\x48\x70\xc7\xe3\xe5\xa4\x4f\x55\x9c\x43\xee\xef\xe8\x8c\x2c\xb5\x4f\xf6\x3d\x8d\x91\x5a\xb2\x6e\x7e\x55\x18\x0e\x95\xc6\x2b\xc6\xfa\xe3\xd7\x15\xde\x50\x71\xa2\xf6\xab\x9b\x5f\xa6\xf2\x43\xbd\xd1\x14\xbf\x6e\x7e\x9d\xa3\x5f\xe8\x4d\xa4\xed\x75\xf3\x1b\x6a\xbc\xd0\x19\xa1\xcb\xba\x5d\xbe\xcb\x54\xe2\x3d\xe4\x99\x37\x71\xbf\xdf\x6c\x96\x77\xe1\xee\x26\x74\x61\x51\x67\xde\xdd\x83\xc7\x32\x9c\x2f\xc0\xff\xf2\xd5\x7f\xfb\x96\xa4\xe6\xde\xd4\x0d\xe8\x8a\xb6\xee\x13\xc1\x40\xd6\x9d\xee\x41\x2f\x7d\x2f\x2a\xba\xc1\x01\x20\x22\x19\x55\x9a\xe2\xa2\xd7\x23\x94\xf4\xc4\xe4\x79\xd6\x2b\xf5\xc6\x28\xf2\xa2\xac\xcc\xb5\xc8\x98\x3e\xf7\x84\xdc\xce\x54\x43\xd1\x03\x9e\xeb\xfd\x69\xc5\x9c\xf3\x06\xbd\x1f\xb6\x9b\x4f\xbf\x53\xb5\x73\x41\x12\xd5\xff\x89\x0f\x86\x8f\x70\x0d\x11\x31\x13\xd1\x2e\xbd\x80\x87\x5e\xce\x04\x6e\x36\x33\x95\x4e\xbe\x3e\x47\x04\x5f\x39\x6e\xa9\xc9\xb3\xdd\xae\x85\xf7\x94\x5a\x9f\x9b\x7a\xfa\x4f\x72\x53\x9f\xa3\x7a\xba\x4f\x70\x54\xdd\xfc\x6a\xb7\x86\xca\xd5\xc0\x73\xfa\x23\xf1\x70\x89\xf5\x64\xb8\xf4\xdb\x7c\x24\x2c\x5d\xd0\x3d\xb6\xfd\x2e\xc4\x6a\xf8\x2a\x5a\xbc\x8a\x00\x1e\xcb\xfc\xb5\x8a\x9a\xdf\xbc\x37\x6f\x7e\xf3\xfe\x37\x00\x4f\xf8\x8f\x05\x39\x1b\x00\x00")
+
+func _8_add_pgstream_get_schema_type_infoUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__8_add_pgstream_get_schema_type_infoUpSql,
+		"8_add_pgstream_get_schema_type_info.up.sql",
+	)
+}
+
+func _8_add_pgstream_get_schema_type_infoUpSql() (*asset, error) {
+	bytes, err := _8_add_pgstream_get_schema_type_infoUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "8_add_pgstream_get_schema_type_info.up.sql", size: 6969, mode: os.FileMode(420), modTime: time.Unix(1792328049, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __9_add_pgstream_get_schema_replica_identityDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x58\x6d\x6f\xdb\x38\x12\xfe\xae\x5f\x31\x1f\xba\x90\x0d\x38\xc2\xed\xd7\xee\xa5\x38\x5f\xa2\xb6\x3e\xe4\xec\x3d\xdb\x45\x77\x51\x14\x0a\x2d\x8e\x65\x6e\x24\x52\x47\x52\x69\xfd\xef\x0f\x43\xbd\x59\x6f\x49\x9a\xe2\x4c\xa3\xa9\xc9\x99\x87\xc3\xe1\xf0\xe1\x0c\xaf\xae\xc0\x9e\x84\x81\x63\x21\x63\x2b\x94\x04\x61\x20\x66\x69\x8a\x1c\x90\xc5\x27\xb0\x22\x43\x60\x10\x9f\x98\x4c\x10\xac\x02\x06\x89\x78\x44\x09\x26\x3e\x61\xc6\x48\x3c\x63\x1c\x03\x58\x59\xf8\x26\xd2\x14\x8c\x55\x1a\xc1\x9e\x10\x34\x9a\x22\xb5\xa0\x8e\xee\x57\x25\x5f\x02\x79\x57\x57\xf0\xed\x24\xe2\x53\xa9\x63\x4f\x28\xe1\x40\x1a\x79\x2a\x62\x66\x91\x07\xb0\x3f\x21\xa8\xc2\xe6\x85\x05\x63\x75\x11\xdb\x42\x63\x39\x5b\x9e\x23\x07\x21\x1d\x6a\xac\x38\x1e\x98\xc1\x05\xe4\x29\x32\x83\x60\xd9\x03\x42\xcc\x48\xf6\x08\xc8\x85\x15\x32\x09\xbc\xab\x2b\x9a\x91\x20\x63\x95\x16\x99\x84\x7b\x7b\xce\x31\x12\xf2\xa8\xee\x81\xa3\x89\xb5\x38\xa0\x81\xc2\xa0\x06\x8e\x47\x21\x91\x03\x49\x18\x98\xa1\x2c\x32\xb3\x00\xae\x32\x26\xa4\x59\x80\x26\xfb\x0d\x30\xc9\x21\x56\x59\xae\x8c\xb0\x58\xca\xce\x17\x50\x18\x21\x13\x67\x18\xa6\x98\xa1\xb4\x34\x2d\x0d\xc2\x51\x69\x60\x5a\xb3\xb3\x21\x57\xf9\x06\x64\x91\xa6\x65\xaf\x3c\x83\xb2\x27\xd4\x25\x4a\x6d\xec\x67\x84\x13\x7b\x2c\x3d\x79\x14\xda\x90\x1b\x30\x87\xfb\x6f\xc2\x9e\xc0\xb2\x43\x8a\x91\x12\xdc\x00\x33\x30\x83\x20\x08\x60\x7e\x4f\x4e\x51\x9a\x13\x92\x82\x44\xb3\x03\xac\x6e\x0d\xd8\x13\xb3\x25\x14\x4b\x35\x32\x7e\x86\x03\xa2\x84\x04\x25\x6a\x72\xf5\x82\xd6\x42\x33\x0a\x69\x50\x5b\xb0\x27\x45\x7e\x24\x2d\xae\xa4\x6f\xe1\x8c\x95\xfe\xea\xb6\x36\x9e\x2b\x89\x90\x8a\x07\x92\x13\x86\xa6\x3b\x61\x9a\x83\xb3\x2d\x47\x7d\x54\x3a\x63\x32\xc6\xc0\xbb\xd9\x86\xcb\x7d\x08\x9b\x2d\x6c\xc3\xdf\xef\x96\x37\x21\xbc\xff\xb4\xbe\xd9\xaf\x36\x6b\xc8\x13\x63\x35\xb2\x2c\x48\xd0\x46\x65\x74\xcc\xca\x3f\x91\x64\x19\xc2\x3e\xfc\x63\x3f\x87\x6d\xb8\xff\xb4\x5d\xef\xe0\x2f\xa3\xe4\xc1\x03\x00\xb8\x5b\xae\x3f\x7c\x5a\x7e\x08\x61\xf7\x9f\x3b\xd7\xb1\x0b\xf7\x60\x90\xe9\xf8\x14\xe5\xcc\x9e\xe0\x1a\xf2\x24\x8a\x99\x65\xa9\x4a\x16\x79\x12\x59\xcc\x72\x27\xb8\xdc\xc1\x9b\x37\xde\xe7\xd5\xfe\xe3\xa5\x03\x97\x3b\x98\xb9\x61\x37\x80\xdf\x85\xa1\x98\xe9\x8d\xd1\x77\x17\xde\x85\x37\x7b\xb8\x5d\xed\xf6\xab\xf5\xcd\xbe\xe9\xa7\x6f\x9e\x38\xa3\x4d\xce\x62\x0c\xa4\xc9\xe9\x07\x2c\x77\xd5\x29\x71\x63\x8b\xbe\x42\x9c\x32\x63\x02\x8d\x69\x2d\x5c\x1a\xf5\x84\xac\x12\xbc\x95\x53\x82\x37\x52\xef\xb7\x9b\x7f\x77\x6c\xe8\xe8\xbb\xef\x76\xf5\xe1\xe3\x1e\xfe\xb5\x59\xad\x1b\x3c\xd8\xac\x3b\x4a\x0e\xff\x7a\x60\x9a\x1b\x83\xe5\xfa\xb6\x33\xf2\x20\x24\x87\xd5\x1a\x66\xbe\xf6\x17\xe0\xe7\xfe\xbc\x99\xf3\xf3\xc7\x70\x1b\x8e\xbb\xe4\xfa\xd2\x23\x4e\x61\x5e\xed\x21\xf9\xb6\x41\xe8\xec\x42\x30\xea\xc4\xae\xc8\x98\xeb\xc6\x24\x94\xe0\xad\x40\xac\x58\x8a\x26\xc6\x59\x13\x8a\xa5\x10\x89\x0b\xbe\x68\x23\x34\xd6\xc8\x2c\x46\xe5\x28\x11\x90\x90\xc9\x6c\x02\x7e\x3e\x6f\xb7\x28\x4f\x4c\x54\xed\x92\xdb\xa1\x8e\x4a\x63\x86\xfb\xde\x85\xef\x9b\xdd\xe9\x1b\x43\xfb\x34\x31\x1b\x5c\x8f\xc8\xd3\x3e\x7a\xf3\x6a\x9d\x25\xe1\xf5\x42\xb9\xef\x6f\xd7\x1a\xd0\x1a\xff\xb9\xb8\x1c\x6a\xf4\x03\xf4\x39\xf9\xd2\x41\xad\x4a\xf9\xbb\xa7\xe5\xd8\xc4\xce\xfc\x5f\xcc\xd5\x2f\xc6\x5f\x4c\xa1\xd0\x7e\x45\xcc\x5a\x2d\x0e\x85\xc5\x80\x59\x2b\x8b\xcc\x6d\x46\xe9\x82\x71\xf0\x81\x4e\xb5\xe6\x4a\x67\x64\xd1\xa5\x39\x11\x91\xf5\xac\xaf\x6d\xcf\xf9\x98\x21\xf6\x9c\x67\x8a\x5f\xda\x42\xda\x43\x4b\x88\x09\xf1\x7b\xae\x6b\x5c\x8e\xc7\x80\xf1\x83\x90\x0d\x64\xd9\xa3\x31\x15\x1d\x38\x8e\x47\x56\xa4\xb6\x87\xb8\xde\xec\x61\x36\x30\x46\x2a\xeb\xee\x9e\xcd\x96\x86\xc8\x90\xc0\x9e\x73\xfa\x0b\xd7\xe0\x73\xbf\x3e\xe9\xf5\x48\x25\x7f\x39\x1d\xe9\xd3\x26\xf4\xe6\x9b\x85\x7f\xac\x76\xfb\x4e\x9c\x75\xa9\xf3\xd7\xc1\x40\x4d\x5c\xb1\x92\xc6\x6a\x26\xa4\x1d\x88\x94\x6c\x12\x2b\xe9\x56\x0d\xd7\x83\x05\xb9\xfe\x81\x1a\xad\x62\xb9\xdd\x2e\xff\xfc\x32\x70\x40\x91\xbd\x7d\x2b\xa4\xfd\x0a\xff\x78\x07\xb1\x92\x0f\x78\x76\xbf\xbf\x7c\x1d\x05\x89\x95\xac\x9d\x53\xf8\x3d\x89\x39\xdd\x6c\xaf\x5f\xb5\x90\x1c\xbf\x0f\x46\xc7\x38\xba\xe5\xff\xeb\x46\x31\x70\xea\xe3\xab\x2f\x9d\x26\x24\xff\x71\xa7\x09\xc9\x85\x29\xa4\xf8\x6f\x81\xaf\x75\xea\xa5\x85\x53\xde\x9d\x5f\x06\x54\x39\x5d\x2f\x9c\xda\x5b\x3c\x88\x55\x1a\x95\x69\x5a\x4e\x49\xea\x6c\x40\x01\xc4\xeb\x23\x26\xb9\x39\x32\xb4\x8c\x33\xcb\x7a\xf0\x37\xcb\x5d\xe8\xd2\xb4\xce\x19\xf0\x60\xc4\x95\x6b\xf0\xd1\x87\xfd\xc7\x70\x5d\xe6\x21\xd1\xa1\x10\x29\x8f\xd4\xe1\x2f\x8c\xed\x70\xdf\x5d\xf3\x29\x8f\x4d\x94\x3e\xd3\xed\x48\x49\xa4\xbf\xf0\xc6\xe4\xca\xc1\xe8\x91\xa5\x05\x12\xbb\x4d\xc0\xb5\xe1\x54\x5a\xc0\x92\x84\x58\x82\x74\x03\xfa\x27\x65\x07\x4c\x61\xb3\xbd\x0d\xb7\xf0\xcf\x3f\xe1\x72\xc8\x28\x6d\x5d\x5a\x38\xf7\x46\x71\x2f\x02\x92\x74\x26\x85\x9a\x6b\xbd\x41\x76\x74\x07\xd7\x17\x5e\xbc\x4c\x4a\x3a\x9f\xb9\xf7\x92\xae\xd2\xd7\xfc\xa7\x7c\x5d\x66\xea\x93\xde\xa6\x6a\xc1\xed\xb8\xbf\xe8\x50\x79\x27\x12\x48\x88\x7a\x17\x83\x00\x21\x1e\xff\x81\xa5\xe8\x9f\x5a\x8a\xab\x35\x26\x57\xe2\x46\x23\x53\x1c\xaa\xd5\x3c\x1b\x39\xbd\x9b\xcb\xe9\x07\x5a\x26\x15\xc4\x02\xd6\x9f\xee\xee\xe6\x93\x28\x75\x90\x38\xbd\xe7\xa3\xa4\x81\xff\x3f\x45\x49\xfc\x53\xae\x6d\x6a\xb7\x49\xf7\x1e\x05\xa6\xfc\x07\x4f\xe4\x8b\x6d\xa9\x9a\x4f\xf9\x85\xbf\x68\x2b\xc9\x1e\x83\x0d\xb3\x8f\xde\xc7\x1f\x89\xe4\x09\xb0\x2a\x37\x99\x1e\x1d\x0f\xed\xea\x33\x6f\xb9\x65\x02\x81\xe8\xf6\xd9\xd8\x69\x34\x88\x98\x47\x80\x26\x01\xea\x2c\x60\x74\xea\xfa\x92\xeb\x1c\xd6\xf1\x1b\xae\xfd\xd0\x5d\x37\x81\x27\x8b\x0c\xde\x5d\x8f\x5c\xdc\x6d\x23\x6d\xca\xaf\x26\x10\x84\xe1\x5a\xd1\x43\xc5\x38\xc4\xdc\x7b\xae\x2b\xa4\x9b\x76\x07\xcd\x33\x85\x37\xed\xca\xae\x62\x9b\x42\xb4\x97\x64\x95\x44\x8c\xfa\x6c\xec\x2a\x9d\x42\xac\xb2\xc2\x31\xb8\xfa\x90\xe7\xc9\x53\x47\xbc\x83\xb2\xdc\xb5\x1b\x46\x90\xed\xee\x95\x79\xce\xd4\xd1\x71\x17\xb7\xbb\x29\xea\xc9\xec\x39\xaf\xcf\x36\xa5\x69\xcb\x41\x0e\x4b\xd0\xf0\xf7\x77\xf0\x37\xd8\xf7\xf4\xdc\x48\x78\xb7\x0b\x9b\x4e\x9a\x3c\x5c\xdf\x7a\xf0\xdc\x16\x0d\xea\xb6\x3a\x43\x7f\xca\xdd\xc3\x3c\xbe\xb6\xb5\x23\x4e\x11\xd8\x13\xee\x5c\xcd\x0d\xcb\x0e\xb4\x28\x6e\xe1\xea\x0a\x52\x34\xee\xed\x47\xc2\xaf\xf4\x6c\xa6\xd1\xa0\x7e\x44\x4e\x4c\x01\xe6\x6c\x2c\x66\xf4\x40\xa7\x0a\x1d\xa3\xf1\x46\x02\x3b\x4f\x26\x22\x9a\xc0\xdd\xab\xdd\x01\xe1\xde\xea\x02\xef\xe9\xa9\xad\x7a\x57\x13\x06\x0e\x48\x6f\x60\x9d\xf0\xaf\x6b\xd1\xc3\xb9\xac\xa1\x5f\x50\x8c\x56\x65\x6b\x30\x59\x7b\x76\x05\x86\xb5\x66\x77\xfc\x45\x85\xe6\x2b\x78\xdc\xaf\xab\xef\x48\x70\xc7\xe3\xe5\xa4\x4f\x55\x9c\x43\xee\xef\xe8\x8c\x2c\xb5\x4f\xf6\x3d\x8d\x91\x5a\xb2\x6e\x7e\x55\x18\x0e\x95\xc6\x2b\xc6\xfa\xe3\xd7\x15\xde\x50\x71\xa2\xf6\xab\x9b\x5f\xa6\xf2\x43\xbd\xd1\x14\xbf\x6e\x7e\x9d\xa3\x5f\xe8\x4d\xa4\xed\x75\xf3\x1b\x6a\xbc\xd0\x19\xa1\xcb\xba\x5d\xbe\xcb\x54\xe2\x3d\xe4\x99\x37\x71\xbf\xdf\x6c\x96\x77\xe1\xee\x26\x74\x61\x51\x67\xde\xdd\x83\xc7\x32\x9c\x2f\xc0\xff\xf2\xd5\x7f\xfb\x96\xa4\xe6\xde\xd4\x0d\xe8\x8a\xb6\xee\x13\xc1\x40\xd6\x9d\xee\x41\x2f\x7d\x2f\x2a\xba\xc1\x01\x20\x22\x19\x55\x9a\xe2\xa2\xd7\x23\x94\xf4\xc4\xe4\x79\xd6\x2b\xf5\xc6\x28\xf2\xa2\xac\xcc\xb5\xc8\x98\x3e\xf7\x84\xdc\xce\x54\x43\xd1\x03\x9e\xeb\xfd\x69\xc5\x9c\xf3\x06\xbd\x1f\xb6\x9b\x4f\xbf\x53\xb5\x73\x41\x12\xd5\xff\x89\x0f\x86\x8f\x70\x0d\x11\x31\x13\xd1\x2e\xbd\x80\x87\x5e\xce\x04\x6e\x36\x33\x95\x4e\xbe\x3e\x47\x04\x5f\x39\x6e\xa9\xc9\xb3\xdd\xae\x85\xf7\x94\x5a\x9f\x9b\x7a\xfa\x4f\x72\x53\x9f\xa3\x7a\xba\x4f\x70\x54\xdd\xfc\x6a\xb7\x86\xca\xd5\xc0\x73\xfa\x23\xf1\x70\x89\xf5\x64\xb8\xf4\xdb\x7c\x24\x2c\x5d\xd0\x3d\xb6\xfd\x2e\xc4\x6a\xf8\x2a\x5a\xbc\x8a\x00\x1e\xcb\xfc\xb5\x8a\x9a\xdf\xbc\x37\x6f\x7e\xf3\xfe\x37\x00\x4f\xf8\x8f\x05\x39\x1b\x00\x00")
+
+func _9_add_pgstream_get_schema_replica_identityDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__9_add_pgstream_get_schema_replica_identityDownSql,
+		"9_add_pgstream_get_schema_replica_identity.down.sql",
+	)
+}
+
+func _9_add_pgstream_get_schema_replica_identityDownSql() (*asset, error) {
+	bytes, err := _9_add_pgstream_get_schema_replica_identityDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "9_add_pgstream_get_schema_replica_identity.down.sql", size: 6969, mode: os.FileMode(420), modTime: time.Unix(1792341116, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __9_add_pgstream_get_schema_replica_identityUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x59\x6d\x8f\xdb\x38\x0e\xfe\xee\x5f\xc1\x0f\x5d\x38\x01\x32\xc1\xed\xd7\xee\x4d\x71\xb9\x19\xb7\xcd\x61\x2e\xb3\x97\xa4\xe8\x2e\x8a\x22\x51\x2c\xc6\xd1\xd6\x96\x7c\x92\x3c\x6d\xfe\xfd\x81\xf2\x4b\xfc\x22\x4f\xa6\x2d\xce\x0e\xda\x89\xc5\x87\xa2\x48\xfa\x11\xa9\xdc\xdc\x80\x3d\x09\x03\xc7\x42\xc6\x56\x28\x09\xc2\x40\xcc\xd2\x14\x39\x20\x8b\x4f\x60\x45\x86\xc0\x20\x3e\x31\x99\x20\x58\x05\x0c\x12\xf1\x84\x12\x4c\x7c\xc2\x8c\x91\x78\xc6\x38\xce\x61\x69\xe1\xab\x48\x53\x30\x56\x69\x04\x7b\x42\xd0\x68\x8a\xd4\x82\x3a\xba\x6f\x95\x7c\xa9\x28\xb8\xb9\x81\xaf\x27\x11\x9f\x4a\x8c\x3d\xa1\x84\x03\x21\xf2\x54\xc4\xcc\x22\x9f\xc3\xf6\x84\xa0\x0a\x9b\x17\x16\x8c\xd5\x45\x6c\x0b\x8d\xe5\x6c\x79\x8e\x1c\x84\x74\x5a\x63\xc5\xf1\xc0\x0c\xce\x20\x4f\x91\x19\x04\xcb\xbe\x20\xc4\x8c\x64\x8f\x80\x5c\x58\x21\x93\x79\x70\x73\x43\x33\x92\xca\x58\xa5\x45\x26\x61\x6f\xcf\x39\xee\x84\x3c\xaa\x3d\x70\x34\xb1\x16\x07\x34\x50\x18\xd4\xc0\xf1\x28\x24\x72\x20\x09\x03\x13\x94\x45\x66\x66\xc0\x55\xc6\x84\x34\x33\xd0\x64\xbf\x01\x26\x39\xc4\x2a\xcb\x95\x11\x16\x4b\xd9\xe9\x0c\x0a\x23\x64\xe2\x0c\xc3\x14\x33\x94\x96\xa6\xa5\x41\x38\x2a\x0d\x4c\x6b\x76\x36\xe4\xaa\xd0\x80\x2c\xd2\xb4\x7c\x2a\xcf\xa0\xec\x09\x75\xa9\xa5\x6d\xac\x65\x87\x14\x61\x5f\xb9\x65\x27\x38\x4a\x2b\xec\x79\x4f\x7e\x50\x12\xc9\xb5\x7b\x8e\x47\x56\xa4\x76\x3f\x83\xbd\x54\xf6\x24\x64\x42\x7f\x1e\x8b\x34\xdd\x83\xd2\xb0\x17\x92\xe3\xb7\xfd\xcc\x19\xcc\xd1\xa2\xce\x84\x44\x53\x79\x5f\xa5\xbc\xf6\xc8\x13\x4b\x0b\x34\x34\x33\x39\x8f\x3d\x31\x91\xba\xd9\x2b\x47\x17\x39\x67\x16\x2b\x2d\x29\x5a\x04\x7c\x42\x69\x1b\x73\x3f\x22\x9c\xd8\x53\x19\xf8\xa3\xd0\x86\xa2\x86\x39\xec\xbf\x0a\x7b\x2a\xd7\xb1\x53\x82\x1b\x60\x06\x26\x30\x9f\xcf\x61\xba\xa7\x18\x2a\xcd\x69\xe1\x0a\x12\xcd\x0e\xb0\xbc\x37\x60\x4f\xcc\x96\xaa\x58\xaa\x91\xf1\x33\x1c\x10\x25\x24\x28\x51\x53\x66\xb8\x95\x90\x99\x42\x1a\xd4\x16\xec\x49\x51\xd8\x09\xc5\x95\x0c\x2d\x9c\xb1\xc2\x2f\xef\x6b\x5f\x73\x72\x56\x2a\xbe\x90\x9c\x30\x34\xdd\x09\xd3\x1c\x9c\x6d\x39\xea\xa3\xd2\x19\x93\x31\xce\x83\xbb\x75\xb4\xd8\x46\xf0\xb8\x86\x75\xf4\xfb\xc3\xe2\x2e\x82\xb7\x1f\x56\x77\xdb\xe5\xe3\x0a\xf2\xc4\x58\x8d\x2c\x9b\x27\x68\x77\x65\x32\x4f\xca\xff\x76\x92\x65\x08\xdb\xe8\x8f\xed\x14\xd6\xd1\xf6\xc3\x7a\xb5\x81\xbf\x8c\x92\x87\x00\x00\xe0\x61\xb1\x7a\xf7\x61\xf1\x2e\x82\xcd\x7f\x1e\xdc\x83\x4d\xb4\x05\x83\x4c\xc7\xa7\x5d\xce\xec\x09\x6e\x21\x4f\x76\x31\xb3\x2c\x55\xc9\x2c\x4f\x76\x16\xb3\xdc\x09\x2e\x36\xf0\xea\x55\xf0\x71\xb9\x7d\xdf\x76\xe0\x62\x03\x13\x37\xec\x06\xf0\x9b\x30\x94\xe2\xbd\x31\xfa\x6c\xa2\x87\xe8\x6e\x0b\xf7\xcb\xcd\x76\xb9\xba\xdb\x36\xcf\xe9\x93\x27\xce\x68\x93\xb3\x18\xe7\xd2\xe4\xf4\x05\x16\x9b\xea\xa5\x76\x63\xb3\x3e\x20\x4e\x99\x31\x73\x8d\x69\x2d\x5c\x1a\xf5\x8c\xac\x12\xfc\x22\xa7\x04\x6f\xa4\xde\xae\x1f\xff\xdd\xb1\xa1\x83\x77\x9f\xf5\xf2\xdd\xfb\x2d\xfc\xeb\x71\xb9\x6a\xf4\xc1\xe3\xaa\x03\x72\xfa\x6f\x07\xa6\xb9\x31\x58\xac\xee\x3b\x23\x5f\x84\xe4\xb0\x5c\xc1\x24\xd4\xe1\x0c\xc2\x3c\x9c\x36\x73\x7e\x7c\x1f\xad\x23\xbf\x4b\x6e\xdb\x1e\x71\x80\x69\x15\x43\xf2\x6d\xa3\xa1\x13\x85\xb9\xd7\x89\x5d\x11\x9f\xeb\x7c\x12\x4a\xf0\x8b\x40\xac\x58\x8a\x26\xc6\x49\x93\x8a\xa5\x10\x89\x0b\x3e\xbb\x64\x68\xac\x91\x59\xdc\x95\xa3\xc4\x97\x42\x26\x93\x11\xf5\xd3\xe9\x25\x44\x79\x62\x76\x55\x94\x5c\x84\x3a\x90\xc6\x0c\xf7\x79\x88\xde\x36\xd1\xe9\x1b\x43\x71\x1a\x99\x0d\x6e\x3d\xf2\x14\xc7\x60\x5a\xad\xb3\x64\xa3\x5e\x2a\xf7\xfd\xed\xee\x46\x69\xad\xff\x5a\x5e\x0e\x11\xfd\x04\xbd\x26\x5f\x3a\xe8\x02\x29\xbf\xf7\x50\x8e\x4d\xec\x24\xfc\xc5\xdc\xfc\x62\xc2\xd9\x98\x16\x8a\xd7\x8e\x59\xab\xc5\xa1\xb0\x38\x67\xd6\xca\x22\x73\xc1\x28\x5d\xe0\x57\x3e\xc0\x54\x6b\xae\x30\x9e\x45\x97\xe6\xec\x68\x6f\x99\xf4\xd1\xf6\x9c\xfb\x0c\xb1\xe7\x3c\x53\xbc\x6d\x0b\xa1\x87\x96\x10\x13\xe2\xb7\x5c\xd7\x7a\x39\x1e\xe7\x8c\x1f\x84\x6c\x54\x96\x4f\x34\xa6\xa2\xa3\xae\xda\xb3\x7a\x1a\x57\x8f\x5b\x98\x0c\x8c\x91\xca\xba\xad\xf2\x71\x4d\x43\x64\xc8\xdc\x9e\x73\xfa\x1f\x6e\x21\xe4\x61\xfd\xa6\xd7\x23\x95\x7c\x7b\x3a\xc2\x53\x10\x7a\xf3\x4d\xa2\x3f\x96\x9b\x6d\x27\xcf\xba\xd4\xf9\xeb\x60\xa0\x26\xae\x58\x49\x63\x35\x13\xd2\x0e\x44\x4a\x36\x89\x95\x74\xab\x86\xdb\xc1\x82\xdc\xf3\x01\x8c\x56\xb1\x58\xaf\x17\x7f\x7e\x1a\x38\xa0\xc8\x5e\xbf\x16\xd2\x7e\x86\x7f\xbc\x81\x58\xc9\x2f\x78\x76\xdf\x3f\x7d\xf6\x2a\x89\x95\xac\x9d\x53\x84\x3d\x89\x29\xed\x6c\x3f\xbe\x6a\x57\x4a\x0c\x46\x7d\x1c\x7d\xe1\xff\xdb\x06\x38\x77\x70\xff\xea\x4b\xa7\x09\xc9\xbf\xdf\x69\x42\x72\x61\x0a\x29\xfe\x5b\xe0\x8f\x3a\xb5\x6d\xe1\x98\x77\xa7\xed\x84\x2a\xa7\xeb\xa5\xd3\x65\x17\x9f\xc7\x2a\xdd\x95\x55\x65\x4e\x35\xf5\x64\x40\x01\xc4\xeb\x1e\x93\xdc\x1c\x19\x5a\xc6\x99\x65\x3d\xf5\x77\x8b\x4d\xe4\xaa\xca\xce\x3b\x10\x80\xc7\x95\x2b\x08\x31\x84\xed\xfb\x68\x55\xd6\x21\xbb\x43\x21\x52\xbe\x53\x87\xbf\x30\xb6\xc3\xb8\xbb\x3b\xa4\xb2\x3b\x51\xfa\x4c\xbb\x23\xd5\xbc\xe1\x2c\xf0\xc9\x95\x83\xbb\xb2\x5a\x0c\x67\x9e\x34\xea\xa5\x53\x69\x01\x4b\x12\x62\x09\xc2\xce\xe9\x9f\x94\x1d\x30\x85\xc7\xf5\x7d\xb4\x86\x7f\xfe\x09\xed\x21\xa3\xb4\x75\x65\xe1\x34\xf0\xea\x6d\x25\x24\x61\x46\x85\x9a\x6d\xbd\xd1\xec\xe8\x0e\x6e\x5b\x5e\x6c\x17\x25\x9d\x6b\x1a\xbc\xe4\x51\xe9\x6b\xfe\x53\xbe\x2e\x1b\x8b\x51\x6f\x53\x73\xe3\x22\x1e\xce\x3a\x54\xde\xc9\x04\x12\xa2\xa7\xb3\x41\x82\x10\x8f\x7f\xc7\x52\xf4\x4f\x2d\xc5\xb5\x46\xa3\x2b\x71\xa3\x3b\x53\x1c\xaa\xd5\x5c\xcd\x9c\xde\xce\xe5\xf0\x73\x2d\x93\x4a\xc5\x0c\x56\x1f\x1e\x1e\xa6\xa3\x5a\xea\x24\x71\xb8\xeb\x59\xd2\xa8\xff\x3f\x65\x49\xfc\x53\xae\x6d\x5a\xcd\x51\xf7\x1e\x05\xa6\xfc\x3b\xdf\xc8\x17\xdb\x52\xdd\x21\xd5\x17\xe1\xec\xd2\xf8\xf6\x18\x6c\x58\x7d\xf4\xae\xd0\x93\xc9\x23\xca\xaa\xda\x64\x7c\xd4\x9f\xda\xd5\x35\xbd\x70\xcb\x88\x06\xa2\xdb\xab\xb9\xd3\x20\x88\x98\x3d\x8a\x46\x15\xd4\x55\x80\x77\xea\x7a\x93\xeb\xbc\xac\xfe\x1d\xee\x72\xd1\x5e\x37\xa2\x4f\x16\x19\xbc\xb9\xf5\x6c\xdc\x97\x9b\xd0\x54\x5f\x8d\x68\x10\x86\x6b\x45\xe7\x2a\x7e\x15\xd3\xe0\xda\xa3\x88\x76\xda\x0d\x34\xa7\x2a\xc1\xb8\x2b\xbb\xc0\x4b\x09\x71\xd9\x24\xab\x22\xc2\xeb\x33\xdf\x56\x3a\xa6\xb1\xaa\x0a\x7d\xea\xea\x97\x3c\x4f\x9e\x7b\xc5\x3b\x5a\x16\x9b\x4b\xc0\x48\xe5\x25\x7a\x65\x9d\x33\xf6\xea\xb8\x8d\xdb\xed\x14\xf5\x64\xf6\x9c\xd7\xef\x36\x95\x69\x8b\x41\x0d\x4b\xaa\xe1\xef\x6f\xe0\x6f\xb0\xed\xe1\xdc\x48\xf4\xb0\x89\x9a\x87\x34\x79\xb4\xba\x0f\xe0\x5a\x88\x06\x7d\x5b\x5d\xa1\x3f\xe7\xee\x61\x1d\x5f\xdb\xda\x11\xa7\x0c\xec\x09\x77\xb6\xe6\x86\x65\x07\x28\xca\x5b\xb8\xb9\x81\x14\x8d\x3b\xfb\x91\xf0\x2b\x9d\x6e\x69\x34\xa8\x9f\x90\x13\x53\x80\x39\x1b\x8b\x19\x9d\x27\xaa\x42\xc7\x68\x02\x4f\x62\xe7\xc9\x48\x46\x93\x72\x77\xc8\x78\x40\xd8\x5b\x5d\xe0\x9e\x4e\x06\xab\x43\x2f\x61\xe0\x80\x74\x64\xd7\x49\xff\xba\x17\x3d\x9c\xcb\x1e\xfa\x05\xcd\x68\xd5\xb6\xce\x47\x7b\xcf\xae\xc0\xb0\xd7\xec\x8e\xbf\xa8\xd1\xfc\x01\x1e\x0f\xeb\xee\x7b\x27\xb8\xe3\xf1\x72\xd2\xe7\x3a\xce\x21\xf7\x77\x30\x9e\xa5\xf6\xc9\xbe\x87\xf0\xf4\x92\xf5\x1d\x56\x8d\xe1\x10\xe4\xef\x18\xeb\x2b\xac\x3b\xbc\x21\x70\xa4\xf7\xab\xef\xb0\x2c\xe5\x87\x38\x6f\x89\x5f\xdf\x61\x5d\xa3\xb7\x70\x23\x65\x7b\x7d\x87\x0d\x35\xb6\x30\x1e\xba\xac\xef\xf6\xb9\x4c\x25\xde\xd3\x3c\x09\x46\xf6\xf7\xbb\xc7\xc5\x43\xb4\xb9\x8b\x5c\x5a\xd4\x95\x77\xf7\xc5\x63\x19\x4e\x67\x10\x7e\xfa\x1c\xbe\x7e\x4d\x52\xd3\x60\x6c\x07\x74\x4d\x5b\xf7\x88\x60\x20\xeb\xde\xee\xc1\x53\xfa\xb4\x3a\xba\xc1\x0b\x40\x44\xe2\x05\x8d\x71\xd1\x8f\x6b\x28\xe9\x89\xc9\xf3\xa4\xd7\xea\xf9\x28\xb2\xd5\x56\xe6\x5a\x64\x4c\x9f\x7b\x42\x2e\x32\xd5\xd0\xee\x0b\x9e\xeb\xf8\x5c\xc4\x9c\xf3\x06\x4f\xdf\xad\x1f\x3f\xfc\x4e\xdd\x4e\x8b\x24\xaa\xbf\x89\x0f\x86\x87\x70\x0d\x11\x31\xb3\xa3\x28\xbd\x80\x87\x5e\xce\x04\x6e\x36\x33\x56\x4e\xfe\x78\x8d\x08\xa1\x72\xdc\x52\x93\xe7\x25\x5c\xb3\xe0\x39\x58\x9f\x9b\x7a\xf8\x67\xb9\xa9\xcf\x51\x3d\xec\x33\x1c\x55\xdf\x61\x15\xad\x21\xb8\x1a\xb8\x86\xf7\xe4\x43\x5b\x97\x67\xf8\x9a\xc6\xfe\xaf\x3e\xcf\x96\xf6\x7d\x0a\xa0\x9a\xa3\x39\x87\xd1\x98\x3a\x65\x1c\x3d\xe7\x55\xbe\xab\xdb\xd7\x36\xac\xfc\x1d\x58\x59\x63\xab\xdf\xa4\xc2\xe0\x19\x48\x73\x97\xf3\x1e\x6b\x2c\xfd\x88\xf5\x3d\x40\x51\x03\xdd\x2b\x7e\x1d\xe9\x2f\x9b\xfc\x3c\xe8\x4e\xb4\xae\x4a\x37\x75\x4e\xfb\x00\xac\x97\x50\xfe\x52\xb3\x75\xf9\x49\xa9\xda\x15\x86\x63\x8e\x8e\x9e\x2e\xcf\x9d\xc5\xf5\x9c\x15\x8f\x04\x55\x5e\x3c\x95\x9d\x4d\xc5\x27\xbf\x05\xaf\x5e\xfd\x16\xfc\x6f\x00\x81\xae\x03\x6c\x02\x1e\x00\x00")
+
+func _9_add_pgstream_get_schema_replica_identityUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__9_add_pgstream_get_schema_replica_identityUpSql,
+		"9_add_pgstream_get_schema_replica_identity.up.sql",
+	)
+}
+
+func _9_add_pgstream_get_schema_replica_identityUpSql() (*asset, error) {
+	bytes, err := _9_add_pgstream_get_schema_replica_identityUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "9_add_pgstream_get_schema_replica_identity.up.sql", size: 7682, mode: os.FileMode(420), modTime: time.Unix(1792341116, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -424,20 +508,24 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"1_create_pgstream_xid.down.sql":                     _1_create_pgstream_xidDownSql,
-	"1_create_pgstream_xid.up.sql":                       _1_create_pgstream_xidUpSql,
-	"2_create_pgstream_schemalog_table.down.sql":         _2_create_pgstream_schemalog_tableDownSql,
-	"2_create_pgstream_schemalog_table.up.sql":           _2_create_pgstream_schemalog_tableUpSql,
-	"3_create_pgstream_tableids_table.down.sql":          _3_create_pgstream_tableids_tableDownSql,
-	"3_create_pgstream_tableids_table.up.sql":            _3_create_pgstream_tableids_tableUpSql,
-	"4_create_pgstream_get_schema_function.down.sql":     _4_create_pgstream_get_schema_functionDownSql,
-	"4_create_pgstream_get_schema_function.up.sql":       _4_create_pgstream_get_schema_functionUpSql,
-	"5_create_pgstream_log_schema_function.down.sql":     _5_create_pgstream_log_schema_functionDownSql,
-	"5_create_pgstream_log_schema_function.up.sql":       _5_create_pgstream_log_schema_functionUpSql,
-	"6_create_pgstream_refresh_schema_function.down.sql": _6_create_pgstream_refresh_schema_functionDownSql,
-	"6_create_pgstream_refresh_schema_function.up.sql":   _6_create_pgstream_refresh_schema_functionUpSql,
-	"7_create_pgstream_event_triggers.down.sql":          _7_create_pgstream_event_triggersDownSql,
-	"7_create_pgstream_event_triggers.up.sql":            _7_create_pgstream_event_triggersUpSql,
+	"1_create_pgstream_xid.down.sql":                      _1_create_pgstream_xidDownSql,
+	"1_create_pgstream_xid.up.sql":                        _1_create_pgstream_xidUpSql,
+	"2_create_pgstream_schemalog_table.down.sql":          _2_create_pgstream_schemalog_tableDownSql,
+	"2_create_pgstream_schemalog_table.up.sql":            _2_create_pgstream_schemalog_tableUpSql,
+	"3_create_pgstream_tableids_table.down.sql":           _3_create_pgstream_tableids_tableDownSql,
+	"3_create_pgstream_tableids_table.up.sql":             _3_create_pgstream_tableids_tableUpSql,
+	"4_create_pgstream_get_schema_function.down.sql":      _4_create_pgstream_get_schema_functionDownSql,
+	"4_create_pgstream_get_schema_function.up.sql":        _4_create_pgstream_get_schema_functionUpSql,
+	"5_create_pgstream_log_schema_function.down.sql":      _5_create_pgstream_log_schema_functionDownSql,
+	"5_create_pgstream_log_schema_function.up.sql":        _5_create_pgstream_log_schema_functionUpSql,
+	"6_create_pgstream_refresh_schema_function.down.sql":  _6_create_pgstream_refresh_schema_functionDownSql,
+	"6_create_pgstream_refresh_schema_function.up.sql":    _6_create_pgstream_refresh_schema_functionUpSql,
+	"7_create_pgstream_event_triggers.down.sql":           _7_create_pgstream_event_triggersDownSql,
+	"7_create_pgstream_event_triggers.up.sql":             _7_create_pgstream_event_triggersUpSql,
+	"8_add_pgstream_get_schema_type_info.down.sql":        _8_add_pgstream_get_schema_type_infoDownSql,
+	"8_add_pgstream_get_schema_type_info.up.sql":          _8_add_pgstream_get_schema_type_infoUpSql,
+	"9_add_pgstream_get_schema_replica_identity.down.sql": _9_add_pgstream_get_schema_replica_identityDownSql,
+	"9_add_pgstream_get_schema_replica_identity.up.sql":   _9_add_pgstream_get_schema_replica_identityUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -481,20 +569,24 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"1_create_pgstream_xid.down.sql":                     &bintree{_1_create_pgstream_xidDownSql, map[string]*bintree{}},
-	"1_create_pgstream_xid.up.sql":                       &bintree{_1_create_pgstream_xidUpSql, map[string]*bintree{}},
-	"2_create_pgstream_schemalog_table.down.sql":         &bintree{_2_create_pgstream_schemalog_tableDownSql, map[string]*bintree{}},
-	"2_create_pgstream_schemalog_table.up.sql":           &bintree{_2_create_pgstream_schemalog_tableUpSql, map[string]*bintree{}},
-	"3_create_pgstream_tableids_table.down.sql":          &bintree{_3_create_pgstream_tableids_tableDownSql, map[string]*bintree{}},
-	"3_create_pgstream_tableids_table.up.sql":            &bintree{_3_create_pgstream_tableids_tableUpSql, map[string]*bintree{}},
-	"4_create_pgstream_get_schema_function.down.sql":     &bintree{_4_create_pgstream_get_schema_functionDownSql, map[string]*bintree{}},
-	"4_create_pgstream_get_schema_function.up.sql":       &bintree{_4_create_pgstream_get_schema_functionUpSql, map[string]*bintree{}},
-	"5_create_pgstream_log_schema_function.down.sql":     &bintree{_5_create_pgstream_log_schema_functionDownSql, map[string]*bintree{}},
-	"5_create_pgstream_log_schema_function.up.sql":       &bintree{_5_create_pgstream_log_schema_functionUpSql, map[string]*bintree{}},
-	"6_create_pgstream_refresh_schema_function.down.sql": &bintree{_6_create_pgstream_refresh_schema_functionDownSql, map[string]*bintree{}},
-	"6_create_pgstream_refresh_schema_function.up.sql":   &bintree{_6_create_pgstream_refresh_schema_functionUpSql, map[string]*bintree{}},
-	"7_create_pgstream_event_triggers.down.sql":          &bintree{_7_create_pgstream_event_triggersDownSql, map[string]*bintree{}},
-	"7_create_pgstream_event_triggers.up.sql":            &bintree{_7_create_pgstream_event_triggersUpSql, map[string]*bintree{}},
+	"1_create_pgstream_xid.down.sql":                      &bintree{_1_create_pgstream_xidDownSql, map[string]*bintree{}},
+	"1_create_pgstream_xid.up.sql":                        &bintree{_1_create_pgstream_xidUpSql, map[string]*bintree{}},
+	"2_create_pgstream_schemalog_table.down.sql":          &bintree{_2_create_pgstream_schemalog_tableDownSql, map[string]*bintree{}},
+	"2_create_pgstream_schemalog_table.up.sql":            &bintree{_2_create_pgstream_schemalog_tableUpSql, map[string]*bintree{}},
+	"3_create_pgstream_tableids_table.down.sql":           &bintree{_3_create_pgstream_tableids_tableDownSql, map[string]*bintree{}},
+	"3_create_pgstream_tableids_table.up.sql":             &bintree{_3_create_pgstream_tableids_tableUpSql, map[string]*bintree{}},
+	"4_create_pgstream_get_schema_function.down.sql":      &bintree{_4_create_pgstream_get_schema_functionDownSql, map[string]*bintree{}},
+	"4_create_pgstream_get_schema_function.up.sql":        &bintree{_4_create_pgstream_get_schema_functionUpSql, map[string]*bintree{}},
+	"5_create_pgstream_log_schema_function.down.sql":      &bintree{_5_create_pgstream_log_schema_functionDownSql, map[string]*bintree{}},
+	"5_create_pgstream_log_schema_function.up.sql":        &bintree{_5_create_pgstream_log_schema_functionUpSql, map[string]*bintree{}},
+	"6_create_pgstream_refresh_schema_function.down.sql":  &bintree{_6_create_pgstream_refresh_schema_functionDownSql, map[string]*bintree{}},
+	"6_create_pgstream_refresh_schema_function.up.sql":    &bintree{_6_create_pgstream_refresh_schema_functionUpSql, map[string]*bintree{}},
+	"7_create_pgstream_event_triggers.down.sql":           &bintree{_7_create_pgstream_event_triggersDownSql, map[string]*bintree{}},
+	"7_create_pgstream_event_triggers.up.sql":             &bintree{_7_create_pgstream_event_triggersUpSql, map[string]*bintree{}},
+	"8_add_pgstream_get_schema_type_info.down.sql":        &bintree{_8_add_pgstream_get_schema_type_infoDownSql, map[string]*bintree{}},
+	"8_add_pgstream_get_schema_type_info.up.sql":          &bintree{_8_add_pgstream_get_schema_type_infoUpSql, map[string]*bintree{}},
+	"9_add_pgstream_get_schema_replica_identity.down.sql": &bintree{_9_add_pgstream_get_schema_replica_identityDownSql, map[string]*bintree{}},
+	"9_add_pgstream_get_schema_replica_identity.up.sql":   &bintree{_9_add_pgstream_get_schema_replica_identityUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
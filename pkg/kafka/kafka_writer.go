@@ -36,6 +36,10 @@ type WriterConfig struct {
 	// BatchSize limits how many messages will be buffered before being sent to
 	// a partition. Defaults to 100 messages.
 	BatchSize int
+	// RoutedTopics are the additional topics the messages can be routed to.
+	// When set, the topic needs to be set on every message, and the topics
+	// are created along with the connection topic if auto create is enabled.
+	RoutedTopics []string
 }
 
 // NewWriter returns a kafka writer that produces messages to the configured
@@ -51,11 +55,18 @@ func NewWriter(config WriterConfig, logger loglib.Logger) (*Writer, error) {
 	})
 
 	if config.Conn.Topic.AutoCreate {
-		if err := createTopic(&config.Conn); err != nil {
+		if err := createTopics(&config.Conn, config.RoutedTopics); err != nil {
 			return nil, err
 		}
 	}
 
+	// the kafka-go writer doesn't allow the topic to be set on both the
+	// writer and the messages
+	topic := config.Conn.Topic.Name
+	if len(config.RoutedTopics) > 0 {
+		topic = ""
+	}
+
 	transport, err := buildTransport(&config.Conn.TLS)
 	if err != nil {
 		return nil, err
@@ -64,7 +75,7 @@ func NewWriter(config WriterConfig, logger loglib.Logger) (*Writer, error) {
 	return &Writer{
 		kafkaWriter: &kafka.Writer{
 			Addr:         kafka.TCP(config.Conn.Servers...),
-			Topic:        config.Conn.Topic.Name,
+			Topic:        topic,
 			RequiredAcks: kafka.RequireAll,
 			Balancer:     &kafka.CRC32Balancer{},
 			Transport:    transport,
@@ -89,14 +100,15 @@ func (w *Writer) Close() error {
 	return w.kafkaWriter.Close()
 }
 
-func createTopic(cfg *ConnConfig) error {
+func createTopics(cfg *ConnConfig, routedTopics []string) error {
 	return withConnection(cfg, func(conn *kafka.Conn) error {
-		topicConfigs := []kafka.TopicConfig{
-			{
-				Topic:             cfg.Topic.Name,
+		topicConfigs := make([]kafka.TopicConfig, 0, len(routedTopics)+1)
+		for _, topic := range append([]string{cfg.Topic.Name}, routedTopics...) {
+			topicConfigs = append(topicConfigs, kafka.TopicConfig{
+				Topic:             topic,
 				NumPartitions:     cfg.Topic.numPartitions(),
 				ReplicationFactor: cfg.Topic.replicationFactor(),
-			},
+			})
 		}
 
 		err := conn.CreateTopics(topicConfigs...)
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pgstream
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/translator"
+	pgreplication "github.com/ApollosProject/pgstream-wal2json/pkg/wal/replication/postgres"
+)
+
+type Config struct {
+	// Replication configuration for the postgres replication slot to listen
+	// on.
+	Replication pgreplication.Config
+	// LosslessNumbers enables the decoding of numeric values without precision
+	// loss.
+	LosslessNumbers bool
+	// Translator configuration, which injects the pgstream metadata into the
+	// events. It requires pgstream to have been initialised on the database.
+	// Translation is disabled when not provided.
+	Translator *translator.Config
+	// Filter restricts the events delivered. All events are delivered when not
+	// provided.
+	Filter *Filter
+	// BatchSize is the max number of events per batch. Defaults to 1.
+	BatchSize int
+	// BatchTimeout is the max time to wait for a batch to be filled before
+	// it's delivered. Defaults to 1s.
+	BatchTimeout time.Duration
+	// CheckpointInterval is the interval at which the position of the acked
+	// events is checkpointed. Defaults to 1s.
+	CheckpointInterval time.Duration
+}
+
+// Filter restricts the events delivered to the ones matching all its non
+// empty fields.
+type Filter struct {
+	Schemas []string
+	Tables  []string
+	// Actions are any of "I", "U", "D", "T" or "DDL".
+	Actions []string
+}
+
+const (
+	defaultBatchSize          = 1
+	defaultBatchTimeout       = time.Second
+	defaultCheckpointInterval = time.Second
+)
+
+func (c *Config) batchSize() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return defaultBatchSize
+}
+
+func (c *Config) batchTimeout() time.Duration {
+	if c.BatchTimeout > 0 {
+		return c.BatchTimeout
+	}
+	return defaultBatchTimeout
+}
+
+func (c *Config) checkpointInterval() time.Duration {
+	if c.CheckpointInterval > 0 {
+		return c.CheckpointInterval
+	}
+	return defaultCheckpointInterval
+}
+
+func (f *Filter) matches(data *wal.Data) bool {
+	if f == nil {
+		return true
+	}
+	return matchesAny(f.Schemas, data.Schema) &&
+		matchesAny(f.Tables, data.Table) &&
+		matchesAny(f.Actions, data.Action)
+}
+
+func matchesAny(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pgstream
+
+import (
+	"sync"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// ackTracker keeps the positions of the processed events in order, so that
+// only the positions up to the first unacked event are checkpointed, even
+// when events are acked out of order.
+type ackTracker struct {
+	mu      sync.Mutex
+	entries []*ackEntry
+}
+
+type ackEntry struct {
+	pos   wal.CommitPosition
+	acked bool
+}
+
+func newAckTracker() *ackTracker {
+	return &ackTracker{}
+}
+
+// add tracks a new event position. Events that are not delivered are added as
+// acked, so that they're checkpointed as soon as the previous events are.
+func (t *ackTracker) add(pos wal.CommitPosition, acked bool) *ackEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// consecutive acked entries only need the latest position, which keeps
+	// the tracker small when most events are filtered out
+	if last := t.last(); acked && last != nil && last.acked {
+		if pos != "" {
+			last.pos = pos
+		}
+		return last
+	}
+
+	entry := &ackEntry{pos: pos, acked: acked}
+	t.entries = append(t.entries, entry)
+	return entry
+}
+
+func (t *ackTracker) ack(entry *ackEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry.acked = true
+}
+
+// popAcked removes the acked entries at the front of the tracker and returns
+// their positions.
+func (t *ackTracker) popAcked() []wal.CommitPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	positions := []wal.CommitPosition{}
+	i := 0
+	for ; i < len(t.entries) && t.entries[i].acked; i++ {
+		if t.entries[i].pos != "" {
+			positions = append(positions, t.entries[i].pos)
+		}
+	}
+	t.entries = t.entries[i:]
+	return positions
+}
+
+func (t *ackTracker) last() *ackEntry {
+	if len(t.entries) == 0 {
+		return nil
+	}
+	return t.entries[len(t.entries)-1]
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pgstream
+
+import (
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newAckTracker()
+	require.Equal(t, []wal.CommitPosition{}, tracker.popAcked())
+
+	e1 := tracker.add("1", false)
+	tracker.add("2", true)
+	tracker.add("3", true)
+	e4 := tracker.add("4", false)
+	e5 := tracker.add("", false)
+	tracker.add("6", true)
+
+	// consecutive acked entries are merged
+	require.Len(t, tracker.entries, 5)
+
+	// nothing is checkpointed until the first event is acked
+	tracker.ack(e4)
+	require.Equal(t, []wal.CommitPosition{}, tracker.popAcked())
+
+	tracker.ack(e1)
+	require.Equal(t, []wal.CommitPosition{"1", "3", "4"}, tracker.popAcked())
+	require.Equal(t, []wal.CommitPosition{}, tracker.popAcked())
+
+	// acking twice is a no-op, and entries without position are skipped
+	tracker.ack(e5)
+	tracker.ack(e5)
+	require.Equal(t, []wal.CommitPosition{"6"}, tracker.popAcked())
+	require.Empty(t, tracker.entries)
+}
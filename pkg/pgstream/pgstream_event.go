@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pgstream
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// Event is a change event delivered by a subscription. It needs to be acked
+// once processed for its position to be checkpointed.
+type Event struct {
+	Data           *wal.Data
+	CommitPosition wal.CommitPosition
+
+	entry   *ackEntry
+	tracker *ackTracker
+}
+
+// Ack marks the event as processed. Its position will be checkpointed once
+// all the previous events have been acked too. Acking an event more than once
+// is a no-op.
+func (e *Event) Ack() {
+	e.tracker.ack(e.entry)
+}
+
+// Batch is a group of consecutive events delivered together.
+type Batch struct {
+	Events []*Event
+}
+
+// Ack marks all the events in the batch as processed.
+func (b *Batch) Ack() {
+	for _, e := range b.Events {
+		e.Ack()
+	}
+}
@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pgstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	pgcheckpoint "github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer/postgres"
+	pglistener "github.com/ApollosProject/pgstream-wal2json/pkg/wal/listener/postgres"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/translator"
+	pgreplication "github.com/ApollosProject/pgstream-wal2json/pkg/wal/replication/postgres"
+	"golang.org/x/sync/errgroup"
+)
+
+// Subscription delivers the change events from a postgres replication slot
+// in batches. The position of the events is only checkpointed once they've
+// been acked, so unacked events will be delivered again after a restart.
+type Subscription struct {
+	logger  loglib.Logger
+	filter  *Filter
+	tracker *ackTracker
+
+	msgChan chan *Event
+	batches chan *Batch
+
+	batchSize          int
+	batchTimeout       time.Duration
+	checkpointInterval time.Duration
+
+	// checkpoint callback to mark what was acked
+	checkpoint checkpointer.Checkpoint
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	err       error
+	closeOnce sync.Once
+	closed    atomic.Bool
+	closers   []func() error
+}
+
+type Option func(*Subscription)
+
+// Subscribe starts the replication from the configured postgres database and
+// returns the subscription delivering its change events. The subscription
+// needs to be closed once done.
+//
+//	sub, err := pgstream.Subscribe(ctx, &pgstream.Config{
+//		Replication: pgreplication.Config{PostgresURL: url},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer sub.Close()
+//
+//	for batch := range sub.Batches() {
+//		// process batch.Events...
+//		batch.Ack()
+//	}
+//	return sub.Err()
+func Subscribe(ctx context.Context, cfg *Config, opts ...Option) (*Subscription, error) {
+	s := newSubscription(cfg, opts...)
+
+	handler, err := pgreplication.NewHandler(ctx, cfg.Replication, pgreplication.WithLogger(s.logger))
+	if err != nil {
+		return nil, fmt.Errorf("setting up postgres replication handler: %w", err)
+	}
+	pgCheckpointer := pgcheckpoint.New(handler)
+	s.checkpoint = pgCheckpointer.SyncLSN
+	// the checkpointer closes the replication handler
+	s.closers = append(s.closers, pgCheckpointer.Close)
+
+	var p processor.Processor = &walProcessor{subscription: s}
+	if cfg.Translator != nil {
+		t, err := translator.New(cfg.Translator, p, translator.WithLogger(s.logger))
+		if err != nil {
+			s.close()
+			return nil, fmt.Errorf("setting up translator: %w", err)
+		}
+		s.closers = append(s.closers, t.Close)
+		p = t
+	}
+
+	listenerOpts := []pglistener.Option{pglistener.WithLogger(s.logger)}
+	if cfg.LosslessNumbers {
+		listenerOpts = append(listenerOpts, pglistener.WithLosslessNumbers())
+	}
+	listener := pglistener.New(handler, p.ProcessWALEvent, listenerOpts...)
+	s.closers = append(s.closers, listener.Close)
+
+	s.start(ctx, listener.Listen)
+
+	return s, nil
+}
+
+func newSubscription(cfg *Config, opts ...Option) *Subscription {
+	s := &Subscription{
+		logger:             loglib.NewNoopLogger(),
+		filter:             cfg.Filter,
+		tracker:            newAckTracker(),
+		msgChan:            make(chan *Event),
+		batches:            make(chan *Batch),
+		batchSize:          cfg.batchSize(),
+		batchTimeout:       cfg.batchTimeout(),
+		checkpointInterval: cfg.checkpointInterval(),
+		done:               make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(s *Subscription) {
+		s.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "pgstream_subscription",
+		})
+	}
+}
+
+// Batches returns the channel the event batches are delivered on. It is
+// closed when the subscription stops, after which Err returns the reason.
+func (s *Subscription) Batches() <-chan *Batch {
+	return s.batches
+}
+
+// Err returns the error that stopped the subscription, if any. It is nil
+// while the subscription is running, or when it was stopped by Close.
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close stops the subscription, checkpointing the position of the events that
+// have been acked.
+func (s *Subscription) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		s.cancel()
+		<-s.done
+
+		// use a new context, since the subscription one is already cancelled
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = s.checkpointAcked(ctx)
+		err = errors.Join(err, s.close())
+	})
+	return err
+}
+
+func (s *Subscription) close() error {
+	var errs error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = errors.Join(errs, s.closers[i]())
+	}
+	return errs
+}
+
+func (s *Subscription) start(ctx context.Context, listen func(context.Context) error) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return listen(ctx)
+	})
+	eg.Go(func() error {
+		return s.deliverBatches(ctx)
+	})
+	eg.Go(func() error {
+		return s.checkpointLoop(ctx)
+	})
+
+	go func() {
+		defer close(s.done)
+		err := eg.Wait()
+		// closing the batches channel once all the go routines have stopped
+		// guarantees there are no more writers
+		close(s.batches)
+		if !s.closed.Load() {
+			s.err = err
+		}
+	}()
+}
+
+// processWALEvent tracks the wal event position and queues it for delivery if
+// it matches the filter. It blocks until the event has been picked up, so
+// that slow consumers apply backpressure on the replication.
+func (s *Subscription) processWALEvent(ctx context.Context, walEvent *wal.Event) error {
+	if walEvent.Data == nil || !s.filter.matches(walEvent.Data) {
+		s.tracker.add(walEvent.CommitPosition, true)
+		return nil
+	}
+
+	event := &Event{
+		Data:           walEvent.Data,
+		CommitPosition: walEvent.CommitPosition,
+		entry:          s.tracker.add(walEvent.CommitPosition, false),
+		tracker:        s.tracker,
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case s.msgChan <- event:
+		return nil
+	}
+}
+
+func (s *Subscription) deliverBatches(ctx context.Context) error {
+	ticker := time.NewTicker(s.batchTimeout)
+	defer ticker.Stop()
+
+	batch := &Batch{}
+	deliver := func() error {
+		if len(batch.Events) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s.batches <- batch:
+			batch = &Batch{}
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := deliver(); err != nil {
+				return err
+			}
+		case event := <-s.msgChan:
+			batch.Events = append(batch.Events, event)
+			if len(batch.Events) >= s.batchSize {
+				if err := deliver(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *Subscription) checkpointLoop(ctx context.Context) error {
+	ticker := time.NewTicker(s.checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.checkpointAcked(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Subscription) checkpointAcked(ctx context.Context) error {
+	positions := s.tracker.popAcked()
+	if s.checkpoint == nil || len(positions) == 0 {
+		return nil
+	}
+	if err := s.checkpoint(ctx, positions); err != nil {
+		return fmt.Errorf("pgstream subscription: checkpointing positions: %w", err)
+	}
+	return nil
+}
+
+// walProcessor adapts the subscription to the processor interface, so that it
+// can be wrapped by the translator.
+type walProcessor struct {
+	subscription *Subscription
+}
+
+func (p *walProcessor) ProcessWALEvent(ctx context.Context, walEvent *wal.Event) error {
+	return p.subscription.processWALEvent(ctx, walEvent)
+}
+
+func (p *walProcessor) Name() string {
+	return "pgstream-subscription"
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pgstream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWalEvent(pos, action, table string) *wal.Event {
+	return &wal.Event{
+		CommitPosition: wal.CommitPosition(pos),
+		Data: &wal.Data{
+			Action: action,
+			LSN:    pos,
+			Schema: "public",
+			Table:  table,
+		},
+	}
+}
+
+// testCheckpoints records the checkpointed positions
+type testCheckpoints struct {
+	mu        sync.Mutex
+	positions []wal.CommitPosition
+}
+
+func (c *testCheckpoints) checkpoint(_ context.Context, positions []wal.CommitPosition) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.positions = append(c.positions, positions...)
+	return nil
+}
+
+func (c *testCheckpoints) get() []wal.CommitPosition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]wal.CommitPosition{}, c.positions...)
+}
+
+func TestSubscription(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := newSubscription(&Config{
+		Filter:             &Filter{Tables: []string{"users"}, Actions: []string{"I", "U"}},
+		BatchSize:          2,
+		BatchTimeout:       200 * time.Millisecond,
+		CheckpointInterval: 10 * time.Millisecond,
+	})
+	checkpoints := &testCheckpoints{}
+	s.checkpoint = checkpoints.checkpoint
+
+	walEvents := []*wal.Event{
+		newTestWalEvent("1", "I", "users"),
+		newTestWalEvent("2", "I", "teams"),
+		newTestWalEvent("3", "U", "users"),
+		{CommitPosition: "4"},
+		newTestWalEvent("5", "D", "users"),
+		newTestWalEvent("6", "I", "users"),
+		newTestWalEvent("7", "I", "teams"),
+	}
+	s.start(ctx, func(ctx context.Context) error {
+		p := &walProcessor{subscription: s}
+		for _, e := range walEvents {
+			if err := p.ProcessWALEvent(ctx, e); err != nil {
+				return err
+			}
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// full batch
+	batch := <-s.Batches()
+	require.Len(t, batch.Events, 2)
+	require.Equal(t, wal.CommitPosition("1"), batch.Events[0].CommitPosition)
+	require.Equal(t, wal.CommitPosition("3"), batch.Events[1].CommitPosition)
+
+	// partial batch delivered after the timeout
+	partialBatch := <-s.Batches()
+	require.Len(t, partialBatch.Events, 1)
+	require.Equal(t, "I", partialBatch.Events[0].Data.Action)
+	require.Equal(t, wal.CommitPosition("6"), partialBatch.Events[0].CommitPosition)
+
+	// nothing is checkpointed until the first event is acked
+	partialBatch.Ack()
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, checkpoints.get())
+
+	batch.Events[1].Ack()
+	batch.Events[0].Ack()
+	require.Eventually(t, func() bool {
+		positions := checkpoints.get()
+		return len(positions) > 0 && positions[len(positions)-1] == "7"
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, s.Close())
+	_, ok := <-s.Batches()
+	require.False(t, ok)
+	require.NoError(t, s.Err())
+}
+
+func TestSubscription_error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("oh noes")
+
+	s := newSubscription(&Config{})
+	s.start(context.Background(), func(ctx context.Context) error {
+		return errTest
+	})
+
+	_, ok := <-s.Batches()
+	require.False(t, ok)
+	require.ErrorIs(t, s.Err(), errTest)
+	require.NoError(t, s.Close())
+}
+
+func TestSubscription_Close(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := newSubscription(&Config{CheckpointInterval: time.Hour})
+	checkpoints := &testCheckpoints{}
+	s.checkpoint = checkpoints.checkpoint
+	s.start(ctx, func(ctx context.Context) error {
+		if err := s.processWALEvent(ctx, newTestWalEvent("1", "I", "users")); err != nil {
+			return err
+		}
+		// the consumer applies backpressure on the replication until the
+		// subscription is closed
+		return s.processWALEvent(ctx, newTestWalEvent("2", "I", "users"))
+	})
+
+	batch := <-s.Batches()
+	batch.Ack()
+
+	// the acked events are checkpointed on close
+	require.NoError(t, s.Close())
+	require.Equal(t, []wal.CommitPosition{"1"}, checkpoints.get())
+	require.NoError(t, s.Err())
+}
@@ -16,6 +16,23 @@ func TestLogEntry_Diff(t *testing.T) {
 		new  *LogEntry
 		want *SchemaDiff
 	}{
+		"no changes": {
+			old: &LogEntry{
+				Schema: Schema{
+					Tables: []Table{
+						{PgstreamID: "1", Name: "table1"},
+					},
+				},
+			},
+			new: &LogEntry{
+				Schema: Schema{
+					Tables: []Table{
+						{PgstreamID: "1", Name: "table1"},
+					},
+				},
+			},
+			want: nopDiff,
+		},
 		"table removed": {
 			old: &LogEntry{
 				Schema: Schema{
@@ -48,7 +65,11 @@ func TestLogEntry_Diff(t *testing.T) {
 					},
 				},
 			},
-			want: nopDiff,
+			want: &SchemaDiff{
+				TablesAdded: []Table{
+					{PgstreamID: "1"},
+				},
+			},
 		},
 		"table renamed": {
 			old: &LogEntry{
@@ -65,7 +86,11 @@ func TestLogEntry_Diff(t *testing.T) {
 					},
 				},
 			},
-			want: nopDiff,
+			want: &SchemaDiff{
+				TablesRenamed: []TableRename{
+					{PgstreamID: "1", OldName: "old", NewName: "new"},
+				},
+			},
 		},
 		"new table added with columns": {
 			old: &LogEntry{
@@ -87,6 +112,15 @@ func TestLogEntry_Diff(t *testing.T) {
 				},
 			},
 			want: &SchemaDiff{
+				TablesAdded: []Table{
+					{
+						PgstreamID: "1",
+						Columns: []Column{
+							{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
+							{Name: "col2", PgstreamID: "1-2", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
+						},
+					},
+				},
 				ColumnsToAdd: []Column{
 					{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
 					{Name: "col2", PgstreamID: "1-2", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
@@ -148,7 +182,11 @@ func TestLogEntry_Diff(t *testing.T) {
 					},
 				},
 			},
-			want: nopDiff,
+			want: &SchemaDiff{
+				ColumnsRemoved: []Column{
+					{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
+				},
+			},
 		},
 		"table and columns removed": {
 			old: &LogEntry{
@@ -204,7 +242,61 @@ func TestLogEntry_Diff(t *testing.T) {
 					},
 				},
 			},
-			want: nopDiff,
+			want: &SchemaDiff{
+				ColumnsRenamed: []ColumnChange{
+					{
+						TablePgstreamID: "1",
+						Old:             Column{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
+						New:             Column{Name: "col1-renamed", PgstreamID: "1-1", DataType: "text", Nullable: true, DefaultValue: ptr("a")},
+					},
+				},
+			},
+		},
+		"column type and nullability changed": {
+			old: &LogEntry{
+				Schema: Schema{
+					Tables: []Table{
+						{
+							PgstreamID: "1",
+							Name:       "table1",
+							Columns: []Column{
+								{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true},
+							},
+						},
+					},
+				},
+			},
+			new: &LogEntry{
+				Schema: Schema{
+					Tables: []Table{
+						{
+							PgstreamID: "1",
+							Name:       "table1",
+							Columns: []Column{
+								{Name: "col1", PgstreamID: "1-1", DataType: "integer", Nullable: false},
+							},
+						},
+					},
+				},
+			},
+			want: &SchemaDiff{
+				ColumnTypeChanged: []ColumnChange{
+					{
+						TablePgstreamID: "1",
+						TableName:       "table1",
+						Old:             Column{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true},
+						New:             Column{Name: "col1", PgstreamID: "1-1", DataType: "integer", Nullable: false},
+					},
+				},
+				ColumnNullabilityChanged: []ColumnChange{
+					{
+						TablePgstreamID: "1",
+						TableName:       "table1",
+						Old:             Column{Name: "col1", PgstreamID: "1-1", DataType: "text", Nullable: true},
+						New:             Column{Name: "col1", PgstreamID: "1-1", DataType: "integer", Nullable: false},
+					},
+				},
+			},
 		},
 	}
 
@@ -20,6 +20,9 @@ type Table struct {
 	PrimaryKeyColumns []string `json:"primary_key_columns"`
 	// PgstreamID is a unique identifier of the table generated by pgstream
 	PgstreamID string `json:"pgstream_id"`
+	// ReplicaIdentity is one of `default`, `nothing`, `full` or `index`. It
+	// will be empty for schema log entries created before it was tracked.
+	ReplicaIdentity string `json:"replica_identity,omitempty"`
 }
 
 type Column struct {
@@ -31,8 +34,45 @@ type Column struct {
 	// Metadata is NOT typed here because we don't fully control the content that is sent from the publisher.
 	Metadata   *string `json:"metadata"`
 	PgstreamID string  `json:"pgstream_id"`
+	// TypeInfo describes the underlying type of user defined column types
+	// (enums, domains, ranges and composites). It will be nil for any other
+	// types.
+	TypeInfo *TypeInfo `json:"type_info,omitempty"`
 }
 
+// TypeInfo contains the details of a user defined type, depending on its
+// category.
+type TypeInfo struct {
+	Category string `json:"category"`
+	// EnumValues contains the enum labels, in their sort order
+	EnumValues []string `json:"enum_values,omitempty"`
+	// BaseType is the formatted type the domain is defined over
+	BaseType string `json:"base_type,omitempty"`
+	// RangeSubtype is the formatted type of the range bounds
+	RangeSubtype string `json:"range_subtype,omitempty"`
+	// Fields contains the attributes of the composite type, in order
+	Fields []CompositeField `json:"fields,omitempty"`
+}
+
+type CompositeField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+const (
+	ReplicaIdentityDefault = "default"
+	ReplicaIdentityNothing = "nothing"
+	ReplicaIdentityFull    = "full"
+	ReplicaIdentityIndex   = "index"
+)
+
+const (
+	TypeCategoryEnum      = "enum"
+	TypeCategoryDomain    = "domain"
+	TypeCategoryRange     = "range"
+	TypeCategoryComposite = "composite"
+)
+
 func (s *Schema) MarshalJSON() ([]byte, error) {
 	if s == nil {
 		return nil, nil
@@ -78,7 +118,16 @@ func (s *Schema) Diff(previous *Schema) *SchemaDiff {
 
 	for i, table := range s.Tables {
 		if previousTable := previous.getTableByID(table.PgstreamID); previousTable != nil {
+			if previousTable.Name != table.Name {
+				d.TablesRenamed = append(d.TablesRenamed, TableRename{
+					PgstreamID: table.PgstreamID,
+					OldName:    previousTable.Name,
+					NewName:    table.Name,
+				})
+			}
 			d.ColumnsToAdd = append(d.ColumnsToAdd, diffColumns(&s.Tables[i], previousTable)...)
+			d.ColumnsRemoved = append(d.ColumnsRemoved, diffColumns(previousTable, &s.Tables[i])...)
+			d.diffColumnChanges(previousTable, &s.Tables[i])
 			if hasPrimaryKeyChanged(previousTable.PrimaryKeyColumns, table.PrimaryKeyColumns) {
 				d.PrimaryKeyChange = append(d.PrimaryKeyChange, table.Name)
 			}
@@ -88,6 +137,7 @@ func (s *Schema) Diff(previous *Schema) *SchemaDiff {
 		} else {
 			// if the "old" schema does not have the table, we can add all
 			// columns without checking further.
+			d.TablesAdded = append(d.TablesAdded, table)
 			d.ColumnsToAdd = append(d.ColumnsToAdd, table.Columns...)
 		}
 	}
@@ -145,6 +195,15 @@ func (t *Table) GetColumnByName(name string) *Column {
 	return nil
 }
 
+func (t *Table) getColumnByID(pgstreamID string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].PgstreamID == pgstreamID {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
 // GetFirstUniqueNotNullColumn will return the first unique not null column in
 // the table. It will sort the columns by pgstream ID, and return the first one
 // matching the not null/unique constraints. It uses the pgstream id instead of
@@ -180,19 +239,94 @@ func (c *Column) IsEqual(other *Column) bool {
 			c.PgstreamID == other.PgstreamID &&
 			c.DefaultValue == other.DefaultValue &&
 			c.Unique == other.Unique &&
-			c.Metadata == other.Metadata
+			c.Metadata == other.Metadata &&
+			c.TypeInfo.IsEqual(other.TypeInfo)
+	}
+}
+
+func (t *TypeInfo) IsEqual(other *TypeInfo) bool {
+	switch {
+	case t == nil && other == nil:
+		return true
+	case t == nil && other != nil, t != nil && other == nil:
+		return false
+	default:
+		return t.Category == other.Category &&
+			t.BaseType == other.BaseType &&
+			t.RangeSubtype == other.RangeSubtype &&
+			slices.Equal(t.EnumValues, other.EnumValues) &&
+			slices.Equal(t.Fields, other.Fields)
 	}
 }
 
 type SchemaDiff struct {
-	TablesToRemove      []Table
-	ColumnsToAdd        []Column
-	PrimaryKeyChange    []string
-	UniqueNotNullChange []string
+	TablesToRemove []Table       `json:"tables_to_remove,omitempty"`
+	TablesAdded    []Table       `json:"tables_added,omitempty"`
+	TablesRenamed  []TableRename `json:"tables_renamed,omitempty"`
+	ColumnsToAdd   []Column      `json:"columns_to_add,omitempty"`
+	ColumnsRemoved []Column      `json:"columns_removed,omitempty"`
+	// ColumnsRenamed contains the columns that kept their pgstream ID but
+	// changed their name.
+	ColumnsRenamed           []ColumnChange `json:"columns_renamed,omitempty"`
+	ColumnTypeChanged        []ColumnChange `json:"column_type_changed,omitempty"`
+	ColumnNullabilityChanged []ColumnChange `json:"column_nullability_changed,omitempty"`
+	PrimaryKeyChange         []string       `json:"primary_key_change,omitempty"`
+	UniqueNotNullChange      []string       `json:"unique_not_null_change,omitempty"`
+}
+
+// TableRename identifies a table that kept its pgstream ID but changed its
+// name.
+type TableRename struct {
+	PgstreamID string `json:"pgstream_id"`
+	OldName    string `json:"old_name"`
+	NewName    string `json:"new_name"`
+}
+
+// ColumnChange contains the previous and the current definition of a column
+// that has been modified. Both columns share the same pgstream ID.
+type ColumnChange struct {
+	TablePgstreamID string `json:"table_pgstream_id"`
+	TableName       string `json:"table_name"`
+	Old             Column `json:"old"`
+	New             Column `json:"new"`
 }
 
 func (d *SchemaDiff) Empty() bool {
-	return len(d.TablesToRemove) == 0 && len(d.ColumnsToAdd) == 0
+	return len(d.TablesToRemove) == 0 &&
+		len(d.TablesAdded) == 0 &&
+		len(d.TablesRenamed) == 0 &&
+		len(d.ColumnsToAdd) == 0 &&
+		len(d.ColumnsRemoved) == 0 &&
+		len(d.ColumnsRenamed) == 0 &&
+		len(d.ColumnTypeChanged) == 0 &&
+		len(d.ColumnNullabilityChanged) == 0
+}
+
+// diffColumnChanges will add to the diff the columns that exist in both
+// tables, but have been renamed or had their type/nullability updated.
+func (d *SchemaDiff) diffColumnChanges(old, new *Table) {
+	for _, newCol := range new.Columns {
+		oldCol := old.getColumnByID(newCol.PgstreamID)
+		if oldCol == nil {
+			continue
+		}
+
+		change := ColumnChange{
+			TablePgstreamID: new.PgstreamID,
+			TableName:       new.Name,
+			Old:             *oldCol,
+			New:             newCol,
+		}
+		if oldCol.Name != newCol.Name {
+			d.ColumnsRenamed = append(d.ColumnsRenamed, change)
+		}
+		if oldCol.DataType != newCol.DataType {
+			d.ColumnTypeChanged = append(d.ColumnTypeChanged, change)
+		}
+		if oldCol.Nullable != newCol.Nullable {
+			d.ColumnNullabilityChanged = append(d.ColumnNullabilityChanged, change)
+		}
+	}
 }
 
 func unorderedColumnsEqual(a, b []Column) bool {
@@ -218,6 +352,8 @@ func unorderedColumnsEqual(a, b []Column) bool {
 	return true
 }
 
+// diffColumns returns the columns in the new table that are not present in
+// the old one, using the pgstream ID for comparison.
 func diffColumns(new, old *Table) []Column {
 	var colsAdded []Column
 
@@ -61,6 +61,7 @@ func TestSchema_Diff(t *testing.T) {
 			},
 
 			wantDiff: &SchemaDiff{
+				TablesAdded: []Table{testTable()},
 				ColumnsToAdd: []Column{
 					{PgstreamID: "1_1", Name: "col-1"},
 				},
@@ -128,6 +129,14 @@ func TestSchema_Diff(t *testing.T) {
 			},
 
 			wantDiff: &SchemaDiff{
+				ColumnNullabilityChanged: []ColumnChange{
+					{
+						TablePgstreamID: "1",
+						TableName:       testTableName,
+						Old:             Column{PgstreamID: "1_1", Name: "col-1", Unique: false, Nullable: true},
+						New:             Column{PgstreamID: "1_1", Name: "col-1", Unique: true, Nullable: false},
+					},
+				},
 				UniqueNotNullChange: []string{testTableName},
 			},
 		},
@@ -4,14 +4,26 @@ package stream
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/ApollosProject/pgstream-wal2json/pkg/kafka"
 	kafkacheckpoint "github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer/kafka"
+	fileprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/file"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
+	grpcprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc"
 	kafkaprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/kafka"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/livefeed"
+	natsprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/nats"
+	parquetprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/parquet"
+	pgprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/postgres"
+	redisprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/redis"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/router"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search/store"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/translator"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/wasm"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/notifier"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/server"
 	pgreplication "github.com/ApollosProject/pgstream-wal2json/pkg/wal/replication/postgres"
@@ -29,18 +41,39 @@ type ListenerConfig struct {
 
 type PostgresListenerConfig struct {
 	Replication pgreplication.Config
+	// LosslessNumbers enables the decoding of numeric values without precision
+	// loss.
+	LosslessNumbers bool
 }
 
 type KafkaListenerConfig struct {
 	Reader       kafka.ReaderConfig
 	Checkpointer kafkacheckpoint.Config
+	// LosslessNumbers enables the decoding of numeric values without precision
+	// loss.
+	LosslessNumbers bool
 }
 
 type ProcessorConfig struct {
 	Kafka      *KafkaProcessorConfig
 	Search     *SearchProcessorConfig
 	Webhook    *WebhookProcessorConfig
+	Postgres   *PostgresProcessorConfig
+	File       *FileProcessorConfig
+	Parquet    *ParquetProcessorConfig
+	NATS       *NATSProcessorConfig
+	Redis      *RedisProcessorConfig
+	LiveFeed   *LiveFeedProcessorConfig
+	GRPC       *GRPCProcessorConfig
 	Translator *translator.Config
+	WASM       *wasm.Config
+	// Filter drops the wal data events that don't match its expression before
+	// they reach the processors.
+	Filter *filter.Config
+	// Router sends the wal data events to the processors matching its
+	// routing rules. It is required to use routing rules, but not to run
+	// multiple processors, in which case all events are sent to all of them.
+	Router *router.Config
 }
 
 type KafkaProcessorConfig struct {
@@ -57,12 +90,50 @@ type WebhookProcessorConfig struct {
 	Notifier           notifier.Config
 	SubscriptionServer server.Config
 	SubscriptionStore  WebhookSubscriptionStoreConfig
+	// Outbox configures the delivery outbox, enabled by default. Failed
+	// deliveries are stored in the subscription store database and retried
+	// asynchronously. The default retrier configuration is used if nil.
+	Outbox *notifier.RetrierConfig
+	// DisableOutbox drops the failed deliveries instead of storing them in
+	// the outbox, in which case their events are lost.
+	DisableOutbox bool
+}
+
+type PostgresProcessorConfig struct {
+	BatchWriter pgprocessor.Config
+}
+
+type FileProcessorConfig struct {
+	Writer fileprocessor.Config
+}
+
+type ParquetProcessorConfig struct {
+	Writer parquetprocessor.Config
+}
+
+type NATSProcessorConfig struct {
+	Publisher natsprocessor.Config
+}
+
+type RedisProcessorConfig struct {
+	Writer redisprocessor.Config
+}
+
+type LiveFeedProcessorConfig struct {
+	Server livefeed.Config
+}
+
+type GRPCProcessorConfig struct {
+	Server grpcprocessor.Config
 }
 
 type WebhookSubscriptionStoreConfig struct {
 	URL                  string
 	CacheEnabled         bool
 	CacheRefreshInterval time.Duration
+	// EncryptionKey is the base64 encoded AES key used to encrypt the
+	// subscription credentials at rest.
+	EncryptionKey string
 }
 
 func (c *Config) IsValid() error {
@@ -70,9 +141,86 @@ func (c *Config) IsValid() error {
 		return errors.New("need at least one listener configured")
 	}
 
-	if c.Processor.Kafka == nil && c.Processor.Search == nil && c.Processor.Webhook == nil {
+	processorNames := c.Processor.names()
+	if len(processorNames) == 0 {
 		return errors.New("need at least one processor configured")
 	}
 
+	// the postgres processor replays the schema changes from the schema
+	// change events, which need to be emitted by the translator when
+	// listening on postgres. Without them, the target tables would drift from
+	// the source ones.
+	if c.Processor.Postgres != nil && c.Listener.Postgres != nil &&
+		(c.Processor.Translator == nil || !c.Processor.Translator.EmitSchemaChangeEvents) {
+		return errors.New("the postgres processor requires the translator to be configured with schema change events enabled")
+	}
+
+	if c.Processor.Router != nil {
+		routed := slices.Clone(c.Processor.Router.DefaultProcessors)
+		for _, route := range c.Processor.Router.Routes {
+			routed = append(routed, route.Processors...)
+		}
+		for _, name := range routed {
+			if !slices.Contains(processorNames, name) {
+				return fmt.Errorf("routing rules reference processor %q, which is not configured", name)
+			}
+		}
+	}
+
 	return nil
 }
+
+// names of the processors, used to reference them in the routing rules
+const (
+	kafkaProcessorName    = "kafka"
+	searchProcessorName   = "search"
+	webhookProcessorName  = "webhook"
+	postgresProcessorName = "postgres"
+	fileProcessorName     = "file"
+	parquetProcessorName  = "parquet"
+	natsProcessorName     = "nats"
+	redisProcessorName    = "redis"
+	liveFeedProcessorName = "live_feed"
+	grpcProcessorName     = "grpc"
+)
+
+// names returns the names of the processors configured.
+func (c *ProcessorConfig) names() []string {
+	names := []string{}
+	for name, configured := range map[string]bool{
+		kafkaProcessorName:    c.Kafka != nil,
+		searchProcessorName:   c.Search != nil,
+		webhookProcessorName:  c.Webhook != nil,
+		postgresProcessorName: c.Postgres != nil,
+		fileProcessorName:     c.File != nil,
+		parquetProcessorName:  c.Parquet != nil,
+		natsProcessorName:     c.NATS != nil,
+		redisProcessorName:    c.Redis != nil,
+		liveFeedProcessorName: c.LiveFeed != nil,
+		grpcProcessorName:     c.GRPC != nil,
+	} {
+		if configured {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// expressions returns all the configured CEL filter expressions.
+func (c *ProcessorConfig) expressions() []string {
+	expressions := []string{}
+	if c.Filter != nil {
+		expressions = append(expressions, c.Filter.Expression)
+	}
+	if c.Router != nil {
+		for _, route := range c.Router.Routes {
+			expressions = append(expressions, route.Expression)
+		}
+	}
+	if c.Kafka != nil && c.Kafka.Writer != nil {
+		for _, route := range c.Kafka.Writer.TopicRoutes {
+			expressions = append(expressions, route.Expression)
+		}
+	}
+	return expressions
+}
@@ -128,7 +128,7 @@ func Test_PostgresToSearch(t *testing.T) {
 			}),
 		}
 
-		client, err := opensearch.NewClient(opensearchURL)
+		client, err := opensearch.NewClient(searchstore.ClientConfig{URL: opensearchURL})
 		require.NoError(t, err)
 
 		run(t, cfg, client, "pg2os_integration_test")
@@ -142,7 +142,7 @@ func Test_PostgresToSearch(t *testing.T) {
 			}),
 		}
 
-		client, err := elasticsearch.NewClient(elasticsearchURL)
+		client, err := elasticsearch.NewClient(searchstore.ClientConfig{URL: elasticsearchURL})
 		require.NoError(t, err)
 
 		run(t, cfg, client, "pg2es_integration_test")
@@ -11,19 +11,32 @@ import (
 	kafkainstrumentation "github.com/ApollosProject/pgstream-wal2json/pkg/kafka/instrumentation"
 	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/otel"
+	pgschemalog "github.com/ApollosProject/pgstream-wal2json/pkg/schemalog/postgres"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
 	kafkacheckpoint "github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer/kafka"
 	pgcheckpoint "github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer/postgres"
 	kafkalistener "github.com/ApollosProject/pgstream-wal2json/pkg/wal/listener/kafka"
 	pglistener "github.com/ApollosProject/pgstream-wal2json/pkg/wal/listener/postgres"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	fileprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/file"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
+	grpcprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc"
 	processinstrumentation "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/instrumentation"
 	kafkaprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/kafka"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/livefeed"
+	natsprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/nats"
+	parquetprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/parquet"
+	pgprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/postgres"
+	redisprocessor "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/redis"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/router"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search"
 	searchinstrumentation "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search/instrumentation"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search/store"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/translator"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/wasm"
 	webhooknotifier "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/notifier"
+	pgoutbox "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store/postgres"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
 	subscriptionserver "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/server"
 	webhookstore "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
 	subscriptionstorecache "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store/cache"
@@ -41,6 +54,11 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 		return fmt.Errorf("incompatible configuration: %w", err)
 	}
 
+	// the expressions are validated before any of the processes start
+	if err := validateExpressions(ctx, config, logger); err != nil {
+		return fmt.Errorf("invalid filter expressions: %w", err)
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 
 	var replicationHandler replication.Handler
@@ -105,11 +123,36 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 
 	// Processor
 
-	var processor processor.Processor
-	switch {
-	case config.Processor.Kafka != nil:
+	// the router is used to send the events to multiple processors, and only
+	// checkpoint their positions once all the processors have processed them
+	var walRouter *router.Router
+	if config.Processor.Router != nil || len(config.Processor.names()) > 1 {
+		routerCfg := config.Processor.Router
+		if routerCfg == nil {
+			routerCfg = &router.Config{}
+		}
+		var err error
+		walRouter, err = router.New(routerCfg,
+			router.WithCheckpoint(checkpoint),
+			router.WithLogger(logger),
+		)
+		if err != nil {
+			return fmt.Errorf("error creating processor router: %w", err)
+		}
+	}
+
+	processorCheckpoint := func(name string) checkpointer.Checkpoint {
+		if walRouter != nil {
+			return walRouter.Checkpoint(name)
+		}
+		return checkpoint
+	}
+
+	processors := map[string]processor.Processor{}
+
+	if config.Processor.Kafka != nil {
 		opts := []kafkaprocessor.Option{
-			kafkaprocessor.WithCheckpoint(checkpoint),
+			kafkaprocessor.WithCheckpoint(processorCheckpoint(kafkaProcessorName)),
 			kafkaprocessor.WithLogger(logger),
 		}
 		if instrumentation.IsEnabled() {
@@ -120,7 +163,7 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 			return err
 		}
 		defer kafkaWriter.Close()
-		processor = kafkaWriter
+		processors[kafkaProcessorName] = kafkaWriter
 
 		// the kafka batch writer requires to initialise a go routine to send
 		// the batches asynchronously
@@ -128,7 +171,9 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 			logger.Info("running kafka batch writer...")
 			return kafkaWriter.Send(ctx)
 		})
-	case config.Processor.Search != nil:
+	}
+
+	if config.Processor.Search != nil {
 		var searchStore search.Store
 		var err error
 		searchStore, err = store.NewStore(config.Processor.Search.Store, store.WithLogger(logger))
@@ -147,11 +192,11 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 			config.Processor.Search.Indexer,
 			searchStore,
 			pgreplication.NewLSNParser(),
-			search.WithCheckpoint(checkpoint),
+			search.WithCheckpoint(processorCheckpoint(searchProcessorName)),
 			search.WithLogger(logger),
 		)
 		defer searchIndexer.Close()
-		processor = searchIndexer
+		processors[searchProcessorName] = searchIndexer
 
 		// the search batch indexer requires to initialise a go routine to send
 		// the batches asynchronously
@@ -159,13 +204,15 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 			logger.Info("running search batch indexer...")
 			return searchIndexer.Send(ctx)
 		})
+	}
 
-	case config.Processor.Webhook != nil:
+	if config.Processor.Webhook != nil {
 		var subscriptionStore webhookstore.Store
 		var err error
 		subscriptionStore, err = pgwebhook.NewSubscriptionStore(ctx,
 			config.Processor.Webhook.SubscriptionStore.URL,
 			pgwebhook.WithLogger(logger),
+			pgwebhook.WithEncryptionKey(config.Processor.Webhook.SubscriptionStore.EncryptionKey),
 		)
 		if err != nil {
 			return err
@@ -183,18 +230,56 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 			}
 		}
 
+		// the delivery stats are shared between the notifier, the retrier and
+		// the subscription server
+		subscriptionStats := subscription.NewStatsRegistry()
+		notifierOpts := []webhooknotifier.Option{
+			webhooknotifier.WithLogger(logger),
+			webhooknotifier.WithCheckpoint(processorCheckpoint(webhookProcessorName)),
+			webhooknotifier.WithStats(subscriptionStats),
+		}
+		serverOpts := []subscriptionserver.Option{
+			subscriptionserver.WithLogger(logger),
+			subscriptionserver.WithStats(subscriptionStats),
+		}
+		if !config.Processor.Webhook.DisableOutbox {
+			logger.Info("setting up webhook delivery outbox...")
+			outboxStore, err := pgoutbox.NewOutboxStore(ctx,
+				config.Processor.Webhook.SubscriptionStore.URL,
+				pgoutbox.WithLogger(logger),
+			)
+			if err != nil {
+				return err
+			}
+			notifierOpts = append(notifierOpts, webhooknotifier.WithOutbox(outboxStore))
+			serverOpts = append(serverOpts, subscriptionserver.WithOutbox(outboxStore))
+
+			retrierCfg := config.Processor.Webhook.Outbox
+			if retrierCfg == nil {
+				retrierCfg = &webhooknotifier.RetrierConfig{}
+			}
+			retrier := webhooknotifier.NewRetrier(retrierCfg,
+				outboxStore,
+				subscriptionStore,
+				webhooknotifier.WithRetrierLogger(logger),
+				webhooknotifier.WithRetrierStats(subscriptionStats))
+			eg.Go(func() error {
+				logger.Info("running webhook delivery retrier...")
+				return retrier.Run(ctx)
+			})
+		}
+
 		notifier := webhooknotifier.New(
 			&config.Processor.Webhook.Notifier,
 			subscriptionStore,
-			webhooknotifier.WithLogger(logger),
-			webhooknotifier.WithCheckpoint(checkpoint))
+			notifierOpts...)
 		defer notifier.Close()
-		processor = notifier
+		processors[webhookProcessorName] = notifier
 
 		subscriptionServer := subscriptionserver.New(
 			&config.Processor.Webhook.SubscriptionServer,
 			subscriptionStore,
-			subscriptionserver.WithLogger(logger))
+			serverOpts...)
 
 		eg.Go(func() error {
 			logger.Info("running subscription server...")
@@ -206,11 +291,199 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 			logger.Info("running webhook notifier...")
 			return notifier.Notify(ctx)
 		})
+	}
+
+	if config.Processor.Postgres != nil {
+		pgWriter, err := pgprocessor.NewBatchWriter(ctx,
+			&config.Processor.Postgres.BatchWriter,
+			pgprocessor.WithCheckpoint(processorCheckpoint(postgresProcessorName)),
+			pgprocessor.WithLogger(logger),
+		)
+		if err != nil {
+			return err
+		}
+		defer pgWriter.Close()
+		processors[postgresProcessorName] = pgWriter
+
+		// the postgres batch writer requires to initialise a go routine to
+		// send the batches asynchronously
+		eg.Go(func() error {
+			logger.Info("running postgres batch writer...")
+			return pgWriter.Send(ctx)
+		})
+	}
+
+	if config.Processor.File != nil {
+		fileWriter, err := fileprocessor.NewBatchWriter(
+			&config.Processor.File.Writer,
+			fileprocessor.WithCheckpoint(processorCheckpoint(fileProcessorName)),
+			fileprocessor.WithLogger(logger),
+		)
+		if err != nil {
+			return err
+		}
+		defer fileWriter.Close()
+		processors[fileProcessorName] = fileWriter
+
+		// the file batch writer requires to initialise a go routine to write
+		// the batches asynchronously
+		eg.Go(func() error {
+			logger.Info("running file batch writer...")
+			return fileWriter.Send(ctx)
+		})
+	}
+
+	if config.Processor.Parquet != nil {
+		parquetWriter, err := parquetprocessor.NewBatchWriter(ctx,
+			&config.Processor.Parquet.Writer,
+			parquetprocessor.WithCheckpoint(processorCheckpoint(parquetProcessorName)),
+			parquetprocessor.WithLogger(logger),
+		)
+		if err != nil {
+			return err
+		}
+		defer parquetWriter.Close()
+		processors[parquetProcessorName] = parquetWriter
+
+		// the parquet batch writer requires to initialise a go routine to
+		// write and commit the files asynchronously
+		eg.Go(func() error {
+			logger.Info("running parquet batch writer...")
+			return parquetWriter.Send(ctx)
+		})
+	}
+
+	if config.Processor.NATS != nil {
+		natsPublisher, err := natsprocessor.NewPublisher(
+			&config.Processor.NATS.Publisher,
+			natsprocessor.WithCheckpoint(processorCheckpoint(natsProcessorName)),
+			natsprocessor.WithLogger(logger),
+		)
+		if err != nil {
+			return err
+		}
+		defer natsPublisher.Close()
+		processors[natsProcessorName] = natsPublisher
 
+		// the nats jetstream publisher requires to initialise a go routine to
+		// publish the messages and wait for their acks asynchronously
+		eg.Go(func() error {
+			logger.Info("running nats jetstream publisher...")
+			return natsPublisher.Send(ctx)
+		})
+	}
+
+	if config.Processor.Redis != nil {
+		redisWriter, err := redisprocessor.NewBatchWriter(
+			&config.Processor.Redis.Writer,
+			redisprocessor.WithCheckpoint(processorCheckpoint(redisProcessorName)),
+			redisprocessor.WithLogger(logger),
+		)
+		if err != nil {
+			return err
+		}
+		defer redisWriter.Close()
+		processors[redisProcessorName] = redisWriter
+
+		// the redis batch writer requires to initialise a go routine to send
+		// the pipelines asynchronously
+		eg.Go(func() error {
+			logger.Info("running redis batch writer...")
+			return redisWriter.Send(ctx)
+		})
+	}
+
+	if config.Processor.LiveFeed != nil {
+		liveFeed := livefeed.New(
+			&config.Processor.LiveFeed.Server,
+			livefeed.WithCheckpoint(processorCheckpoint(liveFeedProcessorName)),
+			livefeed.WithLogger(logger),
+		)
+		defer liveFeed.Close()
+		processors[liveFeedProcessorName] = liveFeed
+
+		eg.Go(func() error {
+			logger.Info("running live feed server...")
+			go liveFeed.Start()
+			<-ctx.Done()
+			return liveFeed.Shutdown(ctx)
+		})
+		eg.Go(func() error {
+			logger.Info("running live feed checkpointer...")
+			return liveFeed.Send(ctx)
+		})
+	}
+
+	if config.Processor.GRPC != nil {
+		grpcServer, err := grpcprocessor.New(
+			&config.Processor.GRPC.Server,
+			grpcprocessor.WithCheckpoint(processorCheckpoint(grpcProcessorName)),
+			grpcprocessor.WithLogger(logger),
+		)
+		if err != nil {
+			return err
+		}
+		defer grpcServer.Close()
+		processors[grpcProcessorName] = grpcServer
+
+		eg.Go(func() error {
+			logger.Info("running grpc server...")
+			go grpcServer.Start()
+			<-ctx.Done()
+			return grpcServer.Shutdown(ctx)
+		})
+		eg.Go(func() error {
+			logger.Info("running grpc server checkpointer...")
+			return grpcServer.Send(ctx)
+		})
+	}
+
+	var processor processor.Processor
+	switch {
+	case walRouter != nil:
+		logger.Info("routing events to processors...")
+		if err := walRouter.SetTargets(processors); err != nil {
+			return fmt.Errorf("error setting up processor router: %w", err)
+		}
+		processor = walRouter
+	case len(processors) == 1:
+		for _, p := range processors {
+			processor = p
+		}
 	default:
 		return errors.New("no processor found")
 	}
 
+	// the transforms are applied after the translation, so that the modules
+	// receive the events with the pgstream metadata
+	if config.Processor.WASM != nil {
+		logger.Info("adding wasm transforms to processor...")
+		opts := []wasm.Option{
+			wasm.WithLogger(logger),
+		}
+		if (config.Listener.Postgres != nil && config.Listener.Postgres.LosslessNumbers) ||
+			(config.Listener.Kafka != nil && config.Listener.Kafka.LosslessNumbers) {
+			opts = append(opts, wasm.WithLosslessNumbers())
+		}
+		transformer, err := wasm.New(ctx, config.Processor.WASM, processor, opts...)
+		if err != nil {
+			return fmt.Errorf("error creating processor wasm transform layer: %w", err)
+		}
+		defer transformer.Close()
+		processor = transformer
+	}
+
+	// the filter is applied before the transforms, so that the modules don't
+	// process events that will be dropped
+	if config.Processor.Filter != nil {
+		logger.Info("adding filter to processor...")
+		walFilter, err := filter.New(config.Processor.Filter, processor, filter.WithLogger(logger))
+		if err != nil {
+			return fmt.Errorf("error creating processor filter layer: %w", err)
+		}
+		processor = walFilter
+	}
+
 	if config.Processor.Translator != nil {
 		logger.Info("adding translation to processor...")
 		opts := []translator.Option{
@@ -239,9 +512,13 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 
 	switch {
 	case config.Listener.Postgres != nil:
+		opts := []pglistener.Option{pglistener.WithLogger(logger)}
+		if config.Listener.Postgres.LosslessNumbers {
+			opts = append(opts, pglistener.WithLosslessNumbers())
+		}
 		listener := pglistener.New(replicationHandler,
 			processor.ProcessWALEvent,
-			pglistener.WithLogger(logger))
+			opts...)
 		defer listener.Close()
 
 		eg.Go(func() error {
@@ -250,10 +527,14 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 		})
 	case config.Listener.Kafka != nil:
 		var err error
+		opts := []kafkalistener.Option{kafkalistener.WithLogger(logger)}
+		if config.Listener.Kafka.LosslessNumbers {
+			opts = append(opts, kafkalistener.WithLosslessNumbers())
+		}
 		listener, err := kafkalistener.NewWALReader(
 			kafkaReader,
 			processor.ProcessWALEvent,
-			kafkalistener.WithLogger(logger))
+			opts...)
 		if err != nil {
 			return err
 		}
@@ -273,3 +554,32 @@ func Run(ctx context.Context, logger loglib.Logger, config *Config, instrumentat
 
 	return nil
 }
+
+// validateExpressions validates the filter and routing expressions against
+// the schema log, when the translator schema log store is configured.
+func validateExpressions(ctx context.Context, config *Config, logger loglib.Logger) error {
+	expressions := []*filter.Expression{}
+	for _, source := range config.Processor.expressions() {
+		expr, err := filter.Compile(source)
+		if err != nil {
+			return err
+		}
+		expressions = append(expressions, expr)
+	}
+
+	if len(expressions) == 0 {
+		return nil
+	}
+	if config.Processor.Translator == nil {
+		logger.Warn(nil, "schema log store not configured, skipping filter expressions schema validation")
+		return nil
+	}
+
+	store, err := pgschemalog.NewStore(ctx, config.Processor.Translator.Store)
+	if err != nil {
+		return fmt.Errorf("creating schema log store: %w", err)
+	}
+	defer store.Close()
+
+	return filter.ValidateSchema(ctx, store, expressions...)
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type ServerConfig struct {
+	// CertFile and KeyFile are the paths to the PEM encoded server
+	// certificate and private key.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the path to the PEM encoded CA certificates used to
+	// verify client certificates. When set, clients must present a valid
+	// certificate.
+	ClientCAFile string
+}
+
+var ErrInvalidClientCA = errors.New("no valid certificates found in client CA file")
+
+// NewServerConfig returns the TLS configuration for a server, requiring and
+// verifying the client certificates when a client CA is provided.
+func NewServerConfig(cfg *ServerConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA file: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, ErrInvalidClientCA
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
@@ -165,6 +165,99 @@ func Test_NewConfig(t *testing.T) {
 	}
 }
 
+func Test_NewServerConfig(t *testing.T) {
+	t.Parallel()
+
+	testPEMBytes, err := os.ReadFile("test/test.pem")
+	require.NoError(t, err)
+	testCertPool := x509.NewCertPool()
+	testCertPool.AppendCertsFromPEM(testPEMBytes)
+
+	testKeyPair, err := tls.LoadX509KeyPair("test/test.pem", "test/test.key")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cfg  *ServerConfig
+
+		wantConfig *tls.Config
+		wantErr    error
+	}{
+		{
+			name: "ok - server certificate",
+			cfg: &ServerConfig{
+				CertFile: "test/test.pem",
+				KeyFile:  "test/test.key",
+			},
+
+			wantConfig: &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{testKeyPair},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ok - server certificate with client CA",
+			cfg: &ServerConfig{
+				CertFile:     "test/test.pem",
+				KeyFile:      "test/test.key",
+				ClientCAFile: "test/test.pem",
+			},
+
+			wantConfig: &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{testKeyPair},
+				ClientCAs:    testCertPool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error - invalid server certificate file",
+			cfg: &ServerConfig{
+				CertFile: "test/doesnotexist.pem",
+				KeyFile:  "test/test.key",
+			},
+
+			wantConfig: nil,
+			wantErr:    os.ErrNotExist,
+		},
+		{
+			name: "error - invalid client CA file",
+			cfg: &ServerConfig{
+				CertFile:     "test/test.pem",
+				KeyFile:      "test/test.key",
+				ClientCAFile: "test/doesnotexist.pem",
+			},
+
+			wantConfig: nil,
+			wantErr:    os.ErrNotExist,
+		},
+		{
+			name: "error - no certificates in client CA file",
+			cfg: &ServerConfig{
+				CertFile:     "test/test.pem",
+				KeyFile:      "test/test.key",
+				ClientCAFile: "test/test.key",
+			},
+
+			wantConfig: nil,
+			wantErr:    ErrInvalidClientCA,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tlsCfg, err := NewServerConfig(tc.cfg)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, "", cmp.Diff(tlsCfg, tc.wantConfig, cmpopts.IgnoreUnexported(tls.Config{}))) //nolint:gosec
+		})
+	}
+}
+
 func Test_readPEMBytes(t *testing.T) {
 	t.Parallel()
 
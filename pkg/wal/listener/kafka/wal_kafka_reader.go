@@ -58,6 +58,15 @@ func WithLogger(logger loglib.Logger) Option {
 	}
 }
 
+// WithLosslessNumbers makes the reader decode the wal event numeric values
+// without losing precision. Integer columns are decoded as int64, and any other
+// numeric values are kept as json.Number.
+func WithLosslessNumbers() Option {
+	return func(r *Reader) {
+		r.unmarshaler = wal.UnmarshalLossless
+	}
+}
+
 func (r *Reader) Listen(ctx context.Context) error {
 	for {
 		select {
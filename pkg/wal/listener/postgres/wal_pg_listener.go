@@ -67,6 +67,15 @@ func WithLogger(logger loglib.Logger) Option {
 	}
 }
 
+// WithLosslessNumbers makes the listener decode the wal2json numeric values
+// without losing precision. Integer columns are decoded as int64, and any other
+// numeric values are kept as json.Number.
+func WithLosslessNumbers() Option {
+	return func(l *Listener) {
+		l.walDataDeserialiser = wal.UnmarshalLossless
+	}
+}
+
 // Listen starts the subscription process to listen for updates from PG.
 func (l *Listener) Listen(ctx context.Context) error {
 	if err := l.replicationHandler.StartReplication(ctx); err != nil {
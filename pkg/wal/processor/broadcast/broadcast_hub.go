@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package broadcast
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+)
+
+// Event is a wal event broadcasted to the clients, with a payload already
+// encoded for the transport in use. The sequence is used by clients to resume
+// the stream, since several events can share the same LSN (i.e, a schema
+// change event and the schema log insert it was generated from).
+type Event[T any] struct {
+	Seq     uint64
+	Action  string
+	Schema  string
+	Table   string
+	Payload T
+}
+
+type Client[T any] struct {
+	filter *subscription.Subscription
+	events chan *Event[T]
+}
+
+// Events returns the channel of events for the client. The channel is closed
+// when the client is dropped for being too slow, or unsubscribed.
+func (c *Client[T]) Events() <-chan *Event[T] {
+	return c.events
+}
+
+func (c *Client[T]) isFor(e *Event[T]) bool {
+	return c.filter.IsFor(e.Action, e.Schema, e.Table)
+}
+
+// Hub keeps track of the connected clients and the ring buffer of recent
+// events. Both are updated under the same lock, so that clients resuming from
+// the buffer don't miss or duplicate any events broadcasted while subscribing.
+type Hub[T any] struct {
+	mu               sync.Mutex
+	clients          map[*Client[T]]struct{}
+	buffer           *ringBuffer[T]
+	clientBufferSize int
+	seq              atomic.Uint64
+}
+
+func NewHub[T any](bufferSize, clientBufferSize int) *Hub[T] {
+	h := &Hub[T]{
+		clients:          map[*Client[T]]struct{}{},
+		buffer:           newRingBuffer[T](bufferSize),
+		clientBufferSize: clientBufferSize,
+	}
+	// the sequence starts from the current time so that it keeps increasing
+	// across restarts. Clients resuming from a sequence of a previous process
+	// receive all the buffered events.
+	h.seq.Store(uint64(time.Now().UnixNano()))
+	return h
+}
+
+// NextSequence returns the sequence for the next event to be broadcasted.
+// Events need to be broadcasted in the order of their sequence.
+func (h *Hub[T]) NextSequence() uint64 {
+	return h.seq.Add(1)
+}
+
+// Broadcast sends the event to all the clients subscribed to it. It never
+// blocks: clients whose queue is full are dropped, and need to reconnect to
+// resume the stream. It returns the number of dropped clients.
+func (h *Hub[T]) Broadcast(e *Event[T]) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buffer.add(e)
+
+	dropped := 0
+	for c := range h.clients {
+		if !c.isFor(e) {
+			continue
+		}
+		select {
+		case c.events <- e:
+		default:
+			h.remove(c)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Subscribe registers a new client for the filter on input. If a last sequence
+// is provided, the buffered events after it that match the filter are
+// returned, so that they can be sent before the new events.
+func (h *Hub[T]) Subscribe(filter *subscription.Subscription, lastSeq *uint64) (*Client[T], []*Event[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c := &Client[T]{
+		filter: filter,
+		events: make(chan *Event[T], h.clientBufferSize),
+	}
+	h.clients[c] = struct{}{}
+
+	if lastSeq == nil {
+		return c, nil
+	}
+
+	replay := []*Event[T]{}
+	for _, e := range h.buffer.since(*lastSeq) {
+		if c.isFor(e) {
+			replay = append(replay, e)
+		}
+	}
+	return c, replay
+}
+
+func (h *Hub[T]) Unsubscribe(c *Client[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(c)
+}
+
+func (h *Hub[T]) remove(c *Client[T]) {
+	if _, found := h.clients[c]; !found {
+		return
+	}
+	delete(h.clients, c)
+	// closing the channel notifies the client handler
+	close(c.events)
+}
+
+func (h *Hub[T]) ClientCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// ringBuffer keeps the most recent events, up to its size.
+type ringBuffer[T any] struct {
+	events []*Event[T]
+	next   int
+	full   bool
+}
+
+func newRingBuffer[T any](size int) *ringBuffer[T] {
+	return &ringBuffer[T]{
+		events: make([]*Event[T], size),
+	}
+}
+
+func (r *ringBuffer[T]) add(e *Event[T]) {
+	r.events[r.next] = e
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// since returns the buffered events with a sequence after the one on input, in
+// the order they were added. If the sequence is older than the oldest buffered
+// event, some events might have been missed.
+func (r *ringBuffer[T]) since(seq uint64) []*Event[T] {
+	ordered := r.events[:r.next]
+	if r.full {
+		ordered = append(append([]*Event[T]{}, r.events[r.next:]...), r.events[:r.next]...)
+	}
+
+	for i, e := range ordered {
+		if e.Seq > seq {
+			return append([]*Event[T]{}, ordered[i:]...)
+		}
+	}
+	return []*Event[T]{}
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package broadcast
+
+import (
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBuffer_since(t *testing.T) {
+	t.Parallel()
+
+	newEvents := func(seqs ...uint64) []*Event[string] {
+		events := make([]*Event[string], 0, len(seqs))
+		for _, seq := range seqs {
+			events = append(events, &Event[string]{Seq: seq})
+		}
+		return events
+	}
+
+	tests := []struct {
+		name    string
+		size    int
+		added   []*Event[string]
+		lastSeq uint64
+
+		wantEvents []*Event[string]
+	}{
+		{
+			name:    "ok - not full",
+			size:    5,
+			added:   newEvents(1, 2, 3),
+			lastSeq: 1,
+
+			wantEvents: newEvents(2, 3),
+		},
+		{
+			name:    "ok - wrapped around",
+			size:    3,
+			added:   newEvents(1, 2, 3, 4, 5),
+			lastSeq: 3,
+
+			wantEvents: newEvents(4, 5),
+		},
+		{
+			name:    "ok - sequence older than buffer",
+			size:    3,
+			added:   newEvents(1, 2, 3, 4, 5),
+			lastSeq: 1,
+
+			wantEvents: newEvents(3, 4, 5),
+		},
+		{
+			name:    "ok - up to date",
+			size:    3,
+			added:   newEvents(1, 2),
+			lastSeq: 2,
+
+			wantEvents: []*Event[string]{},
+		},
+		{
+			name:    "ok - empty",
+			size:    3,
+			lastSeq: 2,
+
+			wantEvents: []*Event[string]{},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer := newRingBuffer[string](tc.size)
+			for _, e := range tc.added {
+				buffer.add(e)
+			}
+			require.Equal(t, tc.wantEvents, buffer.since(tc.lastSeq))
+		})
+	}
+}
+
+func TestHub(t *testing.T) {
+	t.Parallel()
+
+	h := NewHub[string](10, 1)
+
+	// the events with the same LSN are identified by their sequence
+	schemaLogInsert := &Event[string]{Seq: h.NextSequence(), Action: "I", Schema: "pgstream", Table: "schema_log"}
+	usersDDL := &Event[string]{Seq: h.NextSequence(), Action: "DDL", Schema: "public"}
+	usersInsert := &Event[string]{Seq: h.NextSequence(), Action: "I", Schema: "public", Table: "users"}
+	teamsInsert := &Event[string]{Seq: h.NextSequence(), Action: "I", Schema: "public", Table: "teams"}
+	usersDelete := &Event[string]{Seq: h.NextSequence(), Action: "D", Schema: "public", Table: "users"}
+	require.Less(t, schemaLogInsert.Seq, usersDDL.Seq)
+
+	h.Broadcast(schemaLogInsert)
+	h.Broadcast(usersDDL)
+	h.Broadcast(usersInsert)
+	h.Broadcast(teamsInsert)
+
+	// resuming client gets the buffered events matching the filter
+	publicClient, replay := h.Subscribe(&subscription.Subscription{Schema: "public"}, &schemaLogInsert.Seq)
+	require.Equal(t, []*Event[string]{usersDDL, usersInsert, teamsInsert}, replay)
+	h.Unsubscribe(publicClient)
+
+	usersClient, replay := h.Subscribe(&subscription.Subscription{Table: "users"}, &usersDDL.Seq)
+	require.Equal(t, []*Event[string]{usersInsert}, replay)
+
+	deletesClient, replay := h.Subscribe(&subscription.Subscription{EventTypes: []string{"D"}}, nil)
+	require.Nil(t, replay)
+	require.Equal(t, 2, h.ClientCount())
+
+	require.Equal(t, 0, h.Broadcast(usersDelete))
+	require.Equal(t, usersDelete, <-usersClient.Events())
+	require.Equal(t, usersDelete, <-deletesClient.Events())
+
+	// the events not matching the filter are not sent, so they don't count
+	// towards the client queue
+	require.Equal(t, 0, h.Broadcast(teamsInsert))
+
+	// slow clients are dropped instead of blocking the broadcast
+	require.Equal(t, 0, h.Broadcast(usersInsert))
+	require.Equal(t, 1, h.Broadcast(usersInsert))
+	require.Equal(t, 1, h.ClientCount())
+	require.Equal(t, usersInsert, <-usersClient.Events())
+	_, ok := <-usersClient.Events()
+	require.False(t, ok)
+
+	// unsubscribing a dropped client is a no-op
+	h.Unsubscribe(usersClient)
+	h.Unsubscribe(deletesClient)
+	require.Equal(t, 0, h.ClientCount())
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+type Config struct {
+	// Stdout enables writing the events to the standard output instead of
+	// files. Rotation and compression don't apply to the standard output.
+	Stdout bool
+	// Dir is the directory where the files are written.
+	Dir string
+	// FilenameTemplate is the text/template used to generate the file path,
+	// relative to the directory. The `Schema`, `Table`, `Date` (2006-01-02)
+	// and `Hour` (15) fields are available. The rotation suffix and the file
+	// extension are appended to the generated path. Defaults to
+	// `{{.Schema}}/{{.Table}}/{{.Date}}`.
+	FilenameTemplate string
+	// MaxFileBytes is the size in bytes after which a file is rotated. Defaults
+	// to 100MiB.
+	MaxFileBytes int64
+	// MaxFileAge is the time interval after which a file is rotated. Defaults
+	// to 1h.
+	MaxFileAge time.Duration
+	// Compression is the compression applied to the files. One of `gzip` or
+	// `zstd`. Defaults to no compression.
+	Compression string
+	// BatchSize is the max number of wal events accumulated before triggering
+	// a write. Defaults to 100
+	BatchSize int
+	// BatchTime is the max time interval at which the batch write is
+	// triggered. Defaults to 1s
+	BatchTime time.Duration
+	// MaxQueueBytes is the max memory used by the file writer for inflight
+	// batches. Defaults to 100MiB
+	MaxQueueBytes int64
+}
+
+const (
+	defaultFilenameTemplate = "{{.Schema}}/{{.Table}}/{{.Date}}"
+	defaultMaxFileBytes     = int64(100 * 1024 * 1024) // 100MiB
+	defaultMaxFileAge       = time.Hour
+	defaultMaxQueueBytes    = int64(100 * 1024 * 1024) // 100MiB
+	defaultBatchSize        = 100
+	defaultBatchTime        = time.Second
+
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+var errNoOutput = errors.New("either stdout or a directory must be configured")
+
+func (c *Config) validate() error {
+	if !c.Stdout && c.Dir == "" {
+		return errNoOutput
+	}
+	switch c.Compression {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+	default:
+		return fmt.Errorf("unsupported compression: %s", c.Compression)
+	}
+	return nil
+}
+
+func (c *Config) filenameTemplate() (*template.Template, error) {
+	tmpl := c.FilenameTemplate
+	if tmpl == "" {
+		tmpl = defaultFilenameTemplate
+	}
+	t, err := template.New("filename").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing filename template: %w", err)
+	}
+	return t, nil
+}
+
+func (c *Config) maxFileBytes() int64 {
+	if c.MaxFileBytes > 0 {
+		return c.MaxFileBytes
+	}
+	return defaultMaxFileBytes
+}
+
+func (c *Config) maxFileAge() time.Duration {
+	if c.MaxFileAge > 0 {
+		return c.MaxFileAge
+	}
+	return defaultMaxFileAge
+}
+
+func (c *Config) batchSize() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return defaultBatchSize
+}
+
+func (c *Config) batchTime() time.Duration {
+	if c.BatchTime > 0 {
+		return c.BatchTime
+	}
+	return defaultBatchTime
+}
+
+func (c *Config) maxQueueBytes() int64 {
+	if c.MaxQueueBytes > 0 {
+		return c.MaxQueueBytes
+	}
+	return defaultMaxQueueBytes
+}
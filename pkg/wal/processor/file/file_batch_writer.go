@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+)
+
+// BatchWriter is a wal processor that writes the wal events as JSON lines to
+// the standard output or to local files.
+type BatchWriter struct {
+	output output
+	logger loglib.Logger
+
+	// queueBytesSema is used to limit the amount of memory used by the
+	// unbuffered msg channel, optimising the channel performance for variable
+	// size messages, while preventing the process from running oom
+	queueBytesSema synclib.WeightedSemaphore
+	msgChan        chan (*msg)
+
+	batchSize         int
+	batchSendInterval time.Duration
+
+	// checkpoint callback to mark what was safely stored
+	checkpoint checkpointer.Checkpoint
+
+	serialiser func(any) ([]byte, error)
+}
+
+type Option func(*BatchWriter)
+
+// NewBatchWriter returns a processor of wal events that writes them as JSON
+// lines to the output configured.
+func NewBatchWriter(config *Config, opts ...Option) (*BatchWriter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	var out output = newStdoutOutput()
+	if !config.Stdout {
+		var err error
+		out, err = newDirOutput(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w := &BatchWriter{
+		output:            out,
+		logger:            loglib.NewNoopLogger(),
+		batchSize:         config.batchSize(),
+		batchSendInterval: config.batchTime(),
+		msgChan:           make(chan *msg),
+		queueBytesSema:    synclib.NewWeightedSemaphore(config.maxQueueBytes()),
+		serialiser:        json.Marshal,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(w *BatchWriter) {
+		w.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "file_batch_writer",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(w *BatchWriter) {
+		w.checkpoint = c
+	}
+}
+
+// ProcessWALEvent is called on every new message from the wal. The function
+// is responsible for queueing the serialised event, which will be written as
+// part of a batch.
+func (w *BatchWriter) ProcessWALEvent(ctx context.Context, event *wal.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    event.Data,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+			err = fmt.Errorf("file batch writer: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	fileMsg := &msg{
+		pos: event.CommitPosition,
+	}
+
+	if event.Data != nil {
+		dataBytes, err := w.serialiser(event.Data)
+		if err != nil {
+			return fmt.Errorf("marshalling event: %w", err)
+		}
+		fileMsg.line = append(dataBytes, '\n')
+		fileMsg.schema = event.Data.Schema
+		fileMsg.table = event.Data.Table
+	}
+
+	// make sure we don't reach the queue memory limit before adding the new
+	// message to the channel. This will block until messages have been read
+	// from the channel and their size is released
+	msgSize := int64(fileMsg.size())
+	if !w.queueBytesSema.TryAcquire(msgSize) {
+		w.logger.Warn(nil, "file batch writer: max queue bytes reached, processing blocked")
+		if err := w.queueBytesSema.Acquire(ctx, msgSize); err != nil {
+			return err
+		}
+	}
+	w.msgChan <- fileMsg
+
+	return nil
+}
+
+func (w *BatchWriter) Send(ctx context.Context) error {
+	// make sure we write on a separate go routine to isolate the IO operations
+	// and minimise the wait time between batch sending while continuously
+	// building new batches.
+	batchChan := make(chan *msgBatch)
+	defer close(batchChan)
+	sendErrChan := make(chan error, 1)
+	go func() {
+		defer close(sendErrChan)
+		for batch := range batchChan {
+			// If the send fails, this goroutine returns an error over the error channel and shuts down.
+			err := w.sendBatch(ctx, batch)
+			w.queueBytesSema.Release(int64(batch.totalBytes))
+			if err != nil {
+				w.logger.Error(err, "file batch writer")
+				sendErrChan <- err
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(w.batchSendInterval)
+	defer ticker.Stop()
+	msgBatch := &msgBatch{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sendErr := <-sendErrChan:
+			// if there's an error while sending the batch, return the error and
+			// stop sending batches
+			return sendErr
+		case <-ticker.C:
+			if !msgBatch.isEmpty() {
+				batchChan <- msgBatch.drain()
+			}
+		case msg := <-w.msgChan:
+			msgBatch.add(msg)
+			// trigger a send if we reached the configured batch size or if the
+			// event was a keep alive, so that it's checkpointed as soon as
+			// possible.
+			if msgBatch.size() >= w.batchSize || msg.isKeepAlive() {
+				batchChan <- msgBatch.drain()
+			}
+		}
+	}
+}
+
+func (w *BatchWriter) Name() string {
+	return "file-batch-writer"
+}
+
+func (w *BatchWriter) Close() error {
+	close(w.msgChan)
+	return w.output.close()
+}
+
+func (w *BatchWriter) sendBatch(ctx context.Context, batch *msgBatch) error {
+	if batch.isEmpty() {
+		return nil
+	}
+
+	for _, msg := range batch.msgs {
+		if err := w.output.write(msg); err != nil {
+			return fmt.Errorf("writing event: %w", err)
+		}
+	}
+
+	// positions are only checkpointed once the events have been persisted
+	if err := w.output.sync(); err != nil {
+		return fmt.Errorf("syncing output: %w", err)
+	}
+
+	if w.checkpoint != nil {
+		if err := w.checkpoint(ctx, batch.positions); err != nil {
+			return fmt.Errorf("checkpointing positions: %w", err)
+		}
+	}
+
+	return nil
+}
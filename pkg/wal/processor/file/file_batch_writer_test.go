@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOutput struct {
+	writeFn func(*msg) error
+	syncFn  func() error
+	closeFn func() error
+}
+
+func (m *mockOutput) write(msg *msg) error {
+	return m.writeFn(msg)
+}
+
+func (m *mockOutput) sync() error {
+	return m.syncFn()
+}
+
+func (m *mockOutput) close() error {
+	return m.closeFn()
+}
+
+func TestBatchWriter_sendBatch(t *testing.T) {
+	t.Parallel()
+
+	testCommitPos := wal.CommitPosition("0/17773B0")
+	testMsg := &msg{line: []byte("{}\n"), schema: "public", table: "users", pos: testCommitPos}
+	errTest := errors.New("oh noes")
+
+	tests := []struct {
+		name     string
+		batch    *msgBatch
+		writeErr error
+		syncErr  error
+
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name: "ok",
+			batch: &msgBatch{
+				msgs:      []*msg{testMsg, testMsg},
+				positions: []wal.CommitPosition{testCommitPos, testCommitPos},
+			},
+
+			wantCalls: []string{"write", "write", "sync", "checkpoint"},
+			wantErr:   nil,
+		},
+		{
+			name: "ok - keep alive",
+			batch: &msgBatch{
+				positions: []wal.CommitPosition{testCommitPos},
+			},
+
+			wantCalls: []string{"sync", "checkpoint"},
+			wantErr:   nil,
+		},
+		{
+			name:      "ok - empty batch",
+			batch:     &msgBatch{},
+			wantCalls: []string{},
+			wantErr:   nil,
+		},
+		{
+			name: "error - writing",
+			batch: &msgBatch{
+				msgs:      []*msg{testMsg},
+				positions: []wal.CommitPosition{testCommitPos},
+			},
+			writeErr: errTest,
+
+			wantCalls: []string{"write"},
+			wantErr:   errTest,
+		},
+		{
+			name: "error - syncing",
+			batch: &msgBatch{
+				msgs:      []*msg{testMsg},
+				positions: []wal.CommitPosition{testCommitPos},
+			},
+			syncErr: errTest,
+
+			wantCalls: []string{"write", "sync"},
+			wantErr:   errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := []string{}
+			writer := &BatchWriter{
+				output: &mockOutput{
+					writeFn: func(m *msg) error {
+						calls = append(calls, "write")
+						return tc.writeErr
+					},
+					syncFn: func() error {
+						calls = append(calls, "sync")
+						return tc.syncErr
+					},
+				},
+				checkpoint: func(ctx context.Context, positions []wal.CommitPosition) error {
+					calls = append(calls, "checkpoint")
+					require.Equal(t, tc.batch.positions, positions)
+					return nil
+				},
+			}
+
+			err := writer.sendBatch(context.Background(), tc.batch)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
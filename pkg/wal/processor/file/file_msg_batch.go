@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+type msgBatch struct {
+	msgs       []*msg
+	positions  []wal.CommitPosition
+	totalBytes int
+}
+
+type msg struct {
+	// line is the JSON serialised wal event data, new line terminated
+	line   []byte
+	schema string
+	table  string
+	pos    wal.CommitPosition
+}
+
+func (m *msg) size() int {
+	return len(m.line)
+}
+
+func (m *msg) isKeepAlive() bool {
+	return len(m.line) == 0 && m.pos != ""
+}
+
+func (m *msgBatch) add(msg *msg) {
+	if msg == nil {
+		return
+	}
+
+	if len(msg.line) > 0 {
+		m.msgs = append(m.msgs, msg)
+		m.totalBytes += msg.size()
+	}
+	if msg.pos != "" {
+		m.positions = append(m.positions, msg.pos)
+	}
+}
+
+func (m *msgBatch) drain() *msgBatch {
+	batch := &msgBatch{
+		msgs:       m.msgs,
+		positions:  m.positions,
+		totalBytes: m.totalBytes,
+	}
+	m.msgs = []*msg{}
+	m.positions = []wal.CommitPosition{}
+	m.totalBytes = 0
+	return batch
+}
+
+func (m *msgBatch) size() int {
+	return len(m.msgs)
+}
+
+func (m *msgBatch) isEmpty() bool {
+	return len(m.msgs) == 0 && len(m.positions) == 0
+}
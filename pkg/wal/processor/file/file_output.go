@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// output is the destination of the serialised wal events. Writes are only
+// guaranteed to be persisted once sync returns successfully.
+type output interface {
+	write(msg *msg) error
+	sync() error
+	close() error
+}
+
+// stdoutOutput writes the events to the standard output.
+type stdoutOutput struct {
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func newStdoutOutput() *stdoutOutput {
+	return &stdoutOutput{
+		file:   os.Stdout,
+		writer: bufio.NewWriter(os.Stdout),
+	}
+}
+
+func (o *stdoutOutput) write(msg *msg) error {
+	_, err := o.writer.Write(msg.line)
+	return err
+}
+
+func (o *stdoutOutput) sync() error {
+	if err := o.writer.Flush(); err != nil {
+		return err
+	}
+	// pipes and terminals don't support fsync
+	if err := o.file.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTSUP) {
+		return err
+	}
+	return nil
+}
+
+func (o *stdoutOutput) close() error {
+	return o.sync()
+}
+
+// dirOutput writes the events to files in a local directory, using the
+// configured filename template. Files are rotated when they reach the max size
+// or age.
+type dirOutput struct {
+	dir          string
+	template     *template.Template
+	compression  string
+	maxFileBytes int64
+	maxFileAge   time.Duration
+	files        map[string]*rotatingFile
+	now          func() time.Time
+}
+
+type filenameData struct {
+	Schema string
+	Table  string
+	Date   string
+	Hour   string
+}
+
+func newDirOutput(cfg *Config) (*dirOutput, error) {
+	tmpl, err := cfg.filenameTemplate()
+	if err != nil {
+		return nil, err
+	}
+	return &dirOutput{
+		dir:          cfg.Dir,
+		template:     tmpl,
+		compression:  cfg.Compression,
+		maxFileBytes: cfg.maxFileBytes(),
+		maxFileAge:   cfg.maxFileAge(),
+		files:        map[string]*rotatingFile{},
+		now:          time.Now,
+	}, nil
+}
+
+func (o *dirOutput) write(msg *msg) error {
+	now := o.now().UTC()
+	path, err := o.filePath(msg, now)
+	if err != nil {
+		return err
+	}
+
+	f, found := o.files[path]
+	if found && f.bytesWritten+int64(len(msg.line)) > o.maxFileBytes && f.bytesWritten > 0 {
+		if err := f.close(); err != nil {
+			return fmt.Errorf("rotating file %s: %w", f.name, err)
+		}
+		delete(o.files, path)
+		found = false
+	}
+
+	if !found {
+		f, err = openRotatingFile(path, o.compression, now)
+		if err != nil {
+			return err
+		}
+		o.files[path] = f
+	}
+
+	return f.write(msg.line)
+}
+
+// sync persists all the open files. Files that have reached the max age are
+// closed, so that the following writes are done to a new file.
+func (o *dirOutput) sync() error {
+	now := o.now()
+	for path, f := range o.files {
+		if now.Sub(f.openedAt) >= o.maxFileAge {
+			if err := f.close(); err != nil {
+				return fmt.Errorf("rotating file %s: %w", f.name, err)
+			}
+			delete(o.files, path)
+			continue
+		}
+		if err := f.sync(); err != nil {
+			return fmt.Errorf("syncing file %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+func (o *dirOutput) close() error {
+	var errs error
+	for path, f := range o.files {
+		if err := f.close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("closing file %s: %w", f.name, err))
+		}
+		delete(o.files, path)
+	}
+	return errs
+}
+
+// filePath returns the path of the file for the message on input, without the
+// rotation suffix.
+func (o *dirOutput) filePath(msg *msg, now time.Time) (string, error) {
+	var buf bytes.Buffer
+	if err := o.template.Execute(&buf, filenameData{
+		Schema: sanitisePathElement(msg.schema),
+		Table:  sanitisePathElement(msg.table),
+		Date:   now.Format("2006-01-02"),
+		Hour:   now.Format("15"),
+	}); err != nil {
+		return "", fmt.Errorf("rendering filename template: %w", err)
+	}
+	return filepath.Join(o.dir, buf.String()), nil
+}
+
+// rotatingFile is a file writer with optional compression. The uncompressed
+// bytes written are tracked for the size based rotation.
+type rotatingFile struct {
+	name         string
+	file         *os.File
+	buffer       *bufio.Writer
+	compressor   compressor
+	writer       io.Writer
+	bytesWritten int64
+	openedAt     time.Time
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+func openRotatingFile(path, compression string, now time.Time) (*rotatingFile, error) {
+	name := fmt.Sprintf("%s-%s.jsonl%s", path, now.Format("20060102T150405.000000000Z"), compressionExtension(compression))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory for file %s: %w", name, err)
+	}
+
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening file %s: %w", name, err)
+	}
+
+	f := &rotatingFile{
+		name:     name,
+		file:     file,
+		buffer:   bufio.NewWriter(file),
+		openedAt: now,
+	}
+
+	switch compression {
+	case CompressionGzip:
+		f.compressor = gzip.NewWriter(f.buffer)
+	case CompressionZstd:
+		f.compressor, err = zstd.NewWriter(f.buffer)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("creating zstd writer: %w", err)
+		}
+	}
+
+	f.writer = f.buffer
+	if f.compressor != nil {
+		f.writer = f.compressor
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) write(line []byte) error {
+	n, err := f.writer.Write(line)
+	f.bytesWritten += int64(n)
+	return err
+}
+
+func (f *rotatingFile) sync() error {
+	if f.compressor != nil {
+		if err := f.compressor.Flush(); err != nil {
+			return err
+		}
+	}
+	if err := f.buffer.Flush(); err != nil {
+		return err
+	}
+	return f.file.Sync()
+}
+
+func (f *rotatingFile) close() error {
+	if f.compressor != nil {
+		if err := f.compressor.Close(); err != nil {
+			return err
+		}
+	}
+	if err := f.buffer.Flush(); err != nil {
+		return err
+	}
+	if err := f.file.Sync(); err != nil {
+		return err
+	}
+	return f.file.Close()
+}
+
+func compressionExtension(compression string) string {
+	switch compression {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// sanitisePathElement makes sure schema and table names can't be used to
+// write outside of the configured directory.
+func sanitisePathElement(s string) string {
+	s = strings.ReplaceAll(s, string(filepath.Separator), "_")
+	if s == "." || s == ".." {
+		return strings.ReplaceAll(s, ".", "_")
+	}
+	return s
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirOutput(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 10, 13, 0, 0, 0, time.UTC)
+	usersMsg := func(line string) *msg {
+		return &msg{line: []byte(line + "\n"), schema: "public", table: "users"}
+	}
+
+	tests := []struct {
+		name         string
+		config       Config
+		writes       []*msg
+		advanceClock time.Duration
+
+		wantFiles map[string][]string
+	}{
+		{
+			name:   "ok - default template",
+			config: Config{},
+			writes: []*msg{
+				usersMsg("1"),
+				usersMsg("2"),
+				{line: []byte("3\n"), schema: "public", table: "orders"},
+			},
+
+			wantFiles: map[string][]string{
+				"public/users/2024-05-10":  {"1\n2\n"},
+				"public/orders/2024-05-10": {"3\n"},
+			},
+		},
+		{
+			name: "ok - custom template, schema change event",
+			config: Config{
+				FilenameTemplate: "{{.Date}}/{{.Hour}}/{{.Schema}}",
+			},
+			writes: []*msg{
+				{line: []byte("ddl\n"), schema: "public"},
+			},
+
+			wantFiles: map[string][]string{
+				"2024-05-10/13/public": {"ddl\n"},
+			},
+		},
+		{
+			name: "ok - size rotation",
+			config: Config{
+				MaxFileBytes: 4,
+			},
+			writes: []*msg{
+				usersMsg("1"),
+				usersMsg("2"),
+				usersMsg("3"),
+			},
+
+			wantFiles: map[string][]string{
+				"public/users/2024-05-10": {"1\n2\n", "3\n"},
+			},
+		},
+		{
+			name: "ok - age rotation",
+			config: Config{
+				MaxFileAge: time.Minute,
+			},
+			writes: []*msg{
+				usersMsg("1"),
+				usersMsg("2"),
+			},
+			advanceClock: time.Minute,
+
+			wantFiles: map[string][]string{
+				"public/users/2024-05-10": {"1\n", "2\n"},
+			},
+		},
+		{
+			name: "ok - gzip compression",
+			config: Config{
+				Compression: CompressionGzip,
+			},
+			writes: []*msg{
+				usersMsg("1"),
+			},
+
+			wantFiles: map[string][]string{
+				"public/users/2024-05-10": {"1\n"},
+			},
+		},
+		{
+			name: "ok - zstd compression",
+			config: Config{
+				Compression: CompressionZstd,
+			},
+			writes: []*msg{
+				usersMsg("1"),
+			},
+
+			wantFiles: map[string][]string{
+				"public/users/2024-05-10": {"1\n"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.config.Dir = t.TempDir()
+			out, err := newDirOutput(&tc.config)
+			require.NoError(t, err)
+
+			clock := now
+			out.now = func() time.Time { return clock }
+
+			for i, m := range tc.writes {
+				require.NoError(t, out.write(m))
+				// sync after every write, advancing the clock to trigger age
+				// based rotations
+				require.NoError(t, out.sync())
+				if i == 0 {
+					clock = clock.Add(tc.advanceClock)
+					require.NoError(t, out.sync())
+				}
+				clock = clock.Add(time.Millisecond)
+			}
+			require.NoError(t, out.close())
+
+			files := readFiles(t, tc.config.Dir, tc.config.Compression)
+			require.Equal(t, tc.wantFiles, files)
+		})
+	}
+}
+
+// readFiles returns the decompressed contents of the files in the directory,
+// keyed by their path without the rotation suffix, in rotation order.
+func readFiles(t *testing.T, dir, compression string) map[string][]string {
+	paths := []string{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	require.NoError(t, err)
+	sort.Strings(paths)
+
+	files := map[string][]string{}
+	suffixLen := len("-20060102T150405.000000000Z.jsonl") + len(compressionExtension(compression))
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+		key := rel[:len(rel)-suffixLen]
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var reader io.Reader = bytes.NewReader(content)
+		switch compression {
+		case CompressionGzip:
+			reader, err = gzip.NewReader(reader)
+			require.NoError(t, err)
+		case CompressionZstd:
+			reader, err = zstd.NewReader(reader)
+			require.NoError(t, err)
+		}
+		decompressed, err := io.ReadAll(reader)
+		require.NoError(t, err)
+
+		files[key] = append(files[key], string(decompressed))
+	}
+	return files
+}
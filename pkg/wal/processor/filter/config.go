@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+type Config struct {
+	// Expression is the CEL expression the wal data events need to match in
+	// order to be processed. It has access to the `action`, `schema`,
+	// `table`, `lsn` and `timestamp` of the event, as well as the `new` column
+	// values and the `old` identity values, i.e. `table == "orders" &&
+	// new.status != old.status`.
+	Expression string
+}
@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// Expression is a compiled CEL expression that can be evaluated against wal
+// data events. It is safe for concurrent use.
+type Expression struct {
+	source  string
+	program cel.Program
+	refs    *references
+}
+
+// references contains the schema elements an expression refers to, used to
+// validate it against the schema log.
+type references struct {
+	schemas     []string
+	tables      []string
+	newColumns  []string
+	oldColumns  []string
+	comparisons []comparison
+}
+
+// comparison is a comparison between a column and a literal value, i.e.
+// `new.status == "paid"`.
+type comparison struct {
+	variable string
+	column   string
+	literal  ref.Val
+}
+
+const (
+	actionVar    = "action"
+	schemaVar    = "schema"
+	tableVar     = "table"
+	lsnVar       = "lsn"
+	timestampVar = "timestamp"
+	newVar       = "new"
+	oldVar       = "old"
+)
+
+var (
+	errEmptyExpression   = errors.New("empty expression")
+	errNonBoolExpression = errors.New("expression must evaluate to a bool")
+)
+
+var env = mustNewEnv()
+
+func mustNewEnv() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Variable(actionVar, cel.StringType),
+		cel.Variable(schemaVar, cel.StringType),
+		cel.Variable(tableVar, cel.StringType),
+		cel.Variable(lsnVar, cel.StringType),
+		cel.Variable(timestampVar, cel.StringType),
+		cel.Variable(newVar, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(oldVar, cel.MapType(cel.StringType, cel.DynType)),
+		// column values can be decoded as integers or doubles depending on
+		// the listener, so comparisons need to work across numeric types
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("creating cel environment: %v", err))
+	}
+	return env
+}
+
+// Compile parses and type checks the CEL expression on input. The expression
+// must evaluate to a bool.
+func Compile(expression string) (*Expression, error) {
+	if expression == "" {
+		return nil, errEmptyExpression
+	}
+
+	checked, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compiling expression %q: %w", expression, issues.Err())
+	}
+	if !checked.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("expression %q: %w, got %s", expression, errNonBoolExpression, checked.OutputType())
+	}
+
+	program, err := env.Program(checked)
+	if err != nil {
+		return nil, fmt.Errorf("building program for expression %q: %w", expression, err)
+	}
+
+	return &Expression{
+		source:  expression,
+		program: program,
+		refs:    collectReferences(checked.NativeRep().Expr()),
+	}, nil
+}
+
+// Matches evaluates the expression against the wal data on input. Evaluation
+// errors, such as accessing a column that's not part of the event, are
+// returned along with a false result, so they can be treated as a non match.
+func (e *Expression) Matches(data *wal.Data) (bool, error) {
+	out, _, err := e.program.Eval(map[string]any{
+		actionVar:    data.Action,
+		schemaVar:    data.Schema,
+		tableVar:     data.Table,
+		lsnVar:       data.LSN,
+		timestampVar: data.Timestamp,
+		newVar:       columnValues(data.Columns),
+		oldVar:       columnValues(data.Identity),
+	})
+	if err != nil {
+		return false, fmt.Errorf("evaluating expression %q: %w", e.source, err)
+	}
+
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q: %w, got %T", e.source, errNonBoolExpression, out.Value())
+	}
+	return match, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+func columnValues(cols []wal.Column) map[string]any {
+	values := make(map[string]any, len(cols))
+	for _, col := range cols {
+		values[col.Name] = toCELValue(col.Value)
+	}
+	return values
+}
+
+// toCELValue converts the lossless json numbers into native numeric values,
+// since they would otherwise be treated as strings.
+func toCELValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for k, val := range v {
+			converted[k] = toCELValue(val)
+		}
+		return converted
+	case []any:
+		converted := make([]any, 0, len(v))
+		for _, val := range v {
+			converted = append(converted, toCELValue(val))
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+// collectReferences walks the expression tree looking for the column
+// accesses (`new.col`, `old["col"]`) and the schema and table literals the
+// expression compares against.
+func collectReferences(root ast.Expr) *references {
+	refs := &references{}
+	ast.PreOrderVisit(root, ast.NewExprVisitor(func(e ast.Expr) {
+		switch e.Kind() {
+		case ast.SelectKind:
+			if variable, column, ok := columnAccess(e); ok {
+				refs.addColumn(variable, column)
+			}
+		case ast.CallKind:
+			call := e.AsCall()
+			switch call.FunctionName() {
+			case operators.Index, operators.OptIndex:
+				if variable, column, ok := columnAccess(e); ok {
+					refs.addColumn(variable, column)
+				}
+			case operators.Equals, operators.NotEquals,
+				operators.Less, operators.LessEquals,
+				operators.Greater, operators.GreaterEquals:
+				args := call.Args()
+				refs.addComparison(args[0], args[1])
+				refs.addComparison(args[1], args[0])
+			case operators.In:
+				args := call.Args()
+				if args[1].Kind() == ast.ListKind {
+					for _, elem := range args[1].AsList().Elements() {
+						refs.addComparison(args[0], elem)
+					}
+				}
+			}
+		}
+	}))
+	return refs
+}
+
+// columnAccess returns the variable and column name if the expression on
+// input is a column access on the new or old values.
+func columnAccess(e ast.Expr) (variable, column string, ok bool) {
+	var operand ast.Expr
+	switch e.Kind() {
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		operand, column = sel.Operand(), sel.FieldName()
+	case ast.CallKind:
+		args := e.AsCall().Args()
+		if len(args) != 2 || args[1].Kind() != ast.LiteralKind {
+			return "", "", false
+		}
+		key, isString := args[1].AsLiteral().(types.String)
+		if !isString {
+			return "", "", false
+		}
+		operand, column = args[0], string(key)
+	default:
+		return "", "", false
+	}
+
+	if operand.Kind() != ast.IdentKind {
+		return "", "", false
+	}
+	variable = operand.AsIdent()
+	if variable != newVar && variable != oldVar {
+		return "", "", false
+	}
+	return variable, column, true
+}
+
+func (r *references) addColumn(variable, column string) {
+	switch variable {
+	case newVar:
+		if !slices.Contains(r.newColumns, column) {
+			r.newColumns = append(r.newColumns, column)
+		}
+	case oldVar:
+		if !slices.Contains(r.oldColumns, column) {
+			r.oldColumns = append(r.oldColumns, column)
+		}
+	}
+}
+
+func (r *references) addComparison(lhs, rhs ast.Expr) {
+	if rhs.Kind() != ast.LiteralKind {
+		return
+	}
+	literal := rhs.AsLiteral()
+
+	if lhs.Kind() == ast.IdentKind {
+		value, isString := literal.(types.String)
+		if !isString {
+			return
+		}
+		switch lhs.AsIdent() {
+		case schemaVar:
+			if !slices.Contains(r.schemas, string(value)) {
+				r.schemas = append(r.schemas, string(value))
+			}
+		case tableVar:
+			if !slices.Contains(r.tables, string(value)) {
+				r.tables = append(r.tables, string(value))
+			}
+		}
+		return
+	}
+
+	if variable, column, ok := columnAccess(lhs); ok {
+		r.comparisons = append(r.comparisons, comparison{
+			variable: variable,
+			column:   column,
+			literal:  literal,
+		})
+	}
+}
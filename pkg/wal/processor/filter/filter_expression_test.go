@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expression string
+
+		wantErr  error
+		wantRefs *references
+	}{
+		{
+			name:       "ok",
+			expression: `schema == "public" && table in ["orders", "invoices"] && new.status != old["status"] && new.amount > 10`,
+
+			wantRefs: &references{
+				schemas:    []string{"public"},
+				tables:     []string{"orders", "invoices"},
+				newColumns: []string{"status", "amount"},
+				oldColumns: []string{"status"},
+			},
+		},
+		{
+			name:       "ok - has macro",
+			expression: `has(old.status) && action == "U"`,
+
+			wantRefs: &references{
+				oldColumns: []string{"status"},
+			},
+		},
+		{
+			name:       "error - empty expression",
+			expression: "",
+
+			wantErr: errEmptyExpression,
+		},
+		{
+			name:       "error - non bool expression",
+			expression: `table`,
+
+			wantErr: errNonBoolExpression,
+		},
+		{
+			name:       "error - invalid syntax",
+			expression: `table ==`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "error - type mismatch",
+			expression: `table == 1`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "error - unknown variable",
+			expression: `row.id == 1`,
+
+			wantErr: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Compile(tc.expression)
+			if tc.wantRefs == nil {
+				require.Error(t, err)
+				if tc.wantErr != nil {
+					require.ErrorIs(t, err, tc.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantRefs.schemas, expr.refs.schemas)
+			require.Equal(t, tc.wantRefs.tables, expr.refs.tables)
+			require.Equal(t, tc.wantRefs.newColumns, expr.refs.newColumns)
+			require.Equal(t, tc.wantRefs.oldColumns, expr.refs.oldColumns)
+		})
+	}
+}
+
+func TestExpression_Matches(t *testing.T) {
+	t.Parallel()
+
+	testData := func(status, oldStatus any) *wal.Data {
+		return &wal.Data{
+			Action: "U",
+			Schema: "public",
+			Table:  "orders",
+			Columns: []wal.Column{
+				{Name: "id", Value: json.Number("42")},
+				{Name: "status", Value: status},
+				{Name: "amount", Value: 10.5},
+				{Name: "tags", Value: []any{"a", "b"}},
+			},
+			Identity: []wal.Column{
+				{Name: "id", Value: float64(42)},
+				{Name: "status", Value: oldStatus},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		data       *wal.Data
+
+		wantMatch bool
+		wantErr   bool
+	}{
+		{
+			name:       "match - changed column",
+			expression: `table == "orders" && new.status != old.status`,
+			data:       testData("paid", "pending"),
+
+			wantMatch: true,
+		},
+		{
+			name:       "no match - unchanged column",
+			expression: `table == "orders" && new.status != old.status`,
+			data:       testData("paid", "paid"),
+
+			wantMatch: false,
+		},
+		{
+			name:       "match - numeric comparisons across types",
+			expression: `new.id == 42 && old.id == new.id && new.amount > 10`,
+			data:       testData("paid", "paid"),
+
+			wantMatch: true,
+		},
+		{
+			name:       "match - list values",
+			expression: `"a" in new.tags`,
+			data:       testData("paid", "paid"),
+
+			wantMatch: true,
+		},
+		{
+			name:       "match - null values",
+			expression: `old.status == null`,
+			data:       testData("paid", nil),
+
+			wantMatch: true,
+		},
+		{
+			name:       "no match - missing column",
+			expression: `new.missing == "value"`,
+			data:       testData("paid", "paid"),
+
+			wantMatch: false,
+			wantErr:   true,
+		},
+		{
+			name:       "no match - missing old values",
+			expression: `has(old.status) && new.status != old.status`,
+			data: &wal.Data{
+				Action:  "I",
+				Columns: []wal.Column{{Name: "status", Value: "paid"}},
+			},
+
+			wantMatch: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Compile(tc.expression)
+			require.NoError(t, err)
+
+			match, err := expr.Matches(tc.data)
+			require.Equal(t, tc.wantErr, err != nil, "error: %v", err)
+			require.Equal(t, tc.wantMatch, match)
+		})
+	}
+}
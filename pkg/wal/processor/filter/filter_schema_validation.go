@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/google/cel-go/common/types"
+)
+
+// defaultSchema is used to validate the expressions that don't reference a
+// specific schema.
+const defaultSchema = "public"
+
+var (
+	ErrSchemaNotFound     = errors.New("schema not found in schema log")
+	ErrTableNotFound      = errors.New("table not found in schema log")
+	ErrColumnNotFound     = errors.New("column not found in schema log")
+	ErrIncompatibleColumn = errors.New("column type is not compatible with compared value")
+	ErrOldValueMissing    = errors.New("old column value not available without REPLICA IDENTITY FULL")
+)
+
+// ValidateSchema validates the expressions on input against the latest acked
+// schema log entries. It checks that the referenced schemas, tables and
+// columns exist, and that the columns compared against literal values have a
+// compatible type. Old column values are only available on tables with
+// REPLICA IDENTITY FULL (or for the primary key columns with the default
+// replica identity), so references to them on other tables are rejected.
+// Expressions that don't reference a schema are validated against the public
+// schema, and skipped if it's not in the schema log.
+func ValidateSchema(ctx context.Context, store schemalog.Store, exprs ...*Expression) error {
+	schemas := map[string]*schemalog.Schema{}
+	fetchSchema := func(name string) (*schemalog.Schema, error) {
+		if s, found := schemas[name]; found {
+			return s, nil
+		}
+		logEntry, err := store.Fetch(ctx, name, true)
+		if err != nil {
+			if !errors.Is(err, schemalog.ErrNoRows) {
+				return nil, fmt.Errorf("fetching schema log for schema %s: %w", name, err)
+			}
+			schemas[name] = nil
+			return nil, nil
+		}
+		schemas[name] = &logEntry.Schema
+		return &logEntry.Schema, nil
+	}
+
+	var errs []error
+	for _, expr := range exprs {
+		if err := validateExpression(expr, fetchSchema); err != nil {
+			errs = append(errs, fmt.Errorf("expression %q: %w", expr.source, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateExpression(expr *Expression, fetchSchema func(string) (*schemalog.Schema, error)) error {
+	refs := expr.refs
+	schemaNames := refs.schemas
+	if len(schemaNames) == 0 {
+		schemaNames = []string{defaultSchema}
+	}
+
+	schemas := make([]*schemalog.Schema, 0, len(schemaNames))
+	for _, name := range schemaNames {
+		s, err := fetchSchema(name)
+		if err != nil {
+			return err
+		}
+		if s == nil || s.Dropped {
+			if len(refs.schemas) == 0 {
+				// the expression doesn't reference any schema, so there's
+				// nothing to validate against
+				return nil
+			}
+			return fmt.Errorf("%w: %s", ErrSchemaNotFound, name)
+		}
+		schemas = append(schemas, s)
+	}
+
+	// the columns are validated against the referenced tables, or all the
+	// tables in the schemas when the expression doesn't reference any
+	tables := []*schemalog.Table{}
+	var errs []error
+	for _, tableName := range refs.tables {
+		found := false
+		for _, s := range schemas {
+			if t := findTable(s, tableName); t != nil {
+				tables = append(tables, t)
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%w: %s (schemas %s)", ErrTableNotFound, tableName, strings.Join(schemaNames, ", ")))
+		}
+	}
+	if len(refs.tables) == 0 {
+		for _, s := range schemas {
+			for i := range s.Tables {
+				tables = append(tables, &s.Tables[i])
+			}
+		}
+	}
+	if len(tables) == 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, column := range mergeColumns(refs.newColumns, refs.oldColumns) {
+		if findColumn(tables, column) == nil {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrColumnNotFound, column))
+		}
+	}
+
+	for _, column := range refs.oldColumns {
+		for _, t := range tables {
+			if t.GetColumnByName(column) == nil || hasOldValue(t, column) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%w: %s.%s (table %s, replica identity %s)", ErrOldValueMissing, oldVar, column, t.Name, t.ReplicaIdentity))
+		}
+	}
+
+	for _, c := range refs.comparisons {
+		col := findColumn(tables, c.column)
+		if col == nil {
+			continue
+		}
+		if !isCompatible(col.DataType, c.literal) {
+			errs = append(errs, fmt.Errorf("%w: %s.%s (%s) compared to %s", ErrIncompatibleColumn, c.variable, c.column, col.DataType, c.literal.Type().TypeName()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// hasOldValue returns true if the update and delete events for the table on
+// input can include the old value of the column. Tables with an unknown
+// replica identity, or using an index (whose columns are not tracked in the
+// schema log), are not validated.
+func hasOldValue(t *schemalog.Table, column string) bool {
+	switch t.ReplicaIdentity {
+	case "", schemalog.ReplicaIdentityFull, schemalog.ReplicaIdentityIndex:
+		return true
+	case schemalog.ReplicaIdentityDefault:
+		return slices.Contains(t.PrimaryKeyColumns, column)
+	default:
+		return false
+	}
+}
+
+func findTable(s *schemalog.Schema, name string) *schemalog.Table {
+	for i := range s.Tables {
+		if s.Tables[i].Name == name {
+			return &s.Tables[i]
+		}
+	}
+	return nil
+}
+
+func findColumn(tables []*schemalog.Table, name string) *schemalog.Column {
+	for _, t := range tables {
+		if col := t.GetColumnByName(name); col != nil {
+			return col
+		}
+	}
+	return nil
+}
+
+func mergeColumns(a, b []string) []string {
+	merged := slices.Clone(a)
+	for _, col := range b {
+		if !slices.Contains(merged, col) {
+			merged = append(merged, col)
+		}
+	}
+	return merged
+}
+
+type valueKind uint8
+
+const (
+	unknownKind valueKind = iota
+	numericKind
+	stringKind
+	boolKind
+)
+
+// isCompatible returns false if the postgres column type can't be compared
+// to the literal value on input. Types that can't be mapped are considered
+// compatible.
+func isCompatible(dataType string, literal any) bool {
+	columnKind := postgresTypeKind(dataType)
+	if columnKind == unknownKind {
+		return true
+	}
+
+	switch literal.(type) {
+	case types.Int, types.Uint, types.Double:
+		return columnKind == numericKind
+	case types.String:
+		// numeric values can be decoded as strings to avoid precision loss
+		return columnKind == stringKind || columnKind == numericKind
+	case types.Bool:
+		return columnKind == boolKind
+	default:
+		return true
+	}
+}
+
+func postgresTypeKind(dataType string) valueKind {
+	switch {
+	case strings.HasSuffix(dataType, "[]"):
+		return unknownKind
+	case dataType == "boolean":
+		return boolKind
+	case dataType == "smallint", dataType == "integer", dataType == "bigint",
+		dataType == "real", dataType == "double precision",
+		strings.HasPrefix(dataType, "numeric"), strings.HasPrefix(dataType, "decimal"):
+		return numericKind
+	case dataType == "text", dataType == "uuid", dataType == "citext",
+		strings.HasPrefix(dataType, "character"), strings.HasPrefix(dataType, "varchar"):
+		return stringKind
+	default:
+		return unknownKind
+	}
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	schemalogmocks "github.com/ApollosProject/pgstream-wal2json/pkg/schemalog/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchema(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("oh noes")
+
+	testSchemas := map[string]*schemalog.LogEntry{
+		"public": {
+			SchemaName: "public",
+			Schema: schemalog.Schema{
+				Tables: []schemalog.Table{
+					{
+						Name: "orders",
+						Columns: []schemalog.Column{
+							{Name: "id", DataType: "bigint"},
+							{Name: "status", DataType: "text"},
+							{Name: "paid", DataType: "boolean"},
+							{Name: "total", DataType: "numeric(10,2)"},
+						},
+						ReplicaIdentity: schemalog.ReplicaIdentityFull,
+					},
+					{
+						Name: "users",
+						Columns: []schemalog.Column{
+							{Name: "id", DataType: "bigint"},
+							{Name: "email", DataType: "character varying(255)"},
+						},
+						PrimaryKeyColumns: []string{"id"},
+						ReplicaIdentity:   schemalog.ReplicaIdentityDefault,
+					},
+					{
+						Name: "events",
+						Columns: []schemalog.Column{
+							{Name: "name", DataType: "text"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testStore := &schemalogmocks.Store{
+		FetchFn: func(ctx context.Context, schemaName string, ackedOnly bool) (*schemalog.LogEntry, error) {
+			require.True(t, ackedOnly)
+			entry, found := testSchemas[schemaName]
+			if !found {
+				return nil, schemalog.ErrNoRows
+			}
+			return entry, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		store      schemalog.Store
+		expression string
+
+		wantErr error
+	}{
+		{
+			name:       "ok",
+			store:      testStore,
+			expression: `table == "orders" && new.status != old.status && new.total > 10 && new.paid == true`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "ok - numeric column compared to string",
+			store:      testStore,
+			expression: `new.total == "10.50"`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "ok - column from any table in the default schema",
+			store:      testStore,
+			expression: `new.email.endsWith("@example.com")`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "ok - old primary key column with default replica identity",
+			store:      testStore,
+			expression: `table == "users" && old.id == 1`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "ok - old column with unknown replica identity",
+			store:      testStore,
+			expression: `table == "events" && old.name == "signup"`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "ok - default schema not in schema log",
+			store:      &schemalogmocks.Store{FetchFn: func(context.Context, string, bool) (*schemalog.LogEntry, error) { return nil, schemalog.ErrNoRows }},
+			expression: `new.status == "paid"`,
+
+			wantErr: nil,
+		},
+		{
+			name:       "error - schema not found",
+			store:      testStore,
+			expression: `schema == "sales" && table == "orders"`,
+
+			wantErr: ErrSchemaNotFound,
+		},
+		{
+			name:       "error - table not found",
+			store:      testStore,
+			expression: `table == "invoices"`,
+
+			wantErr: ErrTableNotFound,
+		},
+		{
+			name:       "error - column not found in referenced table",
+			store:      testStore,
+			expression: `table == "orders" && new.email == "a@example.com"`,
+
+			wantErr: ErrColumnNotFound,
+		},
+		{
+			name:       "error - incompatible column type",
+			store:      testStore,
+			expression: `new.status > 10`,
+
+			wantErr: ErrIncompatibleColumn,
+		},
+		{
+			name:       "error - old column without replica identity full",
+			store:      testStore,
+			expression: `table == "users" && new.email != old.email`,
+
+			wantErr: ErrOldValueMissing,
+		},
+		{
+			name:       "error - old column from any table without replica identity full",
+			store:      testStore,
+			expression: `old.email == "a@example.com"`,
+
+			wantErr: ErrOldValueMissing,
+		},
+		{
+			name:       "error - fetching schema",
+			store:      &schemalogmocks.Store{FetchFn: func(context.Context, string, bool) (*schemalog.LogEntry, error) { return nil, errTest }},
+			expression: `new.status == "paid"`,
+
+			wantErr: errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Compile(tc.expression)
+			require.NoError(t, err)
+
+			err = ValidateSchema(context.Background(), tc.store, expr)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+)
+
+// Filter is a wrapper around a processor that only passes on the wal data
+// events that match the configured expression.
+type Filter struct {
+	processor  processor.Processor
+	logger     loglib.Logger
+	expression *Expression
+	now        func() time.Time
+
+	// evaluation errors are logged at most once per evalErrorLogInterval,
+	// keeping track of the ones that have been suppressed in between
+	evalErrorMutex     sync.Mutex
+	lastEvalErrorLog   time.Time
+	suppressedEvalErrs uint64
+}
+
+type Option func(f *Filter)
+
+// evalErrorLogInterval is the minimum interval between expression evaluation
+// error logs, so that a filter that fails for every event doesn't flood them.
+const evalErrorLogInterval = time.Minute
+
+// New will return a filter processor wrapper that drops the wal data events
+// that don't match the expression on input before they reach the processor.
+// Schema log and schema change events are always passed on.
+func New(cfg *Config, p processor.Processor, opts ...Option) (*Filter, error) {
+	expression, err := Compile(cfg.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+
+	f := &Filter{
+		processor:  p,
+		logger:     loglib.NewNoopLogger(),
+		expression: expression,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(f *Filter) {
+		f.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "wal_filter",
+		})
+	}
+}
+
+// ProcessWALEvent passes the wal event to the wrapped processor if it matches
+// the filter expression. Events that don't match are passed on as keep alives
+// so that their position can still be checkpointed.
+func (f *Filter) ProcessWALEvent(ctx context.Context, event *wal.Event) error {
+	if event.Data == nil || event.Data.IsSchemaChange() || processor.IsSchemaLogEvent(event.Data) {
+		return f.processor.ProcessWALEvent(ctx, event)
+	}
+
+	match, err := f.expression.Matches(event.Data)
+	if err != nil {
+		// evaluation errors happen when the event doesn't contain the
+		// referenced columns (i.e. old values on inserts, or on tables without
+		// REPLICA IDENTITY FULL), and are treated as a non match
+		f.logEvaluationError(err, event.Data)
+	}
+	if match {
+		return f.processor.ProcessWALEvent(ctx, event)
+	}
+
+	if event.CommitPosition == "" {
+		return nil
+	}
+	return f.processor.ProcessWALEvent(ctx, &wal.Event{CommitPosition: event.CommitPosition})
+}
+
+// logEvaluationError logs the expression evaluation error on input, unless
+// one has already been logged within the last evalErrorLogInterval.
+func (f *Filter) logEvaluationError(err error, data *wal.Data) {
+	f.evalErrorMutex.Lock()
+	defer f.evalErrorMutex.Unlock()
+
+	now := f.now()
+	if !f.lastEvalErrorLog.IsZero() && now.Sub(f.lastEvalErrorLog) < evalErrorLogInterval {
+		f.suppressedEvalErrs++
+		return
+	}
+
+	f.logger.Warn(err, "filter: expression evaluation failed, skipping event", loglib.Fields{
+		"schema":            data.Schema,
+		"table":             data.Table,
+		"suppressed_errors": f.suppressedEvalErrs,
+	})
+	f.lastEvalErrorLog = now
+	f.suppressedEvalErrs = 0
+}
+
+func (f *Filter) Name() string {
+	return f.processor.Name()
+}
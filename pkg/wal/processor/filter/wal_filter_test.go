@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_ProcessWALEvent(t *testing.T) {
+	t.Parallel()
+
+	testPos := wal.CommitPosition("1/CF54A048")
+	testEvent := func(table string) *wal.Event {
+		return &wal.Event{
+			Data: &wal.Data{
+				Action:  "I",
+				Schema:  "public",
+				Table:   table,
+				Columns: []wal.Column{{Name: "status", Value: "paid"}},
+			},
+			CommitPosition: testPos,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		event *wal.Event
+
+		wantEvent *wal.Event
+	}{
+		{
+			name:  "matching event",
+			event: testEvent("orders"),
+
+			wantEvent: testEvent("orders"),
+		},
+		{
+			name:  "non matching event",
+			event: testEvent("users"),
+
+			wantEvent: &wal.Event{CommitPosition: testPos},
+		},
+		{
+			name: "non matching event without position",
+			event: &wal.Event{
+				Data: &wal.Data{Schema: "public", Table: "users"},
+			},
+
+			wantEvent: nil,
+		},
+		{
+			name: "evaluation error",
+			event: &wal.Event{
+				Data:           &wal.Data{Schema: "public", Table: "orders"},
+				CommitPosition: testPos,
+			},
+
+			wantEvent: &wal.Event{CommitPosition: testPos},
+		},
+		{
+			name:  "keep alive",
+			event: &wal.Event{CommitPosition: testPos},
+
+			wantEvent: &wal.Event{CommitPosition: testPos},
+		},
+		{
+			name: "schema log event",
+			event: &wal.Event{
+				Data: &wal.Data{Schema: schemalog.SchemaName, Table: schemalog.TableName},
+			},
+
+			wantEvent: &wal.Event{
+				Data: &wal.Data{Schema: schemalog.SchemaName, Table: schemalog.TableName},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotEvent *wal.Event
+			f, err := New(&Config{Expression: `table == "orders" && new.status == "paid"`},
+				&mocks.Processor{
+					ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+						gotEvent = walEvent
+						return nil
+					},
+				})
+			require.NoError(t, err)
+
+			err = f.ProcessWALEvent(context.Background(), tc.event)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantEvent, gotEvent)
+		})
+	}
+}
+
+func TestFilter_ProcessWALEvent_evaluationErrorLogs(t *testing.T) {
+	t.Parallel()
+
+	logger := &warnLogger{}
+	f, err := New(&Config{Expression: `old.status == "paid"`},
+		&mocks.Processor{
+			ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+				return nil
+			},
+		})
+	require.NoError(t, err)
+	f.logger = logger
+
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	// inserts don't have old values, so the evaluation fails
+	insert := &wal.Event{
+		Data: &wal.Data{
+			Action:  "I",
+			Schema:  "public",
+			Table:   "orders",
+			Columns: []wal.Column{{Name: "status", Value: "paid"}},
+		},
+	}
+	processEvents := func(n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, f.ProcessWALEvent(context.Background(), insert))
+		}
+	}
+
+	processEvents(3)
+	require.Equal(t, []loglib.Fields{
+		{"schema": "public", "table": "orders", "suppressed_errors": uint64(0)},
+	}, logger.warnings)
+
+	now = now.Add(evalErrorLogInterval)
+	processEvents(1)
+	require.Equal(t, []loglib.Fields{
+		{"schema": "public", "table": "orders", "suppressed_errors": uint64(0)},
+		{"schema": "public", "table": "orders", "suppressed_errors": uint64(2)},
+	}, logger.warnings)
+}
+
+type warnLogger struct {
+	loglib.NoopLogger
+	warnings []loglib.Fields
+}
+
+func (l *warnLogger) Warn(err error, msg string, fields ...loglib.Fields) {
+	l.warnings = append(l.warnings, fields...)
+}
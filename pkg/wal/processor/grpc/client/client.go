@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/backoff"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/changespb"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Client subscribes to the change events streamed by the pgstream gRPC
+// server.
+type Client struct {
+	conn            *grpclib.ClientConn
+	client          changespb.ChangeStreamClient
+	backoffProvider backoff.Provider
+	logger          loglib.Logger
+	dialOpts        []grpclib.DialOption
+}
+
+// Filter restricts the events received. Empty fields match all the events.
+type Filter struct {
+	Schema  string
+	Table   string
+	Actions []string
+}
+
+// Handler processes the change events received, in order. If it returns an
+// error, the subscription is ended.
+type Handler func(ctx context.Context, event *changespb.ChangeEvent) error
+
+type Option func(*Client)
+
+var (
+	errHandler     = errors.New("handling change event")
+	errResubscribe = errors.New("stream interrupted after receiving events")
+)
+
+func New(cfg *Config, opts ...Option) (*Client, error) {
+	c := &Client{
+		backoffProvider: backoff.NewProvider(cfg.backoffConfig()),
+		logger:          loglib.NewNoopLogger(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	tlsConfig, err := tlslib.NewConfig(&cfg.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("building tls config: %w", err)
+	}
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	dialOpts := []grpclib.DialOption{grpclib.WithTransportCredentials(creds)}
+	if cfg.APIKey != "" {
+		dialOpts = append(dialOpts, grpclib.WithPerRPCCredentials(&apiKeyCredentials{key: cfg.APIKey}))
+	}
+	dialOpts = append(dialOpts, c.dialOpts...)
+	c.conn, err = grpclib.NewClient(cfg.Address, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating grpc client: %w", err)
+	}
+	c.client = changespb.NewChangeStreamClient(c.conn)
+
+	return c, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(c *Client) {
+		c.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "grpc_client",
+		})
+	}
+}
+
+// WithDialOptions adds the options on input to the ones used to connect to
+// the server.
+func WithDialOptions(opts ...grpclib.DialOption) Option {
+	return func(c *Client) {
+		c.dialOpts = append(c.dialOpts, opts...)
+	}
+}
+
+// Subscribe streams the events matching the filter to the handler, starting
+// after the resume sequence if provided, until the context is cancelled or
+// the handler returns an error. When the stream is interrupted, the client
+// resubscribes from the last event received.
+func (c *Client) Subscribe(ctx context.Context, filter Filter, resumeSequence uint64, handler Handler) error {
+	req := &changespb.SubscribeRequest{
+		Schema:         filter.Schema,
+		Table:          filter.Table,
+		Actions:        filter.Actions,
+		ResumeSequence: resumeSequence,
+	}
+
+	for {
+		// a new backoff is used after every interruption following received
+		// events, so that the retries are only limited for consecutive
+		// failures
+		var subscribeErr error
+		resubscribe := false
+		err := c.backoffProvider(ctx).RetryNotify(
+			func() error {
+				received, err := c.consume(ctx, req, handler)
+				subscribeErr = err
+				retryable := err != nil && isRetryable(err)
+				resubscribe = retryable && received > 0
+				if err != nil && (!retryable || resubscribe) {
+					return fmt.Errorf("%w: %w", err, backoff.ErrPermanent)
+				}
+				return err
+			},
+			func(err error, d time.Duration) {
+				c.logger.Warn(err, fmt.Sprintf("grpc client: subscription interrupted, retrying in %v", d))
+			})
+		if !resubscribe {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if subscribeErr == nil {
+				return err
+			}
+			return subscribeErr
+		}
+		c.logger.Warn(subscribeErr, "grpc client: subscription interrupted, resubscribing", loglib.Fields{
+			"resume_sequence": req.ResumeSequence,
+		})
+	}
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// consume receives the events from a new subscription, updating the resume
+// sequence of the request as they're handled. It returns the number of events
+// received before the stream ended.
+func (c *Client) consume(ctx context.Context, req *changespb.SubscribeRequest, handler Handler) (int, error) {
+	stream, err := c.client.Subscribe(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	received := 0
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return received, ctxErr
+			}
+			return received, err
+		}
+		received++
+
+		if err := handler(ctx, event); err != nil {
+			return received, fmt.Errorf("%w: %w", errHandler, err)
+		}
+		if event.GetSequence() != 0 {
+			req.ResumeSequence = event.GetSequence()
+		}
+	}
+}
+
+// isRetryable returns true if the error ended the stream without the client
+// being at fault, in which case it can resubscribe.
+func isRetryable(err error) bool {
+	if errors.Is(err, io.EOF) {
+		// the server ended the stream
+		return true
+	}
+	if errors.Is(err, errHandler) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
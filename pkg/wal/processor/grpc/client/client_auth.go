@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import "context"
+
+// apiKeyCredentials sends the api key as a bearer token on every call.
+type apiKeyCredentials struct {
+	key string
+}
+
+func (c *apiKeyCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.key}, nil
+}
+
+// RequireTransportSecurity returns false, since the server can run in
+// plaintext behind a TLS terminating proxy.
+func (c *apiKeyCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/backoff"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/changespb"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockChangeStreamServer struct {
+	changespb.UnimplementedChangeStreamServer
+
+	mu          sync.Mutex
+	calls       int
+	subscribeFn func(call int, req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error
+}
+
+func (m *mockChangeStreamServer) Subscribe(req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error {
+	m.mu.Lock()
+	m.calls++
+	call := m.calls
+	m.mu.Unlock()
+	return m.subscribeFn(call, req, stream)
+}
+
+func newTestClient(t *testing.T, srv changespb.ChangeStreamServer) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpclib.NewServer()
+	changespb.RegisterChangeStreamServer(server, srv)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	c, err := New(&Config{
+		Address: "passthrough:///bufconn",
+		Backoff: &backoff.Config{
+			Constant: &backoff.ConstantConfig{Interval: time.Millisecond, MaxRetries: 2},
+		},
+	}, WithDialOptions(grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})))
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("oh noes")
+
+	tests := []struct {
+		name        string
+		subscribeFn func(call int, req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error
+		handlerErr  error
+
+		wantSequences []uint64
+		wantErrCode   codes.Code
+		wantErr       error
+	}{
+		{
+			name: "ok - resubscribes from the last event received",
+			subscribeFn: func(call int, req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error {
+				switch call {
+				case 1:
+					if req.ResumeSequence != 1 || req.Table != "users" {
+						return status.Error(codes.InvalidArgument, "unexpected request")
+					}
+					// events with the same LSN are resumed by sequence
+					if err := stream.Send(&changespb.ChangeEvent{Lsn: "0/2", Sequence: 2}); err != nil {
+						return err
+					}
+					if err := stream.Send(&changespb.ChangeEvent{Lsn: "0/2", Sequence: 3}); err != nil {
+						return err
+					}
+					return status.Error(codes.ResourceExhausted, "client too slow")
+				case 2:
+					// the connection fails before any events are received
+					return status.Error(codes.Unavailable, "unavailable")
+				case 3:
+					if req.ResumeSequence != 3 {
+						return status.Error(codes.InvalidArgument, "unexpected resume sequence")
+					}
+					if err := stream.Send(&changespb.ChangeEvent{Lsn: "0/3", Sequence: 4}); err != nil {
+						return err
+					}
+					return status.Error(codes.PermissionDenied, "done")
+				default:
+					return status.Error(codes.Internal, "unexpected call")
+				}
+			},
+
+			wantSequences: []uint64{2, 3, 4},
+			wantErrCode:   codes.PermissionDenied,
+		},
+		{
+			name: "error - retries exhausted",
+			subscribeFn: func(call int, req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error {
+				return status.Error(codes.Unavailable, "unavailable")
+			},
+
+			wantSequences: []uint64{},
+			wantErrCode:   codes.Unavailable,
+		},
+		{
+			name: "error - handling event",
+			subscribeFn: func(call int, req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error {
+				if call > 1 {
+					return status.Error(codes.Internal, "unexpected call")
+				}
+				if err := stream.Send(&changespb.ChangeEvent{Lsn: "0/2", Sequence: 2}); err != nil {
+					return err
+				}
+				<-stream.Context().Done()
+				return nil
+			},
+			handlerErr: errTest,
+
+			wantSequences: []uint64{2},
+			wantErr:       errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			c := newTestClient(t, &mockChangeStreamServer{subscribeFn: tc.subscribeFn})
+
+			sequences := []uint64{}
+			err := c.Subscribe(ctx, Filter{Table: "users"}, 1, func(_ context.Context, event *changespb.ChangeEvent) error {
+				sequences = append(sequences, event.Sequence)
+				return tc.handlerErr
+			})
+			require.Error(t, err)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.Equal(t, tc.wantErrCode, status.Code(err))
+			}
+			require.Equal(t, tc.wantSequences, sequences)
+		})
+	}
+}
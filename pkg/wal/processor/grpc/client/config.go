@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/backoff"
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+)
+
+type Config struct {
+	// Address of the pgstream gRPC server. The format is "host:port".
+	Address string
+	// TLS configuration for the connection. Plaintext is used when disabled.
+	TLS tlslib.Config
+	// APIKey is sent as a bearer token to authenticate with the server.
+	// Optional.
+	APIKey string
+	// Backoff configuration for resubscribing after the stream is
+	// interrupted. Defaults to an exponential backoff with a 1s initial
+	// interval, for up to 1m.
+	Backoff *backoff.Config
+}
+
+const (
+	defaultInitialInterval = time.Second
+	defaultMaxInterval     = time.Minute
+)
+
+func (c *Config) backoffConfig() *backoff.Config {
+	if c.Backoff != nil {
+		return c.Backoff
+	}
+	return &backoff.Config{
+		Exponential: &backoff.ExponentialConfig{
+			InitialInterval: defaultInitialInterval,
+			MaxInterval:     defaultMaxInterval,
+		},
+	}
+}
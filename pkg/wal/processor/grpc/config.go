@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"time"
+
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+)
+
+type Config struct {
+	// Address for the server to listen on. The format is "host:port". Defaults
+	// to ":9920".
+	Address string
+	// BufferSize is the number of events kept in memory for clients resuming
+	// from a previous sequence. Defaults to 1000.
+	BufferSize int
+	// ClientBufferSize is the number of events queued per client. Clients
+	// that fall behind by more than this number of events are disconnected.
+	// Defaults to 100.
+	ClientBufferSize int
+	// CheckpointInterval is the interval at which the positions of the
+	// broadcasted events are checkpointed. Defaults to 1s.
+	CheckpointInterval time.Duration
+	// APIKeys are the keys accepted to authenticate the clients, sent either
+	// as a bearer token in the authorization metadata or in the x-api-key
+	// metadata. Authentication is disabled if empty.
+	APIKeys []string
+	// TLS enables TLS for the server, and mutual TLS authentication when a
+	// client CA is provided. The server is plaintext if nil.
+	TLS *tlslib.ServerConfig
+}
+
+const (
+	defaultAddress            = ":9920"
+	defaultBufferSize         = 1000
+	defaultClientBufferSize   = 100
+	defaultCheckpointInterval = time.Second
+)
+
+func (c *Config) address() string {
+	if c.Address != "" {
+		return c.Address
+	}
+	return defaultAddress
+}
+
+func (c *Config) bufferSize() int {
+	if c.BufferSize > 0 {
+		return c.BufferSize
+	}
+	return defaultBufferSize
+}
+
+func (c *Config) clientBufferSize() int {
+	if c.ClientBufferSize > 0 {
+		return c.ClientBufferSize
+	}
+	return defaultClientBufferSize
+}
+
+func (c *Config) checkpointInterval() time.Duration {
+	if c.CheckpointInterval > 0 {
+		return c.CheckpointInterval
+	}
+	return defaultCheckpointInterval
+}
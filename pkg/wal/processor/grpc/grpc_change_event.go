@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/changespb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// toChangeEvent converts the wal event on input into its protobuf
+// representation.
+func toChangeEvent(walEvent *wal.Event) (*changespb.ChangeEvent, error) {
+	data := walEvent.Data
+	columns, err := toColumns(data.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("converting columns: %w", err)
+	}
+	identity, err := toColumns(data.Identity)
+	if err != nil {
+		return nil, fmt.Errorf("converting identity: %w", err)
+	}
+
+	event := &changespb.ChangeEvent{
+		Action:    data.Action,
+		Timestamp: data.Timestamp,
+		Lsn:       data.LSN,
+		Schema:    data.Schema,
+		Table:     data.Table,
+		Columns:   columns,
+		Identity:  identity,
+		Metadata: &changespb.Metadata{
+			TablePgstreamId:      data.Metadata.TablePgstreamID,
+			IdColPgstreamIds:     data.Metadata.InternalColIDs,
+			VersionColPgstreamId: data.Metadata.InternalColVersion,
+		},
+		CommitPosition: string(walEvent.CommitPosition),
+	}
+	if !data.Metadata.SchemaID.IsNil() {
+		event.Metadata.SchemaId = data.Metadata.SchemaID.String()
+	}
+
+	if data.SchemaChange != nil {
+		if event.SchemaChange, err = toStruct(data.SchemaChange); err != nil {
+			return nil, fmt.Errorf("converting schema change: %w", err)
+		}
+	}
+
+	return event, nil
+}
+
+func toColumns(walColumns []wal.Column) ([]*changespb.Column, error) {
+	if len(walColumns) == 0 {
+		return nil, nil
+	}
+	columns := make([]*changespb.Column, 0, len(walColumns))
+	for _, col := range walColumns {
+		value, err := toValue(col.Value)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		columns = append(columns, &changespb.Column{
+			Id:    col.ID,
+			Name:  col.Name,
+			Type:  col.Type,
+			Value: value,
+		})
+	}
+	return columns, nil
+}
+
+// toValue converts the column value into a protobuf value. Numbers decoded
+// without loss of precision are kept as strings, since they might not fit in
+// a double.
+func toValue(v any) (*structpb.Value, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return structpb.NewStringValue(val.String()), nil
+	case map[string]any:
+		fields := make(map[string]*structpb.Value, len(val))
+		for k, fv := range val {
+			value, err := toValue(fv)
+			if err != nil {
+				return nil, err
+			}
+			fields[k] = value
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+	case []any:
+		values := make([]*structpb.Value, 0, len(val))
+		for _, lv := range val {
+			value, err := toValue(lv)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+	}
+
+	if value, err := structpb.NewValue(v); err == nil {
+		return value, nil
+	}
+
+	// fall back to the JSON representation of the types not supported by the
+	// protobuf values
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(decoded)
+}
+
+func toStruct(v any) (*structpb.Struct, error) {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(jsonBytes); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
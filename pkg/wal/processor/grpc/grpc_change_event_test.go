@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/changespb"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestToChangeEvent(t *testing.T) {
+	t.Parallel()
+
+	schemaID := xid.New()
+
+	tests := []struct {
+		name     string
+		walEvent *wal.Event
+
+		wantEvent *changespb.ChangeEvent
+		wantErr   error
+	}{
+		{
+			name: "ok - insert",
+			walEvent: &wal.Event{
+				CommitPosition: "0/15D6B58",
+				Data: &wal.Data{
+					Action:    "I",
+					Timestamp: "2024-05-01 10:00:00.000000+00",
+					LSN:       "0/15D6B58",
+					Schema:    "public",
+					Table:     "users",
+					Columns: []wal.Column{
+						{ID: "c1", Name: "id", Type: "bigint", Value: json.Number("9007199254740993")},
+						{ID: "c2", Name: "name", Type: "text", Value: "alice"},
+						{ID: "c3", Name: "deleted", Type: "boolean", Value: nil},
+						{ID: "c4", Name: "tags", Type: "jsonb", Value: map[string]any{"score": json.Number("1.5"), "list": []any{"a", true}}},
+						{ID: "c5", Name: "ints", Type: "integer[]", Value: []int{1, 2}},
+					},
+					Metadata: wal.Metadata{
+						SchemaID:           schemaID,
+						TablePgstreamID:    "t1",
+						InternalColIDs:     []string{"c1"},
+						InternalColVersion: "c2",
+					},
+				},
+			},
+
+			wantEvent: &changespb.ChangeEvent{
+				Action:    "I",
+				Timestamp: "2024-05-01 10:00:00.000000+00",
+				Lsn:       "0/15D6B58",
+				Schema:    "public",
+				Table:     "users",
+				Columns: []*changespb.Column{
+					{Id: "c1", Name: "id", Type: "bigint", Value: structpb.NewStringValue("9007199254740993")},
+					{Id: "c2", Name: "name", Type: "text", Value: structpb.NewStringValue("alice")},
+					{Id: "c3", Name: "deleted", Type: "boolean", Value: structpb.NewNullValue()},
+					{Id: "c4", Name: "tags", Type: "jsonb", Value: structpb.NewStructValue(&structpb.Struct{
+						Fields: map[string]*structpb.Value{
+							"score": structpb.NewStringValue("1.5"),
+							"list": structpb.NewListValue(&structpb.ListValue{
+								Values: []*structpb.Value{structpb.NewStringValue("a"), structpb.NewBoolValue(true)},
+							}),
+						},
+					})},
+					{Id: "c5", Name: "ints", Type: "integer[]", Value: structpb.NewListValue(&structpb.ListValue{
+						Values: []*structpb.Value{structpb.NewNumberValue(1), structpb.NewNumberValue(2)},
+					})},
+				},
+				Metadata: &changespb.Metadata{
+					SchemaId:             schemaID.String(),
+					TablePgstreamId:      "t1",
+					IdColPgstreamIds:     []string{"c1"},
+					VersionColPgstreamId: "c2",
+				},
+				CommitPosition: "0/15D6B58",
+			},
+		},
+		{
+			name: "ok - delete with identity",
+			walEvent: &wal.Event{
+				Data: &wal.Data{
+					Action:   "D",
+					Schema:   "public",
+					Table:    "users",
+					Identity: []wal.Column{{ID: "c1", Name: "id", Type: "bigint", Value: float64(1)}},
+				},
+			},
+
+			wantEvent: &changespb.ChangeEvent{
+				Action:   "D",
+				Schema:   "public",
+				Table:    "users",
+				Identity: []*changespb.Column{{Id: "c1", Name: "id", Type: "bigint", Value: structpb.NewNumberValue(1)}},
+				Metadata: &changespb.Metadata{},
+			},
+		},
+		{
+			name: "ok - schema change",
+			walEvent: &wal.Event{
+				Data: &wal.Data{
+					Action: wal.SchemaChangeAction,
+					Schema: "public",
+					SchemaChange: &wal.SchemaChange{
+						SchemaName: "public",
+						SchemaID:   schemaID,
+						Version:    2,
+						Schema:     schemalog.Schema{Tables: []schemalog.Table{{Name: "users"}}},
+					},
+				},
+			},
+
+			wantEvent: &changespb.ChangeEvent{
+				Action:   wal.SchemaChangeAction,
+				Schema:   "public",
+				Metadata: &changespb.Metadata{},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := toChangeEvent(tc.walEvent)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.walEvent.Data.SchemaChange != nil {
+				require.NotNil(t, event.SchemaChange)
+				require.Equal(t, "public", event.SchemaChange.Fields["schema_name"].GetStringValue())
+				require.Equal(t, float64(2), event.SchemaChange.Fields["version"].GetNumberValue())
+				event.SchemaChange = nil
+			}
+			require.True(t, proto.Equal(tc.wantEvent, event), "got %v", event)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/broadcast"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/changespb"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// Server is a wal processor that streams the wal events to the gRPC clients
+// subscribed to the ChangeStream service. Events are only kept in memory, so
+// the stream is best effort: every client has a bounded queue, and clients
+// that fall behind are disconnected instead of blocking the replication. They
+// can resubscribe from their last sequence as long as it's still in the
+// buffer.
+type Server struct {
+	changespb.UnimplementedChangeStreamServer
+
+	server  *grpclib.Server
+	hub     *broadcast.Hub[*changespb.ChangeEvent]
+	logger  loglib.Logger
+	address string
+
+	// done is closed on shutdown to end the open subscriptions
+	done     chan struct{}
+	doneOnce sync.Once
+
+	checkpointInterval time.Duration
+
+	// checkpoint callback to mark what was broadcasted
+	checkpoint       checkpointer.Checkpoint
+	positionsMutex   sync.Mutex
+	pendingPositions []wal.CommitPosition
+}
+
+type Option func(*Server)
+
+func New(cfg *Config, opts ...Option) (*Server, error) {
+	s := &Server{
+		hub:                broadcast.NewHub[*changespb.ChangeEvent](cfg.bufferSize(), cfg.clientBufferSize()),
+		logger:             loglib.NewNoopLogger(),
+		address:            cfg.address(),
+		done:               make(chan struct{}),
+		checkpointInterval: cfg.checkpointInterval(),
+	}
+
+	serverOpts := []grpclib.ServerOption{}
+	if cfg.TLS != nil {
+		tlsConfig, err := tlslib.NewServerConfig(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("configuring grpc server tls: %w", err)
+		}
+		serverOpts = append(serverOpts, grpclib.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if len(cfg.APIKeys) > 0 {
+		serverOpts = append(serverOpts, grpclib.StreamInterceptor(apiKeyAuth(cfg.APIKeys)))
+	}
+	s.server = grpclib.NewServer(serverOpts...)
+	changespb.RegisterChangeStreamServer(s.server, s)
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(s *Server) {
+		s.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "grpc_server",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(s *Server) {
+		s.checkpoint = c
+	}
+}
+
+// ProcessWALEvent broadcasts the wal event to the subscribed clients. It
+// never blocks on slow clients.
+func (s *Server) ProcessWALEvent(ctx context.Context, walEvent *wal.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    walEvent.Data,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+			err = fmt.Errorf("grpc server: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	if walEvent.Data != nil {
+		changeEvent, err := toChangeEvent(walEvent)
+		if err != nil {
+			return fmt.Errorf("converting event: %w", err)
+		}
+
+		changeEvent.Sequence = s.hub.NextSequence()
+		e := &broadcast.Event[*changespb.ChangeEvent]{
+			Seq:     changeEvent.Sequence,
+			Action:  walEvent.Data.Action,
+			Schema:  walEvent.Data.Schema,
+			Table:   walEvent.Data.Table,
+			Payload: changeEvent,
+		}
+
+		if dropped := s.hub.Broadcast(e); dropped > 0 {
+			s.logger.Warn(nil, "grpc server: slow clients disconnected", loglib.Fields{
+				"dropped_clients": dropped,
+			})
+		}
+	}
+
+	if walEvent.CommitPosition != "" {
+		s.positionsMutex.Lock()
+		s.pendingPositions = append(s.pendingPositions, walEvent.CommitPosition)
+		s.positionsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// Send checkpoints the positions of the broadcasted events periodically, until
+// the context is cancelled.
+func (s *Server) Send(ctx context.Context) error {
+	ticker := time.NewTicker(s.checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.checkpointPending(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Start will start the gRPC server. This call is blocking.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("grpc server: listening on %s: %w", s.address, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve will serve the gRPC requests on the listener on input. This call is
+// blocking.
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info(fmt.Sprintf("grpc server listening on: %s...", lis.Addr()))
+	return s.server.Serve(lis)
+}
+
+// Shutdown ends the open subscriptions and stops the server gracefully. If the
+// context is done before the server has stopped, the remaining connections
+// are closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.doneOnce.Do(func() { close(s.done) })
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.server.GracefulStop()
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) Name() string {
+	return "grpc-server"
+}
+
+func (s *Server) Close() error {
+	return nil
+}
+
+// Subscribe streams the events matching the request filters to the client.
+// Each client is served from its own bounded queue, so that clients consuming
+// at different rates don't affect each other or the replication.
+func (s *Server) Subscribe(req *changespb.SubscribeRequest, stream changespb.ChangeStream_SubscribeServer) error {
+	filter := &subscription.Subscription{
+		Schema:     req.GetSchema(),
+		Table:      req.GetTable(),
+		EventTypes: req.GetActions(),
+	}
+
+	var resumeSeq *uint64
+	if seq := req.GetResumeSequence(); seq != 0 {
+		resumeSeq = &seq
+	}
+
+	client, replay := s.hub.Subscribe(filter, resumeSeq)
+	defer s.hub.Unsubscribe(client)
+
+	for _, e := range replay {
+		if err := stream.Send(e.Payload); err != nil {
+			return err
+		}
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server shutting down")
+		case e, ok := <-client.Events():
+			if !ok {
+				// the client was dropped for being too slow, it can resubscribe
+				// to resume from the last position received
+				return status.Error(codes.ResourceExhausted, "client too slow")
+			}
+			// the send blocks when the client's flow control window is full,
+			// in which case the events are queued on the client buffer
+			if err := stream.Send(e.Payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) checkpointPending(ctx context.Context) error {
+	s.positionsMutex.Lock()
+	positions := s.pendingPositions
+	s.pendingPositions = nil
+	s.positionsMutex.Unlock()
+
+	if s.checkpoint == nil || len(positions) == 0 {
+		return nil
+	}
+	if err := s.checkpoint(ctx, positions); err != nil {
+		return fmt.Errorf("grpc server: checkpointing positions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"strings"
+
+	httplib "github.com/ApollosProject/pgstream-wal2json/internal/http"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationMetadataKey = "authorization"
+	apiKeyMetadataKey        = "x-api-key"
+	bearerPrefix             = "Bearer "
+)
+
+// apiKeyAuth returns a stream interceptor that rejects the calls that don't
+// provide one of the keys on input, either as a bearer token in the
+// authorization metadata or in the api key metadata.
+func apiKeyAuth(keys []string) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, _ *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		key := ""
+		for _, v := range md.Get(authorizationMetadataKey) {
+			if strings.HasPrefix(v, bearerPrefix) {
+				key = strings.TrimPrefix(v, bearerPrefix)
+			}
+		}
+		if values := md.Get(apiKeyMetadataKey); key == "" && len(values) > 0 {
+			key = values[0]
+		}
+		if key == "" || !httplib.IsValidAPIKey(keys, key) {
+			return status.Error(codes.Unauthenticated, "invalid or missing api key")
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/changespb"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/grpc/client"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestServer(t *testing.T, cfg *Config) (*Server, *bufconn.Listener) {
+	t.Helper()
+
+	s, err := New(cfg)
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+	})
+
+	return s, lis
+}
+
+func newTestConn(t *testing.T, lis *bufconn.Listener) *grpclib.ClientConn {
+	t.Helper()
+
+	conn, err := grpclib.NewClient("passthrough:///bufconn",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newTestWalEvent(lsn, action, table string) *wal.Event {
+	return &wal.Event{
+		CommitPosition: wal.CommitPosition(lsn),
+		Data: &wal.Data{
+			Action: action,
+			LSN:    lsn,
+			Schema: "public",
+			Table:  table,
+			Columns: []wal.Column{
+				{ID: "c1", Name: "id", Type: "bigint", Value: float64(1)},
+			},
+		},
+	}
+}
+
+func TestServer_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, lis := newTestServer(t, &Config{})
+
+	// the events broadcasted before the subscription are only used to get
+	// the sequence to resume from
+	stream, err := changespb.NewChangeStreamClient(newTestConn(t, lis)).Subscribe(ctx, &changespb.SubscribeRequest{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/1", "I", "users")))
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/2", "I", "teams")))
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/2", "U", "users")))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	teamsEvent, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "teams", teamsEvent.Table)
+
+	c, err := client.New(&client.Config{Address: "passthrough:///bufconn"},
+		client.WithDialOptions(grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		})))
+	require.NoError(t, err)
+	defer c.Close()
+
+	receivedChan := make(chan *changespb.ChangeEvent, 10)
+	subscribeCtx, subscribeCancel := context.WithCancel(ctx)
+	defer subscribeCancel()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- c.Subscribe(subscribeCtx, client.Filter{Table: "users"}, teamsEvent.Sequence,
+			func(_ context.Context, event *changespb.ChangeEvent) error {
+				receivedChan <- event
+				return nil
+			})
+	}()
+
+	// the buffered events after the resume sequence are replayed, including
+	// the ones with the same LSN
+	event := <-receivedChan
+	require.Equal(t, "0/2", event.Lsn)
+	require.Equal(t, "U", event.Action)
+	require.Greater(t, event.Sequence, teamsEvent.Sequence)
+
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 2 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/4", "I", "teams")))
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/5", "D", "users")))
+
+	event = <-receivedChan
+	require.Equal(t, "0/5", event.Lsn)
+	require.Equal(t, "D", event.Action)
+	require.Equal(t, "public", event.Schema)
+	require.Len(t, event.Columns, 1)
+	require.Equal(t, float64(1), event.Columns[0].Value.GetNumberValue())
+	require.Equal(t, "0/5", event.CommitPosition)
+
+	subscribeCancel()
+	require.ErrorIs(t, <-errChan, context.Canceled)
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestServer_Subscribe_errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("slow client disconnected", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		s, lis := newTestServer(t, &Config{ClientBufferSize: 1})
+		stream, err := changespb.NewChangeStreamClient(newTestConn(t, lis)).Subscribe(ctx, &changespb.SubscribeRequest{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+
+		// broadcast events until the client queue is full, without reading
+		// them. The events are buffered by the transport before blocking the
+		// server stream, so a large number is needed.
+		for i := 0; i < 10000 && s.hub.ClientCount() == 1; i++ {
+			require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("", "I", "users")))
+		}
+		require.Equal(t, 0, s.hub.ClientCount())
+
+		for {
+			if _, err = stream.Recv(); err != nil {
+				break
+			}
+		}
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("server shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		s, lis := newTestServer(t, &Config{})
+		stream, err := changespb.NewChangeStreamClient(newTestConn(t, lis)).Subscribe(ctx, &changespb.SubscribeRequest{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, s.Shutdown(ctx))
+		_, err = stream.Recv()
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestServer_apiKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	const testAPIKey = "test-api-key"
+	s, lis := newTestServer(t, &Config{APIKeys: []string{testAPIKey}})
+	conn := newTestConn(t, lis)
+
+	tests := []struct {
+		name     string
+		metadata []string
+
+		wantCode codes.Code
+	}{
+		{
+			name:     "ok - bearer token",
+			metadata: []string{"authorization", "Bearer " + testAPIKey},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "ok - api key metadata",
+			metadata: []string{"x-api-key", testAPIKey},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "error - missing api key",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "error - invalid api key",
+			metadata: []string{"authorization", "Bearer invalid"},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if len(tc.metadata) > 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, tc.metadata...)
+			}
+
+			stream, err := changespb.NewChangeStreamClient(conn).Subscribe(ctx, &changespb.SubscribeRequest{})
+			require.NoError(t, err)
+			if tc.wantCode != codes.OK {
+				_, err = stream.Recv()
+				require.Equal(t, tc.wantCode, status.Code(err))
+				return
+			}
+
+			require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+			require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/1", "I", "users")))
+			_, err = stream.Recv()
+			require.NoError(t, err)
+			cancel()
+			require.Eventually(t, func() bool { return s.hub.ClientCount() == 0 }, time.Second, 10*time.Millisecond)
+		})
+	}
+
+	t.Run("ok - client api key", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		c, err := client.New(&client.Config{Address: "passthrough:///bufconn", APIKey: testAPIKey},
+			client.WithDialOptions(grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			})))
+		require.NoError(t, err)
+		defer c.Close()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- c.Subscribe(ctx, client.Filter{}, 0, func(context.Context, *changespb.ChangeEvent) error {
+				cancel()
+				return nil
+			})
+		}()
+
+		require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+		require.NoError(t, s.ProcessWALEvent(context.Background(), newTestWalEvent("0/2", "I", "users")))
+		require.ErrorIs(t, <-errChan, context.Canceled)
+	})
+}
+
+func TestServer_Send(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	checkpointed := []wal.CommitPosition{}
+	s, err := New(&Config{CheckpointInterval: 10 * time.Millisecond},
+		WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+			mu.Lock()
+			defer mu.Unlock()
+			checkpointed = append(checkpointed, positions...)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	sendCtx, sendCancel := context.WithCancel(ctx)
+	errChan := make(chan error, 1)
+	go func() { errChan <- s.Send(sendCtx) }()
+
+	// events are checkpointed once broadcasted, even with no clients
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestWalEvent("0/1", "I", "users")))
+	require.NoError(t, s.ProcessWALEvent(ctx, &wal.Event{CommitPosition: "0/2"}))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(checkpointed) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []wal.CommitPosition{"0/1", "0/2"}, checkpointed)
+
+	sendCancel()
+	require.ErrorIs(t, <-errChan, context.Canceled)
+}
@@ -4,6 +4,7 @@ package kafka
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/ApollosProject/pgstream-wal2json/pkg/kafka"
@@ -23,6 +24,15 @@ type Config struct {
 	// MaxQueueBytes is the max memory used by the batch writer for inflight
 	// batches. Defaults to 100MiB
 	MaxQueueBytes int64
+	// TopicRoutes send the wal events matching their CEL filter expression to
+	// a different topic. The events are sent to the topic of the first
+	// matching route, or to the configured topic if none match.
+	TopicRoutes []TopicRoute
+}
+
+type TopicRoute struct {
+	Expression string
+	Topic      string
 }
 
 const (
@@ -53,6 +63,18 @@ func (c *Config) batchTimeout() time.Duration {
 	return defaultBatchTimeout
 }
 
+// routedTopics returns the distinct route topics other than the configured
+// topic.
+func (c *Config) routedTopics() []string {
+	topics := []string{}
+	for _, route := range c.TopicRoutes {
+		if route.Topic != c.Kafka.Topic.Name && !slices.Contains(topics, route.Topic) {
+			topics = append(topics, route.Topic)
+		}
+	}
+	return topics
+}
+
 func (c *Config) maxQueueBytes() (int64, error) {
 	if c.MaxQueueBytes > 0 {
 		if c.MaxQueueBytes < c.batchBytes() {
@@ -18,6 +18,7 @@ import (
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
 )
 
 // BatchWriter is a kafka writer that uses batches to send the data to the
@@ -40,11 +41,23 @@ type BatchWriter struct {
 	checkpointer checkpointer.Checkpoint
 
 	serialiser func(any) ([]byte, error)
+
+	// topicRoutes are used to set the topic of each message when configured
+	topicRoutes  []*topicRoute
+	defaultTopic string
+}
+
+type topicRoute struct {
+	expression *filter.Expression
+	topic      string
 }
 
 type Option func(*BatchWriter)
 
-var errRecordTooLarge = errors.New("record too large")
+var (
+	errRecordTooLarge  = errors.New("record too large")
+	errEmptyRouteTopic = errors.New("empty route topic")
+)
 
 func NewBatchWriter(config *Config, opts ...Option) (*BatchWriter, error) {
 	w := &BatchWriter{
@@ -62,6 +75,23 @@ func NewBatchWriter(config *Config, opts ...Option) (*BatchWriter, error) {
 	}
 	w.queueBytesSema = synclib.NewWeightedSemaphore(int64(maxQueueBytes))
 
+	for i, route := range config.TopicRoutes {
+		if route.Topic == "" {
+			return nil, fmt.Errorf("topic route %d: %w", i, errEmptyRouteTopic)
+		}
+		expression, err := filter.Compile(route.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("topic route %d: %w", i, err)
+		}
+		w.topicRoutes = append(w.topicRoutes, &topicRoute{
+			expression: expression,
+			topic:      route.Topic,
+		})
+	}
+	if len(w.topicRoutes) > 0 {
+		w.defaultTopic = config.Kafka.Topic.Name
+	}
+
 	// Since the batch kafka writer handles the batching, we don't want to have
 	// a timeout configured in the underlying kafka-go writer or the latency for
 	// the send will increase unnecessarily. Instead, we set the kafka-go writer
@@ -78,6 +108,7 @@ func NewBatchWriter(config *Config, opts ...Option) (*BatchWriter, error) {
 		BatchTimeout: kafkaBatchTimeout,
 		BatchSize:    config.batchSize(),
 		BatchBytes:   config.batchBytes(),
+		RoutedTopics: config.routedTopics(),
 	}, w.logger)
 	if err != nil {
 		return nil, err
@@ -152,6 +183,7 @@ func (w *BatchWriter) ProcessWALEvent(ctx context.Context, walEvent *wal.Event)
 		}
 
 		kafkaMsg.msg = kafka.Message{
+			Topic: w.getMessageTopic(walEvent.Data),
 			Key:   w.getMessageKey(walEvent.Data),
 			Value: walDataBytes,
 		}
@@ -268,6 +300,23 @@ func (w *BatchWriter) sendBatch(ctx context.Context, batch *msgBatch) error {
 	return nil
 }
 
+// getMessageTopic returns the topic of the first topic route matching the wal
+// data on input, or the default topic if none match. It returns an empty
+// topic when no routes are configured, since the topic is then set on the
+// writer. Schema log events are always sent to the default topic, since
+// their routing key keeps them ordered with the writes of their schema.
+func (w *BatchWriter) getMessageTopic(walData *wal.Data) string {
+	if processor.IsSchemaLogEvent(walData) {
+		return w.defaultTopic
+	}
+	for _, route := range w.topicRoutes {
+		if match, _ := route.expression.Matches(walData); match {
+			return route.topic
+		}
+	}
+	return w.defaultTopic
+}
+
 // getMessageKey returns the key to be used in a kafka message for the wal event
 // on input. The message key determines which partition the event is routed to,
 // and therefore which order the events will be executed in. For schema logs,
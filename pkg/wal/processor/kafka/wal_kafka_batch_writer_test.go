@@ -19,6 +19,7 @@ import (
 	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
 	"github.com/stretchr/testify/require"
 
 	"golang.org/x/sync/semaphore"
@@ -512,3 +513,79 @@ func TestBatchKafkaWriter_sendBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchKafkaWriter_getMessageTopic(t *testing.T) {
+	t.Parallel()
+
+	ordersRoute, err := filter.Compile(`table == "orders"`)
+	require.NoError(t, err)
+	paidRoute, err := filter.Compile(`new.status == "paid"`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		topicRoutes []*topicRoute
+		data        *wal.Data
+
+		wantTopic string
+	}{
+		{
+			name: "no routes",
+			data: &wal.Data{Schema: testSchema, Table: "orders"},
+
+			wantTopic: "",
+		},
+		{
+			name: "first matching route",
+			topicRoutes: []*topicRoute{
+				{expression: paidRoute, topic: "paid"},
+				{expression: ordersRoute, topic: "orders"},
+			},
+			data: &wal.Data{
+				Schema:  testSchema,
+				Table:   "orders",
+				Columns: []wal.Column{{Name: "status", Value: "paid"}},
+			},
+
+			wantTopic: "paid",
+		},
+		{
+			name: "no matching route",
+			topicRoutes: []*topicRoute{
+				{expression: ordersRoute, topic: "orders"},
+			},
+			data: &wal.Data{Schema: testSchema, Table: testTable},
+
+			wantTopic: "default",
+		},
+		{
+			name: "schema log event",
+			topicRoutes: []*topicRoute{
+				{expression: paidRoute, topic: "paid"},
+			},
+			data: &wal.Data{
+				Schema:  schemalog.SchemaName,
+				Table:   schemalog.TableName,
+				Columns: []wal.Column{{Name: "status", Value: "paid"}},
+			},
+
+			wantTopic: "default",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			writer := &BatchWriter{
+				topicRoutes: tc.topicRoutes,
+			}
+			if len(tc.topicRoutes) > 0 {
+				writer.defaultTopic = "default"
+			}
+
+			require.Equal(t, tc.wantTopic, writer.getMessageTopic(tc.data))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package livefeed
+
+import (
+	"time"
+
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+)
+
+type Config struct {
+	// Address for the server to listen on. The format is "host:port". Defaults
+	// to ":9910".
+	Address string
+	// BufferSize is the number of events kept in memory for clients resuming
+	// from a previous event id. Defaults to 1000.
+	BufferSize int
+	// ClientBufferSize is the number of events queued per client. Clients
+	// that fall behind by more than this number of events are disconnected.
+	// Defaults to 100.
+	ClientBufferSize int
+	// KeepAliveInterval is the interval at which keep alive messages are sent
+	// to idle clients. Defaults to 15s.
+	KeepAliveInterval time.Duration
+	// CheckpointInterval is the interval at which the positions of the
+	// broadcasted events are checkpointed. Defaults to 1s.
+	CheckpointInterval time.Duration
+	// AllowedOrigins is the list of origins allowed for cross origin requests.
+	// Use `*` to allow all origins. Only same origin requests are allowed by
+	// default.
+	AllowedOrigins []string
+	// APIKeys are the keys accepted to authenticate the clients, sent either
+	// as a bearer token in the Authorization header, in the X-API-Key header
+	// or in the `api_key` query parameter. Authentication is disabled if
+	// empty.
+	APIKeys []string
+	// TLS enables HTTPS for the server, and mutual TLS authentication when a
+	// client CA is provided. Disabled if nil.
+	TLS *tlslib.ServerConfig
+}
+
+const (
+	defaultAddress            = ":9910"
+	defaultBufferSize         = 1000
+	defaultClientBufferSize   = 100
+	defaultKeepAliveInterval  = 15 * time.Second
+	defaultCheckpointInterval = time.Second
+)
+
+func (c *Config) address() string {
+	if c.Address != "" {
+		return c.Address
+	}
+	return defaultAddress
+}
+
+func (c *Config) bufferSize() int {
+	if c.BufferSize > 0 {
+		return c.BufferSize
+	}
+	return defaultBufferSize
+}
+
+func (c *Config) clientBufferSize() int {
+	if c.ClientBufferSize > 0 {
+		return c.ClientBufferSize
+	}
+	return defaultClientBufferSize
+}
+
+func (c *Config) keepAliveInterval() time.Duration {
+	if c.KeepAliveInterval > 0 {
+		return c.KeepAliveInterval
+	}
+	return defaultKeepAliveInterval
+}
+
+func (c *Config) checkpointInterval() time.Duration {
+	if c.CheckpointInterval > 0 {
+		return c.CheckpointInterval
+	}
+	return defaultCheckpointInterval
+}
@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package livefeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httplib "github.com/ApollosProject/pgstream-wal2json/internal/http"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/broadcast"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Server is a wal processor that streams the wal events to the connected
+// Server-Sent Events and WebSocket clients. Events are only kept in memory, so
+// the feed is best effort: clients that fall behind are disconnected, and can
+// resume from their last event id as long as it's still in the buffer.
+type Server struct {
+	server   httplib.Server
+	hub      *broadcast.Hub[*event]
+	logger   loglib.Logger
+	address  string
+	upgrader websocket.Upgrader
+
+	keepAliveInterval  time.Duration
+	checkpointInterval time.Duration
+
+	// checkpoint callback to mark what was broadcasted
+	checkpoint       checkpointer.Checkpoint
+	positionsMutex   sync.Mutex
+	pendingPositions []wal.CommitPosition
+
+	serialiser func(any) ([]byte, error)
+}
+
+type Option func(*Server)
+
+// wsMessage is the format of the messages sent to WebSocket clients
+type wsMessage struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	lastEventIDParam  = "last_event_id"
+	apiKeyParam       = "api_key"
+	wsWriteTimeout    = 10 * time.Second
+)
+
+// event is a serialised wal event sent to the clients. The id is the event
+// sequence in the feed, which clients use to resume it.
+type event struct {
+	id   string
+	data []byte
+}
+
+var errInvalidLastEventID = errors.New("invalid last event id")
+
+func New(cfg *Config, opts ...Option) *Server {
+	s := &Server{
+		hub:                broadcast.NewHub[*event](cfg.bufferSize(), cfg.clientBufferSize()),
+		logger:             loglib.NewNoopLogger(),
+		address:            cfg.address(),
+		keepAliveInterval:  cfg.keepAliveInterval(),
+		checkpointInterval: cfg.checkpointInterval(),
+		serialiser:         json.Marshal,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin(cfg.AllowedOrigins),
+		},
+	}
+
+	e := echo.New()
+	// the feed connections are long lived, so no write timeout is set
+	e.Server.ReadTimeout = 5 * time.Second
+	e.Use(middleware.Recover())
+	if len(cfg.AllowedOrigins) > 0 {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins: cfg.AllowedOrigins,
+			AllowHeaders: []string{lastEventIDHeader, echo.HeaderAuthorization, httplib.APIKeyHeader},
+			AllowMethods: []string{http.MethodGet},
+		}))
+	}
+	if len(cfg.APIKeys) > 0 {
+		// browsers can't set headers on EventSource and WebSocket requests,
+		// so the key can be provided as a query parameter
+		e.Use(httplib.APIKeyAuth(cfg.APIKeys, "query:"+apiKeyParam))
+	}
+
+	e.GET("/feed/events", s.serveSSE)
+	e.GET("/feed/ws", s.serveWebSocket)
+	s.server = e
+	if cfg.TLS != nil {
+		s.server = &httplib.TLSServer{Echo: e, Config: cfg.TLS}
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(s *Server) {
+		s.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "live_feed_server",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(s *Server) {
+		s.checkpoint = c
+	}
+}
+
+// ProcessWALEvent broadcasts the wal event to the subscribed clients. It
+// never blocks on slow clients.
+func (s *Server) ProcessWALEvent(ctx context.Context, walEvent *wal.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    walEvent.Data,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+			err = fmt.Errorf("live feed: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	if walEvent.Data != nil {
+		data, err := s.serialiser(walEvent.Data)
+		if err != nil {
+			return fmt.Errorf("marshalling event: %w", err)
+		}
+
+		seq := s.hub.NextSequence()
+		e := &broadcast.Event[*event]{
+			Seq:    seq,
+			Action: walEvent.Data.Action,
+			Schema: walEvent.Data.Schema,
+			Table:  walEvent.Data.Table,
+			Payload: &event{
+				id:   strconv.FormatUint(seq, 10),
+				data: data,
+			},
+		}
+
+		if dropped := s.hub.Broadcast(e); dropped > 0 {
+			s.logger.Warn(nil, "live feed: slow clients disconnected", loglib.Fields{
+				"dropped_clients": dropped,
+			})
+		}
+	}
+
+	if walEvent.CommitPosition != "" {
+		s.positionsMutex.Lock()
+		s.pendingPositions = append(s.pendingPositions, walEvent.CommitPosition)
+		s.positionsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// Send checkpoints the positions of the broadcasted events periodically, until
+// the context is cancelled.
+func (s *Server) Send(ctx context.Context) error {
+	ticker := time.NewTicker(s.checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.checkpointPending(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Start will start the live feed server. This call is blocking.
+func (s *Server) Start() error {
+	s.logger.Info(fmt.Sprintf("live feed server listening on: %s...", s.address))
+	return s.server.Start(s.address)
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) Name() string {
+	return "live-feed"
+}
+
+func (s *Server) Close() error {
+	return nil
+}
+
+func (s *Server) checkpointPending(ctx context.Context) error {
+	s.positionsMutex.Lock()
+	positions := s.pendingPositions
+	s.pendingPositions = nil
+	s.positionsMutex.Unlock()
+
+	if s.checkpoint == nil || len(positions) == 0 {
+		return nil
+	}
+	if err := s.checkpoint(ctx, positions); err != nil {
+		return fmt.Errorf("live feed: checkpointing positions: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) serveSSE(c echo.Context) error {
+	s.logger.Trace("request received on /feed/events endpoint")
+
+	filter, lastSeq, err := s.parseSubscription(c.Request(), c.Request().Header.Get(lastEventIDHeader))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	client, replay := s.hub.Subscribe(filter, lastSeq)
+	defer s.hub.Unsubscribe(client)
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	for _, e := range replay {
+		if err := writeSSEEvent(w, e.Payload); err != nil {
+			return nil
+		}
+	}
+	w.Flush()
+
+	ticker := time.NewTicker(s.keepAliveInterval)
+	defer ticker.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// comment lines are ignored by the clients
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		case e, ok := <-client.Events():
+			if !ok {
+				// the client was dropped for being too slow, it can reconnect
+				// to resume from the last event received
+				return nil
+			}
+			if err := writeSSEEvent(w, e.Payload); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w *echo.Response, e *event) error {
+	if e.id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", e.id); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "data: %s\n\n", e.data)
+	return err
+}
+
+func (s *Server) serveWebSocket(c echo.Context) error {
+	s.logger.Trace("request received on /feed/ws endpoint")
+
+	// browsers can't set headers on websocket requests, so the last event id
+	// can be provided as a query parameter
+	lastEventID := c.QueryParam(lastEventIDParam)
+	if lastEventID == "" {
+		lastEventID = c.Request().Header.Get(lastEventIDHeader)
+	}
+	filter, lastSeq, err := s.parseSubscription(c.Request(), lastEventID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	conn, err := s.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// the upgrader already replied with the relevant error
+		return nil
+	}
+	defer conn.Close()
+
+	client, replay := s.hub.Subscribe(filter, lastSeq)
+	defer s.hub.Unsubscribe(client)
+
+	// the connection needs to be read to process the control messages, and
+	// to detect when the client closes it
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, e := range replay {
+		if err := writeWSEvent(conn, e.Payload); err != nil {
+			return nil
+		}
+	}
+
+	ticker := time.NewTicker(s.keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return nil
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return nil
+			}
+		case e, ok := <-client.Events():
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"),
+					time.Now().Add(wsWriteTimeout))
+				return nil
+			}
+			if err := writeWSEvent(conn, e.Payload); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+func writeWSEvent(conn *websocket.Conn, e *event) error {
+	msg, err := json.Marshal(&wsMessage{ID: e.id, Data: e.data})
+	if err != nil {
+		return err
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+// parseSubscription returns the subscription filter from the request query
+// parameters (`schema`, `table` and `event_types`), which follow the same
+// semantics as the webhook subscriptions, and the sequence to resume from.
+func (s *Server) parseSubscription(r *http.Request, lastEventID string) (*subscription.Subscription, *uint64, error) {
+	query := r.URL.Query()
+	filter := &subscription.Subscription{
+		Schema: query.Get("schema"),
+		Table:  query.Get("table"),
+	}
+	for _, eventTypes := range query["event_types"] {
+		for _, eventType := range strings.Split(eventTypes, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				filter.EventTypes = append(filter.EventTypes, eventType)
+			}
+		}
+	}
+
+	if lastEventID == "" {
+		return filter, nil, nil
+	}
+	seq, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errInvalidLastEventID, err)
+	}
+	return filter, &seq, nil
+}
+
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		// use the default same origin check
+		return nil
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || slices.Contains(allowedOrigins, "*") || slices.Contains(allowedOrigins, origin)
+	}
+}
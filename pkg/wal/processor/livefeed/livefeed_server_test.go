@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package livefeed
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(lsn, table string) *wal.Event {
+	return &wal.Event{
+		Data: &wal.Data{
+			Action: "I",
+			LSN:    lsn,
+			Schema: "public",
+			Table:  table,
+		},
+		CommitPosition: wal.CommitPosition(lsn),
+	}
+}
+
+func newTestServer(t *testing.T, opts ...Option) (*Server, *httptest.Server) {
+	t.Helper()
+
+	s := New(&Config{KeepAliveInterval: time.Hour}, opts...)
+	e, ok := s.server.(*echo.Echo)
+	require.True(t, ok)
+	httpServer := httptest.NewServer(e)
+	t.Cleanup(httpServer.Close)
+	return s, httpServer
+}
+
+func TestServer_SSE(t *testing.T) {
+	t.Parallel()
+
+	s, httpServer := newTestServer(t)
+	ctx := context.Background()
+
+	subscribe := func(lastEventID string) <-chan [2]string {
+		req, err := http.NewRequest(http.MethodGet, httpServer.URL+"/feed/events?table=users&event_types=I,U", nil)
+		require.NoError(t, err)
+		if lastEventID != "" {
+			req.Header.Set(lastEventIDHeader, lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		events := make(chan [2]string, 10)
+		go func() {
+			scanner := bufio.NewScanner(resp.Body)
+			var id string
+			for scanner.Scan() {
+				line := scanner.Text()
+				switch {
+				case strings.HasPrefix(line, "id: "):
+					id = strings.TrimPrefix(line, "id: ")
+				case strings.HasPrefix(line, "data: "):
+					events <- [2]string{id, strings.TrimPrefix(line, "data: ")}
+				}
+			}
+		}()
+		return events
+	}
+
+	// live events, filtered
+	events := subscribe("")
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/1", "users")))
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/2", "teams")))
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/2", "users")))
+	first := <-events
+	second := <-events
+	require.NotEqual(t, first[0], second[0])
+	data := &wal.Data{}
+	require.NoError(t, json.Unmarshal([]byte(second[1]), data))
+	require.Equal(t, "users", data.Table)
+	require.Equal(t, "0/2", data.LSN)
+
+	// replayed from the buffer after the last event id, even when the events
+	// share the same LSN
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/2", "users")))
+	third := <-events
+	resumed := subscribe(second[0])
+	require.Equal(t, third, <-resumed)
+}
+
+func TestServer_SSE_invalidLastEventID(t *testing.T) {
+	t.Parallel()
+
+	_, httpServer := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, httpServer.URL+"/feed/events", nil)
+	require.NoError(t, err)
+	req.Header.Set(lastEventIDHeader, "invalid")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_WebSocket(t *testing.T) {
+	t.Parallel()
+
+	s, httpServer := newTestServer(t)
+	ctx := context.Background()
+
+	dial := func(lastEventID string) (*websocket.Conn, func() wsMessage) {
+		wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/feed/ws?schema=public&last_event_id=" + lastEventID
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			resp.Body.Close()
+			conn.Close()
+		})
+		return conn, func() wsMessage {
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			msg := wsMessage{}
+			require.NoError(t, conn.ReadJSON(&msg))
+			return msg
+		}
+	}
+
+	_, readLive := dial("")
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/1", "users")))
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/1", "teams")))
+	first := readLive()
+	second := readLive()
+	data := &wal.Data{}
+	require.NoError(t, json.Unmarshal(second.Data, data))
+	require.Equal(t, "teams", data.Table)
+
+	// resuming from an event replays the ones after it with the same LSN
+	conn, readResumed := dial(first.ID)
+	require.Equal(t, second, readResumed())
+
+	// the client is unsubscribed once the connection is closed
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 2 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool { return s.hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestServer_apiKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	const testAPIKey = "test-api-key"
+	s := New(&Config{KeepAliveInterval: time.Hour, APIKeys: []string{testAPIKey}})
+	e, ok := s.server.(*echo.Echo)
+	require.True(t, ok)
+	httpServer := httptest.NewServer(e)
+	t.Cleanup(httpServer.Close)
+
+	tests := []struct {
+		name   string
+		query  string
+		header http.Header
+
+		wantStatusCode int
+	}{
+		{
+			name:           "ok - bearer token",
+			header:         http.Header{"Authorization": []string{"Bearer " + testAPIKey}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "ok - api key header",
+			header:         http.Header{"X-Api-Key": []string{testAPIKey}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "ok - api key query parameter",
+			query:          "?api_key=" + testAPIKey,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "error - missing api key",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "error - invalid api key",
+			header:         http.Header{"X-Api-Key": []string{"invalid"}},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/feed/events"+tc.query, nil)
+			require.NoError(t, err)
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tc.wantStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestServer_checkpointPending(t *testing.T) {
+	t.Parallel()
+
+	checkpoints := []wal.CommitPosition{}
+	s := New(&Config{}, WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+		checkpoints = append(checkpoints, positions...)
+		return nil
+	}))
+
+	ctx := context.Background()
+	require.NoError(t, s.ProcessWALEvent(ctx, newTestEvent("0/1", "users")))
+	require.NoError(t, s.ProcessWALEvent(ctx, &wal.Event{CommitPosition: "0/2"}))
+	require.NoError(t, s.checkpointPending(ctx))
+	require.Equal(t, []wal.CommitPosition{"0/1", "0/2"}, checkpoints)
+
+	require.NoError(t, s.checkpointPending(ctx))
+	require.Equal(t, []wal.CommitPosition{"0/1", "0/2"}, checkpoints)
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"time"
+
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+)
+
+type Config struct {
+	// URL is the NATS server URL, or a comma separated list of server URLs.
+	URL string
+	// SubjectTemplate is the Go template used to generate the subject for the
+	// wal events. The `Schema`, `Table` and `Action` fields are available.
+	// Defaults to `pgstream.{{.Schema}}.{{.Table}}.{{.Action}}`.
+	SubjectTemplate string
+	// CredentialsFile is the optional path to the NATS user credentials file.
+	CredentialsFile string
+	TLS             tlslib.Config
+	// MaxPendingAcks is the max number of published messages waiting for a
+	// JetStream ack. Defaults to 1000.
+	MaxPendingAcks int
+	// AckTimeout is the max time to wait for the ack of a published message.
+	// Defaults to 5s.
+	AckTimeout time.Duration
+	// MaxQueueBytes is the max memory used by the publisher for inflight
+	// messages, including the ones waiting for an ack. Defaults to 100MiB
+	MaxQueueBytes int64
+}
+
+const (
+	defaultSubjectTemplate = "pgstream.{{.Schema}}.{{.Table}}.{{.Action}}"
+	defaultMaxPendingAcks  = 1000
+	defaultAckTimeout      = 5 * time.Second
+	defaultMaxQueueBytes   = int64(100 * 1024 * 1024) // 100MiB
+)
+
+func (c *Config) subjectTemplate() string {
+	if c.SubjectTemplate != "" {
+		return c.SubjectTemplate
+	}
+	return defaultSubjectTemplate
+}
+
+func (c *Config) maxPendingAcks() int {
+	if c.MaxPendingAcks > 0 {
+		return c.MaxPendingAcks
+	}
+	return defaultMaxPendingAcks
+}
+
+func (c *Config) ackTimeout() time.Duration {
+	if c.AckTimeout > 0 {
+		return c.AckTimeout
+	}
+	return defaultAckTimeout
+}
+
+func (c *Config) maxQueueBytes() int64 {
+	if c.MaxQueueBytes > 0 {
+		return c.MaxQueueBytes
+	}
+	return defaultMaxQueueBytes
+}
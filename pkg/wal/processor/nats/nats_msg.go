@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	natslib "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type msg struct {
+	msg *natslib.Msg
+	pos wal.CommitPosition
+}
+
+func (m *msg) size() int {
+	if m.msg == nil {
+		return 0
+	}
+	return len(m.msg.Data)
+}
+
+// pendingAck is a message that has been published, and is waiting for the
+// JetStream ack before its position can be checkpointed. Keep alive messages
+// have no ack future.
+type pendingAck struct {
+	future jetstream.PubAckFuture
+	pos    wal.CommitPosition
+	size   int
+}
@@ -0,0 +1,311 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	natslib "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Publisher is a wal processor that publishes the wal events to NATS
+// JetStream. Messages are published asynchronously, and their positions are
+// only checkpointed once JetStream has acked them.
+type Publisher struct {
+	conn     *natslib.Conn
+	js       jetstream.JetStream
+	subjects *subjectBuilder
+	logger   loglib.Logger
+
+	// queueBytesSema is used to limit the amount of memory used by the
+	// unbuffered msg channel and the messages waiting for an ack, optimising
+	// the channel performance for variable size messages, while preventing
+	// the process from running oom
+	queueBytesSema synclib.WeightedSemaphore
+	msgChan        chan (*msg)
+
+	maxPendingAcks int
+	ackTimeout     time.Duration
+
+	// optional checkpointer callback to mark what was safely processed
+	checkpointer checkpointer.Checkpoint
+
+	serialiser func(any) ([]byte, error)
+}
+
+type Option func(*Publisher)
+
+var (
+	errRecordTooLarge = errors.New("record too large")
+	errAckTimeout     = errors.New("timeout waiting for publish ack")
+)
+
+// maxCheckpointBatch is the max number of acked positions that are
+// checkpointed together.
+const maxCheckpointBatch = 100
+
+// NewPublisher returns a processor of wal events that publishes them to the
+// NATS JetStream subjects generated from the configured template.
+func NewPublisher(config *Config, opts ...Option) (*Publisher, error) {
+	subjects, err := newSubjectBuilder(config.subjectTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	natsOpts := []natslib.Option{
+		natslib.Name("pgstream"),
+		natslib.MaxReconnects(-1),
+	}
+	if config.CredentialsFile != "" {
+		natsOpts = append(natsOpts, natslib.UserCredentials(config.CredentialsFile))
+	}
+	tlsConfig, err := tlslib.NewConfig(&config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("building tls config: %w", err)
+	}
+	if tlsConfig != nil {
+		natsOpts = append(natsOpts, natslib.Secure(tlsConfig))
+	}
+
+	conn, err := natslib.Connect(config.URL, natsOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to nats: %w", err)
+	}
+
+	js, err := jetstream.New(conn, jetstream.WithPublishAsyncMaxPending(config.maxPendingAcks()))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("creating jetstream context: %w", err)
+	}
+
+	p := &Publisher{
+		conn:           conn,
+		js:             js,
+		subjects:       subjects,
+		logger:         loglib.NewNoopLogger(),
+		queueBytesSema: synclib.NewWeightedSemaphore(config.maxQueueBytes()),
+		msgChan:        make(chan *msg),
+		maxPendingAcks: config.maxPendingAcks(),
+		ackTimeout:     config.ackTimeout(),
+		serialiser:     json.Marshal,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(p *Publisher) {
+		p.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "nats_jetstream_publisher",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(p *Publisher) {
+		p.checkpointer = c
+	}
+}
+
+// ProcessWALEvent is called on every new message from the wal
+func (p *Publisher) ProcessWALEvent(ctx context.Context, walEvent *wal.Event) (retErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    walEvent,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+
+			retErr = fmt.Errorf("nats jetstream publisher: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	natsMsg := &msg{
+		pos: walEvent.CommitPosition,
+	}
+
+	if walEvent.Data != nil {
+		walDataBytes, err := p.serialiser(walEvent.Data)
+		if err != nil {
+			return fmt.Errorf("marshalling event: %w", err)
+		}
+
+		// check if the event is larger than the max payload accepted by the
+		// server, in which case it can't be published
+		if maxPayload := p.conn.MaxPayload(); int64(len(walDataBytes)) > maxPayload {
+			p.logger.Warn(errRecordTooLarge,
+				"nats jetstream publisher: wal event is larger than server max payload",
+				loglib.Fields{
+					"max_payload": maxPayload,
+					"size":        len(walDataBytes),
+					"table":       walEvent.Data.Table,
+					"schema":      walEvent.Data.Schema,
+				})
+		} else {
+			subject, err := p.subjects.subject(walEvent.Data)
+			if err != nil {
+				return err
+			}
+
+			natsMsg.msg = natslib.NewMsg(subject)
+			natsMsg.msg.Data = walDataBytes
+			if id := msgID(walEvent.Data); id != "" {
+				natsMsg.msg.Header.Set(natslib.MsgIdHdr, id)
+			}
+		}
+	}
+
+	// make sure we don't reach the queue memory limit before adding the new
+	// message to the channel. This will block until messages have been acked
+	// and their size is released
+	msgSize := int64(natsMsg.size())
+	if !p.queueBytesSema.TryAcquire(msgSize) {
+		p.logger.Warn(nil, "nats jetstream publisher: max queue bytes reached, processing blocked")
+		if err := p.queueBytesSema.Acquire(ctx, msgSize); err != nil {
+			return err
+		}
+	}
+
+	p.msgChan <- natsMsg
+
+	return nil
+}
+
+func (p *Publisher) Send(ctx context.Context) error {
+	// the acks are awaited on a separate go routine, in the same order the
+	// messages were published, so that publishing is not blocked by the
+	// round trip to the server and positions are checkpointed in order.
+	pendingChan := make(chan *pendingAck, p.maxPendingAcks)
+	defer close(pendingChan)
+	ackErrChan := make(chan error, 1)
+	go func() {
+		defer close(ackErrChan)
+		if err := p.waitForAcks(ctx, pendingChan); err != nil {
+			p.logger.Error(err, "nats jetstream publisher")
+			ackErrChan <- err
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ackErr := <-ackErrChan:
+			// if a message failed to be acked, stop publishing. The unacked
+			// positions will not be checkpointed.
+			return ackErr
+		case msg, ok := <-p.msgChan:
+			if !ok {
+				return nil
+			}
+			pending, err := p.publish(ctx, msg)
+			if err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ackErr := <-ackErrChan:
+				return ackErr
+			case pendingChan <- pending:
+			}
+		}
+	}
+}
+
+func (p *Publisher) Name() string {
+	return "nats-jetstream-publisher"
+}
+
+func (p *Publisher) Close() error {
+	close(p.msgChan)
+	p.conn.Close()
+	return nil
+}
+
+func (p *Publisher) publish(ctx context.Context, msg *msg) (*pendingAck, error) {
+	pending := &pendingAck{
+		pos:  msg.pos,
+		size: msg.size(),
+	}
+	if msg.msg == nil {
+		return pending, nil
+	}
+
+	for {
+		future, err := p.js.PublishMsgAsync(msg.msg)
+		switch {
+		case err == nil:
+			pending.future = future
+			return pending, nil
+		case errors.Is(err, jetstream.ErrTooManyStalledMsgs):
+			// the max pending acks has been reached, retry once the
+			// outstanding messages have been acked
+			p.logger.Warn(err, "nats jetstream publisher: max pending acks reached, publishing blocked")
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		default:
+			return nil, fmt.Errorf("nats jetstream publisher: publishing to %s: %w", msg.msg.Subject, err)
+		}
+	}
+}
+
+func (p *Publisher) waitForAcks(ctx context.Context, pendingChan <-chan *pendingAck) error {
+	positions := []wal.CommitPosition{}
+	for pending := range pendingChan {
+		if pending.future != nil {
+			timer := time.NewTimer(p.ackTimeout)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+				return fmt.Errorf("%w: %s", errAckTimeout, pending.future.Msg().Subject)
+			case err := <-pending.future.Err():
+				timer.Stop()
+				return fmt.Errorf("nats jetstream publisher: publishing to %s: %w", pending.future.Msg().Subject, err)
+			case <-pending.future.Ok():
+				timer.Stop()
+			}
+		}
+		p.queueBytesSema.Release(int64(pending.size))
+
+		if pending.pos != "" {
+			positions = append(positions, pending.pos)
+		}
+		// checkpoint as soon as there are no more acks to wait for, limiting
+		// the number of positions per checkpoint call under heavy load
+		if len(pendingChan) == 0 || len(positions) >= maxCheckpointBatch {
+			p.checkpoint(ctx, positions)
+			positions = []wal.CommitPosition{}
+		}
+	}
+	return nil
+}
+
+func (p *Publisher) checkpoint(ctx context.Context, positions []wal.CommitPosition) {
+	if p.checkpointer == nil || len(positions) == 0 {
+		return
+	}
+	if err := p.checkpointer(ctx, positions); err != nil {
+		p.logger.Warn(err, "nats jetstream publisher: error updating commit position")
+	}
+}
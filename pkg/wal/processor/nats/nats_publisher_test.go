@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/nats-io/nats-server/v2/server"
+	natslib "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	newEvent := func(lsn string, id float64) *wal.Event {
+		return &wal.Event{
+			Data: &wal.Data{
+				Action:  "I",
+				LSN:     lsn,
+				Schema:  "public",
+				Table:   "users",
+				Columns: []wal.Column{{Name: "id", Type: "bigint", Value: id}},
+			},
+			CommitPosition: wal.CommitPosition(lsn),
+		}
+	}
+
+	tests := []struct {
+		name            string
+		subjectTemplate string
+		events          []*wal.Event
+
+		wantSubjects    []string
+		wantMsgIDs      []string
+		wantCheckpoints []wal.CommitPosition
+		wantErr         bool
+	}{
+		{
+			name: "ok",
+			events: []*wal.Event{
+				newEvent("0/1", 1),
+				{CommitPosition: "0/2"},
+				{
+					Data: &wal.Data{
+						Action: wal.SchemaChangeAction,
+						LSN:    "0/3",
+						Schema: "public",
+					},
+					CommitPosition: "0/3",
+				},
+			},
+
+			wantSubjects:    []string{"pgstream.public.users.insert", "pgstream.public._.ddl"},
+			wantMsgIDs:      []string{"0/1:public.users:I", "0/3:public.:DDL"},
+			wantCheckpoints: []wal.CommitPosition{"0/1", "0/2", "0/3"},
+		},
+		{
+			name: "ok - replayed events are deduplicated",
+			events: []*wal.Event{
+				newEvent("0/1", 1),
+				newEvent("0/2", 2),
+				newEvent("0/1", 1),
+				newEvent("0/2", 2),
+			},
+
+			wantSubjects:    []string{"pgstream.public.users.insert", "pgstream.public.users.insert"},
+			wantMsgIDs:      []string{"0/1:public.users:I", "0/2:public.users:I"},
+			wantCheckpoints: []wal.CommitPosition{"0/1", "0/2", "0/1", "0/2"},
+		},
+		{
+			name:            "error - no stream for subject",
+			subjectTemplate: "other.{{.Table}}",
+			events: []*wal.Event{
+				newEvent("0/1", 1),
+			},
+
+			wantSubjects:    []string{},
+			wantMsgIDs:      []string{},
+			wantCheckpoints: []wal.CommitPosition{},
+			wantErr:         true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			url, stream := runJetStreamServer(ctx, t)
+
+			mu := sync.Mutex{}
+			checkpoints := []wal.CommitPosition{}
+			publisher, err := NewPublisher(&Config{
+				URL:             url,
+				SubjectTemplate: tc.subjectTemplate,
+				AckTimeout:      5 * time.Second,
+			}, WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+				mu.Lock()
+				defer mu.Unlock()
+				checkpoints = append(checkpoints, positions...)
+				return nil
+			}))
+			require.NoError(t, err)
+
+			sendErrChan := make(chan error, 1)
+			go func() {
+				sendErrChan <- publisher.Send(ctx)
+			}()
+
+			for _, event := range tc.events {
+				require.NoError(t, publisher.ProcessWALEvent(ctx, event))
+			}
+
+			if tc.wantErr {
+				require.Error(t, <-sendErrChan)
+			} else {
+				require.Eventually(t, func() bool {
+					mu.Lock()
+					defer mu.Unlock()
+					return len(checkpoints) == len(tc.wantCheckpoints)
+				}, 10*time.Second, 10*time.Millisecond)
+			}
+			publisher.Close()
+
+			mu.Lock()
+			require.Equal(t, tc.wantCheckpoints, checkpoints)
+			mu.Unlock()
+
+			subjects, msgIDs := readStream(ctx, t, stream)
+			require.Equal(t, tc.wantSubjects, subjects)
+			require.Equal(t, tc.wantMsgIDs, msgIDs)
+		})
+	}
+}
+
+// runJetStreamServer starts an embedded NATS server with JetStream enabled,
+// and a stream for the default pgstream subjects.
+func runJetStreamServer(ctx context.Context, t *testing.T) (string, jetstream.Stream) {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+	require.True(t, srv.ReadyForConnections(10*time.Second))
+
+	conn, err := natslib.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:       "pgstream",
+		Subjects:   []string{"pgstream.>"},
+		Duplicates: time.Minute,
+	})
+	require.NoError(t, err)
+
+	return srv.ClientURL(), stream
+}
+
+func readStream(ctx context.Context, t *testing.T, stream jetstream.Stream) ([]string, []string) {
+	t.Helper()
+
+	subjects := []string{}
+	msgIDs := []string{}
+	info, err := stream.Info(ctx)
+	require.NoError(t, err)
+	for seq := info.State.FirstSeq; seq <= info.State.LastSeq && info.State.Msgs > 0; seq++ {
+		msg, err := stream.GetMsg(ctx, seq)
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			continue
+		}
+		require.NoError(t, err)
+		subjects = append(subjects, msg.Subject)
+		msgIDs = append(msgIDs, msg.Header.Get(natslib.MsgIdHdr))
+	}
+	return subjects, msgIDs
+}
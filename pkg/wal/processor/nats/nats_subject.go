@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+type subjectBuilder struct {
+	template *template.Template
+}
+
+// subjectData contains the fields available to the subject template
+type subjectData struct {
+	Schema string
+	Table  string
+	Action string
+}
+
+// subjectTokenReplacer replaces the characters that have a special meaning in
+// NATS subjects, so that the schema and table names are always a single token.
+var subjectTokenReplacer = strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_", "\t", "_")
+
+func newSubjectBuilder(tmpl string) (*subjectBuilder, error) {
+	t, err := template.New("subject").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing subject template: %w", err)
+	}
+	return &subjectBuilder{template: t}, nil
+}
+
+func (b *subjectBuilder) subject(data *wal.Data) (string, error) {
+	sb := strings.Builder{}
+	if err := b.template.Execute(&sb, subjectData{
+		Schema: subjectToken(data.Schema),
+		Table:  subjectToken(data.Table),
+		Action: subjectToken(actionName(data.Action)),
+	}); err != nil {
+		return "", fmt.Errorf("executing subject template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+func subjectToken(s string) string {
+	if s == "" {
+		// empty tokens are not valid in NATS subjects (i.e, schema change
+		// events have no table)
+		return "_"
+	}
+	return subjectTokenReplacer.Replace(s)
+}
+
+func actionName(action string) string {
+	switch action {
+	case "I":
+		return "insert"
+	case "U":
+		return "update"
+	case "D":
+		return "delete"
+	case "T":
+		return "truncate"
+	case wal.SchemaChangeAction:
+		return "ddl"
+	default:
+		return strings.ToLower(action)
+	}
+}
+
+// msgID returns the deterministic JetStream message id for the wal event, used
+// for deduplication when events are replayed after a restart. The LSN is not
+// unique on its own, since schema change events share the LSN of the schema
+// log event they originate from.
+func msgID(data *wal.Data) string {
+	if data.LSN == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s.%s:%s", data.LSN, data.Schema, data.Table, data.Action)
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubjectBuilder_subject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		data     *wal.Data
+
+		wantSubject string
+		wantErr     bool
+	}{
+		{
+			name:     "ok - default template",
+			template: defaultSubjectTemplate,
+			data:     &wal.Data{Action: "I", Schema: "public", Table: "users"},
+
+			wantSubject: "pgstream.public.users.insert",
+		},
+		{
+			name:     "ok - schema change without table",
+			template: defaultSubjectTemplate,
+			data:     &wal.Data{Action: wal.SchemaChangeAction, Schema: "public"},
+
+			wantSubject: "pgstream.public._.ddl",
+		},
+		{
+			name:     "ok - special characters",
+			template: "cdc.{{.Schema}}.{{.Table}}",
+			data:     &wal.Data{Action: "D", Schema: "my schema", Table: "users.*>"},
+
+			wantSubject: "cdc.my_schema.users___",
+		},
+		{
+			name:     "error - unknown field",
+			template: "cdc.{{.Database}}",
+			data:     &wal.Data{Action: "U", Schema: "public", Table: "users"},
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			builder, err := newSubjectBuilder(tc.template)
+			require.NoError(t, err)
+
+			subject, err := builder.subject(tc.data)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSubject, subject)
+		})
+	}
+}
+
+func TestMsgID(t *testing.T) {
+	t.Parallel()
+
+	insert := &wal.Data{Action: "I", LSN: "0/17773B0", Schema: "pgstream", Table: "schema_log"}
+	ddl := &wal.Data{Action: wal.SchemaChangeAction, LSN: "0/17773B0", Schema: "public"}
+
+	require.Equal(t, "0/17773B0:pgstream.schema_log:I", msgID(insert))
+	require.Equal(t, msgID(insert), msgID(insert))
+	require.NotEqual(t, msgID(insert), msgID(ddl))
+	require.Empty(t, msgID(&wal.Data{Action: "I"}))
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	schemalogpg "github.com/ApollosProject/pgstream-wal2json/pkg/schemalog/postgres"
+)
+
+type Config struct {
+	// LocalDir is the local directory where the parquet files are written.
+	// Only one of LocalDir or S3 can be configured.
+	LocalDir string
+	// S3 is the configuration of the S3 compatible store where the parquet
+	// files are written.
+	S3 *S3Config
+	// MaxFileBytes is the approximate uncompressed size in bytes after which a
+	// file is committed. Defaults to 128MiB.
+	MaxFileBytes int64
+	// MaxFileAge is the time interval after which a file is committed.
+	// Defaults to 5m.
+	MaxFileAge time.Duration
+	// Compression is the compression codec used for the parquet column
+	// chunks. One of `snappy`, `gzip`, `zstd` or `none`. Defaults to `snappy`.
+	Compression string
+	// SchemaLogStore is the optional schema log store used to retrieve the
+	// table definitions for schemas that haven't changed since the processor
+	// started. If not provided, the table definition is derived from the wal
+	// event columns until a schema change is received.
+	SchemaLogStore *schemalogpg.Config
+	// MaxQueueBytes is the max memory used by the parquet writer for inflight
+	// events, including the rows buffered in the open files. When reached,
+	// the largest open files are committed. Defaults to 100MiB
+	MaxQueueBytes int64
+}
+
+type S3Config struct {
+	Bucket string
+	// Prefix is the key prefix for all the files written to the bucket.
+	Prefix string
+	Region string
+	// Endpoint is the URL of the S3 compatible store. If not provided, the AWS
+	// S3 endpoint for the region is used.
+	Endpoint string
+	// UsePathStyle enables path style addressing, usually required by S3
+	// compatible stores.
+	UsePathStyle bool
+	// AccessKeyID, SecretAccessKey and SessionToken are optional static
+	// credentials. If not provided, the default AWS credentials chain is used.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+const (
+	defaultMaxFileBytes  = int64(128 * 1024 * 1024) // 128MiB
+	defaultMaxFileAge    = 5 * time.Minute
+	defaultMaxQueueBytes = int64(100 * 1024 * 1024) // 100MiB
+
+	CompressionSnappy = "snappy"
+	CompressionGzip   = "gzip"
+	CompressionZstd   = "zstd"
+	CompressionNone   = "none"
+)
+
+var (
+	errNoStorage        = errors.New("either a local directory or an S3 store must be configured")
+	errMultipleStorages = errors.New("only one of local directory or S3 store can be configured")
+)
+
+func (c *Config) validate() error {
+	switch {
+	case c.LocalDir == "" && c.S3 == nil:
+		return errNoStorage
+	case c.LocalDir != "" && c.S3 != nil:
+		return errMultipleStorages
+	}
+	switch c.Compression {
+	case "", CompressionSnappy, CompressionGzip, CompressionZstd, CompressionNone:
+	default:
+		return fmt.Errorf("unsupported compression: %s", c.Compression)
+	}
+	return nil
+}
+
+func (c *Config) maxFileBytes() int64 {
+	if c.MaxFileBytes > 0 {
+		return c.MaxFileBytes
+	}
+	return defaultMaxFileBytes
+}
+
+func (c *Config) maxFileAge() time.Duration {
+	if c.MaxFileAge > 0 {
+		return c.MaxFileAge
+	}
+	return defaultMaxFileAge
+}
+
+func (c *Config) maxQueueBytes() int64 {
+	if c.MaxQueueBytes > 0 {
+		return c.MaxQueueBytes
+	}
+	return defaultMaxQueueBytes
+}
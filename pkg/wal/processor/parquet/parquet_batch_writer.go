@@ -0,0 +1,476 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	schemalogpg "github.com/ApollosProject/pgstream-wal2json/pkg/schemalog/postgres"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// BatchWriter is a wal processor that writes the wal events to parquet files,
+// partitioned by schema, table and date, on a local directory or an S3
+// compatible store.
+type BatchWriter struct {
+	storage     storage
+	schemaStore schemalog.Store
+	logger      loglib.Logger
+
+	// queueBytesSema is used to limit the amount of memory used by the
+	// unbuffered msg channel and the rows buffered in the open files, while
+	// preventing the process from running oom. The size of the rows is only
+	// released once their file is committed.
+	queueBytesSema synclib.WeightedSemaphore
+	msgChan        chan (*msg)
+	// flushChan is used to request the largest open files to be committed
+	// when the queue memory limit is reached, with the size of the message
+	// waiting to be queued.
+	flushChan     chan int64
+	maxQueueBytes int64
+
+	codec         compress.Codec
+	maxFileBytes  int64
+	maxFileAge    time.Duration
+	checkInterval time.Duration
+	now           func() time.Time
+
+	// checkpoint callback to mark what was safely stored
+	checkpoint checkpointer.Checkpoint
+
+	// schemas and tableSchemas are only accessed by ProcessWALEvent
+	schemas      map[string]*schemalog.Schema
+	tableSchemas map[string]map[string]*tableSchema
+
+	// files, pending and bufferedBytes are only accessed by the Send go
+	// routine
+	files         map[string]*partitionFile
+	pending       []pendingPosition
+	bufferedBytes int64
+}
+
+type Option func(*BatchWriter)
+
+const maxCheckInterval = time.Second
+
+// NewBatchWriter returns a processor of wal events that writes them to parquet
+// files on the storage configured.
+func NewBatchWriter(ctx context.Context, config *Config, opts ...Option) (*BatchWriter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	var store storage
+	var err error
+	if config.S3 != nil {
+		store, err = newS3Storage(ctx, config.S3)
+	} else {
+		store, err = newLocalStorage(config.LocalDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	w := newBatchWriter(config, store, opts...)
+
+	if config.SchemaLogStore != nil {
+		schemaStore, err := schemalogpg.NewStore(ctx, *config.SchemaLogStore)
+		if err != nil {
+			return nil, fmt.Errorf("create schema log postgres store: %w", err)
+		}
+		w.schemaStore = schemalog.NewStoreCache(schemaStore)
+	}
+
+	return w, nil
+}
+
+func newBatchWriter(config *Config, store storage, opts ...Option) *BatchWriter {
+	w := &BatchWriter{
+		storage:        store,
+		logger:         loglib.NewNoopLogger(),
+		msgChan:        make(chan *msg),
+		queueBytesSema: synclib.NewWeightedSemaphore(config.maxQueueBytes()),
+		flushChan:      make(chan int64, 1),
+		maxQueueBytes:  config.maxQueueBytes(),
+		codec:          compressionCodec(config.Compression),
+		maxFileBytes:   config.maxFileBytes(),
+		maxFileAge:     config.maxFileAge(),
+		checkInterval:  min(config.maxFileAge(), maxCheckInterval),
+		now:            time.Now,
+		schemas:        map[string]*schemalog.Schema{},
+		tableSchemas:   map[string]map[string]*tableSchema{},
+		files:          map[string]*partitionFile{},
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(w *BatchWriter) {
+		w.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "parquet_batch_writer",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(w *BatchWriter) {
+		w.checkpoint = c
+	}
+}
+
+// ProcessWALEvent is called on every new message from the wal. The function
+// is responsible for converting the event into a parquet row for its
+// partition, which will be written by the Send go routine.
+func (w *BatchWriter) ProcessWALEvent(ctx context.Context, event *wal.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    event.Data,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+			err = fmt.Errorf("parquet batch writer: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	parquetMsg, err := w.walEventToMsg(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	// make sure we don't reach the queue memory limit before adding the new
+	// message to the channel. This will block until the files containing the
+	// previous messages have been committed and their size is released
+	msgSize := int64(parquetMsg.size())
+	if !w.queueBytesSema.TryAcquire(msgSize) {
+		w.logger.Warn(nil, "parquet batch writer: max queue bytes reached, processing blocked")
+		// request the largest files to be committed instead of waiting for
+		// them to reach their max size or age
+		select {
+		case w.flushChan <- msgSize:
+		default:
+		}
+		if err := w.queueBytesSema.Acquire(ctx, msgSize); err != nil {
+			return err
+		}
+	}
+	w.msgChan <- parquetMsg
+
+	return nil
+}
+
+func (w *BatchWriter) Send(ctx context.Context) error {
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.commitExpiredFiles(ctx); err != nil {
+				w.logger.Error(err, "parquet batch writer")
+				return err
+			}
+		case size := <-w.flushChan:
+			if err := w.commitLargestFiles(ctx, size); err != nil {
+				w.logger.Error(err, "parquet batch writer")
+				return err
+			}
+		case msg, ok := <-w.msgChan:
+			if !ok {
+				// the processor has been closed, commit all the open files so
+				// that their positions can be checkpointed
+				return w.commitAllFiles(ctx)
+			}
+			// the size of the rows is released once their file is committed
+			if err := w.writeMsg(ctx, msg); err != nil {
+				w.logger.Error(err, "parquet batch writer")
+				return err
+			}
+		}
+	}
+}
+
+func (w *BatchWriter) Name() string {
+	return "parquet-batch-writer"
+}
+
+func (w *BatchWriter) Close() error {
+	close(w.msgChan)
+	if w.schemaStore != nil {
+		return w.schemaStore.Close()
+	}
+	return nil
+}
+
+func (w *BatchWriter) writeMsg(ctx context.Context, msg *msg) error {
+	if !msg.hasRow() {
+		w.pending = append(w.pending, pendingPosition{pos: msg.pos})
+		return w.checkpointCommitted(ctx)
+	}
+
+	file, found := w.files[msg.partition]
+	// a table schema change requires a new file, since all the rows in a
+	// parquet file share the same schema
+	if found && file.schema.signature != msg.schema.signature {
+		if err := w.commitFile(ctx, file); err != nil {
+			return err
+		}
+		found = false
+	}
+	if !found {
+		file = newPartitionFile(msg.partition, msg.schema, w.codec, w.now())
+		w.files[msg.partition] = file
+	}
+
+	if err := file.write(msg.row, msg.rowBytes); err != nil {
+		w.queueBytesSema.Release(int64(msg.size()))
+		return fmt.Errorf("writing row to partition %s: %w", msg.partition, err)
+	}
+	w.bufferedBytes += int64(msg.rowBytes)
+	w.pending = append(w.pending, pendingPosition{pos: msg.pos, file: file})
+
+	if file.rowBytes >= w.maxFileBytes {
+		if err := w.commitFile(ctx, file); err != nil {
+			return err
+		}
+	}
+
+	return w.checkpointCommitted(ctx)
+}
+
+func (w *BatchWriter) commitExpiredFiles(ctx context.Context) error {
+	now := w.now()
+	for _, file := range w.files {
+		if now.Sub(file.openedAt) >= w.maxFileAge {
+			if err := w.commitFile(ctx, file); err != nil {
+				return err
+			}
+		}
+	}
+	return w.checkpointCommitted(ctx)
+}
+
+// commitLargestFiles commits the largest open files until there's room in the
+// queue for a message of the size on input, or there are no files left.
+func (w *BatchWriter) commitLargestFiles(ctx context.Context, size int64) error {
+	for w.bufferedBytes+size > w.maxQueueBytes && len(w.files) > 0 {
+		var largest *partitionFile
+		for _, file := range w.files {
+			if largest == nil || file.rowBytes > largest.rowBytes {
+				largest = file
+			}
+		}
+		if err := w.commitFile(ctx, largest); err != nil {
+			return err
+		}
+	}
+	return w.checkpointCommitted(ctx)
+}
+
+func (w *BatchWriter) commitAllFiles(ctx context.Context) error {
+	for _, file := range w.files {
+		if err := w.commitFile(ctx, file); err != nil {
+			return err
+		}
+	}
+	return w.checkpointCommitted(ctx)
+}
+
+// commitFile stores the partition file. Once committed, the positions of the
+// events it contains can be checkpointed.
+func (w *BatchWriter) commitFile(ctx context.Context, file *partitionFile) error {
+	data, err := file.close()
+	if err != nil {
+		return fmt.Errorf("closing parquet file for partition %s: %w", file.partition, err)
+	}
+
+	if err := w.storage.put(ctx, file.key(), data); err != nil {
+		return fmt.Errorf("storing parquet file for partition %s: %w", file.partition, err)
+	}
+
+	w.logger.Debug("parquet file committed", loglib.Fields{
+		"key":   file.key(),
+		"rows":  file.rows,
+		"bytes": len(data),
+	})
+
+	file.committed = true
+	delete(w.files, file.partition)
+	w.bufferedBytes -= file.rowBytes
+	w.queueBytesSema.Release(file.rowBytes)
+	return nil
+}
+
+// checkpointCommitted checkpoints all the pending positions up to the first
+// one whose file hasn't been committed yet. This guarantees no position is
+// checkpointed before all the previous events have been durably stored.
+func (w *BatchWriter) checkpointCommitted(ctx context.Context) error {
+	i := 0
+	for ; i < len(w.pending) && w.pending[i].isCommitted(); i++ {
+	}
+	if i == 0 {
+		return nil
+	}
+
+	positions := make([]wal.CommitPosition, 0, i)
+	for _, p := range w.pending[:i] {
+		if p.pos != "" {
+			positions = append(positions, p.pos)
+		}
+	}
+	w.pending = w.pending[i:]
+
+	if w.checkpoint != nil && len(positions) > 0 {
+		if err := w.checkpoint(ctx, positions); err != nil {
+			return fmt.Errorf("checkpointing positions: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w *BatchWriter) walEventToMsg(ctx context.Context, event *wal.Event) (*msg, error) {
+	parquetMsg := &msg{pos: event.CommitPosition}
+	data := event.Data
+	if data == nil {
+		return parquetMsg, nil
+	}
+
+	switch {
+	case data.IsSchemaChange():
+		if data.SchemaChange != nil {
+			w.updateSchema(data.SchemaChange.SchemaName, &data.SchemaChange.Schema)
+		}
+		return parquetMsg, nil
+	case processor.IsSchemaLogEvent(data):
+		logEntry, err := processor.WalDataToLogEntry(data)
+		if err != nil {
+			return nil, err
+		}
+		w.updateSchema(logEntry.SchemaName, &logEntry.Schema)
+		return parquetMsg, nil
+	case data.Schema == schemalog.SchemaName:
+		// the rest of the pgstream internal tables are not written
+		return parquetMsg, nil
+	}
+
+	schema, err := w.getTableSchema(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	row, rowBytes, err := schema.row(data)
+	if err != nil {
+		// the event can't be represented in the table schema, skip it so that
+		// it doesn't block the processing of the following events
+		w.logger.Error(err, "parquet batch writer: skipping event", loglib.Fields{
+			"severity": "DATALOSS",
+			"schema":   data.Schema,
+			"table":    data.Table,
+			"lsn":      data.LSN,
+		})
+		return parquetMsg, nil
+	}
+
+	parquetMsg.partition = partitionKey(data, w.now())
+	parquetMsg.schema = schema
+	parquetMsg.row = row
+	parquetMsg.rowBytes = rowBytes
+	return parquetMsg, nil
+}
+
+// updateSchema keeps track of the latest schema definition, invalidating the
+// table schemas previously built for it.
+func (w *BatchWriter) updateSchema(schemaName string, schema *schemalog.Schema) {
+	w.schemas[schemaName] = schema
+	delete(w.tableSchemas, schemaName)
+}
+
+// getTableSchema returns the parquet schema for the table of the wal event.
+// The schema log table definition is used when available, otherwise the
+// schema is derived from the event columns.
+func (w *BatchWriter) getTableSchema(ctx context.Context, data *wal.Data) (*tableSchema, error) {
+	tables, found := w.tableSchemas[data.Schema]
+	if !found {
+		tables = map[string]*tableSchema{}
+		w.tableSchemas[data.Schema] = tables
+	}
+	cached := tables[data.Table]
+	if cached != nil && !cached.derived {
+		return cached, nil
+	}
+
+	schema, err := w.getSchema(ctx, data.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		if table := getTableByName(schema, data.Table); table != nil {
+			tables[data.Table] = newTableSchema(table)
+			return tables[data.Table], nil
+		}
+	}
+
+	var derived *tableSchema
+	switch data.Action {
+	case "I", "U":
+		derived = deriveTableSchema(data.Table, data.Columns)
+	default:
+		// deletes and truncates don't have the full set of columns, so the
+		// last known schema for the table is used when available
+		if cached != nil {
+			return cached, nil
+		}
+		derived = deriveTableSchema(data.Table, data.Identity)
+	}
+	if cached != nil && cached.signature == derived.signature {
+		return cached, nil
+	}
+	tables[data.Table] = derived
+	return derived, nil
+}
+
+func (w *BatchWriter) getSchema(ctx context.Context, schemaName string) (*schemalog.Schema, error) {
+	schema, found := w.schemas[schemaName]
+	if found || w.schemaStore == nil {
+		return schema, nil
+	}
+
+	logEntry, err := w.schemaStore.Fetch(ctx, schemaName, true)
+	if err != nil {
+		if !errors.Is(err, schemalog.ErrNoRows) {
+			return nil, fmt.Errorf("fetching schema %s: %w", schemaName, err)
+		}
+		// keep track of schemas that are not in the store to prevent
+		// unnecessary fetches
+		w.schemas[schemaName] = nil
+		return nil, nil
+	}
+
+	w.schemas[schemaName] = &logEntry.Schema
+	return &logEntry.Schema, nil
+}
+
+func getTableByName(schema *schemalog.Schema, tableName string) *schemalog.Table {
+	for i := range schema.Tables {
+		if schema.Tables[i].Name == tableName {
+			return &schema.Tables[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	putFn func(key string, data []byte) error
+}
+
+func (m *mockStorage) put(_ context.Context, key string, data []byte) error {
+	return m.putFn(key, data)
+}
+
+func TestBatchWriter_checkpoint(t *testing.T) {
+	t.Parallel()
+
+	testNow := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	errTest := errors.New("oh noes")
+
+	insertEvent := func(table, pos string) *wal.Event {
+		return &wal.Event{
+			Data: &wal.Data{
+				Action:    "I",
+				Timestamp: "2024-05-01 09:00:00.000000+00",
+				LSN:       pos,
+				Schema:    "public",
+				Table:     table,
+				Columns:   []wal.Column{{Name: "id", Type: "bigint", Value: float64(1)}},
+			},
+			CommitPosition: wal.CommitPosition(pos),
+		}
+	}
+	keepAlive := func(pos string) *wal.Event {
+		return &wal.Event{CommitPosition: wal.CommitPosition(pos)}
+	}
+
+	type step struct {
+		event  *wal.Event
+		commit bool
+	}
+
+	tests := []struct {
+		name         string
+		steps        []step
+		maxFileBytes int64
+		putErr       error
+
+		wantPuts        []string
+		wantCheckpoints [][]wal.CommitPosition
+		wantErr         error
+	}{
+		{
+			name: "ok - keep alive without open files",
+			steps: []step{
+				{event: keepAlive("1")},
+			},
+
+			wantPuts:        []string{},
+			wantCheckpoints: [][]wal.CommitPosition{{"1"}},
+		},
+		{
+			name: "ok - positions wait for the file to be committed",
+			steps: []step{
+				{event: insertEvent("users", "1")},
+				{event: keepAlive("2")},
+				{event: insertEvent("teams", "3")},
+				{commit: true},
+			},
+
+			wantPuts: []string{
+				"public/teams/date=2024-05-01/20240501T100000.000000000Z.parquet",
+				"public/users/date=2024-05-01/20240501T100000.000000000Z.parquet",
+			},
+			wantCheckpoints: [][]wal.CommitPosition{{"1", "2", "3"}},
+		},
+		{
+			name: "ok - file rolled on size",
+			steps: []step{
+				{event: insertEvent("users", "1")},
+				{event: insertEvent("teams", "2")},
+				{event: insertEvent("users", "3")},
+			},
+			maxFileBytes: 20,
+
+			wantPuts: []string{
+				"public/users/date=2024-05-01/20240501T100000.000000000Z.parquet",
+			},
+			// position 3 can't be checkpointed until the teams file with
+			// position 2 is committed
+			wantCheckpoints: [][]wal.CommitPosition{{"1"}},
+		},
+		{
+			name: "error - storing file",
+			steps: []step{
+				{event: insertEvent("users", "1")},
+				{commit: true},
+			},
+			putErr: errTest,
+
+			wantPuts:        []string{},
+			wantCheckpoints: [][]wal.CommitPosition{},
+			wantErr:         errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			puts := []string{}
+			storage := &mockStorage{
+				putFn: func(key string, data []byte) error {
+					if tc.putErr != nil {
+						return tc.putErr
+					}
+					puts = append(puts, key)
+					return nil
+				},
+			}
+			checkpoints := [][]wal.CommitPosition{}
+			w := newBatchWriter(&Config{MaxFileBytes: tc.maxFileBytes}, storage,
+				WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+					checkpoints = append(checkpoints, positions)
+					return nil
+				}))
+			w.now = func() time.Time { return testNow }
+
+			var err error
+			for _, s := range tc.steps {
+				if s.commit {
+					err = w.commitAllFiles(context.Background())
+					continue
+				}
+				msg, msgErr := w.walEventToMsg(context.Background(), s.event)
+				require.NoError(t, msgErr)
+				require.True(t, w.queueBytesSema.TryAcquire(int64(msg.size())))
+				require.NoError(t, w.writeMsg(context.Background(), msg))
+			}
+			require.ErrorIs(t, err, tc.wantErr)
+
+			require.ElementsMatch(t, tc.wantPuts, puts)
+			require.Equal(t, tc.wantCheckpoints, checkpoints)
+		})
+	}
+}
+
+func TestBatchWriter_queueBytes(t *testing.T) {
+	t.Parallel()
+
+	testNow := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	insertEvent := func(table string) *wal.Event {
+		return &wal.Event{
+			Data: &wal.Data{
+				Action:    "I",
+				Timestamp: "2024-05-01 09:00:00.000000+00",
+				Schema:    "public",
+				Table:     table,
+				Columns:   []wal.Column{{Name: "id", Type: "bigint", Value: float64(1)}},
+			},
+		}
+	}
+
+	puts := []string{}
+	storage := &mockStorage{
+		putFn: func(key string, data []byte) error {
+			puts = append(puts, key)
+			return nil
+		},
+	}
+	w := newBatchWriter(&Config{}, storage)
+	w.now = func() time.Time { return testNow }
+
+	msgs := []*msg{}
+	for _, table := range []string{"users", "users", "teams"} {
+		msg, err := w.walEventToMsg(context.Background(), insertEvent(table))
+		require.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+	rowSize := int64(msgs[0].size())
+	w.maxQueueBytes = 3 * rowSize
+	w.queueBytesSema = synclib.NewWeightedSemaphore(w.maxQueueBytes)
+
+	for _, msg := range msgs {
+		require.True(t, w.queueBytesSema.TryAcquire(int64(msg.size())))
+		require.NoError(t, w.writeMsg(context.Background(), msg))
+	}
+
+	// the rows are kept in the queue while their files are open
+	require.Equal(t, 3*rowSize, w.bufferedBytes)
+	require.False(t, w.queueBytesSema.TryAcquire(rowSize))
+
+	// the largest file is committed to make room for the next message
+	require.NoError(t, w.commitLargestFiles(context.Background(), rowSize))
+	require.Equal(t, []string{"public/users/date=2024-05-01/20240501T100000.000000000Z.parquet"}, puts)
+	require.Equal(t, rowSize, w.bufferedBytes)
+	require.True(t, w.queueBytesSema.TryAcquire(2*rowSize))
+}
+
+func TestBatchWriter_localStorage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	storage, err := newLocalStorage(dir)
+	require.NoError(t, err)
+
+	checkpoints := []wal.CommitPosition{}
+	w := newBatchWriter(&Config{}, storage,
+		WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+			checkpoints = append(checkpoints, positions...)
+			return nil
+		}))
+
+	testSchema := schemalog.Schema{
+		Tables: []schemalog.Table{
+			{
+				Name: "users",
+				Columns: []schemalog.Column{
+					{Name: "id", DataType: "integer"},
+					{Name: "name", DataType: "text"},
+				},
+			},
+		},
+	}
+	events := []*wal.Event{
+		{
+			Data: &wal.Data{
+				Action:       wal.SchemaChangeAction,
+				SchemaChange: &wal.SchemaChange{SchemaName: "public", Schema: testSchema},
+			},
+			CommitPosition: "1",
+		},
+		{
+			Data: &wal.Data{
+				Action:    "U",
+				Timestamp: "2024-05-01 09:00:00.000000+00",
+				LSN:       "0/2",
+				Schema:    "public",
+				Table:     "users",
+				Columns:   []wal.Column{{Name: "id", Type: "integer", Value: float64(1)}},
+			},
+			CommitPosition: "2",
+		},
+	}
+	for _, event := range events {
+		msg, err := w.walEventToMsg(context.Background(), event)
+		require.NoError(t, err)
+		require.True(t, w.queueBytesSema.TryAcquire(int64(msg.size())))
+		require.NoError(t, w.writeMsg(context.Background(), msg))
+	}
+	require.Equal(t, []wal.CommitPosition{"1"}, checkpoints)
+
+	require.NoError(t, w.commitAllFiles(context.Background()))
+	require.Equal(t, []wal.CommitPosition{"1", "2"}, checkpoints)
+
+	files, err := filepath.Glob(filepath.Join(dir, "public", "users", "date=2024-05-01", "*.parquet"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	rows := readRows(t, data)
+	require.Len(t, rows, 1)
+	require.Equal(t, int32(1), rows[0]["id"].Int32())
+	require.True(t, rows[0]["name"].IsNull())
+	require.Equal(t, "update", rows[0][OpColumn].String())
+	require.Equal(t, "0/2", rows[0][LSNColumn].String())
+}
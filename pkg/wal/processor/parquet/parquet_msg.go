@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	parquetgo "github.com/parquet-go/parquet-go"
+)
+
+// msg is a wal event converted to a parquet row for the partition. Events that
+// don't produce a row (keep alives, schema changes or skipped events) only
+// contain the position to be checkpointed.
+type msg struct {
+	partition string
+	schema    *tableSchema
+	row       parquetgo.Row
+	rowBytes  int
+	pos       wal.CommitPosition
+}
+
+func (m *msg) size() int {
+	return m.rowBytes
+}
+
+func (m *msg) hasRow() bool {
+	return m.row != nil
+}
+
+// pendingPosition is a position waiting for the file that contains its event
+// to be committed before it can be checkpointed. Positions without a file can
+// be checkpointed as soon as all previous positions have been.
+type pendingPosition struct {
+	pos  wal.CommitPosition
+	file *partitionFile
+}
+
+func (p *pendingPosition) isCommitted() bool {
+	return p.file == nil || p.file.committed
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	parquetgo "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// partitionFile is an open parquet file for a schema/table/date partition.
+// Rows are encoded in memory, and the file is only stored once it's
+// committed.
+type partitionFile struct {
+	partition string
+	schema    *tableSchema
+	openedAt  time.Time
+	buf       *bytes.Buffer
+	writer    *parquetgo.Writer
+	rowBytes  int64
+	rows      int
+	committed bool
+}
+
+func newPartitionFile(partition string, schema *tableSchema, codec compress.Codec, now time.Time) *partitionFile {
+	buf := &bytes.Buffer{}
+	return &partitionFile{
+		partition: partition,
+		schema:    schema,
+		openedAt:  now,
+		buf:       buf,
+		writer:    parquetgo.NewWriter(buf, schema.schema, parquetgo.Compression(codec)),
+	}
+}
+
+func (f *partitionFile) write(row parquetgo.Row, size int) error {
+	if _, err := f.writer.WriteRows([]parquetgo.Row{row}); err != nil {
+		return err
+	}
+	f.rowBytes += int64(size)
+	f.rows++
+	return nil
+}
+
+// close flushes the parquet footer and returns the encoded file.
+func (f *partitionFile) close() ([]byte, error) {
+	if err := f.writer.Close(); err != nil {
+		return nil, err
+	}
+	return f.buf.Bytes(), nil
+}
+
+// key returns the object key for the file, relative to the storage root.
+func (f *partitionFile) key() string {
+	return path.Join(f.partition, fmt.Sprintf("%s.parquet", f.openedAt.UTC().Format("20060102T150405.000000000Z")))
+}
+
+// partitionKey returns the schema/table/date=YYYY-MM-DD partition for the wal
+// event on input. The date is based on the event commit timestamp, falling
+// back to the time on input when not available.
+func partitionKey(data *wal.Data, now time.Time) string {
+	ts, err := data.GetTimestamp()
+	if err != nil {
+		ts = now
+	}
+	return path.Join(
+		sanitisePathElement(data.Schema),
+		sanitisePathElement(data.Table),
+		fmt.Sprintf("date=%s", ts.UTC().Format("2006-01-02")),
+	)
+}
+
+func sanitisePathElement(s string) string {
+	return strings.NewReplacer("/", "_", `\`, "_", "..", "_").Replace(s)
+}
+
+func compressionCodec(compression string) compress.Codec {
+	switch compression {
+	case CompressionGzip:
+		return &parquetgo.Gzip
+	case CompressionZstd:
+		return &parquetgo.Zstd
+	case CompressionNone:
+		return &parquetgo.Uncompressed
+	default:
+		return &parquetgo.Snappy
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Storage_put(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+
+		wantObjects map[string][]byte
+		wantErr     bool
+	}{
+		{
+			name:       "ok",
+			statusCode: http.StatusOK,
+
+			wantObjects: map[string][]byte{
+				"/bucket/prefix/public/users/date=2024-05-01/file.parquet": []byte("data"),
+			},
+		},
+		{
+			name:       "error - upload failed",
+			statusCode: http.StatusForbidden,
+
+			wantObjects: map[string][]byte{},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// local stand-in for the S3 compatible store
+			mu := sync.Mutex{}
+			objects := map[string][]byte{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				if tc.statusCode != http.StatusOK {
+					w.WriteHeader(tc.statusCode)
+					return
+				}
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				mu.Lock()
+				objects[r.URL.Path] = body
+				mu.Unlock()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			storage, err := newS3Storage(context.Background(), &S3Config{
+				Bucket:          "bucket",
+				Prefix:          "prefix",
+				Region:          "us-east-1",
+				Endpoint:        server.URL,
+				UsePathStyle:    true,
+				AccessKeyID:     "AKID",
+				SecretAccessKey: "secret",
+			})
+			require.NoError(t, err)
+
+			err = storage.put(context.Background(), "public/users/date=2024-05-01/file.parquet", []byte("data"))
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, tc.wantObjects, objects)
+		})
+	}
+}
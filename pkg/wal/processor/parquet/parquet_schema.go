@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	parquetgo "github.com/parquet-go/parquet-go"
+)
+
+// tableSchema is the parquet schema for a postgres table. Every table column
+// is mapped to an optional parquet column, and the operation and LSN columns
+// are added for merge-on-read.
+type tableSchema struct {
+	schema  *parquetgo.Schema
+	columns []column
+	// signature identifies the set of columns and types of the schema
+	signature string
+	// derived is true when the schema has been derived from the wal event
+	// columns instead of the schema log table definition.
+	derived bool
+}
+
+type column struct {
+	name      string
+	kind      valueKind
+	leafIndex int
+}
+
+type valueKind uint8
+
+const (
+	stringKind valueKind = iota
+	int32Kind
+	int64Kind
+	floatKind
+	doubleKind
+	boolKind
+	dateKind
+	timestampKind
+	bytesKind
+)
+
+const (
+	// OpColumn and LSNColumn are the metadata columns added to every table
+	OpColumn  = "_pgstream_op"
+	LSNColumn = "_pgstream_lsn"
+
+	// postgres special date/time values and era suffix
+	infinityValue    = "infinity"
+	negInfinityValue = "-infinity"
+	bcSuffix         = " BC"
+)
+
+var (
+	errUnsupportedValue = errors.New("unsupported value")
+
+	typeModifiersRegex = regexp.MustCompile(`\(\d+(,\s*\d+)?\)`)
+
+	timestampLayouts = []string{
+		"2006-01-02 15:04:05.999999999Z07",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+		time.RFC3339Nano,
+	}
+)
+
+// newTableSchema returns the parquet schema for the schema log table on input.
+func newTableSchema(table *schemalog.Table) *tableSchema {
+	columns := make([]column, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		columns = append(columns, column{name: col.Name, kind: pgTypeToValueKind(col.DataType)})
+	}
+	return buildTableSchema(table.Name, columns, false)
+}
+
+// deriveTableSchema returns the parquet schema based on the wal event columns,
+// for tables without a schema log definition.
+func deriveTableSchema(table string, walColumns []wal.Column) *tableSchema {
+	columns := make([]column, 0, len(walColumns))
+	for _, col := range walColumns {
+		columns = append(columns, column{name: col.Name, kind: pgTypeToValueKind(col.Type)})
+	}
+	return buildTableSchema(table, columns, true)
+}
+
+func buildTableSchema(table string, columns []column, derived bool) *tableSchema {
+	group := parquetgo.Group{
+		OpColumn:  parquetgo.String(),
+		LSNColumn: parquetgo.String(),
+	}
+	signature := strings.Builder{}
+	for _, col := range columns {
+		group[col.name] = parquetgo.Optional(col.kind.node())
+		fmt.Fprintf(&signature, "%s:%d|", col.name, col.kind)
+	}
+
+	schema := parquetgo.NewSchema(table, group)
+	for i := range columns {
+		leaf, _ := schema.Lookup(columns[i].name)
+		columns[i].leafIndex = leaf.ColumnIndex
+	}
+
+	return &tableSchema{
+		schema:    schema,
+		columns:   columns,
+		signature: signature.String(),
+		derived:   derived,
+	}
+}
+
+// row returns the parquet row for the wal event on input. Update and insert
+// events use the new column values, while delete events only have the
+// identity column values. Columns without a value are set to null.
+func (s *tableSchema) row(data *wal.Data) (parquetgo.Row, int, error) {
+	values := make(map[string]any, len(data.Columns)+len(data.Identity))
+	for _, col := range data.Identity {
+		values[col.Name] = col.Value
+	}
+	for _, col := range data.Columns {
+		values[col.Name] = col.Value
+	}
+
+	row := make(parquetgo.Row, len(s.columns)+2)
+	size := 0
+	for _, col := range s.columns {
+		value, err := col.kind.value(values[col.name])
+		if err != nil {
+			return nil, 0, fmt.Errorf("column %s: %w", col.name, err)
+		}
+		definitionLevel := 0
+		if !value.IsNull() {
+			definitionLevel = 1
+			size += len(value.ByteArray()) + 8
+		}
+		row[col.leafIndex] = value.Level(0, definitionLevel, col.leafIndex)
+	}
+
+	for name, value := range map[string]string{
+		OpColumn:  walActionToOp(data.Action),
+		LSNColumn: data.LSN,
+	} {
+		leaf, _ := s.schema.Lookup(name)
+		row[leaf.ColumnIndex] = parquetgo.ByteArrayValue([]byte(value)).Level(0, 0, leaf.ColumnIndex)
+		size += len(value)
+	}
+
+	return row, size, nil
+}
+
+func walActionToOp(action string) string {
+	switch action {
+	case "I":
+		return "insert"
+	case "U":
+		return "update"
+	case "D":
+		return "delete"
+	case "T":
+		return "truncate"
+	default:
+		return action
+	}
+}
+
+// pgTypeToValueKind maps the postgres type on input to the parquet value kind.
+// Types without a direct equivalent (i.e, numeric, json or arrays) are stored
+// as strings, using their postgres text representation.
+func pgTypeToValueKind(pgType string) valueKind {
+	if strings.HasSuffix(pgType, "[]") {
+		return stringKind
+	}
+
+	switch typeModifiersRegex.ReplaceAllString(pgType, "") {
+	case "smallint", "integer", "int2", "int4":
+		return int32Kind
+	case "bigint", "int8":
+		return int64Kind
+	case "real", "float4":
+		return floatKind
+	case "double precision", "float8":
+		return doubleKind
+	case "boolean", "bool":
+		return boolKind
+	case "date":
+		return dateKind
+	case "timestamp", "timestamptz", "timestamp without time zone", "timestamp with time zone":
+		return timestampKind
+	case "bytea":
+		return bytesKind
+	default:
+		return stringKind
+	}
+}
+
+func (k valueKind) node() parquetgo.Node {
+	switch k {
+	case int32Kind:
+		return parquetgo.Int(32)
+	case int64Kind:
+		return parquetgo.Int(64)
+	case floatKind:
+		return parquetgo.Leaf(parquetgo.FloatType)
+	case doubleKind:
+		return parquetgo.Leaf(parquetgo.DoubleType)
+	case boolKind:
+		return parquetgo.Leaf(parquetgo.BooleanType)
+	case dateKind:
+		return parquetgo.Date()
+	case timestampKind:
+		return parquetgo.Timestamp(parquetgo.Microsecond)
+	case bytesKind:
+		return parquetgo.Leaf(parquetgo.ByteArrayType)
+	default:
+		return parquetgo.String()
+	}
+}
+
+// value converts the wal column value on input to a parquet value of the
+// kind.
+func (k valueKind) value(v any) (parquetgo.Value, error) {
+	if v == nil {
+		return parquetgo.NullValue(), nil
+	}
+
+	switch k {
+	case int32Kind:
+		i, err := toInt64(v)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.Int32Value(int32(i)), nil
+	case int64Kind:
+		i, err := toInt64(v)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.Int64Value(i), nil
+	case floatKind:
+		f, err := toFloat64(v)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.FloatValue(float32(f)), nil
+	case doubleKind:
+		f, err := toFloat64(v)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.DoubleValue(f), nil
+	case boolKind:
+		b, ok := v.(bool)
+		if !ok {
+			return parquetgo.Value{}, fmt.Errorf("%w: %T for boolean column", errUnsupportedValue, v)
+		}
+		return parquetgo.BooleanValue(b), nil
+	case dateKind:
+		s, ok := v.(string)
+		if !ok {
+			return parquetgo.Value{}, fmt.Errorf("%w: %T for date column", errUnsupportedValue, v)
+		}
+		// infinite dates are mapped to the max/min values, which is how
+		// postgres represents them internally
+		switch s {
+		case infinityValue:
+			return parquetgo.Int32Value(math.MaxInt32), nil
+		case negInfinityValue:
+			return parquetgo.Int32Value(math.MinInt32), nil
+		}
+		date, err := parseDate(s)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.Int32Value(int32(date.Unix() / int64((24 * time.Hour).Seconds()))), nil
+	case timestampKind:
+		s, ok := v.(string)
+		if !ok {
+			return parquetgo.Value{}, fmt.Errorf("%w: %T for timestamp column", errUnsupportedValue, v)
+		}
+		switch s {
+		case infinityValue:
+			return parquetgo.Int64Value(math.MaxInt64), nil
+		case negInfinityValue:
+			return parquetgo.Int64Value(math.MinInt64), nil
+		}
+		ts, err := parseTimestamp(s)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.Int64Value(ts.UnixMicro()), nil
+	case bytesKind:
+		s, ok := v.(string)
+		if !ok {
+			return parquetgo.Value{}, fmt.Errorf("%w: %T for bytea column", errUnsupportedValue, v)
+		}
+		b, err := hex.DecodeString(strings.TrimPrefix(s, `\x`))
+		if err != nil {
+			return parquetgo.Value{}, fmt.Errorf("%w: %v", errUnsupportedValue, err)
+		}
+		return parquetgo.ByteArrayValue(b), nil
+	default:
+		s, err := toString(v)
+		if err != nil {
+			return parquetgo.Value{}, err
+		}
+		return parquetgo.ByteArrayValue([]byte(s)), nil
+	}
+}
+
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case json.Number:
+		return n.Int64()
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	default:
+		return 0, fmt.Errorf("%w: %T for integer column", errUnsupportedValue, v)
+	}
+}
+
+func toFloat64(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int64:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		// special values such as NaN or Infinity are sent as strings
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("%w: %T for float column", errUnsupportedValue, v)
+	}
+}
+
+func toString(v any) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case json.Number:
+		return s.String(), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(s, 10), nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	default:
+		b, err := json.Marshal(s)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", errUnsupportedValue, err)
+		}
+		return string(b), nil
+	}
+}
+
+func parseDate(s string) (time.Time, error) {
+	trimmed, bc := strings.CutSuffix(s, bcSuffix)
+	date, err := time.Parse("2006-01-02", trimmed)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", errUnsupportedValue, err)
+	}
+	if bc {
+		return toBC(date), nil
+	}
+	return date, nil
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	trimmed, bc := strings.CutSuffix(s, bcSuffix)
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, trimmed); err == nil {
+			if bc {
+				return toBC(ts), nil
+			}
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid timestamp %q", errUnsupportedValue, s)
+}
+
+// toBC converts the year of the time on input to the BC era. There's no year
+// 0 in postgres, so 1 BC is year 0 in the proleptic gregorian calendar.
+func toBC(t time.Time) time.Time {
+	return time.Date(1-t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	parquetgo "github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPgTypeToValueKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pgType   string
+		wantKind valueKind
+	}{
+		{pgType: "integer", wantKind: int32Kind},
+		{pgType: "smallint", wantKind: int32Kind},
+		{pgType: "bigint", wantKind: int64Kind},
+		{pgType: "real", wantKind: floatKind},
+		{pgType: "double precision", wantKind: doubleKind},
+		{pgType: "boolean", wantKind: boolKind},
+		{pgType: "date", wantKind: dateKind},
+		{pgType: "timestamp(3) with time zone", wantKind: timestampKind},
+		{pgType: "timestamp without time zone", wantKind: timestampKind},
+		{pgType: "bytea", wantKind: bytesKind},
+		{pgType: "numeric(10, 2)", wantKind: stringKind},
+		{pgType: "integer[]", wantKind: stringKind},
+		{pgType: "jsonb", wantKind: stringKind},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.pgType, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.wantKind, pgTypeToValueKind(tc.pgType))
+		})
+	}
+}
+
+func TestValueKind_value(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		kind  valueKind
+		value any
+
+		wantValue parquetgo.Value
+		wantErr   bool
+	}{
+		{
+			name:      "null",
+			kind:      int64Kind,
+			value:     nil,
+			wantValue: parquetgo.NullValue(),
+		},
+		{
+			name:      "int32",
+			kind:      int32Kind,
+			value:     float64(42),
+			wantValue: parquetgo.Int32Value(42),
+		},
+		{
+			name:      "int64 lossless number",
+			kind:      int64Kind,
+			value:     json.Number("9007199254740993"),
+			wantValue: parquetgo.Int64Value(9007199254740993),
+		},
+		{
+			name:      "double special value",
+			kind:      doubleKind,
+			value:     "Infinity",
+			wantValue: parquetgo.DoubleValue(mustParseInf()),
+		},
+		{
+			name:      "boolean",
+			kind:      boolKind,
+			value:     true,
+			wantValue: parquetgo.BooleanValue(true),
+		},
+		{
+			name:      "date",
+			kind:      dateKind,
+			value:     "1970-01-11",
+			wantValue: parquetgo.Int32Value(10),
+		},
+		{
+			name:      "date infinity",
+			kind:      dateKind,
+			value:     "infinity",
+			wantValue: parquetgo.Int32Value(math.MaxInt32),
+		},
+		{
+			name:      "date -infinity",
+			kind:      dateKind,
+			value:     "-infinity",
+			wantValue: parquetgo.Int32Value(math.MinInt32),
+		},
+		{
+			name:      "date BC",
+			kind:      dateKind,
+			value:     "0044-03-15 BC",
+			wantValue: parquetgo.Int32Value(int32(time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC).Unix() / 86400)),
+		},
+		{
+			name:      "timestamp infinity",
+			kind:      timestampKind,
+			value:     "infinity",
+			wantValue: parquetgo.Int64Value(math.MaxInt64),
+		},
+		{
+			name:      "timestamp -infinity",
+			kind:      timestampKind,
+			value:     "-infinity",
+			wantValue: parquetgo.Int64Value(math.MinInt64),
+		},
+		{
+			name:      "timestamp with time zone BC",
+			kind:      timestampKind,
+			value:     "0001-12-31 23:00:00+00 BC",
+			wantValue: parquetgo.Int64Value(time.Date(0, 12, 31, 23, 0, 0, 0, time.UTC).UnixMicro()),
+		},
+		{
+			name:      "timestamp with time zone",
+			kind:      timestampKind,
+			value:     "2024-05-01 10:00:00.123456+02",
+			wantValue: parquetgo.Int64Value(time.Date(2024, 5, 1, 8, 0, 0, 123456000, time.UTC).UnixMicro()),
+		},
+		{
+			name:      "timestamp without time zone",
+			kind:      timestampKind,
+			value:     "2024-05-01 10:00:00",
+			wantValue: parquetgo.Int64Value(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMicro()),
+		},
+		{
+			name:      "bytea",
+			kind:      bytesKind,
+			value:     `\x0102`,
+			wantValue: parquetgo.ByteArrayValue([]byte{1, 2}),
+		},
+		{
+			name:      "string from json",
+			kind:      stringKind,
+			value:     map[string]any{"a": "b"},
+			wantValue: parquetgo.ByteArrayValue([]byte(`{"a":"b"}`)),
+		},
+		{
+			name:    "error - invalid boolean",
+			kind:    boolKind,
+			value:   "yes",
+			wantErr: true,
+		},
+		{
+			name:    "error - invalid date",
+			kind:    dateKind,
+			value:   "epoch BC",
+			wantErr: true,
+		},
+		{
+			name:    "error - invalid timestamp",
+			kind:    timestampKind,
+			value:   "yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := tc.kind.value(tc.value)
+			if tc.wantErr {
+				require.ErrorIs(t, err, errUnsupportedValue)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, parquetgo.Equal(tc.wantValue, value), "got %v, want %v", value, tc.wantValue)
+		})
+	}
+}
+
+func TestTableSchema_row(t *testing.T) {
+	t.Parallel()
+
+	schema := newTableSchema(&schemalog.Table{
+		Name: "users",
+		Columns: []schemalog.Column{
+			{Name: "id", DataType: "bigint"},
+			{Name: "name", DataType: "text"},
+			{Name: "created_at", DataType: "timestamp with time zone"},
+		},
+	})
+
+	insert := &wal.Data{
+		Action: "I",
+		LSN:    "0/17773B0",
+		Columns: []wal.Column{
+			{Name: "id", Type: "bigint", Value: float64(1)},
+			{Name: "name", Type: "text", Value: "alice"},
+			{Name: "created_at", Type: "timestamp with time zone", Value: "2024-05-01 10:00:00+00"},
+		},
+	}
+	del := &wal.Data{
+		Action:   "D",
+		LSN:      "0/17773C0",
+		Identity: []wal.Column{{Name: "id", Type: "bigint", Value: float64(1)}},
+	}
+
+	buf := &bytes.Buffer{}
+	writer := parquetgo.NewWriter(buf, schema.schema)
+	for _, data := range []*wal.Data{insert, del} {
+		row, size, err := schema.row(data)
+		require.NoError(t, err)
+		require.Greater(t, size, 0)
+		_, err = writer.WriteRows([]parquetgo.Row{row})
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	rows := readRows(t, buf.Bytes())
+	require.Len(t, rows, 2)
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, int64(1), rows[0]["id"].Int64())
+	require.Equal(t, "alice", rows[0]["name"].String())
+	require.Equal(t, createdAt.UnixMicro(), rows[0]["created_at"].Int64())
+	require.Equal(t, "insert", rows[0][OpColumn].String())
+	require.Equal(t, "0/17773B0", rows[0][LSNColumn].String())
+
+	require.Equal(t, int64(1), rows[1]["id"].Int64())
+	require.True(t, rows[1]["name"].IsNull())
+	require.True(t, rows[1]["created_at"].IsNull())
+	require.Equal(t, "delete", rows[1][OpColumn].String())
+	require.Equal(t, "0/17773C0", rows[1][LSNColumn].String())
+}
+
+// readRows returns the rows of the parquet file on input, indexed by column
+// name.
+func readRows(t *testing.T, data []byte) []map[string]parquetgo.Value {
+	t.Helper()
+
+	file, err := parquetgo.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	reader := parquetgo.NewReader(file)
+	defer reader.Close()
+
+	rows := []map[string]parquetgo.Value{}
+	buf := make([]parquetgo.Row, 10)
+	for {
+		n, err := reader.ReadRows(buf)
+		for _, row := range buf[:n] {
+			values := map[string]parquetgo.Value{}
+			for _, value := range row {
+				values[file.Schema().Columns()[value.Column()][0]] = value
+			}
+			rows = append(rows, values)
+		}
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		require.NoError(t, err)
+	}
+}
+
+func mustParseInf() float64 {
+	f, _ := toFloat64("Infinity")
+	return f
+}
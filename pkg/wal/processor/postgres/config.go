@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"time"
+)
+
+type Config struct {
+	// URL is the connection string of the target postgres database.
+	URL string
+	// BatchSize is the max number of wal events accumulated before triggering
+	// a write to the target database. Defaults to 100
+	BatchSize int
+	// BatchTime is the max time interval at which the batch write to the
+	// target database is triggered. Defaults to 1s
+	BatchTime time.Duration
+	// MaxQueueBytes is the max memory used by the batch writer for inflight
+	// batches. Defaults to 100MiB
+	MaxQueueBytes int64
+	// IncludeTables is the list of tables to be replicated, in the
+	// `<schema>.<table>` format. Wildcards are supported (i.e, `public.*`). If
+	// empty, all tables are replicated.
+	IncludeTables []string
+}
+
+const (
+	defaultMaxQueueBytes = int64(100 * 1024 * 1024) // 100MiB
+	defaultBatchSize     = 100
+	defaultBatchTime     = time.Second
+)
+
+func (c *Config) batchSize() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return defaultBatchSize
+}
+
+func (c *Config) batchTime() time.Duration {
+	if c.BatchTime > 0 {
+		return c.BatchTime
+	}
+	return defaultBatchTime
+}
+
+func (c *Config) maxQueueBytes() int64 {
+	if c.MaxQueueBytes > 0 {
+		return c.MaxQueueBytes
+	}
+	return defaultMaxQueueBytes
+}
@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	pglib "github.com/ApollosProject/pgstream-wal2json/internal/postgres"
+	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+)
+
+// BatchWriter is a wal processor that applies the wal events to a target
+// postgres database, using batches of statements executed in a single
+// transaction.
+type BatchWriter struct {
+	conn    pglib.Querier
+	adapter walAdapter
+	logger  loglib.Logger
+
+	// queueBytesSema is used to limit the amount of memory used by the
+	// unbuffered msg channel, optimising the channel performance for variable
+	// size messages, while preventing the process from running oom
+	queueBytesSema synclib.WeightedSemaphore
+	msgChan        chan (*msg)
+
+	batchSize         int
+	batchSendInterval time.Duration
+
+	// primaryKeys caches the primary key columns of the target tables, used
+	// as conflict target for the inserts of events without pgstream identity
+	// columns. It's only accessed by the sending goroutine, and reset after
+	// schema changes are applied.
+	primaryKeys map[string][]string
+
+	// checkpoint callback to mark what was safely stored
+	checkpoint checkpointer.Checkpoint
+}
+
+type Option func(*BatchWriter)
+
+var errEmptyQueueMsg = errors.New("invalid empty queue message")
+
+// NewBatchWriter returns a processor of wal events that applies the data and
+// schema changes to the target postgres database.
+func NewBatchWriter(ctx context.Context, config *Config, opts ...Option) (*BatchWriter, error) {
+	conn, err := pglib.NewConnPool(ctx, config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("create postgres connection pool: %w", err)
+	}
+
+	return newBatchWriter(config, conn, opts...), nil
+}
+
+func newBatchWriter(config *Config, conn pglib.Querier, opts ...Option) *BatchWriter {
+	w := &BatchWriter{
+		conn:              conn,
+		adapter:           newAdapter(config.IncludeTables),
+		logger:            loglib.NewNoopLogger(),
+		batchSize:         config.batchSize(),
+		batchSendInterval: config.batchTime(),
+		msgChan:           make(chan *msg),
+		queueBytesSema:    synclib.NewWeightedSemaphore(config.maxQueueBytes()),
+		primaryKeys:       map[string][]string{},
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(w *BatchWriter) {
+		w.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "postgres_batch_writer",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(w *BatchWriter) {
+		w.checkpoint = c
+	}
+}
+
+// ProcessWALEvent is called on every new message from the wal. The function
+// is responsible for queueing the event, which will be applied to the target
+// database as part of a batch.
+func (w *BatchWriter) ProcessWALEvent(ctx context.Context, event *wal.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    event.Data,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+			err = fmt.Errorf("postgres batch writer: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	w.logger.Trace("postgres batch writer: received wal event", loglib.Fields{
+		"wal_data":            event.Data,
+		"wal_commit_position": event.CommitPosition,
+	})
+
+	msg, err := w.adapter.walEventToMsg(event)
+	if err != nil {
+		if errors.Is(err, errMissingIdentity) || errors.Is(err, errInvalidDataType) {
+			w.logger.Warn(err, "postgres batch writer: invalid event, skipping message")
+			return nil
+		}
+		return fmt.Errorf("wal event to queue item: %w", err)
+	}
+
+	if msg == nil {
+		return nil
+	}
+
+	// make sure we don't reach the queue memory limit before adding the new
+	// message to the channel. This will block until messages have been read
+	// from the channel and their size is released
+	msgSize := int64(msg.size())
+	if !w.queueBytesSema.TryAcquire(msgSize) {
+		w.logger.Warn(nil, "postgres batch writer: max queue bytes reached, processing blocked")
+		if err := w.queueBytesSema.Acquire(ctx, msgSize); err != nil {
+			return err
+		}
+	}
+	w.msgChan <- msg
+
+	return nil
+}
+
+func (w *BatchWriter) Send(ctx context.Context) error {
+	// make sure we send to the target database on a separate go routine to
+	// isolate the IO operations and minimise the wait time between batch
+	// sending while continuously building new batches.
+	batchChan := make(chan *msgBatch)
+	defer close(batchChan)
+	sendErrChan := make(chan error, 1)
+	go func() {
+		defer close(sendErrChan)
+		for batch := range batchChan {
+			// If the send fails, this goroutine returns an error over the error channel and shuts down.
+			err := w.sendBatch(ctx, batch)
+			w.queueBytesSema.Release(int64(batch.totalBytes))
+			if err != nil {
+				w.logger.Error(err, "postgres batch writer")
+				sendErrChan <- err
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(w.batchSendInterval)
+	defer ticker.Stop()
+	msgBatch := &msgBatch{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sendErr := <-sendErrChan:
+			// if there's an error while sending the batch, return the error and
+			// stop sending batches
+			return sendErr
+		case <-ticker.C:
+			if !msgBatch.isEmpty() {
+				batchChan <- msgBatch.drain()
+			}
+		case msg := <-w.msgChan:
+			msgBatch.add(msg)
+			// trigger a send if we reached the configured batch size or if the
+			// event was for a schema change/keep alive. Schema changes are
+			// applied in the same transaction as the preceding events, to make
+			// sure following events are written to the right table definition.
+			if msgBatch.size() >= w.batchSize || msg.isSchemaChange() || msg.isKeepAlive() {
+				batchChan <- msgBatch.drain()
+			}
+		}
+	}
+}
+
+func (w *BatchWriter) Name() string {
+	return "postgres-batch-writer"
+}
+
+func (w *BatchWriter) Close() error {
+	close(w.msgChan)
+	return w.conn.Close(context.Background())
+}
+
+func (w *BatchWriter) sendBatch(ctx context.Context, batch *msgBatch) error {
+	if batch.isEmpty() {
+		return nil
+	}
+
+	queries, err := w.batchQueries(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	if len(queries) > 0 {
+		if err := w.conn.ExecInTx(ctx, func(tx pglib.Tx) error {
+			for _, q := range queries {
+				if _, err := tx.Exec(ctx, q.sql, q.args...); err != nil {
+					return fmt.Errorf("executing query [%s]: %w", q.sql, err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("applying batch: %w", err)
+		}
+	}
+
+	// the primary keys of the target tables might have changed
+	if batch.hasSchemaChange() {
+		clear(w.primaryKeys)
+	}
+
+	if w.checkpoint != nil {
+		if err := w.checkpoint(ctx, batch.positions); err != nil {
+			return fmt.Errorf("checkpointing positions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (w *BatchWriter) batchQueries(ctx context.Context, batch *msgBatch) ([]*query, error) {
+	builder := &dmlBuilder{}
+	for _, msg := range batch.msgs {
+		switch {
+		case msg.rowChange != nil:
+			if err := w.setPrimaryKey(ctx, msg.rowChange); err != nil {
+				return nil, err
+			}
+			builder.addRowChange(msg.rowChange)
+		case len(msg.queries) > 0:
+			for _, q := range msg.queries {
+				builder.addQuery(q)
+			}
+		default:
+			return nil, errEmptyQueueMsg
+		}
+	}
+	return builder.build(), nil
+}
+
+// setPrimaryKey uses the primary key of the target table as the key columns
+// of inserts without pgstream identity columns (i.e, when the events are not
+// translated), so that they're applied as upserts and replaying them after a
+// restart doesn't fail with duplicate key errors.
+func (w *BatchWriter) setPrimaryKey(ctx context.Context, rc *rowChange) error {
+	if rc.action != insertAction || len(rc.keyColumns) > 0 {
+		return nil
+	}
+
+	table := quotedTableName(rc.schema, rc.table)
+	primaryKey, found := w.primaryKeys[table]
+	if !found {
+		var err error
+		if primaryKey, err = w.getPrimaryKey(ctx, table); err != nil {
+			return fmt.Errorf("retrieving primary key of table %s: %w", table, err)
+		}
+		w.primaryKeys[table] = primaryKey
+	}
+
+	// all the primary key columns need to be part of the insert
+	for _, key := range primaryKey {
+		if !rc.hasColumn(key) {
+			return nil
+		}
+	}
+	rc.keyColumns = primaryKey
+	return nil
+}
+
+// getPrimaryKey returns the primary key columns of the target table on input,
+// in the constraint order. It returns an empty list if the table doesn't exist
+// or doesn't have a primary key.
+func (w *BatchWriter) getPrimaryKey(ctx context.Context, table string) ([]string, error) {
+	query := `SELECT array_agg(a.attname::text ORDER BY k.ord) FROM pg_index i
+	CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+	JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+	WHERE i.indrelid = to_regclass($1) AND i.indisprimary`
+
+	var primaryKey []string
+	if err := w.conn.QueryRow(ctx, query, table).Scan(&primaryKey); err != nil {
+		return nil, err
+	}
+	return primaryKey, nil
+}
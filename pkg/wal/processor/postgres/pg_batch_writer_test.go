@@ -0,0 +1,379 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pglib "github.com/ApollosProject/pgstream-wal2json/internal/postgres"
+	pgmocks "github.com/ApollosProject/pgstream-wal2json/internal/postgres/mocks"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchWriter_sendBatch(t *testing.T) {
+	t.Parallel()
+
+	testCommitPos := wal.CommitPosition("0/17773B0")
+	errTest := errors.New("oh noes")
+
+	testInsertEvent := func(id float64) *wal.Event {
+		return &wal.Event{
+			Data: &wal.Data{
+				Action: "I",
+				Schema: "public",
+				Table:  "users",
+				Columns: []wal.Column{
+					{ID: "t1-1", Name: "id", Type: "integer", Value: id},
+					{ID: "t1-2", Name: "name", Type: "text", Value: "alice"},
+				},
+				Metadata: wal.Metadata{
+					TablePgstreamID: "t1",
+					InternalColIDs:  []string{"t1-1"},
+				},
+			},
+			CommitPosition: testCommitPos,
+		}
+	}
+	testSchemaChangeEvent := &wal.Event{
+		Data: &wal.Data{
+			Action: wal.SchemaChangeAction,
+			Schema: "public",
+			SchemaChange: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema: schemalog.Schema{Tables: []schemalog.Table{
+					{
+						Name:       "users",
+						PgstreamID: "t1",
+						Columns: []schemalog.Column{
+							{Name: "id", DataType: "integer", PgstreamID: "t1-1"},
+							{Name: "email", DataType: "text", Nullable: true, PgstreamID: "t1-3"},
+						},
+					},
+				}},
+				Diff: &schemalog.SchemaDiff{
+					ColumnsToAdd: []schemalog.Column{{Name: "email", DataType: "text", Nullable: true, PgstreamID: "t1-3"}},
+				},
+			},
+		},
+		CommitPosition: testCommitPos,
+	}
+
+	tests := []struct {
+		name       string
+		events     []*wal.Event
+		execErr    error
+		checkpoint func(ctx context.Context, positions []wal.CommitPosition) error
+
+		wantQueries []string
+		wantErr     error
+	}{
+		{
+			name:   "ok - data and schema changes applied in a single transaction",
+			events: []*wal.Event{testInsertEvent(1), testInsertEvent(2), testSchemaChangeEvent},
+			checkpoint: func(ctx context.Context, positions []wal.CommitPosition) error {
+				require.Len(t, positions, 3)
+				return nil
+			},
+
+			wantQueries: []string{
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text), ($3::text::integer, $4::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+				`ALTER TABLE "public"."users" ADD COLUMN IF NOT EXISTS "email" text`,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ok - keep alive and filtered events",
+			events: []*wal.Event{
+				{CommitPosition: testCommitPos},
+				{Data: &wal.Data{Action: "I", Schema: schemalog.SchemaName, Table: schemalog.TableName}, CommitPosition: testCommitPos},
+			},
+			checkpoint: func(ctx context.Context, positions []wal.CommitPosition) error {
+				require.Len(t, positions, 2)
+				return nil
+			},
+
+			wantQueries: []string{},
+			wantErr:     nil,
+		},
+		{
+			name:    "error - executing query",
+			events:  []*wal.Event{testInsertEvent(1)},
+			execErr: errTest,
+			checkpoint: func(ctx context.Context, positions []wal.CommitPosition) error {
+				return errors.New("checkpoint: unexpected call")
+			},
+
+			wantQueries: []string{
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+			},
+			wantErr: errTest,
+		},
+		{
+			name:   "error - checkpointing",
+			events: []*wal.Event{testInsertEvent(1)},
+			checkpoint: func(ctx context.Context, positions []wal.CommitPosition) error {
+				return errTest
+			},
+
+			wantQueries: []string{
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+			},
+			wantErr: errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queries := []string{}
+			mockTx := &pgmocks.Tx{
+				ExecFn: func(ctx context.Context, query string, args ...any) (pglib.CommandTag, error) {
+					queries = append(queries, query)
+					return pglib.CommandTag{}, tc.execErr
+				},
+			}
+			mockQuerier := &pgmocks.Querier{
+				ExecInTxFn: func(ctx context.Context, fn func(tx pglib.Tx) error) error {
+					return fn(mockTx)
+				},
+			}
+
+			writer := newBatchWriter(&Config{}, mockQuerier, WithCheckpoint(tc.checkpoint))
+
+			batch := &msgBatch{}
+			for _, event := range tc.events {
+				msg, err := writer.adapter.walEventToMsg(event)
+				require.NoError(t, err)
+				batch.add(msg)
+			}
+
+			err := writer.sendBatch(context.Background(), batch)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantQueries, queries)
+		})
+	}
+}
+
+func TestBatchWriter_sendBatch_replay(t *testing.T) {
+	t.Parallel()
+
+	testCommitPos := wal.CommitPosition("0/17773B0")
+	errTest := errors.New("oh noes")
+
+	// events without pgstream identity columns, as received when the
+	// translator is not enabled
+	testInsertEvent := func(id float64) *wal.Event {
+		return &wal.Event{
+			Data: &wal.Data{
+				Action: "I",
+				Schema: "public",
+				Table:  "users",
+				Columns: []wal.Column{
+					{Name: "id", Type: "integer", Value: id},
+					{Name: "name", Type: "text", Value: "alice"},
+				},
+			},
+			CommitPosition: testCommitPos,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		primaryKey []string
+		scanErr    error
+
+		wantQueries   []string
+		wantPKLookups int
+		wantErr       error
+	}{
+		{
+			name:       "ok - inserts applied as upserts on the target primary key",
+			primaryKey: []string{"id"},
+
+			wantQueries: []string{
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text), ($3::text::integer, $4::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text), ($3::text::integer, $4::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+			},
+			wantPKLookups: 1,
+			wantErr:       nil,
+		},
+		{
+			name:       "ok - target table without primary key",
+			primaryKey: nil,
+
+			wantQueries: []string{
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text), ($3::text::integer, $4::text::text)`,
+				`INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text), ($3::text::integer, $4::text::text)`,
+			},
+			wantPKLookups: 1,
+			wantErr:       nil,
+		},
+		{
+			name:    "error - retrieving primary key",
+			scanErr: errTest,
+
+			wantQueries:   []string{},
+			wantPKLookups: 1,
+			wantErr:       errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queries := []string{}
+			pkLookups := 0
+			mockTx := &pgmocks.Tx{
+				ExecFn: func(ctx context.Context, query string, args ...any) (pglib.CommandTag, error) {
+					queries = append(queries, query)
+					return pglib.CommandTag{}, nil
+				},
+			}
+			mockQuerier := &pgmocks.Querier{
+				QueryRowFn: func(ctx context.Context, query string, args ...any) pglib.Row {
+					pkLookups++
+					require.Equal(t, []any{`"public"."users"`}, args)
+					return &mockRow{scanFn: func(dest ...any) error {
+						if tc.scanErr != nil {
+							return tc.scanErr
+						}
+						pk, ok := dest[0].(*[]string)
+						require.True(t, ok)
+						*pk = tc.primaryKey
+						return nil
+					}}
+				},
+				ExecInTxFn: func(ctx context.Context, fn func(tx pglib.Tx) error) error {
+					return fn(mockTx)
+				},
+			}
+
+			writer := newBatchWriter(&Config{}, mockQuerier)
+
+			// the same events are applied twice, as they would be when
+			// replayed after a restart before the position was checkpointed
+			var err error
+			for i := 0; i < 2 && err == nil; i++ {
+				batch := &msgBatch{}
+				for _, event := range []*wal.Event{testInsertEvent(1), testInsertEvent(2)} {
+					msg, err := writer.adapter.walEventToMsg(event)
+					require.NoError(t, err)
+					batch.add(msg)
+				}
+				err = writer.sendBatch(context.Background(), batch)
+			}
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantQueries, queries)
+			require.Equal(t, tc.wantPKLookups, pkLookups)
+		})
+	}
+}
+
+func TestAdapter_walEventToMsg(t *testing.T) {
+	t.Parallel()
+
+	testCommitPos := wal.CommitPosition("0/17773B0")
+
+	tests := []struct {
+		name          string
+		includeTables []string
+		event         *wal.Event
+
+		wantMsg *msg
+		wantErr error
+	}{
+		{
+			name: "ok - delete",
+			event: &wal.Event{
+				Data: &wal.Data{
+					Action:   "D",
+					Schema:   "public",
+					Table:    "users",
+					Identity: []wal.Column{{Name: "id", Type: "integer", Value: float64(1)}},
+				},
+				CommitPosition: testCommitPos,
+			},
+
+			wantMsg: &msg{
+				rowChange: &rowChange{
+					schema:   "public",
+					table:    "users",
+					action:   deleteAction,
+					identity: []columnValue{{name: "id", dataType: "integer", value: ptr("1")}},
+				},
+				bytesSize: 10,
+				pos:       testCommitPos,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ok - truncate",
+			event: &wal.Event{
+				Data:           &wal.Data{Action: "T", Schema: "public", Table: "users"},
+				CommitPosition: testCommitPos,
+			},
+
+			wantMsg: &msg{
+				queries:   []*query{{sql: `TRUNCATE "public"."users"`}},
+				bytesSize: 25,
+				pos:       testCommitPos,
+			},
+			wantErr: nil,
+		},
+		{
+			name:          "ok - table not included",
+			includeTables: []string{"public.orders"},
+			event: &wal.Event{
+				Data:           &wal.Data{Action: "T", Schema: "public", Table: "users"},
+				CommitPosition: testCommitPos,
+			},
+
+			wantMsg: &msg{pos: testCommitPos},
+			wantErr: nil,
+		},
+		{
+			name: "error - delete without identity",
+			event: &wal.Event{
+				Data:           &wal.Data{Action: "D", Schema: "public", Table: "users"},
+				CommitPosition: testCommitPos,
+			},
+
+			wantMsg: nil,
+			wantErr: errMissingIdentity,
+		},
+		{
+			name: "error - invalid column data type",
+			event: &wal.Event{
+				Data: &wal.Data{
+					Action:  "I",
+					Schema:  "public",
+					Table:   "users",
+					Columns: []wal.Column{{Name: "id", Type: "integer; DROP TABLE users", Value: float64(1)}},
+				},
+				CommitPosition: testCommitPos,
+			},
+
+			wantMsg: nil,
+			wantErr: errInvalidDataType,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := newAdapter(tc.includeTables).walEventToMsg(tc.event)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantMsg, msg)
+		})
+	}
+}
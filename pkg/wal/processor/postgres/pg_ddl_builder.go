@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// ddlBuilder generates the DDL statements to replay schema changes on the
+// target database. Only table and column definitions (names, types,
+// nullability and primary keys) are replicated.
+type ddlBuilder struct {
+	includeTable tableFilter
+}
+
+type tableFilter func(schema, table string) bool
+
+// schemaChangeQueries returns the queries to apply the schema change on the
+// target database. Schema changes with invalid column data types are
+// rejected.
+func (b *ddlBuilder) schemaChangeQueries(change *wal.SchemaChange) ([]*query, error) {
+	schemaName := change.SchemaName
+	// dropped schemas are not dropped on the target database, since they can
+	// contain tables that are not replicated. Their replicated tables are
+	// part of the removed tables in the diff instead.
+	diff := change.Diff
+	if diff == nil || (diff.Empty() && len(diff.PrimaryKeyChange) == 0) {
+		return nil, nil
+	}
+
+	queries := []*query{}
+	add := func(table string, format string, args ...any) {
+		if !b.includeTable(schemaName, table) {
+			return
+		}
+		queries = append(queries, &query{sql: fmt.Sprintf(format, args...)})
+	}
+
+	removedTables := map[string]struct{}{}
+	for _, table := range diff.TablesToRemove {
+		removedTables[table.PgstreamID] = struct{}{}
+		add(table.Name, "DROP TABLE IF EXISTS %s", quotedTableName(schemaName, table.Name))
+	}
+
+	schemaCreated := false
+	createTable := func(table *schemalog.Table) error {
+		// make sure the schema exists before the first table is created
+		if !schemaCreated {
+			add(table.Name, "CREATE SCHEMA IF NOT EXISTS %s", quoteIdentifier(schemaName))
+			schemaCreated = true
+		}
+		statement, err := createTableStatement(schemaName, table)
+		if err != nil {
+			return err
+		}
+		add(table.Name, "%s", statement)
+		return nil
+	}
+
+	// tables renamed in or out of the included tables are created or dropped
+	// instead, since they don't exist or are no longer replicated on the
+	// target database
+	createdTables := map[string]struct{}{}
+	for _, rename := range diff.TablesRenamed {
+		oldIncluded := b.includeTable(schemaName, rename.OldName)
+		newIncluded := b.includeTable(schemaName, rename.NewName)
+		switch {
+		case oldIncluded && newIncluded:
+			add(rename.NewName, "ALTER TABLE %s RENAME TO %s", quotedTableName(schemaName, rename.OldName), quoteIdentifier(rename.NewName))
+		case oldIncluded:
+			add(rename.OldName, "DROP TABLE IF EXISTS %s", quotedTableName(schemaName, rename.OldName))
+		case newIncluded:
+			table := getTableByID(&change.Schema, rename.PgstreamID)
+			if table == nil {
+				continue
+			}
+			if err := createTable(table); err != nil {
+				return nil, err
+			}
+			createdTables[table.PgstreamID] = struct{}{}
+		}
+	}
+
+	for _, table := range diff.TablesAdded {
+		createdTables[table.PgstreamID] = struct{}{}
+		if !b.includeTable(schemaName, table.Name) {
+			continue
+		}
+		if err := createTable(&table); err != nil {
+			return nil, err
+		}
+	}
+
+	// the column changes of the tables created are already part of the
+	// create table statement
+	isCreated := func(tableID string) bool {
+		_, created := createdTables[tableID]
+		return created
+	}
+
+	for _, col := range diff.ColumnsRemoved {
+		tableID := columnTableID(&col)
+		if _, removed := removedTables[tableID]; removed || isCreated(tableID) {
+			continue
+		}
+		table := getTableByID(&change.Schema, tableID)
+		if table == nil {
+			continue
+		}
+		add(table.Name, "ALTER TABLE %s DROP COLUMN IF EXISTS %s", quotedTableName(schemaName, table.Name), quoteIdentifier(col.Name))
+	}
+
+	for _, rename := range diff.ColumnsRenamed {
+		if isCreated(rename.TablePgstreamID) {
+			continue
+		}
+		add(rename.TableName, "ALTER TABLE %s RENAME COLUMN %s TO %s", quotedTableName(schemaName, rename.TableName), quoteIdentifier(rename.Old.Name), quoteIdentifier(rename.New.Name))
+	}
+
+	for _, col := range diff.ColumnsToAdd {
+		tableID := columnTableID(&col)
+		if isCreated(tableID) {
+			continue
+		}
+		table := getTableByID(&change.Schema, tableID)
+		if table == nil || !b.includeTable(schemaName, table.Name) {
+			continue
+		}
+		definition, err := columnDefinition(&col)
+		if err != nil {
+			return nil, err
+		}
+		add(table.Name, "ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s", quotedTableName(schemaName, table.Name), definition)
+	}
+
+	for _, colChange := range diff.ColumnTypeChanged {
+		if isCreated(colChange.TablePgstreamID) || !b.includeTable(schemaName, colChange.TableName) {
+			continue
+		}
+		if err := validateDataType(colChange.New.DataType); err != nil {
+			return nil, fmt.Errorf("column %s: %w", colChange.New.Name, err)
+		}
+		colName := quoteIdentifier(colChange.New.Name)
+		add(colChange.TableName, "ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::text::%s", quotedTableName(schemaName, colChange.TableName), colName, colChange.New.DataType, colName, colChange.New.DataType)
+	}
+
+	for _, colChange := range diff.ColumnNullabilityChanged {
+		if isCreated(colChange.TablePgstreamID) {
+			continue
+		}
+		action := "SET NOT NULL"
+		if colChange.New.Nullable {
+			action = "DROP NOT NULL"
+		}
+		add(colChange.TableName, "ALTER TABLE %s ALTER COLUMN %s %s", quotedTableName(schemaName, colChange.TableName), quoteIdentifier(colChange.New.Name), action)
+	}
+
+	for _, tableName := range diff.PrimaryKeyChange {
+		table := getTableByName(&change.Schema, tableName)
+		if table == nil || isCreated(table.PgstreamID) {
+			continue
+		}
+		add(tableName, "%s", dropPrimaryKeyStatement(schemaName, tableName))
+		if len(table.PrimaryKeyColumns) > 0 {
+			add(tableName, "ALTER TABLE %s ADD PRIMARY KEY (%s)", quotedTableName(schemaName, tableName), quoteIdentifiers(table.PrimaryKeyColumns))
+		}
+	}
+
+	return queries, nil
+}
+
+func createTableStatement(schemaName string, table *schemalog.Table) (string, error) {
+	definitions := make([]string, 0, len(table.Columns)+1)
+	for i := range table.Columns {
+		definition, err := columnDefinition(&table.Columns[i])
+		if err != nil {
+			return "", fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		definitions = append(definitions, definition)
+	}
+	if len(table.PrimaryKeyColumns) > 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdentifiers(table.PrimaryKeyColumns)))
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quotedTableName(schemaName, table.Name), strings.Join(definitions, ", ")), nil
+}
+
+// columnDefinition returns the column definition for the column on input.
+// Default values are not replicated, since they can reference sequences or
+// functions that don't exist in the target database.
+func columnDefinition(col *schemalog.Column) (string, error) {
+	if err := validateDataType(col.DataType); err != nil {
+		return "", fmt.Errorf("column %s: %w", col.Name, err)
+	}
+	definition := fmt.Sprintf("%s %s", quoteIdentifier(col.Name), col.DataType)
+	if !col.Nullable {
+		definition += " NOT NULL"
+	}
+	return definition, nil
+}
+
+// dropPrimaryKeyStatement returns a statement that drops the primary key
+// constraint of the table, if any. The constraint name is looked up, since it
+// doesn't follow the default naming if the table has been renamed.
+func dropPrimaryKeyStatement(schemaName, tableName string) string {
+	table := quotedTableName(schemaName, tableName)
+	return fmt.Sprintf(`DO $$
+DECLARE pk_name text;
+BEGIN
+	SELECT conname INTO pk_name FROM pg_constraint WHERE conrelid = %[1]s::regclass AND contype = 'p';
+	IF pk_name IS NOT NULL THEN
+		EXECUTE format('ALTER TABLE %%s DROP CONSTRAINT %%I', %[1]s, pk_name);
+	END IF;
+END $$`, quoteLiteral(table))
+}
+
+// columnTableID returns the pgstream ID of the table the column belongs to.
+// Column pgstream IDs have the format `<table pgstream id>-<column number>`.
+func columnTableID(col *schemalog.Column) string {
+	i := strings.LastIndex(col.PgstreamID, "-")
+	if i < 0 {
+		return ""
+	}
+	return col.PgstreamID[:i]
+}
+
+func getTableByID(schema *schemalog.Schema, pgstreamID string) *schemalog.Table {
+	for i := range schema.Tables {
+		if schema.Tables[i].PgstreamID == pgstreamID {
+			return &schema.Tables[i]
+		}
+	}
+	return nil
+}
+
+func getTableByName(schema *schemalog.Schema, name string) *schemalog.Table {
+	for i := range schema.Tables {
+		if schema.Tables[i].Name == name {
+			return &schema.Tables[i]
+		}
+	}
+	return nil
+}
+
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteIdentifier(name))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLBuilder_schemaChangeQueries(t *testing.T) {
+	t.Parallel()
+
+	usersTable := schemalog.Table{
+		Name:       "users",
+		PgstreamID: "t1",
+		Columns: []schemalog.Column{
+			{Name: "id", DataType: "integer", PgstreamID: "t1-1"},
+			{Name: "name", DataType: "text", Nullable: true, PgstreamID: "t1-2"},
+		},
+		PrimaryKeyColumns: []string{"id"},
+	}
+	ordersTable := schemalog.Table{
+		Name:       "orders",
+		PgstreamID: "t2",
+		Columns: []schemalog.Column{
+			{Name: "id", DataType: "bigint", PgstreamID: "t2-1"},
+			{Name: "total", DataType: "numeric(10,2)", PgstreamID: "t2-3"},
+		},
+		PrimaryKeyColumns: []string{"id"},
+	}
+
+	tests := []struct {
+		name          string
+		includeTables []string
+		change        *wal.SchemaChange
+
+		wantQueries []string
+		wantErr     error
+	}{
+		{
+			name: "ok - tables added",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesAdded:  []schemalog.Table{usersTable},
+					ColumnsToAdd: usersTable.Columns,
+				},
+			},
+
+			wantQueries: []string{
+				`CREATE SCHEMA IF NOT EXISTS "public"`,
+				`CREATE TABLE IF NOT EXISTS "public"."users" ("id" integer NOT NULL, "name" text, PRIMARY KEY ("id"))`,
+			},
+		},
+		{
+			name:          "ok - tables added, filtered",
+			includeTables: []string{"public.orders"},
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable, ordersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesAdded:  []schemalog.Table{usersTable, ordersTable},
+					ColumnsToAdd: append(usersTable.Columns, ordersTable.Columns...),
+				},
+			},
+
+			wantQueries: []string{
+				`CREATE SCHEMA IF NOT EXISTS "public"`,
+				`CREATE TABLE IF NOT EXISTS "public"."orders" ("id" bigint NOT NULL, "total" numeric(10,2) NOT NULL, PRIMARY KEY ("id"))`,
+			},
+		},
+		{
+			name: "ok - table and column changes",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable, ordersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesToRemove: []schemalog.Table{{Name: "items", PgstreamID: "t3"}},
+					TablesRenamed:  []schemalog.TableRename{{PgstreamID: "t2", OldName: "purchases", NewName: "orders"}},
+					ColumnsToAdd:   []schemalog.Column{{Name: "total", DataType: "numeric(10,2)", PgstreamID: "t2-3"}},
+					ColumnsRemoved: []schemalog.Column{
+						{Name: "amount", DataType: "integer", PgstreamID: "t2-2"},
+						{Name: "sku", DataType: "text", PgstreamID: "t3-2"},
+					},
+					ColumnsRenamed: []schemalog.ColumnChange{
+						{TablePgstreamID: "t1", TableName: "users", Old: schemalog.Column{Name: "username"}, New: schemalog.Column{Name: "name"}},
+					},
+					ColumnTypeChanged: []schemalog.ColumnChange{
+						{TablePgstreamID: "t2", TableName: "orders", Old: schemalog.Column{Name: "id", DataType: "integer"}, New: schemalog.Column{Name: "id", DataType: "bigint"}},
+					},
+					ColumnNullabilityChanged: []schemalog.ColumnChange{
+						{TablePgstreamID: "t1", TableName: "users", Old: schemalog.Column{Name: "name"}, New: schemalog.Column{Name: "name", Nullable: true}},
+					},
+				},
+			},
+
+			wantQueries: []string{
+				`DROP TABLE IF EXISTS "public"."items"`,
+				`ALTER TABLE "public"."purchases" RENAME TO "orders"`,
+				`ALTER TABLE "public"."orders" DROP COLUMN IF EXISTS "amount"`,
+				`ALTER TABLE "public"."users" RENAME COLUMN "username" TO "name"`,
+				`ALTER TABLE "public"."orders" ADD COLUMN IF NOT EXISTS "total" numeric(10,2) NOT NULL`,
+				`ALTER TABLE "public"."orders" ALTER COLUMN "id" TYPE bigint USING "id"::text::bigint`,
+				`ALTER TABLE "public"."users" ALTER COLUMN "name" DROP NOT NULL`,
+			},
+		},
+		{
+			name:          "ok - table renamed into the included tables",
+			includeTables: []string{"public.orders"},
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable, ordersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesRenamed: []schemalog.TableRename{{PgstreamID: "t2", OldName: "purchases", NewName: "orders"}},
+					ColumnsRenamed: []schemalog.ColumnChange{
+						{TablePgstreamID: "t2", TableName: "orders", Old: schemalog.Column{Name: "amount"}, New: schemalog.Column{Name: "total"}},
+					},
+				},
+			},
+
+			wantQueries: []string{
+				`CREATE SCHEMA IF NOT EXISTS "public"`,
+				`CREATE TABLE IF NOT EXISTS "public"."orders" ("id" bigint NOT NULL, "total" numeric(10,2) NOT NULL, PRIMARY KEY ("id"))`,
+			},
+		},
+		{
+			name:          "ok - table renamed out of the included tables",
+			includeTables: []string{"public.purchases"},
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable, ordersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesRenamed: []schemalog.TableRename{{PgstreamID: "t2", OldName: "purchases", NewName: "orders"}},
+				},
+			},
+
+			wantQueries: []string{
+				`DROP TABLE IF EXISTS "public"."purchases"`,
+			},
+		},
+		{
+			name:          "ok - table renamed outside of the included tables",
+			includeTables: []string{"public.users"},
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable, ordersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesRenamed: []schemalog.TableRename{{PgstreamID: "t2", OldName: "purchases", NewName: "orders"}},
+				},
+			},
+
+			wantQueries: []string{},
+		},
+		{
+			name: "ok - primary key change",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable}},
+				Diff: &schemalog.SchemaDiff{
+					PrimaryKeyChange: []string{"users"},
+				},
+			},
+
+			wantQueries: []string{
+				dropPrimaryKeyStatement("public", "users"),
+				`ALTER TABLE "public"."users" ADD PRIMARY KEY ("id")`,
+			},
+		},
+		{
+			name: "ok - schema dropped",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Dropped: true},
+				Diff: &schemalog.SchemaDiff{
+					TablesToRemove: []schemalog.Table{usersTable, {Name: "orders", PgstreamID: "t2"}},
+				},
+			},
+
+			wantQueries: []string{
+				`DROP TABLE IF EXISTS "public"."users"`,
+				`DROP TABLE IF EXISTS "public"."orders"`,
+			},
+		},
+		{
+			name:          "ok - schema dropped, only replicated tables are dropped",
+			includeTables: []string{"public.users"},
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Dropped: true},
+				Diff: &schemalog.SchemaDiff{
+					TablesToRemove: []schemalog.Table{usersTable, {Name: "orders", PgstreamID: "t2"}},
+				},
+			},
+
+			wantQueries: []string{
+				`DROP TABLE IF EXISTS "public"."users"`,
+			},
+		},
+		{
+			name:          "ok - schema dropped, filtered",
+			includeTables: []string{"analytics.*"},
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Dropped: true},
+				Diff: &schemalog.SchemaDiff{
+					TablesToRemove: []schemalog.Table{usersTable},
+				},
+			},
+
+			wantQueries: []string{},
+		},
+		{
+			name: "error - invalid column data type",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable}},
+				Diff: &schemalog.SchemaDiff{
+					ColumnTypeChanged: []schemalog.ColumnChange{
+						{TablePgstreamID: "t1", TableName: "users", Old: schemalog.Column{Name: "id", DataType: "integer"}, New: schemalog.Column{Name: "id", DataType: "bigint; DROP TABLE users"}},
+					},
+				},
+			},
+
+			wantErr: errInvalidDataType,
+		},
+		{
+			name: "error - invalid column data type in added table",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable}},
+				Diff: &schemalog.SchemaDiff{
+					TablesAdded: []schemalog.Table{{
+						Name:       "users",
+						PgstreamID: "t1",
+						Columns:    []schemalog.Column{{Name: "id", DataType: "integer) --", PgstreamID: "t1-1"}},
+					}},
+				},
+			},
+
+			wantErr: errInvalidDataType,
+		},
+		{
+			name: "ok - no diff",
+			change: &wal.SchemaChange{
+				SchemaName: "public",
+				Schema:     schemalog.Schema{Tables: []schemalog.Table{usersTable}},
+				Diff:       &schemalog.SchemaDiff{},
+			},
+
+			wantQueries: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := &ddlBuilder{includeTable: newTableFilter(tc.includeTables)}
+			queries, err := builder.schemaChangeQueries(tc.change)
+			require.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr != nil {
+				return
+			}
+
+			sqls := make([]string, 0, len(queries))
+			for _, q := range queries {
+				sqls = append(sqls, q.sql)
+			}
+			require.Equal(t, tc.wantQueries, sqls)
+		})
+	}
+}
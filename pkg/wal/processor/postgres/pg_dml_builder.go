@@ -0,0 +1,406 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type query struct {
+	sql  string
+	args []any
+}
+
+// rowChange represents an insert, update or delete wal event, with the column
+// values converted to their text representation.
+type rowChange struct {
+	schema   string
+	table    string
+	action   string
+	columns  []columnValue
+	identity []columnValue
+	// keyColumns are the names of the pgstream identity columns, used as
+	// conflict target for upserts. If the event metadata hasn't been
+	// populated, inserts use the primary key of the target table instead.
+	keyColumns []string
+}
+
+type columnValue struct {
+	name     string
+	dataType string
+	// value is the text representation of the column value. It will be nil
+	// for NULL values.
+	value *string
+}
+
+const (
+	insertAction   = "I"
+	updateAction   = "U"
+	deleteAction   = "D"
+	truncateAction = "T"
+
+	// maxQueryParams is the max number of parameters supported by postgres in
+	// a single query
+	maxQueryParams = 65535
+)
+
+// dmlBuilder generates the queries to apply the row changes on input on the
+// target database. Consecutive upserts to the same table are combined into a
+// multi row insert statement.
+type dmlBuilder struct {
+	queries []*query
+	upserts *upsertBatch
+}
+
+func (b *dmlBuilder) addRowChange(rc *rowChange) {
+	switch rc.action {
+	case insertAction:
+		b.addUpsert(rc)
+	case updateAction:
+		if len(rc.keyColumns) == 0 {
+			b.addQuery(updateQuery(rc))
+			return
+		}
+		// if the identity changed, the row with the old identity needs to be
+		// removed before the new one is upserted
+		if rc.identityChanged() {
+			b.addQuery(deleteQuery(rc))
+		}
+		b.addUpsert(rc)
+	case deleteAction:
+		b.addQuery(deleteQuery(rc))
+	}
+}
+
+func (b *dmlBuilder) addQuery(q *query) {
+	b.flushUpserts()
+	b.queries = append(b.queries, q)
+}
+
+func (b *dmlBuilder) addUpsert(rc *rowChange) {
+	if b.upserts != nil && !b.upserts.accepts(rc) {
+		b.flushUpserts()
+	}
+	if b.upserts == nil {
+		b.upserts = newUpsertBatch(rc)
+	}
+	b.upserts.add(rc)
+}
+
+func (b *dmlBuilder) flushUpserts() {
+	if b.upserts == nil {
+		return
+	}
+	b.queries = append(b.queries, b.upserts.query())
+	b.upserts = nil
+}
+
+// build returns the queries accumulated so far, and resets the builder.
+func (b *dmlBuilder) build() []*query {
+	b.flushUpserts()
+	queries := b.queries
+	b.queries = nil
+	return queries
+}
+
+// upsertBatch groups upserts for the same table and set of columns into a
+// single multi row insert statement.
+type upsertBatch struct {
+	signature  string
+	table      string
+	columns    []columnValue
+	keyColumns []string
+	rows       [][]columnValue
+	// keys keeps track of the identities included in the batch, since the same
+	// row can't be affected twice by the same upsert statement.
+	keys map[string]struct{}
+}
+
+func newUpsertBatch(rc *rowChange) *upsertBatch {
+	return &upsertBatch{
+		signature:  rc.signature(),
+		table:      quotedTableName(rc.schema, rc.table),
+		columns:    rc.columns,
+		keyColumns: rc.keyColumns,
+		keys:       map[string]struct{}{},
+	}
+}
+
+func (b *upsertBatch) accepts(rc *rowChange) bool {
+	if b.signature != rc.signature() {
+		return false
+	}
+	if (len(b.rows)+1)*len(b.columns) > maxQueryParams {
+		return false
+	}
+	if len(b.keyColumns) == 0 {
+		return true
+	}
+	_, found := b.keys[rc.keyValue()]
+	return !found
+}
+
+func (b *upsertBatch) add(rc *rowChange) {
+	b.rows = append(b.rows, rc.columns)
+	if len(b.keyColumns) > 0 {
+		b.keys[rc.keyValue()] = struct{}{}
+	}
+}
+
+func (b *upsertBatch) query() *query {
+	columnNames := make([]string, 0, len(b.columns))
+	for _, col := range b.columns {
+		columnNames = append(columnNames, quoteIdentifier(col.name))
+	}
+
+	args := make([]any, 0, len(b.rows)*len(b.columns))
+	values := make([]string, 0, len(b.rows))
+	for _, row := range b.rows {
+		placeholders := make([]string, 0, len(row))
+		for _, col := range row {
+			args = append(args, col.arg())
+			placeholders = append(placeholders, placeholder(len(args), col.dataType))
+		}
+		values = append(values, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", b.table, strings.Join(columnNames, ", "), strings.Join(values, ", "))
+	if len(b.keyColumns) > 0 {
+		sql = fmt.Sprintf("%s %s", sql, b.onConflictClause())
+	}
+
+	return &query{sql: sql, args: args}
+}
+
+func (b *upsertBatch) onConflictClause() string {
+	keyColumns := make([]string, 0, len(b.keyColumns))
+	for _, key := range b.keyColumns {
+		keyColumns = append(keyColumns, quoteIdentifier(key))
+	}
+
+	updates := []string{}
+	for _, col := range b.columns {
+		if slices.Contains(b.keyColumns, col.name) {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf("%[1]s = EXCLUDED.%[1]s", quoteIdentifier(col.name)))
+	}
+
+	if len(updates) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(keyColumns, ", "))
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(keyColumns, ", "), strings.Join(updates, ", "))
+}
+
+func updateQuery(rc *rowChange) *query {
+	args := make([]any, 0, len(rc.columns)+len(rc.identity))
+	sets := make([]string, 0, len(rc.columns))
+	for _, col := range rc.columns {
+		args = append(args, col.arg())
+		sets = append(sets, fmt.Sprintf("%s = %s", quoteIdentifier(col.name), placeholder(len(args), col.dataType)))
+	}
+
+	where, args := whereClause(rc.identity, args)
+	return &query{
+		sql:  fmt.Sprintf("UPDATE %s SET %s WHERE %s", quotedTableName(rc.schema, rc.table), strings.Join(sets, ", "), where),
+		args: args,
+	}
+}
+
+func deleteQuery(rc *rowChange) *query {
+	where, args := whereClause(rc.identityKey(), nil)
+	return &query{
+		sql:  fmt.Sprintf("DELETE FROM %s WHERE %s", quotedTableName(rc.schema, rc.table), where),
+		args: args,
+	}
+}
+
+func truncateQuery(schema, table string) *query {
+	return &query{sql: fmt.Sprintf("TRUNCATE %s", quotedTableName(schema, table))}
+}
+
+func whereClause(columns []columnValue, args []any) (string, []any) {
+	conditions := make([]string, 0, len(columns))
+	for _, col := range columns {
+		args = append(args, col.arg())
+		conditions = append(conditions, fmt.Sprintf("%s IS NOT DISTINCT FROM %s", quoteIdentifier(col.name), placeholder(len(args), col.dataType)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// signature identifies the table and the set of columns of the row change, so
+// that upserts with the same signature can be combined.
+func (rc *rowChange) signature() string {
+	var sb strings.Builder
+	sb.WriteString(quotedTableName(rc.schema, rc.table))
+	for _, col := range rc.columns {
+		sb.WriteString("|")
+		sb.WriteString(col.name)
+		sb.WriteString(":")
+		sb.WriteString(col.dataType)
+	}
+	sb.WriteString("|")
+	sb.WriteString(strings.Join(rc.keyColumns, ","))
+	return sb.String()
+}
+
+// keyValue returns a string representation of the key column values of the
+// row change.
+func (rc *rowChange) keyValue() string {
+	values := make([]*string, 0, len(rc.keyColumns))
+	for _, key := range rc.keyColumns {
+		for _, col := range rc.columns {
+			if col.name == key {
+				values = append(values, col.value)
+			}
+		}
+	}
+	keyBytes, _ := json.Marshal(values)
+	return string(keyBytes)
+}
+
+// identityKey returns the identity columns to be used to identify the row
+// being updated or deleted. If key columns are known, only those are used.
+func (rc *rowChange) identityKey() []columnValue {
+	if len(rc.keyColumns) == 0 {
+		return rc.identity
+	}
+
+	key := make([]columnValue, 0, len(rc.keyColumns))
+	for _, col := range rc.identity {
+		if slices.Contains(rc.keyColumns, col.name) {
+			key = append(key, col)
+		}
+	}
+	if len(key) == 0 {
+		return rc.identity
+	}
+	return key
+}
+
+// identityChanged returns true if the old identity values of the row change
+// are different to the new values.
+func (rc *rowChange) identityChanged() bool {
+	for _, oldCol := range rc.identityKey() {
+		for _, newCol := range rc.columns {
+			if newCol.name == oldCol.name && !equalValues(newCol.value, oldCol.value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rc *rowChange) hasColumn(name string) bool {
+	for _, col := range rc.columns {
+		if col.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (rc *rowChange) size() int {
+	size := 0
+	for _, cols := range [][]columnValue{rc.columns, rc.identity} {
+		for _, col := range cols {
+			size += len(col.name) + len(col.dataType)
+			if col.value != nil {
+				size += len(*col.value)
+			}
+		}
+	}
+	return size
+}
+
+func (c columnValue) arg() any {
+	if c.value == nil {
+		return nil
+	}
+	return *c.value
+}
+
+// placeholder returns the query parameter placeholder for the position on
+// input. Values are sent in their text representation and cast to the column
+// type, so that any type can be written without requiring a specific encoding.
+// The data type must have been validated with validateDataType.
+func placeholder(position int, dataType string) string {
+	if dataType == "" {
+		return fmt.Sprintf("$%d", position)
+	}
+	return fmt.Sprintf("$%d::text::%s", position, dataType)
+}
+
+// toText converts the wal column value on input to its postgres text
+// representation.
+func toText(value any) (*string, error) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case int:
+		text = strconv.Itoa(v)
+	case bool:
+		text = strconv.FormatBool(v)
+	default:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("converting value of type %T to text: %w", v, err)
+		}
+		text = string(valueBytes)
+	}
+	return &text, nil
+}
+
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func quotedTableName(schema, table string) string {
+	return pgx.Identifier{schema, table}.Sanitize()
+}
+
+func quoteIdentifier(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+var errInvalidDataType = errors.New("invalid column data type")
+
+// dataTypeRegex matches the type names as formatted by postgres: an optionally
+// schema qualified name made of unquoted words or a quoted identifier, with
+// optional type modifiers and array dimensions (i.e, `character varying(255)`,
+// `timestamp(3) with time zone`, `public."MyType"[]`).
+var dataTypeRegex = func() *regexp.Regexp {
+	identifier := `(?:[A-Za-z_][A-Za-z0-9_$]*|"(?:[^"]|"")+")`
+	word := identifier + `(?:\(\d+(?:, ?\d+)*\))?`
+	return regexp.MustCompile(`^(?:` + identifier + `\.)?` + word + `(?: ` + word + `)*(?:\[\d*\])*$`)
+}()
+
+// validateDataType returns an error if the data type on input is not a valid
+// postgres type name, since the data types are part of the generated queries
+// and can't be passed as parameters.
+func validateDataType(dataType string) error {
+	if !dataTypeRegex.MatchString(dataType) {
+		return fmt.Errorf("%w: %q", errInvalidDataType, dataType)
+	}
+	return nil
+}
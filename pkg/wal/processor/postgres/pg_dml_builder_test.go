@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDMLBuilder(t *testing.T) {
+	t.Parallel()
+
+	idCol := func(id string) columnValue {
+		return columnValue{name: "id", dataType: "integer", value: ptr(id)}
+	}
+	nameCol := func(name *string) columnValue {
+		return columnValue{name: "name", dataType: "text", value: name}
+	}
+
+	tests := []struct {
+		name       string
+		rowChanges []*rowChange
+
+		wantQueries []*query
+	}{
+		{
+			name: "ok - consecutive inserts combined in a single upsert",
+			rowChanges: []*rowChange{
+				{schema: "public", table: "users", action: insertAction, columns: []columnValue{idCol("1"), nameCol(ptr("a"))}, keyColumns: []string{"id"}},
+				{schema: "public", table: "users", action: insertAction, columns: []columnValue{idCol("2"), nameCol(nil)}, keyColumns: []string{"id"}},
+			},
+
+			wantQueries: []*query{
+				{
+					sql:  `INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text), ($3::text::integer, $4::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+					args: []any{"1", "a", "2", nil},
+				},
+			},
+		},
+		{
+			name: "ok - upserts for the same key are not combined",
+			rowChanges: []*rowChange{
+				{schema: "public", table: "users", action: insertAction, columns: []columnValue{idCol("1"), nameCol(ptr("a"))}, keyColumns: []string{"id"}},
+				{schema: "public", table: "users", action: updateAction, columns: []columnValue{idCol("1"), nameCol(ptr("b"))}, identity: []columnValue{idCol("1")}, keyColumns: []string{"id"}},
+			},
+
+			wantQueries: []*query{
+				{
+					sql:  `INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+					args: []any{"1", "a"},
+				},
+				{
+					sql:  `INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+					args: []any{"1", "b"},
+				},
+			},
+		},
+		{
+			name: "ok - update with identity change",
+			rowChanges: []*rowChange{
+				{schema: "public", table: "users", action: updateAction, columns: []columnValue{idCol("2"), nameCol(ptr("a"))}, identity: []columnValue{idCol("1")}, keyColumns: []string{"id"}},
+			},
+
+			wantQueries: []*query{
+				{
+					sql:  `DELETE FROM "public"."users" WHERE "id" IS NOT DISTINCT FROM $1::text::integer`,
+					args: []any{"1"},
+				},
+				{
+					sql:  `INSERT INTO "public"."users" ("id", "name") VALUES ($1::text::integer, $2::text::text) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+					args: []any{"2", "a"},
+				},
+			},
+		},
+		{
+			name: "ok - update without key columns",
+			rowChanges: []*rowChange{
+				{schema: "public", table: "users", action: updateAction, columns: []columnValue{idCol("1"), nameCol(ptr("a"))}, identity: []columnValue{idCol("1")}},
+			},
+
+			wantQueries: []*query{
+				{
+					sql:  `UPDATE "public"."users" SET "id" = $1::text::integer, "name" = $2::text::text WHERE "id" IS NOT DISTINCT FROM $3::text::integer`,
+					args: []any{"1", "a", "1"},
+				},
+			},
+		},
+		{
+			name: "ok - insert, delete and insert",
+			rowChanges: []*rowChange{
+				{schema: "public", table: "users", action: insertAction, columns: []columnValue{idCol("1")}, keyColumns: []string{"id"}},
+				{schema: "public", table: "users", action: deleteAction, identity: []columnValue{idCol("1"), nameCol(ptr("a"))}, keyColumns: []string{"id"}},
+				{schema: "public", table: "users", action: insertAction, columns: []columnValue{idCol("1")}},
+			},
+
+			wantQueries: []*query{
+				{
+					sql:  `INSERT INTO "public"."users" ("id") VALUES ($1::text::integer) ON CONFLICT ("id") DO NOTHING`,
+					args: []any{"1"},
+				},
+				{
+					sql:  `DELETE FROM "public"."users" WHERE "id" IS NOT DISTINCT FROM $1::text::integer`,
+					args: []any{"1"},
+				},
+				{
+					sql:  `INSERT INTO "public"."users" ("id") VALUES ($1::text::integer)`,
+					args: []any{"1"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := &dmlBuilder{}
+			for _, rc := range tc.rowChanges {
+				builder.addRowChange(rc)
+			}
+			require.Equal(t, tc.wantQueries, builder.build())
+		})
+	}
+}
+
+func TestToText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+
+		wantText *string
+		wantErr  error
+	}{
+		{name: "nil", value: nil, wantText: nil},
+		{name: "string", value: "a", wantText: ptr("a")},
+		{name: "float", value: float64(1000000), wantText: ptr("1000000")},
+		{name: "json number", value: json.Number("12345678901234567890.123"), wantText: ptr("12345678901234567890.123")},
+		{name: "bool", value: true, wantText: ptr("true")},
+		{name: "map", value: map[string]any{"a": 1}, wantText: ptr(`{"a":1}`)},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			text, err := toText(tc.value)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantText, text)
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestValidateDataType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dataType string
+		wantErr  error
+	}{
+		{dataType: "integer"},
+		{dataType: "character varying(255)"},
+		{dataType: "numeric(10,2)"},
+		{dataType: "numeric(10, 2)"},
+		{dataType: "timestamp(3) with time zone"},
+		{dataType: "double precision"},
+		{dataType: "text[]"},
+		{dataType: "integer[][]"},
+		{dataType: "public.citext"},
+		{dataType: `"MyType"`},
+		{dataType: `public."My ""Quoted"" Type"[]`},
+		{dataType: "", wantErr: errInvalidDataType},
+		{dataType: "integer; DROP TABLE users", wantErr: errInvalidDataType},
+		{dataType: "text) --", wantErr: errInvalidDataType},
+		{dataType: `"unterminated`, wantErr: errInvalidDataType},
+		{dataType: `"a" "b"; --"`, wantErr: errInvalidDataType},
+		{dataType: "integer /* comment */", wantErr: errInvalidDataType},
+		{dataType: "varchar(abc)", wantErr: errInvalidDataType},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.dataType, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateDataType(tc.dataType)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+type msgBatch struct {
+	msgs       []*msg
+	positions  []wal.CommitPosition
+	totalBytes int
+}
+
+type msg struct {
+	// rowChange is set for insert, update and delete events
+	rowChange *rowChange
+	// queries is set for truncate and schema change events
+	queries      []*query
+	schemaChange bool
+	bytesSize    int
+	pos          wal.CommitPosition
+}
+
+func (m *msg) size() int {
+	return m.bytesSize
+}
+
+func (m *msg) isSchemaChange() bool {
+	return m.schemaChange
+}
+
+func (m *msg) isKeepAlive() bool {
+	return m.rowChange == nil && len(m.queries) == 0 && m.pos != ""
+}
+
+func (m *msgBatch) add(msg *msg) {
+	if msg == nil {
+		return
+	}
+
+	if msg.rowChange != nil || len(msg.queries) > 0 {
+		m.msgs = append(m.msgs, msg)
+		m.totalBytes += msg.size()
+	}
+	if msg.pos != "" {
+		m.positions = append(m.positions, msg.pos)
+	}
+}
+
+func (m *msgBatch) drain() *msgBatch {
+	batch := &msgBatch{
+		msgs:       m.msgs,
+		positions:  m.positions,
+		totalBytes: m.totalBytes,
+	}
+	m.msgs = []*msg{}
+	m.positions = []wal.CommitPosition{}
+	m.totalBytes = 0
+	return batch
+}
+
+func (m *msgBatch) size() int {
+	return len(m.msgs)
+}
+
+func (m *msgBatch) isEmpty() bool {
+	return len(m.msgs) == 0 && len(m.positions) == 0
+}
+
+func (m *msgBatch) hasSchemaChange() bool {
+	for _, msg := range m.msgs {
+		if msg.isSchemaChange() {
+			return true
+		}
+	}
+	return false
+}
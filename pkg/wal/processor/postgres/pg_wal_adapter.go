@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+type walAdapter interface {
+	walEventToMsg(*wal.Event) (*msg, error)
+}
+
+type adapter struct {
+	includeTable tableFilter
+	ddl          *ddlBuilder
+}
+
+var errMissingIdentity = errors.New("update or delete event without identity columns")
+
+func newAdapter(includeTables []string) *adapter {
+	includeTable := newTableFilter(includeTables)
+	return &adapter{
+		includeTable: includeTable,
+		ddl:          &ddlBuilder{includeTable: includeTable},
+	}
+}
+
+func (a *adapter) walEventToMsg(e *wal.Event) (*msg, error) {
+	if e.Data == nil {
+		return &msg{pos: e.CommitPosition}, nil
+	}
+
+	data := e.Data
+	switch {
+	case data.IsSchemaChange():
+		if data.SchemaChange == nil {
+			return &msg{pos: e.CommitPosition}, nil
+		}
+		queries, err := a.ddl.schemaChangeQueries(data.SchemaChange)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", data.SchemaChange.SchemaName, err)
+		}
+		return &msg{
+			queries:      queries,
+			schemaChange: len(queries) > 0,
+			bytesSize:    queriesSize(queries),
+			pos:          e.CommitPosition,
+		}, nil
+	case data.Schema == schemalog.SchemaName, !a.includeTable(data.Schema, data.Table):
+		// pgstream internal tables and tables not included are not replicated,
+		// but the position still needs to be checkpointed
+		return &msg{pos: e.CommitPosition}, nil
+	case data.Action == truncateAction:
+		truncate := truncateQuery(data.Schema, data.Table)
+		return &msg{
+			queries:   []*query{truncate},
+			bytesSize: len(truncate.sql),
+			pos:       e.CommitPosition,
+		}, nil
+	case data.Action == insertAction, data.Action == updateAction, data.Action == deleteAction:
+		rowChange, err := walDataToRowChange(data)
+		if err != nil {
+			return nil, err
+		}
+		return &msg{
+			rowChange: rowChange,
+			bytesSize: rowChange.size(),
+			pos:       e.CommitPosition,
+		}, nil
+	default:
+		return &msg{pos: e.CommitPosition}, nil
+	}
+}
+
+func walDataToRowChange(data *wal.Data) (*rowChange, error) {
+	rc := &rowChange{
+		schema: data.Schema,
+		table:  data.Table,
+		action: data.Action,
+	}
+
+	var err error
+	if rc.columns, err = toColumnValues(data.Columns); err != nil {
+		return nil, err
+	}
+	if rc.identity, err = toColumnValues(data.Identity); err != nil {
+		return nil, err
+	}
+
+	// the pgstream identity columns are only available when the event has
+	// been translated
+	for _, cols := range [][]wal.Column{data.Columns, data.Identity} {
+		for _, col := range cols {
+			if data.Metadata.IsIDColumn(col.ID) && !slices.Contains(rc.keyColumns, col.Name) {
+				rc.keyColumns = append(rc.keyColumns, col.Name)
+			}
+		}
+	}
+
+	if data.Action != insertAction && len(rc.identity) == 0 && len(rc.keyColumns) == 0 {
+		return nil, fmt.Errorf("%s.%s: %w", data.Schema, data.Table, errMissingIdentity)
+	}
+
+	// updates without identity can be applied as upserts as long as the key
+	// columns are known
+	if data.Action == updateAction && len(rc.identity) == 0 {
+		rc.identity = rc.keyColumnValues()
+	}
+
+	return rc, nil
+}
+
+func (rc *rowChange) keyColumnValues() []columnValue {
+	values := make([]columnValue, 0, len(rc.keyColumns))
+	for _, col := range rc.columns {
+		if slices.Contains(rc.keyColumns, col.name) {
+			values = append(values, col)
+		}
+	}
+	return values
+}
+
+func toColumnValues(cols []wal.Column) ([]columnValue, error) {
+	if len(cols) == 0 {
+		return nil, nil
+	}
+
+	values := make([]columnValue, 0, len(cols))
+	for _, col := range cols {
+		if col.Type != "" {
+			if err := validateDataType(col.Type); err != nil {
+				return nil, fmt.Errorf("column %s: %w", col.Name, err)
+			}
+		}
+		value, err := toText(col.Value)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		values = append(values, columnValue{
+			name:     col.Name,
+			dataType: col.Type,
+			value:    value,
+		})
+	}
+	return values, nil
+}
+
+// newTableFilter returns a filter for the `<schema>.<table>` patterns on
+// input. If no patterns are provided, all tables are included.
+func newTableFilter(patterns []string) tableFilter {
+	if len(patterns) == 0 {
+		return func(string, string) bool { return true }
+	}
+
+	return func(schema, table string) bool {
+		for _, pattern := range patterns {
+			schemaPattern, tablePattern, found := strings.Cut(pattern, ".")
+			if !found {
+				tablePattern = "*"
+			}
+			if match, _ := path.Match(schemaPattern, schema); !match {
+				continue
+			}
+			if match, _ := path.Match(tablePattern, table); match {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func queriesSize(queries []*query) int {
+	size := 0
+	for _, q := range queries {
+		size += len(q.sql)
+	}
+	return size
+}
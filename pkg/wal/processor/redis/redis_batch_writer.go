@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// BatchWriter is a wal processor that applies the wal events to redis, either
+// appending them to streams or invalidating cached keys. The commands for a
+// batch are sent in a single pipeline.
+type BatchWriter struct {
+	client  *goredis.Client
+	adapter walAdapter
+	logger  loglib.Logger
+
+	// queueBytesSema is used to limit the amount of memory used by the
+	// unbuffered msg channel, optimising the channel performance for variable
+	// size messages, while preventing the process from running oom
+	queueBytesSema synclib.WeightedSemaphore
+	msgChan        chan (*msg)
+
+	batchSize         int
+	batchSendInterval time.Duration
+
+	// checkpoint callback to mark what was safely stored
+	checkpoint checkpointer.Checkpoint
+}
+
+type Option func(*BatchWriter)
+
+// NewBatchWriter returns a processor of wal events that applies them to the
+// configured redis server.
+func NewBatchWriter(config *Config, opts ...Option) (*BatchWriter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	redisOpts, err := goredis.ParseURL(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing redis url: %w", err)
+	}
+
+	adapter, err := newAdapter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &BatchWriter{
+		client:            goredis.NewClient(redisOpts),
+		adapter:           adapter,
+		logger:            loglib.NewNoopLogger(),
+		batchSize:         config.batchSize(),
+		batchSendInterval: config.batchTime(),
+		msgChan:           make(chan *msg),
+		queueBytesSema:    synclib.NewWeightedSemaphore(config.maxQueueBytes()),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(w *BatchWriter) {
+		w.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "redis_batch_writer",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(w *BatchWriter) {
+		w.checkpoint = c
+	}
+}
+
+// ProcessWALEvent is called on every new message from the wal. The function
+// is responsible for converting the event into the relevant redis commands,
+// which will be sent as part of a batch.
+func (w *BatchWriter) ProcessWALEvent(ctx context.Context, event *wal.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Panic("[PANIC] Panic while processing replication event", loglib.Fields{
+				"wal_data":    event.Data,
+				"panic":       r,
+				"stack_trace": debug.Stack(),
+			})
+			err = fmt.Errorf("redis batch writer: %w: %v", processor.ErrPanic, r)
+		}
+	}()
+
+	redisMsg := &msg{
+		pos: event.CommitPosition,
+	}
+
+	if event.Data != nil {
+		cmds, err := w.adapter.walDataToCommands(event.Data)
+		switch {
+		case errors.Is(err, errTruncateNotApplied):
+			w.logger.Warn(err, "redis batch writer: cached keys for truncated table need to be invalidated manually", loglib.Fields{
+				"schema": event.Data.Schema,
+				"table":  event.Data.Table,
+			})
+		case errors.Is(err, errMissingKeyColumn):
+			// the event can't be mapped to a key, skip it so that it doesn't
+			// block the processing of the following events
+			w.logger.Error(err, "redis batch writer: skipping event", loglib.Fields{
+				"severity": "DATALOSS",
+				"schema":   event.Data.Schema,
+				"table":    event.Data.Table,
+				"lsn":      event.Data.LSN,
+			})
+		case err != nil:
+			return fmt.Errorf("converting wal event to redis commands: %w", err)
+		}
+		redisMsg.cmds = cmds
+		redisMsg.bytesSize = commandsSize(cmds)
+	}
+
+	// make sure we don't reach the queue memory limit before adding the new
+	// message to the channel. This will block until messages have been read
+	// from the channel and their size is released
+	msgSize := int64(redisMsg.size())
+	if !w.queueBytesSema.TryAcquire(msgSize) {
+		w.logger.Warn(nil, "redis batch writer: max queue bytes reached, processing blocked")
+		if err := w.queueBytesSema.Acquire(ctx, msgSize); err != nil {
+			return err
+		}
+	}
+	w.msgChan <- redisMsg
+
+	return nil
+}
+
+func (w *BatchWriter) Send(ctx context.Context) error {
+	// make sure we send the pipelines on a separate go routine to isolate the
+	// IO operations and minimise the wait time between batch sending while
+	// continuously building new batches.
+	batchChan := make(chan *msgBatch)
+	defer close(batchChan)
+	sendErrChan := make(chan error, 1)
+	go func() {
+		defer close(sendErrChan)
+		for batch := range batchChan {
+			// If the send fails, this goroutine returns an error over the error channel and shuts down.
+			err := w.sendBatch(ctx, batch)
+			w.queueBytesSema.Release(int64(batch.totalBytes))
+			if err != nil {
+				w.logger.Error(err, "redis batch writer")
+				sendErrChan <- err
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(w.batchSendInterval)
+	defer ticker.Stop()
+	msgBatch := &msgBatch{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sendErr := <-sendErrChan:
+			// if there's an error while sending the batch, return the error and
+			// stop sending batches
+			return sendErr
+		case <-ticker.C:
+			if !msgBatch.isEmpty() {
+				batchChan <- msgBatch.drain()
+			}
+		case msg := <-w.msgChan:
+			msgBatch.add(msg)
+			// trigger a send if we reached the configured batch size or if the
+			// event was a keep alive, so that it's checkpointed as soon as
+			// possible.
+			if msgBatch.size() >= w.batchSize || msg.isKeepAlive() {
+				batchChan <- msgBatch.drain()
+			}
+		}
+	}
+}
+
+func (w *BatchWriter) Name() string {
+	return "redis-batch-writer"
+}
+
+func (w *BatchWriter) Close() error {
+	close(w.msgChan)
+	return w.client.Close()
+}
+
+func (w *BatchWriter) sendBatch(ctx context.Context, batch *msgBatch) error {
+	if batch.isEmpty() {
+		return nil
+	}
+
+	if len(batch.cmds) > 0 {
+		pipe := w.client.Pipeline()
+		for _, cmd := range batch.cmds {
+			pipe.Do(ctx, cmd...)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("executing redis pipeline: %w", err)
+		}
+	}
+
+	// positions are only checkpointed once all the commands in the pipeline
+	// have succeeded
+	if w.checkpoint != nil && len(batch.positions) > 0 {
+		if err := w.checkpoint(ctx, batch.positions); err != nil {
+			return fmt.Errorf("checkpointing positions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func commandsSize(cmds []command) int {
+	size := 0
+	for _, cmd := range cmds {
+		for _, arg := range cmd {
+			if s, ok := arg.(string); ok {
+				size += len(s)
+			} else {
+				size += 8
+			}
+		}
+	}
+	return size
+}
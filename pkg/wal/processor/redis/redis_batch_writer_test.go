@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchWriter_sendBatch(t *testing.T) {
+	t.Parallel()
+
+	testCommitPos := wal.CommitPosition("0/17773B0")
+
+	tests := []struct {
+		name         string
+		config       Config
+		events       []*wal.Event
+		stopServer   bool
+		existingKeys map[string]string
+
+		wantKeys        map[string]string
+		wantStreams     map[string]int
+		wantCheckpoints []wal.CommitPosition
+		wantErr         bool
+	}{
+		{
+			name: "ok - stream mode with trimming",
+			config: Config{
+				Stream: StreamConfig{MaxLen: 1},
+			},
+			events: []*wal.Event{
+				{Data: &wal.Data{Action: "I", Schema: "public", Table: "users"}, CommitPosition: testCommitPos},
+				{Data: &wal.Data{Action: "U", Schema: "public", Table: "users"}, CommitPosition: testCommitPos},
+				{Data: &wal.Data{Action: "I", Schema: "public", Table: "teams"}, CommitPosition: testCommitPos},
+			},
+
+			wantStreams:     map[string]int{"pgstream:public.users": 1, "pgstream:public.teams": 1},
+			wantCheckpoints: []wal.CommitPosition{testCommitPos, testCommitPos, testCommitPos},
+		},
+		{
+			name: "ok - cache mode",
+			config: Config{
+				Mode: ModeCache,
+				Cache: CacheConfig{
+					KeyTemplates: map[string]string{"public.users": "user:{id}"},
+					Action:       CacheActionUpdate,
+				},
+			},
+			existingKeys: map[string]string{"user:1": "stale", "user:2": "stale"},
+			events: []*wal.Event{
+				{
+					Data: &wal.Data{
+						Action: "U", Schema: "public", Table: "users",
+						Columns: []wal.Column{{Name: "id", Value: float64(1)}, {Name: "name", Value: "alice"}},
+					},
+					CommitPosition: testCommitPos,
+				},
+				{
+					Data: &wal.Data{
+						Action: "D", Schema: "public", Table: "users",
+						Identity: []wal.Column{{Name: "id", Value: float64(2)}},
+					},
+					CommitPosition: testCommitPos,
+				},
+				{CommitPosition: testCommitPos},
+			},
+
+			wantKeys:        map[string]string{"user:1": `{"id":1,"name":"alice"}`},
+			wantCheckpoints: []wal.CommitPosition{testCommitPos, testCommitPos, testCommitPos},
+		},
+		{
+			name:   "error - pipeline failed",
+			config: Config{},
+			events: []*wal.Event{
+				{Data: &wal.Data{Action: "I", Schema: "public", Table: "users"}, CommitPosition: testCommitPos},
+			},
+			stopServer: true,
+
+			wantCheckpoints: []wal.CommitPosition{},
+			wantErr:         true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := miniredis.RunT(t)
+			for key, value := range tc.existingKeys {
+				require.NoError(t, server.Set(key, value))
+			}
+
+			checkpoints := []wal.CommitPosition{}
+			tc.config.URL = "redis://" + server.Addr()
+			writer, err := NewBatchWriter(&tc.config,
+				WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+					checkpoints = append(checkpoints, positions...)
+					return nil
+				}))
+			require.NoError(t, err)
+			defer writer.client.Close()
+
+			batch := &msgBatch{}
+			for _, event := range tc.events {
+				msg := &msg{pos: event.CommitPosition}
+				if event.Data != nil {
+					msg.cmds, err = writer.adapter.walDataToCommands(event.Data)
+					require.NoError(t, err)
+				}
+				batch.add(msg)
+			}
+
+			if tc.stopServer {
+				server.Close()
+			}
+
+			err = writer.sendBatch(context.Background(), batch)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantCheckpoints, checkpoints)
+
+			if tc.stopServer {
+				return
+			}
+			for key, value := range tc.wantKeys {
+				got, err := server.Get(key)
+				require.NoError(t, err)
+				require.Equal(t, value, got)
+			}
+			for key := range tc.existingKeys {
+				if _, found := tc.wantKeys[key]; !found {
+					require.False(t, server.Exists(key))
+				}
+			}
+			for key, length := range tc.wantStreams {
+				entries, err := server.Stream(key)
+				require.NoError(t, err)
+				require.Len(t, entries, length)
+			}
+		})
+	}
+}
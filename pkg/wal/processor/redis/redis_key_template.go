@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// keyTemplate builds cache keys from the `{column}` placeholders in the
+// template, replaced by the column values.
+type keyTemplate struct {
+	template string
+	columns  []string
+}
+
+var (
+	errMissingKeyColumn = errors.New("missing key column value")
+
+	placeholderRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+)
+
+func newKeyTemplate(template string) (*keyTemplate, error) {
+	matches := placeholderRegex.FindAllStringSubmatch(template, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("key template %q has no column placeholders", template)
+	}
+	columns := make([]string, 0, len(matches))
+	for _, match := range matches {
+		columns = append(columns, match[1])
+	}
+	return &keyTemplate{
+		template: template,
+		columns:  columns,
+	}, nil
+}
+
+// key returns the key for the column values on input. All the columns in the
+// template must have a non null value.
+func (t *keyTemplate) key(values map[string]any) (string, error) {
+	replacements := make([]string, 0, len(t.columns)*2)
+	for _, col := range t.columns {
+		value, found := values[col]
+		if !found || value == nil {
+			return "", fmt.Errorf("%w: %s", errMissingKeyColumn, col)
+		}
+		replacements = append(replacements, "{"+col+"}", toString(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(t.template), nil
+}
+
+func toString(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		// avoid the exponent format for large integer values
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
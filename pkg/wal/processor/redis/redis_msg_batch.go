@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// command is a redis command and its arguments (i.e, `DEL user:1`)
+type command []any
+
+type msg struct {
+	cmds      []command
+	bytesSize int
+	pos       wal.CommitPosition
+}
+
+type msgBatch struct {
+	cmds       []command
+	positions  []wal.CommitPosition
+	totalBytes int
+}
+
+func (m *msg) size() int {
+	return m.bytesSize
+}
+
+func (m *msg) isKeepAlive() bool {
+	return len(m.cmds) == 0 && m.pos != ""
+}
+
+func (m *msgBatch) add(msg *msg) {
+	if msg == nil {
+		return
+	}
+
+	if len(msg.cmds) > 0 {
+		m.cmds = append(m.cmds, msg.cmds...)
+		m.totalBytes += msg.size()
+	}
+	if msg.pos != "" {
+		m.positions = append(m.positions, msg.pos)
+	}
+}
+
+func (m *msgBatch) drain() *msgBatch {
+	batch := &msgBatch{
+		cmds:       m.cmds,
+		positions:  m.positions,
+		totalBytes: m.totalBytes,
+	}
+	m.cmds = []command{}
+	m.positions = []wal.CommitPosition{}
+	m.totalBytes = 0
+	return batch
+}
+
+func (m *msgBatch) size() int {
+	return len(m.cmds)
+}
+
+func (m *msgBatch) isEmpty() bool {
+	return len(m.cmds) == 0 && len(m.positions) == 0
+}
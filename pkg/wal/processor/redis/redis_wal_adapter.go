@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// walAdapter converts the wal event data into the redis commands to be
+// executed for it.
+type walAdapter interface {
+	walDataToCommands(data *wal.Data) ([]command, error)
+}
+
+var errTruncateNotApplied = errors.New("truncate can't be applied to cached keys")
+
+// streamAdapter appends the wal events to per table redis streams.
+type streamAdapter struct {
+	keyTemplate *template.Template
+	maxLen      int64
+	marshaler   func(any) ([]byte, error)
+}
+
+// cacheAdapter deletes or updates the cached keys for the wal event rows.
+type cacheAdapter struct {
+	keyTemplates map[string]*keyTemplate
+	action       string
+	ttlMillis    int64
+	marshaler    func(any) ([]byte, error)
+}
+
+func newAdapter(config *Config) (walAdapter, error) {
+	switch config.mode() {
+	case ModeCache:
+		return newCacheAdapter(&config.Cache)
+	default:
+		return newStreamAdapter(&config.Stream)
+	}
+}
+
+func newStreamAdapter(config *StreamConfig) (*streamAdapter, error) {
+	tmpl, err := template.New("stream_key").Option("missingkey=error").Parse(config.keyTemplate())
+	if err != nil {
+		return nil, fmt.Errorf("parsing stream key template: %w", err)
+	}
+	return &streamAdapter{
+		keyTemplate: tmpl,
+		maxLen:      config.maxLen(),
+		marshaler:   json.Marshal,
+	}, nil
+}
+
+func (a *streamAdapter) walDataToCommands(data *wal.Data) ([]command, error) {
+	key := strings.Builder{}
+	if err := a.keyTemplate.Execute(&key, struct{ Schema, Table string }{
+		Schema: data.Schema,
+		Table:  data.Table,
+	}); err != nil {
+		return nil, fmt.Errorf("executing stream key template: %w", err)
+	}
+
+	dataBytes, err := a.marshaler(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling event: %w", err)
+	}
+
+	cmd := command{"XADD", key.String()}
+	if a.maxLen > 0 {
+		// approximate trimming is much more efficient, since it only removes
+		// whole nodes of the stream
+		cmd = append(cmd, "MAXLEN", "~", a.maxLen)
+	}
+	cmd = append(cmd, "*", "action", data.Action, "lsn", data.LSN, "data", string(dataBytes))
+
+	return []command{cmd}, nil
+}
+
+func newCacheAdapter(config *CacheConfig) (*cacheAdapter, error) {
+	keyTemplates := make(map[string]*keyTemplate, len(config.KeyTemplates))
+	for table, tmpl := range config.KeyTemplates {
+		kt, err := newKeyTemplate(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", table, err)
+		}
+		keyTemplates[table] = kt
+	}
+	return &cacheAdapter{
+		keyTemplates: keyTemplates,
+		action:       config.action(),
+		ttlMillis:    config.TTL.Milliseconds(),
+		marshaler:    json.Marshal,
+	}, nil
+}
+
+func (a *cacheAdapter) walDataToCommands(data *wal.Data) ([]command, error) {
+	if data.Schema == schemalog.SchemaName {
+		return nil, nil
+	}
+	keyTemplate, found := a.keyTemplates[data.Schema+"."+data.Table]
+	if !found {
+		return nil, nil
+	}
+
+	switch data.Action {
+	case "I", "U":
+		newValues := columnValues(data.Columns)
+		newKey, err := keyTemplate.key(newValues)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds := []command{}
+		// if the key columns changed, the old key needs to be removed
+		if len(data.Identity) > 0 {
+			oldKey, err := keyTemplate.key(columnValues(data.Identity))
+			if err == nil && oldKey != newKey {
+				cmds = append(cmds, command{"DEL", oldKey})
+			}
+		}
+
+		if a.action == CacheActionDelete {
+			return append(cmds, command{"DEL", newKey}), nil
+		}
+
+		valueBytes, err := a.marshaler(newValues)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling row: %w", err)
+		}
+		cmd := command{"SET", newKey, string(valueBytes)}
+		if a.ttlMillis > 0 {
+			cmd = append(cmd, "PX", a.ttlMillis)
+		}
+		return append(cmds, cmd), nil
+
+	case "D":
+		key, err := keyTemplate.key(columnValues(data.Identity))
+		if err != nil {
+			return nil, err
+		}
+		return []command{{"DEL", key}}, nil
+
+	case "T":
+		// truncates can't be mapped to specific keys
+		return nil, errTruncateNotApplied
+	default:
+		// schema changes don't affect the cached rows
+		return nil, nil
+	}
+}
+
+func columnValues(columns []wal.Column) map[string]any {
+	values := make(map[string]any, len(columns))
+	for _, col := range columns {
+		values[col.Name] = col.Value
+	}
+	return values
+}
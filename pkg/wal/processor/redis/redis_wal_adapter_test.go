@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamAdapter_walDataToCommands(t *testing.T) {
+	t.Parallel()
+
+	testData := &wal.Data{
+		Action: "I",
+		LSN:    "0/17773B0",
+		Schema: "public",
+		Table:  "users",
+	}
+	mockMarshaler := func(any) ([]byte, error) { return []byte("{}"), nil }
+
+	tests := []struct {
+		name   string
+		config StreamConfig
+
+		wantCmds []command
+	}{
+		{
+			name:   "ok - default config",
+			config: StreamConfig{},
+
+			wantCmds: []command{
+				{"XADD", "pgstream:public.users", "MAXLEN", "~", int64(10000), "*", "action", "I", "lsn", "0/17773B0", "data", "{}"},
+			},
+		},
+		{
+			name: "ok - custom key without trimming",
+			config: StreamConfig{
+				KeyTemplate: "cdc:{{.Table}}",
+				MaxLen:      -1,
+			},
+
+			wantCmds: []command{
+				{"XADD", "cdc:users", "*", "action", "I", "lsn", "0/17773B0", "data", "{}"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			adapter, err := newStreamAdapter(&tc.config)
+			require.NoError(t, err)
+			adapter.marshaler = mockMarshaler
+
+			cmds, err := adapter.walDataToCommands(testData)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCmds, cmds)
+		})
+	}
+}
+
+func TestCacheAdapter_walDataToCommands(t *testing.T) {
+	t.Parallel()
+
+	testKeyTemplates := map[string]string{
+		"public.users": "user:{id}",
+		"public.teams": "team:{org_id}:{id}",
+	}
+
+	tests := []struct {
+		name   string
+		config CacheConfig
+		data   *wal.Data
+
+		wantCmds []command
+		wantErr  error
+	}{
+		{
+			name: "ok - insert with delete action",
+			data: &wal.Data{
+				Action: "I", Schema: "public", Table: "users",
+				Columns: []wal.Column{{Name: "id", Value: float64(1)}, {Name: "name", Value: "alice"}},
+			},
+
+			wantCmds: []command{{"DEL", "user:1"}},
+		},
+		{
+			name: "ok - update with update action and ttl",
+			config: CacheConfig{
+				Action: CacheActionUpdate,
+				TTL:    time.Minute,
+			},
+			data: &wal.Data{
+				Action: "U", Schema: "public", Table: "users",
+				Columns: []wal.Column{{Name: "id", Value: float64(1e7)}, {Name: "name", Value: "alice"}},
+			},
+
+			wantCmds: []command{{"SET", "user:10000000", `{"id":10000000,"name":"alice"}`, "PX", int64(60000)}},
+		},
+		{
+			name: "ok - update with key change",
+			data: &wal.Data{
+				Action: "U", Schema: "public", Table: "teams",
+				Columns:  []wal.Column{{Name: "id", Value: "a"}, {Name: "org_id", Value: float64(2)}},
+				Identity: []wal.Column{{Name: "id", Value: "a"}, {Name: "org_id", Value: float64(1)}},
+			},
+
+			wantCmds: []command{{"DEL", "team:1:a"}, {"DEL", "team:2:a"}},
+		},
+		{
+			name: "ok - delete",
+			config: CacheConfig{
+				Action: CacheActionUpdate,
+			},
+			data: &wal.Data{
+				Action: "D", Schema: "public", Table: "users",
+				Identity: []wal.Column{{Name: "id", Value: float64(1)}},
+			},
+
+			wantCmds: []command{{"DEL", "user:1"}},
+		},
+		{
+			name: "ok - table without key template",
+			data: &wal.Data{
+				Action: "I", Schema: "public", Table: "orders",
+				Columns: []wal.Column{{Name: "id", Value: float64(1)}},
+			},
+
+			wantCmds: nil,
+		},
+		{
+			name: "error - truncate",
+			data: &wal.Data{Action: "T", Schema: "public", Table: "users"},
+
+			wantErr: errTruncateNotApplied,
+		},
+		{
+			name: "error - missing key column",
+			data: &wal.Data{
+				Action: "D", Schema: "public", Table: "users",
+				Identity: []wal.Column{{Name: "email", Value: "a@b.com"}},
+			},
+
+			wantErr: errMissingKeyColumn,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.config.KeyTemplates = testKeyTemplates
+			adapter, err := newCacheAdapter(&tc.config)
+			require.NoError(t, err)
+
+			cmds, err := adapter.walDataToCommands(tc.data)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantCmds, cmds)
+		})
+	}
+}
+
+func TestNewKeyTemplate(t *testing.T) {
+	t.Parallel()
+
+	_, err := newKeyTemplate("user")
+	require.Error(t, err)
+
+	kt, err := newKeyTemplate("user:{id}")
+	require.NoError(t, err)
+	_, err = kt.key(map[string]any{"id": nil})
+	require.ErrorIs(t, err, errMissingKeyColumn)
+}
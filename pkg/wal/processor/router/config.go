@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+type Config struct {
+	// Routes are the CEL expression based rules that determine which
+	// processors receive the wal data events. An event is sent to the
+	// processors of all the routes it matches. If no routes are configured,
+	// all the events are sent to all the processors.
+	Routes []Route
+	// DefaultProcessors receive the wal data events that don't match any
+	// route. If empty, the events that don't match any route are dropped.
+	DefaultProcessors []string
+}
+
+type Route struct {
+	// Expression is the CEL filter expression the wal data events need to
+	// match to be sent to the route processors.
+	Expression string
+	// Processors are the names of the processors the matching events are
+	// sent to.
+	Processors []string
+}
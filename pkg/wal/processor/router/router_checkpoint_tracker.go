@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// checkpointTracker keeps track of the commit positions routed to each
+// target, so that a position is only checkpointed once all the targets it
+// was sent to have processed it, along with all the positions before it. It
+// is not safe for concurrent use.
+type checkpointTracker struct {
+	// positions contains the tracked positions in the order they were routed
+	positions []*trackedPosition
+	// targetPositions contains the ordered positions pending for each target
+	targetPositions map[string][]*trackedPosition
+}
+
+type trackedPosition struct {
+	pos     wal.CommitPosition
+	pending int
+}
+
+func newCheckpointTracker() *checkpointTracker {
+	return &checkpointTracker{
+		targetPositions: map[string][]*trackedPosition{},
+	}
+}
+
+// track adds the position on input as pending for the targets on input. It
+// returns the positions that are ready to be checkpointed, which will be the
+// case if the position has no targets and there are no positions pending
+// before it.
+func (t *checkpointTracker) track(pos wal.CommitPosition, targets []string) []wal.CommitPosition {
+	tracked := &trackedPosition{pos: pos, pending: len(targets)}
+	t.positions = append(t.positions, tracked)
+	for _, target := range targets {
+		t.targetPositions[target] = append(t.targetPositions[target], tracked)
+	}
+	return t.popDone()
+}
+
+// done marks the positions on input as processed by the target, along with
+// all the positions routed to the target before them, since the targets
+// process events in order. It returns the positions that are ready to be
+// checkpointed.
+func (t *checkpointTracker) done(target string, positions []wal.CommitPosition) []wal.CommitPosition {
+	for _, pos := range positions {
+		pending := t.targetPositions[target]
+		for i, tracked := range pending {
+			if tracked.pos != pos {
+				continue
+			}
+			for _, p := range pending[:i+1] {
+				p.pending--
+			}
+			t.targetPositions[target] = pending[i+1:]
+			break
+		}
+	}
+	return t.popDone()
+}
+
+func (t *checkpointTracker) popDone() []wal.CommitPosition {
+	var ready []wal.CommitPosition
+	i := 0
+	for ; i < len(t.positions) && t.positions[i].pending <= 0; i++ {
+		ready = append(ready, t.positions[i].pos)
+	}
+	t.positions = t.positions[i:]
+	return ready
+}
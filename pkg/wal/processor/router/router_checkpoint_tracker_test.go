@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointTracker(t *testing.T) {
+	t.Parallel()
+
+	type step struct {
+		track     wal.CommitPosition
+		targets   []string
+		doneBy    string
+		positions []wal.CommitPosition
+
+		wantReady []wal.CommitPosition
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "position checkpointed once all targets are done",
+			steps: []step{
+				{track: "1", targets: []string{"a", "b"}},
+				{doneBy: "a", positions: []wal.CommitPosition{"1"}},
+				{doneBy: "b", positions: []wal.CommitPosition{"1"}, wantReady: []wal.CommitPosition{"1"}},
+			},
+		},
+		{
+			name: "positions checkpointed in order",
+			steps: []step{
+				{track: "1", targets: []string{"a"}},
+				{track: "2", targets: []string{"b"}},
+				{doneBy: "b", positions: []wal.CommitPosition{"2"}},
+				{doneBy: "a", positions: []wal.CommitPosition{"1"}, wantReady: []wal.CommitPosition{"1", "2"}},
+			},
+		},
+		{
+			name: "position without targets",
+			steps: []step{
+				{track: "1", targets: nil, wantReady: []wal.CommitPosition{"1"}},
+				{track: "2", targets: []string{"a"}},
+				{track: "3", targets: nil},
+				{doneBy: "a", positions: []wal.CommitPosition{"2"}, wantReady: []wal.CommitPosition{"2", "3"}},
+			},
+		},
+		{
+			name: "later position marks previous target positions as done",
+			steps: []step{
+				{track: "1", targets: []string{"a"}},
+				{track: "2", targets: []string{"a", "b"}},
+				{doneBy: "b", positions: []wal.CommitPosition{"2"}},
+				{doneBy: "a", positions: []wal.CommitPosition{"2"}, wantReady: []wal.CommitPosition{"1", "2"}},
+			},
+		},
+		{
+			name: "unknown position",
+			steps: []step{
+				{track: "1", targets: []string{"a"}},
+				{doneBy: "a", positions: []wal.CommitPosition{"5"}},
+				{doneBy: "b", positions: []wal.CommitPosition{"1"}},
+				{doneBy: "a", positions: []wal.CommitPosition{"1"}, wantReady: []wal.CommitPosition{"1"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tracker := newCheckpointTracker()
+			for i, s := range tc.steps {
+				var ready []wal.CommitPosition
+				if s.track != "" {
+					ready = tracker.track(s.track, s.targets)
+				} else {
+					ready = tracker.done(s.doneBy, s.positions)
+				}
+				require.Equal(t, s.wantReady, ready, "step %d", i)
+			}
+		})
+	}
+}
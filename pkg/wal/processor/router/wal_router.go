@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
+)
+
+// Router is a processor that sends the wal events to one or more target
+// processors, based on the configured routing rules. The commit positions
+// are only checkpointed once all the targets they were routed to have
+// processed them.
+type Router struct {
+	logger         loglib.Logger
+	routes         []*route
+	defaultTargets []string
+	targets        map[string]processor.Processor
+	targetNames    []string
+
+	// optional checkpointer callback to mark what was safely processed
+	checkpointer checkpointer.Checkpoint
+
+	// mutex protects the checkpoint tracker and serialises the calls to the
+	// checkpointer, since targets checkpoint concurrently
+	mutex   sync.Mutex
+	tracker *checkpointTracker
+	// unpositioned contains the targets that received events without commit
+	// position since the last positioned event was routed
+	unpositioned map[string]struct{}
+}
+
+type route struct {
+	expression *filter.Expression
+	targets    []string
+}
+
+type Option func(r *Router)
+
+var (
+	ErrNoTargets      = errors.New("router requires at least one target processor")
+	ErrUnknownTarget  = errors.New("unknown target processor")
+	ErrRouteNoTargets = errors.New("route has no target processors")
+)
+
+// New returns a router for the configuration on input. The target processors
+// need to be set with SetTargets before processing any events.
+func New(cfg *Config, opts ...Option) (*Router, error) {
+	r := &Router{
+		logger:         loglib.NewNoopLogger(),
+		defaultTargets: cfg.DefaultProcessors,
+		tracker:        newCheckpointTracker(),
+		unpositioned:   map[string]struct{}{},
+	}
+
+	for i, routeCfg := range cfg.Routes {
+		if len(routeCfg.Processors) == 0 {
+			return nil, fmt.Errorf("route %d: %w", i, ErrRouteNoTargets)
+		}
+		expression, err := filter.Compile(routeCfg.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		r.routes = append(r.routes, &route{
+			expression: expression,
+			targets:    routeCfg.Processors,
+		})
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(r *Router) {
+		r.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "wal_router",
+		})
+	}
+}
+
+func WithCheckpoint(c checkpointer.Checkpoint) Option {
+	return func(r *Router) {
+		r.checkpointer = c
+	}
+}
+
+// SetTargets sets the processors the events are routed to, indexed by the
+// name used in the routing rules. It returns an error if the routing rules
+// reference a processor that's not part of the targets.
+func (r *Router) SetTargets(targets map[string]processor.Processor) error {
+	if len(targets) == 0 {
+		return ErrNoTargets
+	}
+
+	referenced := slices.Clone(r.defaultTargets)
+	for _, route := range r.routes {
+		referenced = append(referenced, route.targets...)
+	}
+	for _, name := range referenced {
+		if _, found := targets[name]; !found {
+			return fmt.Errorf("%w: %s", ErrUnknownTarget, name)
+		}
+	}
+
+	r.targets = targets
+	r.targetNames = make([]string, 0, len(targets))
+	for name := range targets {
+		r.targetNames = append(r.targetNames, name)
+	}
+	sort.Strings(r.targetNames)
+	return nil
+}
+
+// Checkpoint returns the checkpoint callback to be used by the target
+// processor on input.
+func (r *Router) Checkpoint(target string) checkpointer.Checkpoint {
+	return func(ctx context.Context, positions []wal.CommitPosition) error {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		return r.checkpoint(ctx, r.tracker.done(target, positions))
+	}
+}
+
+// ProcessWALEvent sends the wal event to the target processors it's routed
+// to. Schema log, schema change and keep alive events are sent to all the
+// targets.
+func (r *Router) ProcessWALEvent(ctx context.Context, event *wal.Event) error {
+	targets := r.routeTargets(event.Data)
+
+	if event.CommitPosition == "" {
+		for _, target := range targets {
+			r.unpositioned[target] = struct{}{}
+		}
+		return r.send(ctx, targets, event)
+	}
+
+	// the targets that received events without position since the last
+	// positioned event need to process this position too, to make sure those
+	// events are processed before it's checkpointed
+	keepAliveTargets := []string{}
+	for _, target := range r.targetNames {
+		if _, found := r.unpositioned[target]; found && !slices.Contains(targets, target) {
+			keepAliveTargets = append(keepAliveTargets, target)
+		}
+	}
+	clear(r.unpositioned)
+
+	// the position needs to be tracked before the event is sent, since the
+	// targets can checkpoint it as soon as they receive it
+	r.track(ctx, event.CommitPosition, append(slices.Clone(targets), keepAliveTargets...))
+
+	if err := r.send(ctx, targets, event); err != nil {
+		return err
+	}
+	return r.send(ctx, keepAliveTargets, &wal.Event{CommitPosition: event.CommitPosition})
+}
+
+func (r *Router) Name() string {
+	return "wal-router"
+}
+
+// routeTargets returns the names of the target processors the wal data on
+// input needs to be sent to.
+func (r *Router) routeTargets(data *wal.Data) []string {
+	if data == nil || data.IsSchemaChange() || processor.IsSchemaLogEvent(data) || len(r.routes) == 0 {
+		return r.targetNames
+	}
+
+	targets := []string{}
+	for _, route := range r.routes {
+		match, err := route.expression.Matches(data)
+		if err != nil {
+			r.logger.Debug("router: route expression evaluation failed", loglib.Fields{
+				"error":  err.Error(),
+				"schema": data.Schema,
+				"table":  data.Table,
+			})
+		}
+		if !match {
+			continue
+		}
+		for _, target := range route.targets {
+			if !slices.Contains(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		return r.defaultTargets
+	}
+	return targets
+}
+
+func (r *Router) send(ctx context.Context, targets []string, event *wal.Event) error {
+	for _, target := range targets {
+		if err := r.targets[target].ProcessWALEvent(ctx, event); err != nil {
+			return fmt.Errorf("router: target %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+func (r *Router) track(ctx context.Context, pos wal.CommitPosition, targets []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// positions not routed to any target can be ready to be checkpointed
+	// straight away
+	if err := r.checkpoint(ctx, r.tracker.track(pos, targets)); err != nil {
+		r.logger.Warn(err, "router: error updating commit position")
+	}
+}
+
+// checkpoint must be called with the mutex held.
+func (r *Router) checkpoint(ctx context.Context, positions []wal.CommitPosition) error {
+	if r.checkpointer == nil || len(positions) == 0 {
+		return nil
+	}
+	return r.checkpointer(ctx, positions)
+}
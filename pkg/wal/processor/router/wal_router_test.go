@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_ProcessWALEvent(t *testing.T) {
+	t.Parallel()
+
+	testPos := wal.CommitPosition("1/CF54A048")
+	testEvent := func(table string) *wal.Event {
+		return &wal.Event{
+			Data:           &wal.Data{Action: "I", Schema: "public", Table: table},
+			CommitPosition: testPos,
+		}
+	}
+	keepAlive := &wal.Event{CommitPosition: testPos}
+
+	testRoutes := &Config{
+		Routes: []Route{
+			{Expression: `table == "orders"`, Processors: []string{"a"}},
+			{Expression: `table.startsWith("order")`, Processors: []string{"a", "b"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		event  *wal.Event
+
+		wantEvents      map[string][]*wal.Event
+		wantCheckpoints []wal.CommitPosition
+	}{
+		{
+			name:   "no routes",
+			config: &Config{},
+			event:  testEvent("orders"),
+
+			wantEvents: map[string][]*wal.Event{
+				"a": {testEvent("orders")},
+				"b": {testEvent("orders")},
+				"c": {testEvent("orders")},
+			},
+		},
+		{
+			name:   "matching routes",
+			config: testRoutes,
+			event:  testEvent("orders"),
+
+			wantEvents: map[string][]*wal.Event{
+				"a": {testEvent("orders")},
+				"b": {testEvent("orders")},
+			},
+		},
+		{
+			name: "default processors",
+			config: &Config{
+				Routes:            testRoutes.Routes,
+				DefaultProcessors: []string{"c"},
+			},
+			event: testEvent("users"),
+
+			wantEvents: map[string][]*wal.Event{
+				"c": {testEvent("users")},
+			},
+		},
+		{
+			name:   "no matching route",
+			config: testRoutes,
+			event:  testEvent("users"),
+
+			wantEvents:      map[string][]*wal.Event{},
+			wantCheckpoints: []wal.CommitPosition{testPos},
+		},
+		{
+			name:   "keep alive",
+			config: testRoutes,
+			event:  keepAlive,
+
+			wantEvents: map[string][]*wal.Event{
+				"a": {keepAlive},
+				"b": {keepAlive},
+				"c": {keepAlive},
+			},
+		},
+		{
+			name:   "schema log event",
+			config: testRoutes,
+			event: &wal.Event{
+				Data: &wal.Data{Schema: schemalog.SchemaName, Table: schemalog.TableName},
+			},
+
+			wantEvents: map[string][]*wal.Event{
+				"a": {{Data: &wal.Data{Schema: schemalog.SchemaName, Table: schemalog.TableName}}},
+				"b": {{Data: &wal.Data{Schema: schemalog.SchemaName, Table: schemalog.TableName}}},
+				"c": {{Data: &wal.Data{Schema: schemalog.SchemaName, Table: schemalog.TableName}}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var checkpoints []wal.CommitPosition
+			r, err := New(tc.config, WithCheckpoint(func(_ context.Context, positions []wal.CommitPosition) error {
+				checkpoints = append(checkpoints, positions...)
+				return nil
+			}))
+			require.NoError(t, err)
+
+			gotEvents := map[string][]*wal.Event{}
+			targets := map[string]processor.Processor{}
+			for _, name := range []string{"a", "b", "c"} {
+				name := name
+				targets[name] = &mocks.Processor{
+					ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+						gotEvents[name] = append(gotEvents[name], walEvent)
+						return nil
+					},
+				}
+			}
+			require.NoError(t, r.SetTargets(targets))
+
+			err = r.ProcessWALEvent(context.Background(), tc.event)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantEvents, gotEvents)
+			require.Equal(t, tc.wantCheckpoints, checkpoints)
+		})
+	}
+}
+
+func TestRouter_Checkpoint(t *testing.T) {
+	t.Parallel()
+
+	r, err := New(&Config{
+		Routes: []Route{
+			{Expression: `table == "orders"`, Processors: []string{"a", "b"}},
+			{Expression: `table == "users"`, Processors: []string{"b"}},
+		},
+	})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var checkpoints []wal.CommitPosition
+	r.checkpointer = func(_ context.Context, positions []wal.CommitPosition) error {
+		mu.Lock()
+		defer mu.Unlock()
+		checkpoints = append(checkpoints, positions...)
+		return nil
+	}
+
+	noopProcessor := &mocks.Processor{
+		ProcessWALEventFn: func(context.Context, *wal.Event) error { return nil },
+	}
+	require.NoError(t, r.SetTargets(map[string]processor.Processor{
+		"a": noopProcessor,
+		"b": noopProcessor,
+	}))
+
+	ctx := context.Background()
+	require.NoError(t, r.ProcessWALEvent(ctx, &wal.Event{
+		Data:           &wal.Data{Schema: "public", Table: "orders"},
+		CommitPosition: "1",
+	}))
+	require.NoError(t, r.ProcessWALEvent(ctx, &wal.Event{
+		Data:           &wal.Data{Schema: "public", Table: "users"},
+		CommitPosition: "2",
+	}))
+
+	// b processes both events, but the first one is still pending on a
+	require.NoError(t, r.Checkpoint("b")(ctx, []wal.CommitPosition{"1", "2"}))
+	require.Empty(t, checkpoints)
+
+	require.NoError(t, r.Checkpoint("a")(ctx, []wal.CommitPosition{"1"}))
+	require.Equal(t, []wal.CommitPosition{"1", "2"}, checkpoints)
+}
+
+func TestRouter_unpositionedEvents(t *testing.T) {
+	t.Parallel()
+
+	r, err := New(&Config{
+		Routes: []Route{
+			{Expression: `table == "orders"`, Processors: []string{"a"}},
+			{Expression: `table == "users"`, Processors: []string{"b"}},
+		},
+	})
+	require.NoError(t, err)
+
+	gotEvents := map[string][]*wal.Event{}
+	targets := map[string]processor.Processor{}
+	for _, name := range []string{"a", "b"} {
+		name := name
+		targets[name] = &mocks.Processor{
+			ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+				gotEvents[name] = append(gotEvents[name], walEvent)
+				return nil
+			},
+		}
+	}
+	require.NoError(t, r.SetTargets(targets))
+
+	ctx := context.Background()
+	orders := &wal.Event{Data: &wal.Data{Schema: "public", Table: "orders"}}
+	users := &wal.Event{Data: &wal.Data{Schema: "public", Table: "users"}, CommitPosition: "1"}
+	require.NoError(t, r.ProcessWALEvent(ctx, orders))
+	require.NoError(t, r.ProcessWALEvent(ctx, users))
+
+	// the target that received the event without position needs to process
+	// the position of the event that followed it
+	require.Equal(t, map[string][]*wal.Event{
+		"a": {orders, {CommitPosition: "1"}},
+		"b": {users},
+	}, gotEvents)
+}
+
+func TestRouter_SetTargets(t *testing.T) {
+	t.Parallel()
+
+	noopProcessor := &mocks.Processor{}
+
+	tests := []struct {
+		name    string
+		config  *Config
+		targets map[string]processor.Processor
+
+		wantErr error
+	}{
+		{
+			name: "ok",
+			config: &Config{
+				Routes:            []Route{{Expression: `action == "I"`, Processors: []string{"a"}}},
+				DefaultProcessors: []string{"b"},
+			},
+			targets: map[string]processor.Processor{"a": noopProcessor, "b": noopProcessor},
+
+			wantErr: nil,
+		},
+		{
+			name:    "error - no targets",
+			config:  &Config{},
+			targets: map[string]processor.Processor{},
+
+			wantErr: ErrNoTargets,
+		},
+		{
+			name: "error - unknown route target",
+			config: &Config{
+				Routes: []Route{{Expression: `action == "I"`, Processors: []string{"c"}}},
+			},
+			targets: map[string]processor.Processor{"a": noopProcessor},
+
+			wantErr: ErrUnknownTarget,
+		},
+		{
+			name: "error - unknown default target",
+			config: &Config{
+				DefaultProcessors: []string{"c"},
+			},
+			targets: map[string]processor.Processor{"a": noopProcessor},
+
+			wantErr: ErrUnknownTarget,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := New(tc.config)
+			require.NoError(t, err)
+
+			err = r.SetTargets(tc.targets)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config *Config
+
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			config: &Config{
+				Routes: []Route{{Expression: `action == "I"`, Processors: []string{"a"}}},
+			},
+
+			wantErr: false,
+		},
+		{
+			name: "error - route without processors",
+			config: &Config{
+				Routes: []Route{{Expression: `action == "I"`}},
+			},
+
+			wantErr: true,
+		},
+		{
+			name: "error - invalid expression",
+			config: &Config{
+				Routes: []Route{{Expression: `action`, Processors: []string{"a"}}},
+			},
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tc.config)
+			require.Equal(t, tc.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
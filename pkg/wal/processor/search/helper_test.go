@@ -135,6 +135,7 @@ func withID(id string) testDocOption {
 func newTestDocument(opts ...testDocOption) *Document {
 	doc := &Document{
 		Schema:  testSchemaName,
+		Table:   testTableName,
 		ID:      fmt.Sprintf("%s_id-1", testTableID),
 		Version: 0,
 		Data: map[string]any{
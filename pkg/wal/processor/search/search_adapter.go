@@ -63,6 +63,12 @@ func (a *adapter) walEventToMsg(e *wal.Event) (*msg, error) {
 		}, nil
 	}
 
+	// schema changes are applied from the schema log events, the structured
+	// schema change events are not relevant for the search store
+	if e.Data.IsSchemaChange() {
+		return nil, nil
+	}
+
 	if e.Data.Metadata.IsEmpty() {
 		return nil, errMetadataMissing
 	}
@@ -153,6 +159,7 @@ func (a *adapter) walDataToDocument(data *wal.Data) (*Document, error) {
 					Name:       col.Name,
 					DataType:   col.Type,
 					PgstreamID: col.ID,
+					TypeInfo:   col.TypeInfo,
 				}, col.Value)
 				if err != nil {
 					// we do not map unsupported types
@@ -173,6 +180,7 @@ func (a *adapter) walDataToDocument(data *wal.Data) (*Document, error) {
 	}
 
 	doc.Schema = data.Schema
+	doc.Table = data.Table
 	return doc, nil
 }
 
@@ -198,6 +206,7 @@ func (a *adapter) parseColumns(columns []wal.Column, metadata wal.Metadata, lsnS
 				Name:       col.Name,
 				DataType:   col.Type,
 				PgstreamID: col.ID,
+				TypeInfo:   col.TypeInfo,
 			}, col.Value)
 			if err != nil {
 				// we do not map unsupported types
@@ -259,6 +268,8 @@ func (a *adapter) parseIDColumns(tableName string, idColumns []wal.Column, doc *
 			addToID(strconv.FormatInt(v, 10))
 		case float64:
 			addToID(strconv.FormatFloat(v, 'f', -1, 64))
+		case json.Number:
+			addToID(v.String())
 		case nil:
 			return errNilIDValue
 		default:
@@ -273,6 +284,7 @@ func (a *adapter) parseIDColumns(tableName string, idColumns []wal.Column, doc *
 				Name:       col.Name,
 				DataType:   col.Type,
 				PgstreamID: col.ID,
+				TypeInfo:   col.TypeInfo,
 			}, col.Value)
 			if err != nil {
 				return fmt.Errorf("mapping id column value: %w", err)
@@ -291,6 +303,15 @@ func (a *adapter) parseVersionColumn(version any) (int, error) {
 		return int(v), nil
 	case float64:
 		return roundFloat64(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("parsing version value: %w", err)
+		}
+		return roundFloat64(f), nil
 	case nil:
 		return 0, errNilVersionValue
 	default:
@@ -3,6 +3,7 @@
 package search
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -73,6 +74,20 @@ func TestAdapter_walEventToMsg(t *testing.T) {
 			wantMsg: nil,
 			wantErr: nil,
 		},
+		{
+			name: "ok - ddl event",
+			event: &wal.Event{
+				Data: &wal.Data{
+					Action:       wal.SchemaChangeAction,
+					Schema:       "test_schema",
+					SchemaChange: &wal.SchemaChange{SchemaName: "test_schema"},
+				},
+				CommitPosition: newTestCommitPosition(),
+			},
+
+			wantMsg: nil,
+			wantErr: nil,
+		},
 		{
 			name:      "ok - data event",
 			event:     newTestDataEvent("I"),
@@ -291,6 +306,7 @@ func TestAdapter_walDataToDocument(t *testing.T) {
 				ID:      fmt.Sprintf("%s_id-1", testTableID),
 				Version: 0,
 				Schema:  testSchema,
+				Table:   testTableName,
 				Data: map[string]any{
 					"col-3":  "a",
 					"col-4":  "very-long-value",
@@ -321,6 +337,7 @@ func TestAdapter_walDataToDocument(t *testing.T) {
 				ID:      fmt.Sprintf("%s_id-1", testTableID),
 				Version: 0,
 				Schema:  testSchema,
+				Table:   testTableName,
 				Data: map[string]any{
 					"col-3":  "a",
 					"_table": testTableID,
@@ -337,6 +354,7 @@ func TestAdapter_walDataToDocument(t *testing.T) {
 				ID:      fmt.Sprintf("%s_id-1", testTableID),
 				Version: 1,
 				Schema:  testSchema,
+				Table:   testTableName,
 				Data: map[string]any{
 					"col-3":  "a",
 					"_table": testTableID,
@@ -666,6 +684,14 @@ func TestAdapter_parseIDColumns(t *testing.T) {
 			wantDoc: newDoc(fmt.Sprintf("%s_1", testTable)),
 			wantErr: nil,
 		},
+		{
+			name: "ok - json number",
+			idColumns: []wal.Column{
+				{Name: "id-1", Value: json.Number("9007199254740993")},
+			},
+			wantDoc: newDoc(fmt.Sprintf("%s_9007199254740993", testTable)),
+			wantErr: nil,
+		},
 		{
 			name: "ok - multiple id columns",
 			idColumns: []wal.Column{
@@ -766,6 +792,18 @@ func TestAdapter_parseVersionColumn(t *testing.T) {
 			wantVersion: -1,
 			wantErr:     nil,
 		},
+		{
+			name:        "ok - json number",
+			version:     json.Number("9007199254740993"),
+			wantVersion: 9007199254740993,
+			wantErr:     nil,
+		},
+		{
+			name:        "ok - json number float",
+			version:     json.Number("1.5"),
+			wantVersion: 2,
+			wantErr:     nil,
+		},
 		{
 			name:        "error - nil",
 			version:     nil,
@@ -136,7 +136,7 @@ func (s *StoreRetrier) getRetriableDocs(failedDocs []DocumentError) ([]Document,
 	docsDropped := make([]DocumentError, 0, len(failedDocs))
 	for _, f := range failedDocs {
 		switch f.Severity {
-		case SeverityDataLoss:
+		case SeverityDataLoss, SeverityMappingConflict:
 			docsDropped = append(docsDropped, f)
 		case SeverityRetriable:
 			docsToRetry = append(docsToRetry, f.Document)
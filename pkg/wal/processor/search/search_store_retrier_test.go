@@ -104,6 +104,22 @@ func TestStoreRetrier_SendDocuments(t *testing.T) {
 			wantFailedDocs: failedDocs(SeverityDataLoss),
 			wantErr:        nil,
 		},
+		{
+			name: "ok - mapping conflict documents dropped",
+			store: &mockStore{
+				sendDocumentsFn: func(ctx context.Context, i uint, docs []Document) ([]DocumentError, error) {
+					switch i {
+					case 1:
+						require.Equal(t, testDocs, docs)
+						return failedDocs(SeverityMappingConflict), nil
+					default:
+						return nil, fmt.Errorf("sendDocumentsFn: unexpected call %d", i)
+					}
+				},
+			},
+			wantFailedDocs: failedDocs(SeverityMappingConflict),
+			wantErr:        nil,
+		},
 		{
 			name: "ok - some failed documents",
 			store: &mockStore{
@@ -26,6 +26,7 @@ type Mapper interface {
 type Document struct {
 	ID      string
 	Schema  string
+	Table   string
 	Data    map[string]any
 	Version int
 	Delete  bool
@@ -44,6 +45,9 @@ const (
 	SeverityDataLoss
 	SeverityIgnored
 	SeverityRetriable
+	// SeverityMappingConflict is used for documents that are not compatible
+	// with the existing index mapping. They are dropped.
+	SeverityMappingConflict
 )
 
 func (s *Severity) String() string {
@@ -59,6 +63,8 @@ func (s *Severity) String() string {
 		return "IGNORED"
 	case SeverityRetriable:
 		return "RETRIABLE"
+	case SeverityMappingConflict:
+		return "MAPPING_CONFLICT"
 	default:
 		return ""
 	}
@@ -99,14 +99,17 @@ func (a *adapter) bulkItemToSearchDocErr(item searchstore.BulkItem) search.Docum
 		doc.Document.Delete = true
 	}
 
-	doc.Severity = a.parseSeverity(item.Status, doc.Document.Delete)
+	doc.Severity = a.parseSeverity(item.Status, doc.Document.Delete, item.Error)
 
 	return doc
 }
 
-func (a *adapter) parseSeverity(status int, delete bool) search.Severity {
+func (a *adapter) parseSeverity(status int, delete bool, itemErr json.RawMessage) search.Severity {
 	switch status {
 	case 400: // 400 means that the document is invalid. We drop it.
+		if a.isMappingConflict(itemErr) {
+			return search.SeverityMappingConflict
+		}
 		return search.SeverityDataLoss
 	case 409: // ignore, likely event out of order
 		return search.SeverityIgnored
@@ -127,3 +130,24 @@ func (a *adapter) parseSeverity(status int, delete bool) search.Severity {
 		return search.SeverityDataLoss
 	}
 }
+
+// isMappingConflict returns true if the bulk item error on input is caused by
+// the document not being compatible with the index mapping (i.e. a json value
+// that doesn't match the configured json mapping mode).
+func (a *adapter) isMappingConflict(itemErr json.RawMessage) bool {
+	if len(itemErr) == 0 {
+		return false
+	}
+	var bulkErr struct {
+		Type string `json:"type"`
+	}
+	if err := a.unmarshaler(itemErr, &bulkErr); err != nil {
+		return false
+	}
+	switch bulkErr.Type {
+	case "mapper_parsing_exception", "document_parsing_exception", "strict_dynamic_mapping_exception":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultIndexTemplateName = "pgstream"
+	indexSettingsPrefix      = "index."
+)
+
+var errIndexTemplatePatternsMissing = errors.New("index template must define index_patterns")
+
+// indexSettings returns the settings to be used when creating the index for
+// the schema on input. The settings are resolved in order of precedence:
+//   - the schema settings (exact schema name match first, then patterns in
+//     lexicographical order)
+//   - the configured index settings
+//   - the index template settings
+//   - the search store default settings
+//
+// Settings provided by the index template are not included, so that the
+// template values are applied by the search store.
+func (s *Store) indexSettings(schemaName string) map[string]any {
+	settings := flattenIndexSettings(s.defaultIndexSettings)
+	for key := range s.indexTemplateSettings {
+		delete(settings, key)
+	}
+
+	mergeIndexSettings(settings, s.configIndexSettings)
+
+	patterns := make([]string, 0, len(s.schemaIndexSettings))
+	for pattern := range s.schemaIndexSettings {
+		if pattern != schemaName {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, schemaName); matched {
+			mergeIndexSettings(settings, s.schemaIndexSettings[pattern])
+		}
+	}
+	if schemaSettings, found := s.schemaIndexSettings[schemaName]; found {
+		mergeIndexSettings(settings, schemaSettings)
+	}
+
+	return settings
+}
+
+// ensureIndexTemplate puts the configured index template, if any. It is only
+// applied once per store, before the first schema index is created.
+func (s *Store) ensureIndexTemplate(ctx context.Context) error {
+	if s.indexTemplate == nil || s.indexTemplateApplied.Load() {
+		return nil
+	}
+
+	if err := s.client.PutIndexTemplate(ctx, s.indexTemplateName, s.indexTemplate); err != nil {
+		return fmt.Errorf("putting index template %s: %w", s.indexTemplateName, mapError(err))
+	}
+	s.indexTemplateApplied.Store(true)
+	return nil
+}
+
+// indexTemplateSettings returns the flattened settings defined in the index
+// template body, validating the template contains the index patterns it
+// applies to.
+func indexTemplateSettings(template map[string]any) (map[string]any, error) {
+	if len(template) == 0 {
+		return nil, nil
+	}
+	if patterns, found := template["index_patterns"]; !found || patterns == nil {
+		return nil, errIndexTemplatePatternsMissing
+	}
+
+	templateBody, _ := template["template"].(map[string]any)
+	settings, _ := templateBody["settings"].(map[string]any)
+	return flattenIndexSettings(settings), nil
+}
+
+// flattenIndexSettings returns the settings on input using the flat dotted
+// notation, with the `index.` prefix, so that settings provided as nested
+// objects or without the prefix can be compared and merged.
+func flattenIndexSettings(settings map[string]any) map[string]any {
+	flattened := make(map[string]any, len(settings))
+	var flatten func(prefix string, m map[string]any)
+	flatten = func(prefix string, m map[string]any) {
+		for key, value := range m {
+			if nested, ok := value.(map[string]any); ok {
+				flatten(prefix+key+".", nested)
+				continue
+			}
+			flattened[normaliseIndexSettingKey(prefix+key)] = value
+		}
+	}
+	flatten("", settings)
+	return flattened
+}
+
+func normaliseIndexSettingKey(key string) string {
+	if strings.HasPrefix(key, indexSettingsPrefix) {
+		return key
+	}
+	return indexSettingsPrefix + key
+}
+
+func mergeIndexSettings(dst, src map[string]any) {
+	for key, value := range flattenIndexSettings(src) {
+		dst[key] = value
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_indexSettings(t *testing.T) {
+	t.Parallel()
+
+	defaultSettings := map[string]any{
+		"number_of_shards":                 1,
+		"number_of_replicas":               1,
+		"index.mapping.total_fields.limit": 2000,
+	}
+
+	tests := []struct {
+		name                  string
+		schemaName            string
+		configIndexSettings   map[string]any
+		schemaIndexSettings   map[string]map[string]any
+		indexTemplateSettings map[string]any
+
+		wantSettings map[string]any
+	}{
+		{
+			name:       "ok - default settings",
+			schemaName: "public",
+
+			wantSettings: map[string]any{
+				"index.number_of_shards":           1,
+				"index.number_of_replicas":         1,
+				"index.mapping.total_fields.limit": 2000,
+			},
+		},
+		{
+			name:       "ok - config settings",
+			schemaName: "public",
+			configIndexSettings: map[string]any{
+				"number_of_replicas": 0,
+				"index": map[string]any{
+					"refresh_interval": "30s",
+				},
+			},
+
+			wantSettings: map[string]any{
+				"index.number_of_shards":           1,
+				"index.number_of_replicas":         0,
+				"index.mapping.total_fields.limit": 2000,
+				"index.refresh_interval":           "30s",
+			},
+		},
+		{
+			name:       "ok - schema settings with exact match and patterns",
+			schemaName: "tenant_large",
+			configIndexSettings: map[string]any{
+				"number_of_replicas": 0,
+			},
+			schemaIndexSettings: map[string]map[string]any{
+				"tenant_*":     {"number_of_shards": 3, "refresh_interval": "5s"},
+				"tenant_large": {"number_of_shards": 10},
+				"other":        {"number_of_shards": 2},
+			},
+
+			wantSettings: map[string]any{
+				"index.number_of_shards":           10,
+				"index.number_of_replicas":         0,
+				"index.mapping.total_fields.limit": 2000,
+				"index.refresh_interval":           "5s",
+			},
+		},
+		{
+			name:       "ok - index template settings take precedence over defaults",
+			schemaName: "public",
+			configIndexSettings: map[string]any{
+				"number_of_replicas": 0,
+			},
+			indexTemplateSettings: map[string]any{
+				"index.number_of_shards":   5,
+				"index.number_of_replicas": 2,
+			},
+
+			wantSettings: map[string]any{
+				"index.number_of_replicas":         0,
+				"index.mapping.total_fields.limit": 2000,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Store{
+				defaultIndexSettings:  defaultSettings,
+				configIndexSettings:   tc.configIndexSettings,
+				schemaIndexSettings:   tc.schemaIndexSettings,
+				indexTemplateSettings: tc.indexTemplateSettings,
+			}
+
+			require.Equal(t, tc.wantSettings, s.indexSettings(tc.schemaName))
+		})
+	}
+}
+
+func Test_indexTemplateSettings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template map[string]any
+
+		wantSettings map[string]any
+		wantErr      error
+	}{
+		{
+			name:     "ok - no template",
+			template: nil,
+
+			wantSettings: nil,
+			wantErr:      nil,
+		},
+		{
+			name: "ok - template with settings",
+			template: map[string]any{
+				"index_patterns": []any{"tenant_*"},
+				"template": map[string]any{
+					"settings": map[string]any{
+						"number_of_shards": 3,
+						"analysis": map[string]any{
+							"analyzer": map[string]any{
+								"default": map[string]any{"type": "english"},
+							},
+						},
+					},
+				},
+			},
+
+			wantSettings: map[string]any{
+				"index.number_of_shards":               3,
+				"index.analysis.analyzer.default.type": "english",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error - missing index patterns",
+			template: map[string]any{
+				"template": map[string]any{},
+			},
+
+			wantSettings: nil,
+			wantErr:      errIndexTemplatePatternsMissing,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			settings, err := indexTemplateSettings(tc.template)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantSettings, settings)
+		})
+	}
+}
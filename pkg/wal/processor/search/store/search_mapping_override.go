@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+)
+
+// columnCommentSection represents the pgstream section of a JSON column
+// comment, used to configure the search mapping of the column. Example:
+//
+//	COMMENT ON COLUMN users.email IS '{"pgstream": {"search_mapping": {"type": "keyword"}}}';
+type columnCommentSection struct {
+	SearchMapping map[string]any `json:"search_mapping"`
+}
+
+const columnCommentSectionKey = "pgstream"
+
+// mappingOverrideKey returns the key used to identify a column in the mapping
+// overrides config (`<schema>.<table>.<column>`).
+func mappingOverrideKey(schemaName, tableName, columnName string) string {
+	return fmt.Sprintf("%s.%s.%s", schemaName, tableName, columnName)
+}
+
+// parseColumnCommentMapping returns the search mapping defined in the pgstream
+// section of the column comment. Comments that are not JSON objects or that
+// don't have a pgstream section are ignored.
+func parseColumnCommentMapping(column schemalog.Column) (map[string]any, error) {
+	if column.Metadata == nil {
+		return nil, nil
+	}
+
+	comment := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(*column.Metadata), &comment); err != nil {
+		return nil, nil
+	}
+
+	rawSection, found := comment[columnCommentSectionKey]
+	if !found {
+		return nil, nil
+	}
+
+	var section columnCommentSection
+	if err := json.Unmarshal(rawSection, &section); err != nil {
+		return nil, fmt.Errorf("invalid pgstream section in column comment: %w", err)
+	}
+	return section.SearchMapping, nil
+}
+
+// applyMappingOverride returns a copy of the mapping on input with the override
+// keys replacing the existing ones.
+func applyMappingOverride(mapping, override map[string]any) map[string]any {
+	if len(override) == 0 {
+		return mapping
+	}
+	merged := make(map[string]any, len(mapping)+len(override))
+	maps.Copy(merged, mapping)
+	maps.Copy(merged, override)
+	return merged
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColumnCommentMapping(t *testing.T) {
+	t.Parallel()
+
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		comment *string
+
+		wantMapping map[string]any
+		wantErr     bool
+	}{
+		{
+			name:    "ok - no comment",
+			comment: nil,
+
+			wantMapping: nil,
+		},
+		{
+			name:    "ok - plain text comment",
+			comment: strPtr("the user email"),
+
+			wantMapping: nil,
+		},
+		{
+			name:    "ok - json comment without pgstream section",
+			comment: strPtr(`{"owner": "analytics"}`),
+
+			wantMapping: nil,
+		},
+		{
+			name:    "ok - json comment with pgstream section",
+			comment: strPtr(`{"owner": "analytics", "pgstream": {"search_mapping": {"type": "text", "analyzer": "english", "fields": {"raw": {"type": "keyword"}}}}}`),
+
+			wantMapping: map[string]any{
+				"type":     "text",
+				"analyzer": "english",
+				"fields": map[string]any{
+					"raw": map[string]any{"type": "keyword"},
+				},
+			},
+		},
+		{
+			name:    "error - invalid pgstream section",
+			comment: strPtr(`{"pgstream": {"search_mapping": "keyword"}}`),
+
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mapping, err := parseColumnCommentMapping(schemalog.Column{Name: "col-1", Metadata: tc.comment})
+			require.Equal(t, tc.wantErr, err != nil)
+			require.Equal(t, tc.wantMapping, mapping)
+		})
+	}
+}
+
+func TestApplyMappingOverride(t *testing.T) {
+	t.Parallel()
+
+	mapping := map[string]any{"type": "text"}
+
+	require.Equal(t, mapping, applyMappingOverride(mapping, nil))
+	require.Equal(t, map[string]any{"type": "keyword", "index": false}, applyMappingOverride(mapping, map[string]any{"type": "keyword", "index": false}))
+	// the original mapping is not modified
+	require.Equal(t, map[string]any{"type": "text"}, mapping)
+}
@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// WKB geometry types, as defined by the OGC Simple Features specification.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// EWKB flags used by PostGIS to encode the dimensions and SRID of the geometry
+// in the type field.
+const (
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+	ewkbSRIDFlag = 0x20000000
+)
+
+var errInvalidWKB = errors.New("invalid WKB geometry")
+
+// isPostGISType returns true if the type name on input is a PostGIS geometry
+// or geography type. The type name can include the schema the PostGIS
+// extension was installed in.
+func isPostGISType(typeName string) bool {
+	switch unqualifiedTypeName(typeName) {
+	case "geometry", "geography":
+		return true
+	default:
+		return false
+	}
+}
+
+// isPostGISPointType returns true if the PostGIS type on input has a point type
+// modifier, i.e. `geometry(Point,4326)` or `geography(PointZ)`.
+func isPostGISPointType(pgTypeName string) bool {
+	openingBracketIndex := strings.Index(pgTypeName, "(")
+	if openingBracketIndex == -1 {
+		return false
+	}
+	typmod := strings.TrimSuffix(pgTypeName[openingBracketIndex+1:], ")")
+	geometryType, _, _ := strings.Cut(typmod, ",")
+	geometryType = strings.ToLower(strings.TrimSpace(geometryType))
+	return strings.TrimRight(geometryType, "zm") == "point"
+}
+
+func unqualifiedTypeName(typeName string) string {
+	if i := strings.LastIndex(typeName, "."); i != -1 {
+		return typeName[i+1:]
+	}
+	return typeName
+}
+
+// wkbHexToGeoJSON decodes the hex encoded WKB/EWKB geometry on input into its
+// GeoJSON representation. The SRID is ignored, since GeoJSON coordinates are
+// expected to be WGS84 longitude/latitude.
+func wkbHexToGeoJSON(hexWKB string) (map[string]any, error) {
+	wkb, err := hex.DecodeString(hexWKB)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidWKB, err)
+	}
+
+	r := &wkbReader{buf: wkb}
+	geometry, err := r.readGeometry()
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(r.buf) {
+		return nil, fmt.Errorf("%w: unexpected trailing bytes", errInvalidWKB)
+	}
+	return geometry, nil
+}
+
+type wkbReader struct {
+	buf   []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) readGeometry() (map[string]any, error) {
+	byteOrder, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+	switch byteOrder {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("%w: unknown byte order %d", errInvalidWKB, byteOrder)
+	}
+
+	rawType, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+
+	hasZ := rawType&ewkbZFlag != 0
+	hasM := rawType&ewkbMFlag != 0
+	if rawType&ewkbSRIDFlag != 0 {
+		if _, err := r.readUint32(); err != nil {
+			return nil, err
+		}
+	}
+
+	geometryType := rawType &^ (ewkbZFlag | ewkbMFlag | ewkbSRIDFlag)
+	// ISO WKB encodes the dimensions by adding 1000 (Z), 2000 (M) or 3000 (ZM)
+	// to the geometry type
+	switch geometryType / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	geometryType %= 1000
+
+	dims := 2
+	if hasZ {
+		dims++
+	}
+	if hasM {
+		dims++
+	}
+
+	switch geometryType {
+	case wkbPoint:
+		point, err := r.readPoint(dims, hasZ)
+		if err != nil {
+			return nil, err
+		}
+		if point == nil {
+			// empty points are encoded with NaN coordinates
+			return nil, nil
+		}
+		return geoJSON("Point", point), nil
+	case wkbLineString:
+		line, err := r.readPoints(dims, hasZ)
+		if err != nil {
+			return nil, err
+		}
+		return geoJSON("LineString", line), nil
+	case wkbPolygon:
+		polygon, err := r.readPolygon(dims, hasZ)
+		if err != nil {
+			return nil, err
+		}
+		return geoJSON("Polygon", polygon), nil
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon:
+		geometries, err := r.readGeometries()
+		if err != nil {
+			return nil, err
+		}
+		coordinates := make([]any, 0, len(geometries))
+		for _, g := range geometries {
+			if g == nil {
+				continue
+			}
+			coordinates = append(coordinates, g["coordinates"])
+		}
+		return geoJSON(multiGeometryName(geometryType), coordinates), nil
+	case wkbGeometryCollection:
+		geometries, err := r.readGeometries()
+		if err != nil {
+			return nil, err
+		}
+		collection := make([]any, 0, len(geometries))
+		for _, g := range geometries {
+			if g == nil {
+				continue
+			}
+			collection = append(collection, g)
+		}
+		return map[string]any{
+			"type":       "GeometryCollection",
+			"geometries": collection,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported geometry type %d", errInvalidWKB, geometryType)
+	}
+}
+
+func (r *wkbReader) readGeometries() ([]map[string]any, error) {
+	n, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	geometries := make([]map[string]any, 0, min(int(n), len(r.buf)))
+	for i := uint32(0); i < n; i++ {
+		// each of the geometries has its own byte order
+		g, err := r.readGeometry()
+		if err != nil {
+			return nil, err
+		}
+		geometries = append(geometries, g)
+	}
+	return geometries, nil
+}
+
+func (r *wkbReader) readPolygon(dims int, hasZ bool) ([]any, error) {
+	n, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	rings := make([]any, 0, min(int(n), len(r.buf)))
+	for i := uint32(0); i < n; i++ {
+		ring, err := r.readPoints(dims, hasZ)
+		if err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+	}
+	return rings, nil
+}
+
+func (r *wkbReader) readPoints(dims int, hasZ bool) ([]any, error) {
+	n, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]any, 0, min(int(n), len(r.buf)))
+	for i := uint32(0); i < n; i++ {
+		point, err := r.readPoint(dims, hasZ)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// readPoint returns the x, y (and z if present) coordinates of the point. The
+// M coordinate is not supported by GeoJSON and is discarded.
+func (r *wkbReader) readPoint(dims int, hasZ bool) ([]float64, error) {
+	coordinates := make([]float64, dims)
+	for i := range coordinates {
+		bits, err := r.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		coordinates[i] = math.Float64frombits(bits)
+	}
+	if math.IsNaN(coordinates[0]) && math.IsNaN(coordinates[1]) {
+		return nil, nil
+	}
+	if hasZ {
+		return coordinates[:3], nil
+	}
+	return coordinates[:2], nil
+}
+
+func (r *wkbReader) readByte() (byte, error) {
+	if r.pos+1 > len(r.buf) {
+		return 0, fmt.Errorf("%w: unexpected end of input", errInvalidWKB)
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *wkbReader) readUint32() (uint32, error) {
+	if r.pos+4 > len(r.buf) {
+		return 0, fmt.Errorf("%w: unexpected end of input", errInvalidWKB)
+	}
+	v := r.order.Uint32(r.buf[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *wkbReader) readUint64() (uint64, error) {
+	if r.pos+8 > len(r.buf) {
+		return 0, fmt.Errorf("%w: unexpected end of input", errInvalidWKB)
+	}
+	v := r.order.Uint64(r.buf[r.pos:])
+	r.pos += 8
+	return v, nil
+}
+
+func geoJSON(geometryType string, coordinates any) map[string]any {
+	return map[string]any{
+		"type":        geometryType,
+		"coordinates": coordinates,
+	}
+}
+
+func multiGeometryName(geometryType uint32) string {
+	switch geometryType {
+	case wkbMultiPoint:
+		return "MultiPoint"
+	case wkbMultiLineString:
+		return "MultiLineString"
+	default:
+		return "MultiPolygon"
+	}
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWKBHexToGeoJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		hexWKB string
+
+		wantGeoJSON map[string]any
+		wantErr     error
+	}{
+		{
+			name:   "ok - EWKB point with SRID",
+			hexWKB: "0101000020E6100000000000000000F03F0000000000000040",
+
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+		},
+		{
+			name:   "ok - big endian WKB point",
+			hexWKB: "00000000013FF00000000000004000000000000000",
+
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+		},
+		{
+			name:   "ok - ISO WKB point Z",
+			hexWKB: "01E9030000000000000000F03F00000000000000400000000000000840",
+
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{1, 2, 3}},
+		},
+		{
+			name:   "ok - EWKB point ZM",
+			hexWKB: "01010000C0000000000000F03F000000000000004000000000000008400000000000001040",
+
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{1, 2, 3}},
+		},
+		{
+			name:   "ok - empty point",
+			hexWKB: "0101000000000000000000F87F000000000000F87F",
+
+			wantGeoJSON: nil,
+		},
+		{
+			name:   "ok - line string",
+			hexWKB: "01020000000200000000000000000000000000000000000000000000000000F03F000000000000F03F",
+
+			wantGeoJSON: map[string]any{
+				"type":        "LineString",
+				"coordinates": []any{[]float64{0, 0}, []float64{1, 1}},
+			},
+		},
+		{
+			name:   "ok - polygon",
+			hexWKB: "0103000000010000000400000000000000000000000000000000000000000000000000F03F0000000000000000000000000000F03F000000000000F03F00000000000000000000000000000000",
+
+			wantGeoJSON: map[string]any{
+				"type": "Polygon",
+				"coordinates": []any{
+					[]any{[]float64{0, 0}, []float64{1, 0}, []float64{1, 1}, []float64{0, 0}},
+				},
+			},
+		},
+		{
+			name:   "ok - multi point",
+			hexWKB: "0104000000020000000101000000000000000000F03F0000000000000040010100000000000000000008400000000000001040",
+
+			wantGeoJSON: map[string]any{
+				"type":        "MultiPoint",
+				"coordinates": []any{[]float64{1, 2}, []float64{3, 4}},
+			},
+		},
+		{
+			name:   "ok - geometry collection",
+			hexWKB: "0107000000020000000101000000000000000000F03F000000000000004001020000000200000000000000000000000000000000000000000000000000F03F000000000000F03F",
+
+			wantGeoJSON: map[string]any{
+				"type": "GeometryCollection",
+				"geometries": []any{
+					map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+					map[string]any{"type": "LineString", "coordinates": []any{[]float64{0, 0}, []float64{1, 1}}},
+				},
+			},
+		},
+		{
+			name:   "error - invalid hex",
+			hexWKB: "not-hex",
+
+			wantErr: errInvalidWKB,
+		},
+		{
+			name:   "error - truncated geometry",
+			hexWKB: "0101000020E6100000000000000000F03F",
+
+			wantErr: errInvalidWKB,
+		},
+		{
+			name:   "error - trailing bytes",
+			hexWKB: "00000000013FF0000000000000400000000000000000",
+
+			wantErr: errInvalidWKB,
+		},
+		{
+			name:   "error - unsupported geometry type",
+			hexWKB: "0111000000",
+
+			wantErr: errInvalidWKB,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			geoJSON, err := wkbHexToGeoJSON(tc.hexWKB)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantGeoJSON, geoJSON)
+		})
+	}
+}
+
+func TestIsPostGISPointType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pgType string
+		want   bool
+	}{
+		{pgType: "geometry", want: false},
+		{pgType: "geometry(Point,4326)", want: true},
+		{pgType: "geography(PointZ,4326)", want: true},
+		{pgType: "public.geometry(POINTZM)", want: true},
+		{pgType: "geometry(MultiPoint,4326)", want: false},
+		{pgType: "geometry(Polygon,4326)", want: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.pgType, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, isPostGISPointType(tc.pgType))
+		})
+	}
+}
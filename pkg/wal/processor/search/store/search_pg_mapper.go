@@ -4,6 +4,7 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -19,8 +20,11 @@ import (
 type PgMapper struct {
 	searchMapper searchstore.Mapper
 	pgTypeMap    *pgtype.Map
+	jsonMode     searchstore.JSONMode
 }
 
+type PgMapperOption func(*PgMapper)
+
 const (
 	// Default date_time pattern
 	timestampTZFormat = "2006-01-02T15:04:05.000Z"
@@ -30,15 +34,30 @@ const (
 
 // NewPostgresMapper returns a mapper that maps between postgres and search
 // store types
-func NewPostgresMapper(mapper searchstore.Mapper) *PgMapper {
-	return &PgMapper{
+func NewPostgresMapper(mapper searchstore.Mapper, opts ...PgMapperOption) *PgMapper {
+	m := &PgMapper{
 		searchMapper: mapper,
 		pgTypeMap:    pgtype.NewMap(),
+		jsonMode:     searchstore.JSONModeText,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// WithJSONMode sets the mapping mode for json/jsonb columns. Defaults to text.
+func WithJSONMode(mode searchstore.JSONMode) PgMapperOption {
+	return func(m *PgMapper) {
+		m.jsonMode = mode
 	}
 }
 
 // ColumnToSearchMapping maps the column on input into the equivalent search mapping
 func (m *PgMapper) ColumnToSearchMapping(column schemalog.Column) (map[string]any, error) {
+	column = domainBaseColumn(column)
 	searchField, err := m.columnToSearchField(column)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse pg type (%s): %w", column.DataType, err)
@@ -50,9 +69,12 @@ func (m *PgMapper) ColumnToSearchMapping(column schemalog.Column) (map[string]an
 // MapColumnValue maps a value emitted from PG into a value that the search
 // store can handle. If the column is a timestamp: we need to parse it. If the
 // column is an array of any type except json, we need to map it to a Go slice.
-// If column type is unknown we return nil. This avoids dropping the whole
-// record if one field type is unknown.
+// If the column is json and the json mode is not text, the value is parsed.
+// Ranges and composite types are parsed into objects. If column type is
+// unknown we return nil. This avoids dropping the whole record if one field
+// type is unknown.
 func (m *PgMapper) MapColumnValue(column schemalog.Column, value any) (any, error) {
+	column = domainBaseColumn(column)
 	searchField, err := m.columnToSearchField(column)
 	if err != nil {
 		return nil, fmt.Errorf("mapping column from pg to search store: %w", err)
@@ -87,6 +109,33 @@ func (m *PgMapper) MapColumnValue(column schemalog.Column, value any) (any, erro
 			return nil, fmt.Errorf("vector value is not array: %w", err)
 		}
 		return array, nil
+	case searchstore.JSONType:
+		if searchField.Metadata.JSONMode == searchstore.JSONModeText {
+			return value, nil
+		}
+		if searchField.IsArray {
+			return m.mapJSONArray(value)
+		}
+		return m.mapJSON(value)
+	case searchstore.GeoPointType, searchstore.GeoShapeType:
+		if searchField.IsArray {
+			return m.mapGeometryArray(value)
+		}
+		return m.mapGeometry(value)
+	case searchstore.RangeType:
+		if searchField.IsArray {
+			return m.mapArray(value, func(v string) (any, error) {
+				return m.mapRange(searchField.Metadata.RangeSubtype, v)
+			})
+		}
+		return m.mapRange(searchField.Metadata.RangeSubtype, value)
+	case searchstore.ObjectType:
+		if searchField.IsArray {
+			return m.mapArray(value, func(v string) (any, error) {
+				return m.mapComposite(column.TypeInfo.Fields, v)
+			})
+		}
+		return m.mapComposite(column.TypeInfo.Fields, value)
 	default:
 		if searchField.IsArray { // catches all other array types
 			// handle arrays
@@ -103,12 +152,10 @@ func (m *PgMapper) MapColumnValue(column schemalog.Column, value any) (any, erro
 				var a pgtype.FlatArray[bool]
 				err := m.pgTypeMap.SQLScanner(&a).Scan(value)
 				return []bool(a), err
-			case searchstore.StringType:
+			case searchstore.StringType, searchstore.KeywordType:
 				var a pgtype.FlatArray[string]
 				err := m.pgTypeMap.SQLScanner(&a).Scan(value)
 				return []string(a), err
-			case searchstore.JSONType:
-				// nothing to do for json array types
 			default:
 				// should never get here
 				panic(fmt.Sprintf("indexer: unexpected array type: %v", searchField.SearchType))
@@ -127,6 +174,10 @@ func (m *PgMapper) columnToSearchField(column schemalog.Column) (*searchstore.Fi
 		return nil, fmt.Errorf("pg to search type: failed to parse pg type: %w", err)
 	}
 
+	if column.TypeInfo != nil {
+		return m.userDefinedTypeToSearchField(column, isArray)
+	}
+
 	metadata := searchstore.Metadata{}
 
 	var searchType searchstore.Type
@@ -141,6 +192,7 @@ func (m *PgMapper) columnToSearchField(column schemalog.Column) (*searchstore.Fi
 		searchType = searchstore.StringType
 	case "jsonb", "json":
 		searchType = searchstore.JSONType
+		metadata.JSONMode = m.jsonMode
 	case "date":
 		searchType = searchstore.DateType
 	case "time", "time with time zone", "time without time zone":
@@ -151,13 +203,21 @@ func (m *PgMapper) columnToSearchField(column schemalog.Column) (*searchstore.Fi
 		searchType = searchstore.DateTimeTZType
 	default:
 		// pgvector includes the schema (sometimes? seems only a problem when testing locally)
-		if isPGVector(typeName) {
+		switch {
+		case isPGVector(typeName):
 			searchType = searchstore.PGVectorType
 			metadata.VectorDimension, err = getPGVectorDimension(typeName)
 			if err != nil {
 				return nil, search.ErrTypeInvalid{Input: pgTypeName}
 			}
-		} else {
+		case isPostGISType(typeName):
+			// only columns constrained to points can be mapped to geo points,
+			// any other geometry will be mapped to a geo shape
+			searchType = searchstore.GeoShapeType
+			if isPostGISPointType(pgTypeName) {
+				searchType = searchstore.GeoPointType
+			}
+		default:
 			return nil, search.ErrTypeInvalid{Input: pgTypeName}
 		}
 	}
@@ -169,6 +229,53 @@ func (m *PgMapper) columnToSearchField(column schemalog.Column) (*searchstore.Fi
 	}, nil
 }
 
+// userDefinedTypeToSearchField maps enums, ranges and composite types into
+// their search field. Domains are expected to have been resolved to their base
+// type.
+func (m *PgMapper) userDefinedTypeToSearchField(column schemalog.Column, isArray bool) (*searchstore.Field, error) {
+	typeInfo := column.TypeInfo
+	switch typeInfo.Category {
+	case schemalog.TypeCategoryEnum:
+		return &searchstore.Field{
+			SearchType: searchstore.KeywordType,
+			IsArray:    isArray,
+		}, nil
+	case schemalog.TypeCategoryRange:
+		subtypeField, err := m.columnToSearchField(schemalog.Column{DataType: typeInfo.RangeSubtype})
+		if err != nil {
+			return nil, search.ErrTypeInvalid{Input: column.DataType}
+		}
+		switch subtypeField.SearchType {
+		case searchstore.IntegerType, searchstore.FloatType, searchstore.DateType,
+			searchstore.DateTimeType, searchstore.DateTimeTZType:
+		default:
+			return nil, search.ErrTypeInvalid{Input: column.DataType}
+		}
+		return &searchstore.Field{
+			SearchType: searchstore.RangeType,
+			IsArray:    isArray,
+			Metadata:   searchstore.Metadata{RangeSubtype: subtypeField.SearchType},
+		}, nil
+	case schemalog.TypeCategoryComposite:
+		properties := make(map[string]*searchstore.Field, len(typeInfo.Fields))
+		for _, f := range typeInfo.Fields {
+			field, err := m.columnToSearchField(schemalog.Column{DataType: f.Type})
+			if err != nil {
+				// we do not map unsupported types
+				continue
+			}
+			properties[f.Name] = field
+		}
+		return &searchstore.Field{
+			SearchType: searchstore.ObjectType,
+			IsArray:    isArray,
+			Metadata:   searchstore.Metadata{Properties: properties},
+		}, nil
+	default:
+		return nil, search.ErrTypeInvalid{Input: column.DataType}
+	}
+}
+
 func (m *PgMapper) mapDateTimeArray(searchField *searchstore.Field, value any) (any, error) {
 	switch searchField.SearchType {
 	case searchstore.DateTimeTZType:
@@ -221,6 +328,212 @@ func (m *PgMapper) mapDateTime(searchField *searchstore.Field, value any) (any,
 	return value, nil
 }
 
+// mapJSON parses the json value on input, so that it can be indexed as a
+// structured value.
+func (m *PgMapper) mapJSON(value any) (any, error) {
+	var jsonBytes []byte
+	switch v := value.(type) {
+	case string:
+		jsonBytes = []byte(v)
+	case []byte:
+		jsonBytes = v
+	default:
+		// already parsed
+		return value, nil
+	}
+
+	var parsed any
+	if err := json.Unmarshal(jsonBytes, &parsed); err != nil {
+		return nil, fmt.Errorf("mapping json from pg to search store failed: %w", err)
+	}
+	return parsed, nil
+}
+
+func (m *PgMapper) mapJSONArray(value any) (any, error) {
+	var a pgtype.FlatArray[string]
+	if err := m.pgTypeMap.SQLScanner(&a).Scan(value); err != nil {
+		return nil, fmt.Errorf("mapping json array from pg to search store failed: %w (value: %s)", err, value)
+	}
+
+	values := make([]any, 0, len(a))
+	for _, v := range a {
+		parsed, err := m.mapJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, parsed)
+	}
+	return values, nil
+}
+
+// mapRange maps the range value on input into an object with the range bounds,
+// i.e. `[1,10)` is mapped to {"gte": 1, "lt": 10}. Unbounded sides are
+// omitted, and empty ranges are mapped to nil.
+func (m *PgMapper) mapRange(subtype searchstore.Type, value any) (any, error) {
+	rangeStr, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type for range column: %T", value)
+	}
+	bounds, err := parseRange(rangeStr)
+	if err != nil {
+		return nil, fmt.Errorf("mapping range from pg to search store failed: %w", err)
+	}
+	if bounds == nil {
+		return nil, nil
+	}
+
+	mapped := map[string]any{}
+	if bounds.lower != nil {
+		lower, err := m.mapRangeBound(subtype, *bounds.lower)
+		if err != nil {
+			return nil, err
+		}
+		if bounds.lowerInclusive {
+			mapped["gte"] = lower
+		} else {
+			mapped["gt"] = lower
+		}
+	}
+	if bounds.upper != nil {
+		upper, err := m.mapRangeBound(subtype, *bounds.upper)
+		if err != nil {
+			return nil, err
+		}
+		if bounds.upperInclusive {
+			mapped["lte"] = upper
+		} else {
+			mapped["lt"] = upper
+		}
+	}
+	return mapped, nil
+}
+
+func (m *PgMapper) mapRangeBound(subtype searchstore.Type, bound string) (any, error) {
+	switch subtype {
+	case searchstore.IntegerType:
+		i, err := strconv.ParseInt(bound, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mapping range bound from pg to search store failed: %w (value: %s)", err, bound)
+		}
+		return i, nil
+	case searchstore.FloatType:
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mapping range bound from pg to search store failed: %w (value: %s)", err, bound)
+		}
+		return f, nil
+	case searchstore.DateType:
+		var d pgtype.Date
+		if err := d.Scan(bound); err != nil {
+			return nil, fmt.Errorf("mapping range bound from pg to search store failed: %w (value: %s)", err, bound)
+		}
+		return d.Time.Format(dateFormat), nil
+	default:
+		return m.mapDateTime(&searchstore.Field{SearchType: subtype}, bound)
+	}
+}
+
+// mapComposite maps the composite value on input into an object keyed by the
+// composite field names. Fields with unsupported types are not mapped.
+func (m *PgMapper) mapComposite(fields []schemalog.CompositeField, value any) (any, error) {
+	recordStr, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type for composite column: %T", value)
+	}
+	elements, err := parseComposite(recordStr)
+	if err != nil {
+		return nil, fmt.Errorf("mapping composite from pg to search store failed: %w", err)
+	}
+	if len(elements) != len(fields) {
+		return nil, fmt.Errorf("mapping composite from pg to search store failed: expected %d fields, got %d", len(fields), len(elements))
+	}
+
+	mapped := make(map[string]any, len(fields))
+	for i, f := range fields {
+		var fieldValue any
+		if elements[i] != nil {
+			fieldValue = *elements[i]
+		}
+		v, err := m.MapColumnValue(schemalog.Column{Name: f.Name, DataType: f.Type}, fieldValue)
+		if err != nil {
+			if errors.As(err, &search.ErrTypeInvalid{}) {
+				continue
+			}
+			return nil, err
+		}
+		mapped[f.Name] = v
+	}
+	return mapped, nil
+}
+
+// mapArray parses the pg array value on input and maps each of the non null
+// elements with the mapping function provided.
+func (m *PgMapper) mapArray(value any, mapFn func(string) (any, error)) (any, error) {
+	var a pgtype.FlatArray[*string]
+	if err := m.pgTypeMap.SQLScanner(&a).Scan(value); err != nil {
+		return nil, fmt.Errorf("mapping array from pg to search store failed: %w (value: %s)", err, value)
+	}
+
+	values := make([]any, 0, len(a))
+	for _, v := range a {
+		if v == nil {
+			continue
+		}
+		mapped, err := mapFn(*v)
+		if err != nil {
+			return nil, err
+		}
+		if mapped != nil {
+			values = append(values, mapped)
+		}
+	}
+	return values, nil
+}
+
+// mapGeometry maps the hex encoded WKB/EWKB PostGIS value on input into GeoJSON.
+func (m *PgMapper) mapGeometry(value any) (any, error) {
+	hexWKB, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type for geometry column: %T", value)
+	}
+	geometry, err := wkbHexToGeoJSON(hexWKB)
+	if err != nil {
+		return nil, fmt.Errorf("mapping geometry from pg to search store failed: %w", err)
+	}
+	if geometry == nil {
+		return nil, nil
+	}
+	return geometry, nil
+}
+
+// mapGeometryArray maps the PostGIS array value on input into a list of GeoJSON
+// geometries. PostGIS types use `:` as array delimiter, so the generic pg array
+// parsing can't be used.
+func (m *PgMapper) mapGeometryArray(value any) (any, error) {
+	arrayStr, ok := value.(string)
+	if !ok || !strings.HasPrefix(arrayStr, "{") || !strings.HasSuffix(arrayStr, "}") {
+		return nil, fmt.Errorf("mapping geometry array from pg to search store failed: unexpected value: %v", value)
+	}
+
+	elements := strings.FieldsFunc(arrayStr[1:len(arrayStr)-1], func(r rune) bool {
+		return r == ':' || r == ','
+	})
+	geometries := make([]any, 0, len(elements))
+	for _, hexWKB := range elements {
+		if hexWKB == "NULL" {
+			continue
+		}
+		geometry, err := m.mapGeometry(hexWKB)
+		if err != nil {
+			return nil, err
+		}
+		if geometry != nil {
+			geometries = append(geometries, geometry)
+		}
+	}
+	return geometries, nil
+}
+
 func (m *PgMapper) parsePGType(name string) (typeName string, isArray bool, err error) {
 	inputName := name
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ApollosProject/pgstream-wal2json/internal/searchstore"
 	"github.com/ApollosProject/pgstream-wal2json/internal/searchstore/opensearch"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search"
@@ -20,6 +21,8 @@ func TestMapper_ColumnToSearchMapping(t *testing.T) {
 	tests := map[string]struct {
 		pg             string
 		columnMetadata *string
+		typeInfo       *schemalog.TypeInfo
+		jsonMode       searchstore.JSONMode
 		mapping        map[string]any
 	}{
 		"int8": {
@@ -161,6 +164,37 @@ func TestMapper_ColumnToSearchMapping(t *testing.T) {
 			pg:      "json",
 			mapping: map[string]any{"type": "text"},
 		},
+		"jsonb object mode": {
+			pg:       "jsonb",
+			jsonMode: searchstore.JSONModeObject,
+			mapping:  map[string]any{"type": "object", "dynamic": true},
+		},
+		"jsonb flattened mode": {
+			pg:       "jsonb",
+			jsonMode: searchstore.JSONModeFlattened,
+			mapping:  map[string]any{"type": "flat_object"},
+		},
+		"jsonb nested mode": {
+			pg:       "jsonb",
+			jsonMode: searchstore.JSONModeNested,
+			mapping:  map[string]any{"type": "nested", "dynamic": true},
+		},
+		"geometry(Point,4326)": {
+			pg:      "geometry(Point,4326)",
+			mapping: map[string]any{"type": "geo_point"},
+		},
+		"public.geography(PointZ,4326)": {
+			pg:      "public.geography(PointZ,4326)",
+			mapping: map[string]any{"type": "geo_point"},
+		},
+		"geometry": {
+			pg:      "geometry",
+			mapping: map[string]any{"type": "geo_shape"},
+		},
+		"geometry(Polygon,4326)[]": {
+			pg:      "geometry(Polygon,4326)[]",
+			mapping: map[string]any{"type": "geo_shape"},
+		},
 		"macaddr": {
 			pg: "macaddr",
 			mapping: map[string]any{
@@ -173,14 +207,93 @@ func TestMapper_ColumnToSearchMapping(t *testing.T) {
 				},
 			},
 		},
+		"enum": {
+			pg:       "mood",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryEnum, EnumValues: []string{"sad", "happy"}},
+			mapping:  map[string]any{"type": "keyword"},
+		},
+		"enum[]": {
+			pg:       "public.mood[]",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryEnum, EnumValues: []string{"sad", "happy"}},
+			mapping:  map[string]any{"type": "keyword"},
+		},
+		"domain": {
+			pg:       "positive_int",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryDomain, BaseType: "integer"},
+			mapping:  map[string]any{"type": "long"},
+		},
+		"domain[]": {
+			pg:       "us_postal_code[]",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryDomain, BaseType: "character varying(10)"},
+			mapping: map[string]any{
+				"type":         "keyword",
+				"ignore_above": termByteLengthLimit,
+				"fields": map[string]any{
+					"text": map[string]any{
+						"type": "text",
+					},
+				},
+			},
+		},
+		"int4range": {
+			pg:       "int4range",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "integer"},
+			mapping:  map[string]any{"type": "long_range"},
+		},
+		"numrange": {
+			pg:       "numrange",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "numeric"},
+			mapping:  map[string]any{"type": "double_range"},
+		},
+		"daterange": {
+			pg:       "daterange",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "date"},
+			mapping:  map[string]any{"type": "date_range", "format": "date"},
+		},
+		"tstzrange[]": {
+			pg:       "tstzrange[]",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "timestamp with time zone"},
+			mapping: map[string]any{
+				"type":   "date_range",
+				"format": "yyyy-MM-dd HH:mm:ss[.SSS][x]||yyyy-MM-dd HH:mm:ss[.SS][x]||yyyy-MM-dd HH:mm:ss[.S][x]||yyyy-MM-dd'T'HH:mm:ss[.SSS][X]",
+			},
+		},
+		"composite": {
+			pg: "inventory_item",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryComposite, Fields: []schemalog.CompositeField{
+				{Name: "name", Type: "text"},
+				{Name: "price", Type: "numeric"},
+				{Name: "custom", Type: "custom_type"},
+			}},
+			mapping: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{
+						"type":         "keyword",
+						"ignore_above": termByteLengthLimit,
+						"fields": map[string]any{
+							"text": map[string]any{
+								"type": "text",
+							},
+						},
+					},
+					"price": map[string]any{"type": "double"},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			m := NewPostgresMapper(opensearch.NewMapper())
+			opts := []PgMapperOption{}
+			if test.jsonMode != "" {
+				opts = append(opts, WithJSONMode(test.jsonMode))
+			}
+			m := NewPostgresMapper(opensearch.NewMapper(), opts...)
 			mapping, err := m.ColumnToSearchMapping(schemalog.Column{
 				DataType: test.pg,
 				Metadata: test.columnMetadata,
+				TypeInfo: test.typeInfo,
 			})
 			require.NoError(t, err)
 			require.Equal(t, test.mapping, mapping)
@@ -188,7 +301,8 @@ func TestMapper_ColumnToSearchMapping(t *testing.T) {
 	}
 
 	errorTests := map[string]struct {
-		pg string
+		pg       string
+		typeInfo *schemalog.TypeInfo
 	}{
 		"invalid type": {
 			pg: "a",
@@ -196,12 +310,16 @@ func TestMapper_ColumnToSearchMapping(t *testing.T) {
 		"badly formatted parameters": {
 			pg: "numeric)[]",
 		},
+		"range with unsupported subtype": {
+			pg:       "textrange",
+			typeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "text"},
+		},
 	}
 
 	for name, test := range errorTests {
 		t.Run(name, func(t *testing.T) {
 			m := NewPostgresMapper(opensearch.NewMapper())
-			_, err := m.ColumnToSearchMapping(schemalog.Column{DataType: test.pg})
+			_, err := m.ColumnToSearchMapping(schemalog.Column{DataType: test.pg, TypeInfo: test.typeInfo})
 			require.Error(t, err)
 
 			var k search.ErrTypeInvalid
@@ -220,9 +338,10 @@ func TestMapper_MapColumnValue(t *testing.T) {
 	const pgFormat = "2006-01-02 15:04:05.000000"
 
 	tests := []struct {
-		name   string
-		column schemalog.Column
-		value  any
+		name     string
+		column   schemalog.Column
+		value    any
+		jsonMode searchstore.JSONMode
 
 		wantValue any
 		wantErr   error
@@ -267,6 +386,146 @@ func TestMapper_MapColumnValue(t *testing.T) {
 			wantValue: []string{tsNow},
 			wantErr:   nil,
 		},
+		{
+			name:   "geometry point",
+			column: schemalog.Column{DataType: "geometry(Point,4326)"},
+			value:  "0101000020E6100000000000000000F03F0000000000000040",
+
+			wantValue: map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+			wantErr:   nil,
+		},
+		{
+			name:   "geometry array",
+			column: schemalog.Column{DataType: "geometry[]"},
+			value:  "{0101000020E6100000000000000000F03F0000000000000040:0101000000000000000000F87F000000000000F87F}",
+
+			wantValue: []any{map[string]any{"type": "Point", "coordinates": []float64{1, 2}}},
+			wantErr:   nil,
+		},
+		{
+			name:   "jsonb text mode",
+			column: schemalog.Column{DataType: "jsonb"},
+			value:  `{"a": {"b": 1}}`,
+
+			wantValue: `{"a": {"b": 1}}`,
+			wantErr:   nil,
+		},
+		{
+			name:     "jsonb object mode",
+			column:   schemalog.Column{DataType: "jsonb"},
+			value:    `{"a": {"b": 1}}`,
+			jsonMode: searchstore.JSONModeObject,
+
+			wantValue: map[string]any{"a": map[string]any{"b": float64(1)}},
+			wantErr:   nil,
+		},
+		{
+			name:     "jsonb nested mode with array of objects",
+			column:   schemalog.Column{DataType: "jsonb"},
+			value:    `[{"a": 1}, {"a": 2}]`,
+			jsonMode: searchstore.JSONModeNested,
+
+			wantValue: []any{map[string]any{"a": float64(1)}, map[string]any{"a": float64(2)}},
+			wantErr:   nil,
+		},
+		{
+			name:     "jsonb array flattened mode",
+			column:   schemalog.Column{DataType: "jsonb[]"},
+			value:    `{"{\"a\": 1}","{\"b\": 2}"}`,
+			jsonMode: searchstore.JSONModeFlattened,
+
+			wantValue: []any{map[string]any{"a": float64(1)}, map[string]any{"b": float64(2)}},
+			wantErr:   nil,
+		},
+		{
+			name:     "jsonb invalid value",
+			column:   schemalog.Column{DataType: "jsonb"},
+			value:    `{"a":`,
+			jsonMode: searchstore.JSONModeObject,
+
+			wantValue: nil,
+			wantErr:   errors.New("mapping json from pg to search store failed"),
+		},
+		{
+			name:   "enum array",
+			column: schemalog.Column{DataType: "mood[]", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryEnum}},
+			value:  "{sad,happy}",
+
+			wantValue: []string{"sad", "happy"},
+			wantErr:   nil,
+		},
+		{
+			name:   "domain",
+			column: schemalog.Column{DataType: "event_date", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryDomain, BaseType: "date"}},
+			value:  "2024-03-12",
+
+			wantValue: "2024-03-12",
+			wantErr:   nil,
+		},
+		{
+			name:   "int4range",
+			column: schemalog.Column{DataType: "int4range", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "integer"}},
+			value:  "[1,10)",
+
+			wantValue: map[string]any{"gte": int64(1), "lt": int64(10)},
+			wantErr:   nil,
+		},
+		{
+			name:   "unbounded numrange",
+			column: schemalog.Column{DataType: "numrange", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "numeric"}},
+			value:  "(1.5,]",
+
+			wantValue: map[string]any{"gt": 1.5},
+			wantErr:   nil,
+		},
+		{
+			name:   "empty range",
+			column: schemalog.Column{DataType: "int4range", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "integer"}},
+			value:  "empty",
+
+			wantValue: nil,
+			wantErr:   nil,
+		},
+		{
+			name:   "tstzrange array",
+			column: schemalog.Column{DataType: "tstzrange[]", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryRange, RangeSubtype: "timestamp with time zone"}},
+			value:  `{"[\"2024-03-12 10:00:00+00\",\"2024-03-13 10:00:00+00\")",empty}`,
+
+			wantValue: []any{map[string]any{"gte": "2024-03-12T10:00:00.000Z", "lt": "2024-03-13T10:00:00.000Z"}},
+			wantErr:   nil,
+		},
+		{
+			name: "composite",
+			column: schemalog.Column{DataType: "inventory_item", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryComposite, Fields: []schemalog.CompositeField{
+				{Name: "name", Type: "text"},
+				{Name: "tags", Type: "text[]"},
+				{Name: "price", Type: "numeric"},
+				{Name: "custom", Type: "custom_type"},
+			}}},
+			value: `("fuzzy ""dice""","{a,b}",,x)`,
+
+			wantValue: map[string]any{"name": `fuzzy "dice"`, "tags": []string{"a", "b"}, "price": nil},
+			wantErr:   nil,
+		},
+		{
+			name: "composite array",
+			column: schemalog.Column{DataType: "inventory_item[]", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryComposite, Fields: []schemalog.CompositeField{
+				{Name: "name", Type: "text"},
+				{Name: "count", Type: "integer"},
+			}}},
+			value: `{"(dice,1)","(\"fuzzy dice\",2)"}`,
+
+			wantValue: []any{map[string]any{"name": "dice", "count": "1"}, map[string]any{"name": "fuzzy dice", "count": "2"}},
+			wantErr:   nil,
+		},
+		{
+			name:   "invalid composite",
+			column: schemalog.Column{DataType: "inventory_item", TypeInfo: &schemalog.TypeInfo{Category: schemalog.TypeCategoryComposite, Fields: []schemalog.CompositeField{{Name: "name", Type: "text"}}}},
+			value:  `("dice`,
+
+			wantValue: nil,
+			wantErr:   errors.New("mapping composite from pg to search store failed"),
+		},
 		{
 			name:   "unknonwn column type",
 			column: schemalog.Column{DataType: "custom_type"},
@@ -282,7 +541,11 @@ func TestMapper_MapColumnValue(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			mapper := NewPostgresMapper(opensearch.NewMapper())
+			opts := []PgMapperOption{}
+			if tc.jsonMode != "" {
+				opts = append(opts, WithJSONMode(tc.jsonMode))
+			}
+			mapper := NewPostgresMapper(opensearch.NewMapper(), opts...)
 			value, err := mapper.MapColumnValue(tc.column, tc.value)
 			if !errors.Is(err, tc.wantErr) {
 				require.Error(t, err, tc.wantErr.Error())
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+)
+
+var errInvalidRecord = errors.New("invalid record value")
+
+const emptyRange = "empty"
+
+// domainBaseColumn returns the column on input with the data type replaced by
+// the base type of the domain, if the column is a domain. Otherwise the column
+// is returned unchanged.
+func domainBaseColumn(column schemalog.Column) schemalog.Column {
+	if column.TypeInfo == nil ||
+		column.TypeInfo.Category != schemalog.TypeCategoryDomain ||
+		column.TypeInfo.BaseType == "" {
+		return column
+	}
+
+	baseType := column.TypeInfo.BaseType
+	if strings.HasSuffix(column.DataType, "[]") {
+		baseType += "[]"
+	}
+	column.DataType = baseType
+	column.TypeInfo = nil
+	return column
+}
+
+// rangeBounds contains the bounds of a postgres range value. A nil bound means
+// the range is unbounded on that side.
+type rangeBounds struct {
+	lower, upper                   *string
+	lowerInclusive, upperInclusive bool
+}
+
+// parseRange parses the postgres text representation of a range, i.e.
+// `[1,10)` or `["2024-01-01 00:00:00+00",)`. It returns nil for empty ranges.
+func parseRange(value string) (*rangeBounds, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, emptyRange) {
+		return nil, nil
+	}
+	if len(value) < 2 {
+		return nil, fmt.Errorf("%w: %q", errInvalidRecord, value)
+	}
+
+	bounds := &rangeBounds{}
+	switch value[0] {
+	case '[':
+		bounds.lowerInclusive = true
+	case '(':
+	default:
+		return nil, fmt.Errorf("%w: %q", errInvalidRecord, value)
+	}
+	switch value[len(value)-1] {
+	case ']':
+		bounds.upperInclusive = true
+	case ')':
+	default:
+		return nil, fmt.Errorf("%w: %q", errInvalidRecord, value)
+	}
+
+	elements, err := splitRecordElements(value[1 : len(value)-1])
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) != 2 {
+		return nil, fmt.Errorf("%w: %q", errInvalidRecord, value)
+	}
+	bounds.lower, bounds.upper = elements[0], elements[1]
+	return bounds, nil
+}
+
+// parseComposite parses the postgres text representation of a composite
+// value, i.e. `(1,"some text",)`. Null fields are returned as nil.
+func parseComposite(value string) ([]*string, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || value[0] != '(' || value[len(value)-1] != ')' {
+		return nil, fmt.Errorf("%w: %q", errInvalidRecord, value)
+	}
+	return splitRecordElements(value[1 : len(value)-1])
+}
+
+// splitRecordElements splits the comma separated elements of a composite or
+// range value. Elements can be double quoted, with quotes escaped by doubling
+// them or with a backslash. Empty unquoted elements are returned as nil.
+func splitRecordElements(s string) ([]*string, error) {
+	elements := []*string{}
+	var current strings.Builder
+	quoted := false
+	inQuotes := false
+
+	appendElement := func() {
+		if !quoted && current.Len() == 0 {
+			elements = append(elements, nil)
+		} else {
+			element := current.String()
+			elements = append(elements, &element)
+		}
+		current.Reset()
+		quoted = false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inQuotes && c == '\\':
+			if i+1 >= len(s) {
+				return nil, fmt.Errorf("%w: unterminated escape", errInvalidRecord)
+			}
+			i++
+			current.WriteByte(s[i])
+		case inQuotes && c == '"':
+			if i+1 < len(s) && s[i+1] == '"' {
+				current.WriteByte('"')
+				i++
+				continue
+			}
+			inQuotes = false
+		case c == '"':
+			inQuotes = true
+			quoted = true
+		case !inQuotes && c == ',':
+			appendElement()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("%w: unterminated quotes", errInvalidRecord)
+	}
+	appendElement()
+
+	return elements, nil
+}
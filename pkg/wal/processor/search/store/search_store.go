@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/ApollosProject/pgstream-wal2json/internal/searchstore"
 	elasticsearchstore "github.com/ApollosProject/pgstream-wal2json/internal/searchstore/elasticsearch"
@@ -15,6 +17,7 @@ import (
 
 	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/tls"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/search"
 )
 
@@ -26,11 +29,60 @@ type Store struct {
 	indexNameAdapter     IndexNameAdapter
 	marshaler            func(any) ([]byte, error)
 	defaultIndexSettings map[string]any
+	columnNameAliases    bool
+	mappingOverrides     map[string]map[string]any
+	// aliasedSchemas keeps track of the schemas whose index mapping has been
+	// checked to contain the column name aliases.
+	aliasedSchemas sync.Map
+
+	configIndexSettings   map[string]any
+	schemaIndexSettings   map[string]map[string]any
+	indexTemplateName     string
+	indexTemplate         map[string]any
+	indexTemplateSettings map[string]any
+	indexTemplateApplied  atomic.Bool
 }
 
 type Config struct {
 	OpenSearchURL    string
 	ElasticsearchURL string
+	// Auth contains the authentication configuration for the search store.
+	Auth AuthConfig
+	TLS  tls.Config
+	// ColumnNameAliases enables human readable field names for the indexed
+	// documents. Documents are still stored using the pgstream column ids, but
+	// an alias field is added for each column using the `<table name>.<column
+	// name>` path, and the table name is indexed in the `_table_name` field.
+	// Defaults to false.
+	ColumnNameAliases bool
+	// MappingOverrides allows to override the default search mapping for
+	// specific columns, keyed by `<schema>.<table>.<column>`. The override keys
+	// replace the ones in the default mapping for the column type. Overrides
+	// can also be defined in the column comment, using a JSON `pgstream`
+	// section (i.e, `{"pgstream": {"search_mapping": {"type": "keyword"}}}`).
+	// Config overrides take precedence over column comments.
+	MappingOverrides map[string]map[string]any
+	// JSONMappingMode determines how json/jsonb columns are indexed. One of
+	// `text`, `object`, `flattened` (`flat_object` in OpenSearch) or `nested`.
+	// Defaults to `text`.
+	JSONMappingMode string
+	// IndexSettings are applied to the schema indices when they're created,
+	// on top of the search store default settings (i.e,
+	// `{"number_of_replicas": 0}`).
+	IndexSettings map[string]any
+	// SchemaIndexSettings contains index settings overrides keyed by schema
+	// name or pattern (i.e, `tenant_*`), applied on top of IndexSettings. An
+	// exact schema name match takes precedence over patterns.
+	SchemaIndexSettings map[string]map[string]any
+	// IndexTemplate is an optional composable index template body, put in the
+	// search store before the schema indices are created. It must define the
+	// `index_patterns` it applies to. The template settings take precedence
+	// over the search store default settings, but not over IndexSettings or
+	// SchemaIndexSettings.
+	IndexTemplate map[string]any
+	// IndexTemplateName is the name of the index template. Defaults to
+	// `pgstream`.
+	IndexTemplateName string
 }
 
 type Option func(*Store)
@@ -41,6 +93,9 @@ const (
 	idFieldLengthLimit = 512
 
 	schemalogIndexName = "pgstream"
+
+	tableIDField   = "_table"
+	tableNameField = "_table_name"
 )
 
 func NewStore(cfg Config, opts ...Option) (*Store, error) {
@@ -52,15 +107,37 @@ func NewStore(cfg Config, opts ...Option) (*Store, error) {
 	case cfg.OpenSearchURL == "" && cfg.ElasticsearchURL == "":
 		return nil, errors.New("a store URL must be provided")
 	case cfg.OpenSearchURL != "":
-		searchStore, err = opensearchstore.NewClient(cfg.OpenSearchURL)
+		searchStore, err = opensearchstore.NewClient(cfg.clientConfig(cfg.OpenSearchURL))
 	case cfg.ElasticsearchURL != "":
-		searchStore, err = elasticsearchstore.NewClient(cfg.ElasticsearchURL)
+		searchStore, err = elasticsearchstore.NewClient(cfg.clientConfig(cfg.ElasticsearchURL))
 	}
 	if err != nil {
 		return nil, fmt.Errorf("create search store client: %w", err)
 	}
 
+	jsonMode, err := searchstore.ParseJSONMode(cfg.JSONMappingMode)
+	if err != nil {
+		return nil, err
+	}
+
+	templateSettings, err := indexTemplateSettings(cfg.IndexTemplate)
+	if err != nil {
+		return nil, err
+	}
+
 	s := NewStoreWithClient(searchStore)
+	s.columnNameAliases = cfg.ColumnNameAliases
+	s.configIndexSettings = cfg.IndexSettings
+	s.schemaIndexSettings = cfg.SchemaIndexSettings
+	if len(cfg.IndexTemplate) > 0 {
+		s.indexTemplate = cfg.IndexTemplate
+		s.indexTemplateSettings = templateSettings
+	}
+	if cfg.IndexTemplateName != "" {
+		s.indexTemplateName = cfg.IndexTemplateName
+	}
+	s.mappingOverrides = cfg.MappingOverrides
+	s.mapper = NewPostgresMapper(searchStore.GetMapper(), WithJSONMode(jsonMode))
 
 	for _, opt := range opts {
 		opt(s)
@@ -80,6 +157,7 @@ func NewStoreWithClient(client searchstore.Client) *Store {
 		mapper:               NewPostgresMapper(mapper),
 		marshaler:            json.Marshal,
 		defaultIndexSettings: mapper.GetDefaultIndexSettings(),
+		indexTemplateName:    defaultIndexTemplateName,
 	}
 }
 
@@ -156,6 +234,19 @@ func (s *Store) ApplySchemaChange(ctx context.Context, newEntry *schemalog.LogEn
 	for _, tbl := range changes.UniqueNotNullChange {
 		s.logger.Warn(nil, fmt.Sprintf("unique not null identity column changed for table %s, reindexing required", tbl))
 	}
+	// the search store doesn't allow to update the type of an existing field
+	// mapping, so documents with values incompatible with the existing mapping
+	// will fail to be indexed until the index is recreated.
+	for _, c := range changes.ColumnTypeChanged {
+		s.logger.Warn(nil, fmt.Sprintf("column type changed for table %s, reindexing required", c.TableName), loglib.Fields{
+			"column": map[string]any{
+				"id":       c.New.PgstreamID,
+				"name":     c.New.Name,
+				"old_type": c.Old.DataType,
+				"new_type": c.New.DataType,
+			},
+		})
+	}
 
 	if err := s.updateMapping(ctx, newEntry.SchemaName, newEntry, changes); err != nil {
 		return fmt.Errorf("update mapping for schema: %w", err)
@@ -173,8 +264,16 @@ func (s *Store) SendDocuments(ctx context.Context, docs []search.Document) ([]se
 			})
 			continue
 		}
+		if s.columnNameAliases && doc.Table != "" && !doc.Delete {
+			doc.Data[tableNameField] = doc.Table
+		}
 		items = append(items, s.adapter.SearchDocToBulkItem(doc))
 	}
+
+	if err := s.ensureDocumentsColumnNameAliases(ctx, docs); err != nil {
+		return nil, err
+	}
+
 	failed, err := s.client.SendBulkRequest(ctx, items)
 	if err != nil {
 		return nil, mapError(err)
@@ -195,6 +294,7 @@ func (s *Store) DeleteSchema(ctx context.Context, schemaName string) error {
 			return mapError(err)
 		}
 	}
+	s.aliasedSchemas.Delete(schemaName)
 
 	// delete the schema from the schema log index
 	if err := s.client.DeleteByQuery(ctx, &searchstore.DeleteByQueryRequest{
@@ -280,17 +380,27 @@ func (s *Store) schemaExists(ctx context.Context, schemaName string) (bool, erro
 }
 
 func (s *Store) createSchema(ctx context.Context, schemaName string) error {
+	if err := s.ensureIndexTemplate(ctx); err != nil {
+		return err
+	}
+
 	index := s.indexNameAdapter.SchemaNameToIndex(schemaName)
+	properties := map[string]any{
+		tableIDField: map[string]any{
+			"type": "keyword",
+		},
+	}
+	if s.columnNameAliases {
+		properties[tableNameField] = map[string]any{
+			"type": "keyword",
+		}
+	}
 	err := s.client.CreateIndex(ctx, index.NameWithVersion(), map[string]any{
 		"mappings": map[string]any{
-			"dynamic": "strict",
-			"properties": map[string]any{
-				"_table": map[string]any{
-					"type": "keyword",
-				},
-			},
+			"dynamic":    "strict",
+			"properties": properties,
 		},
-		"settings": s.defaultIndexSettings,
+		"settings": s.indexSettings(schemaName),
 	})
 	if err != nil {
 		if errors.As(err, &searchstore.ErrResourceAlreadyExists{}) {
@@ -310,10 +420,24 @@ func (s *Store) createSchema(ctx context.Context, schemaName string) error {
 func (s *Store) updateMapping(ctx context.Context, schemaName string, logEntry *schemalog.LogEntry, diff *schemalog.SchemaDiff) error {
 	index := s.indexNameAdapter.SchemaNameToIndex(schemaName)
 	if diff != nil {
-		if err := s.updateMappingAddNewColumns(ctx, index, diff.ColumnsToAdd); err != nil {
+		if err := s.updateMappingAddNewColumns(ctx, index, logEntry, diff.ColumnsToAdd); err != nil {
 			return fmt.Errorf("failed to add new columns: %w", mapError(err))
 		}
 
+		if err := s.updateMappingAddColumnAliases(ctx, index, renamedColumnAliases(diff.ColumnsRenamed)); err != nil {
+			return fmt.Errorf("failed to add renamed column aliases: %w", mapError(err))
+		}
+
+		if s.columnNameAliases {
+			if err := s.updateMappingAddColumnNames(ctx, index, logEntry, diff); err != nil {
+				return fmt.Errorf("failed to add column name aliases: %w", mapError(err))
+			}
+		}
+
+		if err := s.removeDocumentFields(ctx, index, diff.ColumnsRemoved); err != nil {
+			return fmt.Errorf("failed to remove dropped columns: %w", mapError(err))
+		}
+
 		if len(diff.TablesToRemove) > 0 {
 			tableIDs := make([]string, 0, len(diff.TablesToRemove))
 			for _, table := range diff.TablesToRemove {
@@ -342,7 +466,7 @@ func (s *Store) deleteTableDocuments(ctx context.Context, index IndexName, table
 		Query: map[string]any{
 			"query": map[string]any{
 				"terms": map[string]any{
-					"_table": tableIDs,
+					tableIDField: tableIDs,
 				},
 			},
 		},
@@ -389,11 +513,18 @@ func (s *Store) createSchemaLogIndex(ctx context.Context, name string) error {
 	})
 }
 
-func (s *Store) updateMappingAddNewColumns(ctx context.Context, indexName IndexName, newColumns []schemalog.Column) error {
+func (s *Store) updateMappingAddNewColumns(ctx context.Context, indexName IndexName, logEntry *schemalog.LogEntry, newColumns []schemalog.Column) error {
 	if len(newColumns) == 0 {
 		return nil
 	}
 
+	tableNames := map[string]string{}
+	for _, table := range logEntry.Schema.Tables {
+		for _, c := range table.Columns {
+			tableNames[c.PgstreamID] = table.Name
+		}
+	}
+
 	properties := map[string]any{}
 
 	for _, c := range newColumns {
@@ -413,7 +544,7 @@ func (s *Store) updateMappingAddNewColumns(ctx context.Context, indexName IndexN
 		}
 
 		if mapping != nil {
-			properties[c.PgstreamID] = mapping
+			properties[c.PgstreamID] = s.applyMappingOverrides(indexName.SchemaName(), tableNames[c.PgstreamID], c, mapping)
 		}
 	}
 
@@ -422,6 +553,206 @@ func (s *Store) updateMappingAddNewColumns(ctx context.Context, indexName IndexN
 	})
 }
 
+// applyMappingOverrides returns the column mapping with the overrides from the
+// column comment and the config applied, in that order.
+func (s *Store) applyMappingOverrides(schemaName, tableName string, column schemalog.Column, mapping map[string]any) map[string]any {
+	commentOverride, err := parseColumnCommentMapping(column)
+	if err != nil {
+		s.logger.Warn(err, "ignoring column comment search mapping", loglib.Fields{
+			"column": map[string]any{
+				"id":   column.PgstreamID,
+				"name": column.Name,
+			},
+			"schema": schemaName,
+		})
+	}
+	mapping = applyMappingOverride(mapping, commentOverride)
+
+	if tableName == "" {
+		return mapping
+	}
+	return applyMappingOverride(mapping, s.mappingOverrides[mappingOverrideKey(schemaName, tableName, column.Name)])
+}
+
+// columnAlias represents an alias field for a column, using the `<table
+// name>.<column name>` path and pointing to the column pgstream id field.
+type columnAlias struct {
+	tableName  string
+	columnName string
+	path       string
+}
+
+func renamedColumnAliases(renamedColumns []schemalog.ColumnChange) []columnAlias {
+	aliases := make([]columnAlias, 0, len(renamedColumns))
+	for _, c := range renamedColumns {
+		aliases = append(aliases, columnAlias{
+			tableName:  c.TableName,
+			columnName: c.New.Name,
+			path:       c.New.PgstreamID,
+		})
+	}
+	return aliases
+}
+
+// updateMappingAddColumnNames adds the column name aliases for the new columns
+// and renamed tables in the diff on input, and makes sure the `_table_name`
+// field is part of the mapping. Documents from renamed tables are updated to
+// use the new table name.
+func (s *Store) updateMappingAddColumnNames(ctx context.Context, indexName IndexName, logEntry *schemalog.LogEntry, diff *schemalog.SchemaDiff) error {
+	if err := s.client.PutIndexMappings(ctx, indexName.Name(), map[string]any{
+		"properties": map[string]any{
+			tableNameField: map[string]any{
+				"type": "keyword",
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	newColumns := make(map[string]struct{}, len(diff.ColumnsToAdd))
+	for _, c := range diff.ColumnsToAdd {
+		newColumns[c.PgstreamID] = struct{}{}
+	}
+	renamedTables := make(map[string]struct{}, len(diff.TablesRenamed))
+	for _, t := range diff.TablesRenamed {
+		renamedTables[t.PgstreamID] = struct{}{}
+	}
+
+	aliases := []columnAlias{}
+	for _, table := range logEntry.Schema.Tables {
+		_, tableRenamed := renamedTables[table.PgstreamID]
+		for _, c := range table.Columns {
+			if _, isNew := newColumns[c.PgstreamID]; !isNew && !tableRenamed {
+				continue
+			}
+			// only columns with a field mapping can be aliased
+			if mapping, err := s.mapper.ColumnToSearchMapping(c); err != nil || mapping == nil {
+				continue
+			}
+			aliases = append(aliases, columnAlias{
+				tableName:  table.Name,
+				columnName: c.Name,
+				path:       c.PgstreamID,
+			})
+		}
+	}
+
+	if err := s.updateMappingAddColumnAliases(ctx, indexName, aliases); err != nil {
+		return err
+	}
+
+	return s.updateDocumentsTableName(ctx, indexName, diff.TablesRenamed)
+}
+
+// updateMappingAddColumnAliases adds an alias field for each of the column
+// aliases on input, so that documents can be queried by column name. Existing
+// aliases can't be updated, so any mapping conflicts will be logged and
+// ignored.
+func (s *Store) updateMappingAddColumnAliases(ctx context.Context, indexName IndexName, aliases []columnAlias) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	properties := map[string]any{}
+	for _, a := range aliases {
+		tableProperties, found := properties[a.tableName].(map[string]any)
+		if !found {
+			tableProperties = map[string]any{}
+			properties[a.tableName] = map[string]any{
+				"properties": tableProperties,
+			}
+		} else {
+			tableProperties = tableProperties["properties"].(map[string]any)
+		}
+		tableProperties[a.columnName] = map[string]any{
+			"type": "alias",
+			"path": a.path,
+		}
+	}
+
+	err := s.client.PutIndexMappings(ctx, indexName.Name(), map[string]any{
+		"properties": properties,
+	})
+	if err != nil {
+		if errors.As(err, &searchstore.ErrQueryInvalid{}) {
+			s.logger.Warn(err, "unable to add column aliases", loglib.Fields{
+				"schema":  indexName.SchemaName(),
+				"aliases": properties,
+			})
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// updateDocumentsTableName sets the `_table_name` field of the documents
+// belonging to the renamed tables on input to the new table name.
+func (s *Store) updateDocumentsTableName(ctx context.Context, indexName IndexName, renamedTables []schemalog.TableRename) error {
+	for _, t := range renamedTables {
+		if err := s.client.UpdateByQuery(ctx, &searchstore.UpdateByQueryRequest{
+			Index: []string{indexName.Name()},
+			Query: map[string]any{
+				"query": map[string]any{
+					"term": map[string]any{
+						tableIDField: t.PgstreamID,
+					},
+				},
+				"script": map[string]any{
+					"source": "ctx._source." + tableNameField + " = params.name",
+					"lang":   "painless",
+					"params": map[string]any{
+						"name": t.NewName,
+					},
+				},
+			},
+			Refresh: true,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeDocumentFields removes the fields for the dropped columns on input
+// from all the documents in the index. The field mappings can't be removed
+// from an existing index, but the documents will no longer contain the stale
+// values.
+func (s *Store) removeDocumentFields(ctx context.Context, indexName IndexName, removedColumns []schemalog.Column) error {
+	if len(removedColumns) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(removedColumns))
+	conditions := make([]any, 0, len(removedColumns))
+	for _, c := range removedColumns {
+		fields = append(fields, c.PgstreamID)
+		conditions = append(conditions, map[string]any{
+			"exists": map[string]any{"field": c.PgstreamID},
+		})
+	}
+
+	return s.client.UpdateByQuery(ctx, &searchstore.UpdateByQueryRequest{
+		Index: []string{indexName.Name()},
+		Query: map[string]any{
+			"query": map[string]any{
+				"bool": map[string]any{
+					"should":               conditions,
+					"minimum_should_match": 1,
+				},
+			},
+			"script": map[string]any{
+				"source": "for (String f : params.fields) { ctx._source.remove(f); }",
+				"lang":   "painless",
+				"params": map[string]any{
+					"fields": fields,
+				},
+			},
+		},
+		Refresh: true,
+	})
+}
+
 func (s *Store) insertNewSchemaLog(ctx context.Context, m *schemalog.LogEntry) error {
 	logBytes, err := json.Marshal(m)
 	if err != nil {
@@ -464,7 +795,76 @@ func (s *Store) ensureSchemaMapping(ctx context.Context, schemaName string, meta
 				return fmt.Errorf("updating mapping for missing schema: %w", err)
 			}
 		}
+		// the new index mapping already contains the column name aliases
+		s.aliasedSchemas.Store(schemaName, struct{}{})
+		return nil
+	}
+
+	return s.ensureColumnNameAliases(ctx, schemaName, metadata)
+}
+
+// ensureDocumentsColumnNameAliases makes sure the existing indices for the
+// schemas of the documents on input contain the column name aliases before
+// the documents are indexed.
+func (s *Store) ensureDocumentsColumnNameAliases(ctx context.Context, docs []search.Document) error {
+	if !s.columnNameAliases {
+		return nil
+	}
+
+	checked := map[string]struct{}{}
+	for _, doc := range docs {
+		if doc.Schema == "" || doc.Delete {
+			continue
+		}
+		if _, found := checked[doc.Schema]; found {
+			continue
+		}
+		checked[doc.Schema] = struct{}{}
+		if _, found := s.aliasedSchemas.Load(doc.Schema); found {
+			continue
+		}
+
+		exists, err := s.schemaExists(ctx, doc.Schema)
+		if err != nil {
+			return fmt.Errorf("checking existence of schema: %w", err)
+		}
+		// the index will be created with the aliases on the first schema change
+		if !exists {
+			continue
+		}
+
+		logEntry, err := s.getLastSchemaLogEntry(ctx, doc.Schema)
+		if err != nil && !errors.As(err, &search.ErrSchemaNotFound{}) {
+			return fmt.Errorf("get latest schema: %w", err)
+		}
+
+		if err := s.ensureColumnNameAliases(ctx, doc.Schema, logEntry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureColumnNameAliases adds the `_table_name` mapping and the column name
+// aliases for all the columns in the log entry on input to the existing index
+// for the schema, the first time it's used. This makes sure the aliases are
+// available when they're enabled on indices that were created without them.
+func (s *Store) ensureColumnNameAliases(ctx context.Context, schemaName string, logEntry *schemalog.LogEntry) error {
+	if !s.columnNameAliases {
+		return nil
+	}
+	if _, found := s.aliasedSchemas.Load(schemaName); found {
+		return nil
+	}
+
+	if logEntry == nil {
+		logEntry = &schemalog.LogEntry{}
+	}
+	index := s.indexNameAdapter.SchemaNameToIndex(schemaName)
+	if err := s.updateMappingAddColumnNames(ctx, index, logEntry, logEntry.Diff(nil)); err != nil {
+		return fmt.Errorf("failed to add column name aliases: %w", mapError(err))
 	}
+	s.aliasedSchemas.Store(schemaName, struct{}{})
 	return nil
 }
 
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"github.com/ApollosProject/pgstream-wal2json/internal/searchstore"
+)
+
+// AuthConfig contains the search store authentication configuration. Only one
+// authentication method can be configured.
+type AuthConfig struct {
+	// Username and Password are used for basic authentication.
+	Username string
+	Password string
+	// APIKey is the base64 encoded Elasticsearch API key. Not supported by
+	// OpenSearch.
+	APIKey string
+	// BearerToken is sent in the Authorization header of every request.
+	BearerToken string
+	// AWSSigV4 enables AWS Signature Version 4 request signing, for Amazon
+	// OpenSearch Service. Not supported by Elasticsearch.
+	AWSSigV4 *AWSSigV4Config
+}
+
+// AWSSigV4Config contains the AWS request signing configuration.
+type AWSSigV4Config struct {
+	Region string
+	// Service is the signing service name. Use `es` for Amazon OpenSearch
+	// Service domains and `aoss` for OpenSearch Serverless. Defaults to `es`.
+	Service string
+	// AccessKeyID, SecretAccessKey and SessionToken are optional static
+	// credentials. If not provided, the default AWS credentials chain is used.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+func (c *Config) clientConfig(url string) searchstore.ClientConfig {
+	cfg := searchstore.ClientConfig{
+		URL:         url,
+		Username:    c.Auth.Username,
+		Password:    c.Auth.Password,
+		APIKey:      c.Auth.APIKey,
+		BearerToken: c.Auth.BearerToken,
+		TLS:         c.TLS,
+	}
+	if c.Auth.AWSSigV4 != nil {
+		cfg.AWSSigV4 = &searchstore.AWSSigV4Config{
+			Region:          c.Auth.AWSSigV4.Region,
+			Service:         c.Auth.AWSSigV4.Service,
+			AccessKeyID:     c.Auth.AWSSigV4.AccessKeyID,
+			SecretAccessKey: c.Auth.AWSSigV4.SecretAccessKey,
+			SessionToken:    c.Auth.AWSSigV4.SessionToken,
+		}
+	}
+	return cfg
+}
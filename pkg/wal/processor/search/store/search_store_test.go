@@ -5,6 +5,7 @@ package store
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -227,17 +228,24 @@ func TestStore_SendDocuments(t *testing.T) {
 	t.Parallel()
 
 	testSchemaName := "test_schema"
-	testDocs := []search.Document{
-		{
-			ID:     "1",
-			Schema: testSchemaName,
-		},
+	newTestDocs := func() []search.Document {
+		return []search.Document{
+			{
+				ID:     "1",
+				Schema: testSchemaName,
+				Table:  "test_table",
+				Data: map[string]any{
+					"_table": "t1",
+				},
+			},
+		}
 	}
 	errTest := errors.New("oh noes")
 
 	tests := []struct {
-		name   string
-		client searchstore.Client
+		name              string
+		client            searchstore.Client
+		columnNameAliases bool
 
 		wantErrDocs []search.DocumentError
 		wantErr     error
@@ -256,6 +264,27 @@ func TestStore_SendDocuments(t *testing.T) {
 			wantErrDocs: nil,
 			wantErr:     nil,
 		},
+		{
+			name: "ok - with column name aliases",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				IndexExistsFn: func(ctx context.Context, index string) (bool, error) { return false, nil },
+				SendBulkRequestFn: func(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error) {
+					require.Len(t, items, 1)
+					require.Equal(t, map[string]any{
+						"_table":      "t1",
+						"_table_name": "test_table",
+					}, items[0].Doc)
+					return nil, nil
+				},
+			},
+			columnNameAliases: true,
+
+			wantErrDocs: nil,
+			wantErr:     nil,
+		},
 		{
 			name: "ok - with failed documents",
 			client: &searchstoremocks.Client{
@@ -288,6 +317,38 @@ func TestStore_SendDocuments(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "ok - with mapping conflict documents",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				SendBulkRequestFn: func(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error) {
+					return []searchstore.BulkItem{
+						{
+							Index: &searchstore.BulkIndex{
+								Index: testSchemaName,
+								ID:    "doc-1",
+							},
+							Status: http.StatusBadRequest,
+							Error:  []byte(`{"type":"mapper_parsing_exception","reason":"object mapping tried to parse field as object, but found a concrete value"}`),
+						},
+					}, nil
+				},
+			},
+
+			wantErrDocs: []search.DocumentError{
+				{
+					Document: search.Document{
+						ID:     "doc-1",
+						Schema: testSchemaName,
+					},
+					Severity: search.SeverityMappingConflict,
+					Error:    `{"type":"mapper_parsing_exception","reason":"object mapping tried to parse field as object, but found a concrete value"}`,
+				},
+			},
+			wantErr: nil,
+		},
 		{
 			name: "error - sending bulk request",
 			client: &searchstoremocks.Client{
@@ -299,6 +360,28 @@ func TestStore_SendDocuments(t *testing.T) {
 				},
 			},
 
+			wantErrDocs: nil,
+			wantErr:     errTest,
+		},
+		{
+			name: "error - adding column name aliases",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				IndexExistsFn: func(ctx context.Context, index string) (bool, error) { return true, nil },
+				SearchFn: func(ctx context.Context, req *searchstore.SearchRequest) (*searchstore.SearchResponse, error) {
+					return &searchstore.SearchResponse{}, nil
+				},
+				PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+					return errTest
+				},
+				SendBulkRequestFn: func(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error) {
+					return nil, errors.New("SendBulkRequestFn: should not be called")
+				},
+			},
+			columnNameAliases: true,
+
 			wantErrDocs: nil,
 			wantErr:     errTest,
 		},
@@ -310,14 +393,113 @@ func TestStore_SendDocuments(t *testing.T) {
 			t.Parallel()
 
 			s := NewStoreWithClient(tc.client)
+			s.columnNameAliases = tc.columnNameAliases
 
-			errDocs, err := s.SendDocuments(context.Background(), testDocs)
+			errDocs, err := s.SendDocuments(context.Background(), newTestDocs())
 			require.ErrorIs(t, err, tc.wantErr)
 			require.Equal(t, tc.wantErrDocs, errDocs)
 		})
 	}
 }
 
+func TestStore_SendDocuments_existingIndexColumnNameAliases(t *testing.T) {
+	t.Parallel()
+
+	testSchemaName := "test_schema"
+	testLogEntry := &schemalog.LogEntry{
+		ID:         xid.New(),
+		SchemaName: testSchemaName,
+		Version:    1,
+		Schema: schemalog.Schema{
+			Tables: []schemalog.Table{
+				{
+					Name:       "test_table",
+					PgstreamID: "t1",
+					Columns: []schemalog.Column{
+						{Name: "id", DataType: "integer", PgstreamID: "t1-1"},
+						{Name: "name", DataType: "text", PgstreamID: "t1-2"},
+					},
+				},
+			},
+		},
+	}
+	logEntryBytes, err := json.Marshal(testLogEntry)
+	require.NoError(t, err)
+	logEntrySource := map[string]any{}
+	require.NoError(t, json.Unmarshal(logEntryBytes, &logEntrySource))
+
+	mappings := []map[string]any{}
+	bulkRequests := 0
+	client := &searchstoremocks.Client{
+		GetMapperFn: func() searchstore.Mapper {
+			return &searchstoremocks.Mapper{}
+		},
+		// the index was created before the column name aliases were enabled
+		IndexExistsFn: func(ctx context.Context, index string) (bool, error) { return true, nil },
+		SearchFn: func(ctx context.Context, req *searchstore.SearchRequest) (*searchstore.SearchResponse, error) {
+			return &searchstore.SearchResponse{
+				Hits: searchstore.Hits{
+					Hits: []searchstore.Hit{{Source: logEntrySource}},
+				},
+			}, nil
+		},
+		PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+			require.Equal(t, "test_schema", index)
+			mappings = append(mappings, body)
+			return nil
+		},
+		SendBulkRequestFn: func(ctx context.Context, items []searchstore.BulkItem) ([]searchstore.BulkItem, error) {
+			bulkRequests++
+			return nil, nil
+		},
+	}
+
+	s := NewStoreWithClient(client)
+	s.columnNameAliases = true
+	s.mapper = &searchmocks.Mapper{
+		ColumnToSearchMappingFn: func(column schemalog.Column) (map[string]any, error) {
+			return map[string]any{"type": "keyword"}, nil
+		},
+	}
+
+	docs := []search.Document{
+		{
+			ID:     "1",
+			Schema: testSchemaName,
+			Table:  "test_table",
+			Data:   map[string]any{"_table": "t1"},
+		},
+	}
+
+	// the mapping is only updated on first use of the index
+	for i := 0; i < 2; i++ {
+		errDocs, err := s.SendDocuments(context.Background(), docs)
+		require.NoError(t, err)
+		require.Nil(t, errDocs)
+	}
+
+	require.Equal(t, 2, bulkRequests)
+	require.Equal(t, []map[string]any{
+		{
+			"properties": map[string]any{
+				"_table_name": map[string]any{
+					"type": "keyword",
+				},
+			},
+		},
+		{
+			"properties": map[string]any{
+				"test_table": map[string]any{
+					"properties": map[string]any{
+						"id":   map[string]any{"type": "alias", "path": "t1-1"},
+						"name": map[string]any{"type": "alias", "path": "t1-2"},
+					},
+				},
+			},
+		},
+	}, mappings)
+}
+
 func TestStore_DeleteSchema(t *testing.T) {
 	t.Parallel()
 
@@ -765,10 +947,18 @@ func TestStore_createSchema(t *testing.T) {
 
 	testSchemaName := "test_schema"
 	errTest := errors.New("oh noes")
+	testIndexTemplate := map[string]any{
+		"index_patterns": []string{"test_*"},
+		"template": map[string]any{
+			"settings": map[string]any{"refresh_interval": "30s"},
+		},
+	}
 
 	tests := []struct {
-		name   string
-		client searchstore.Client
+		name              string
+		client            searchstore.Client
+		columnNameAliases bool
+		indexTemplate     map[string]any
 
 		wantErr error
 	}{
@@ -790,6 +980,71 @@ func TestStore_createSchema(t *testing.T) {
 
 			wantErr: nil,
 		},
+		{
+			name: "ok - with column name aliases",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				CreateIndexFn: func(ctx context.Context, index string, body map[string]any) error {
+					require.Equal(t, map[string]any{
+						"dynamic": "strict",
+						"properties": map[string]any{
+							"_table":      map[string]any{"type": "keyword"},
+							"_table_name": map[string]any{"type": "keyword"},
+						},
+					}, body["mappings"])
+					return nil
+				},
+				PutIndexAliasFn: func(ctx context.Context, index []string, name string) error {
+					require.Equal(t, []string{fmt.Sprintf("%s-1", testSchemaName)}, index)
+					require.Equal(t, testSchemaName, name)
+					return nil
+				},
+			},
+			columnNameAliases: true,
+
+			wantErr: nil,
+		},
+		{
+			name: "ok - with index template",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexTemplateFn: func(ctx context.Context, name string, body map[string]any) error {
+					require.Equal(t, defaultIndexTemplateName, name)
+					require.Equal(t, testIndexTemplate, body)
+					return nil
+				},
+				CreateIndexFn: func(ctx context.Context, index string, body map[string]any) error {
+					return nil
+				},
+				PutIndexAliasFn: func(ctx context.Context, index []string, name string) error {
+					return nil
+				},
+			},
+			indexTemplate: testIndexTemplate,
+
+			wantErr: nil,
+		},
+		{
+			name: "error - putting index template",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexTemplateFn: func(ctx context.Context, name string, body map[string]any) error {
+					return errTest
+				},
+				CreateIndexFn: func(ctx context.Context, index string, body map[string]any) error {
+					return errors.New("CreateIndexFn: should not be called")
+				},
+			},
+			indexTemplate: testIndexTemplate,
+
+			wantErr: errTest,
+		},
 		{
 			name: "error - creating index",
 			client: &searchstoremocks.Client{
@@ -830,6 +1085,8 @@ func TestStore_createSchema(t *testing.T) {
 			t.Parallel()
 
 			s := NewStoreWithClient(tc.client)
+			s.columnNameAliases = tc.columnNameAliases
+			s.indexTemplate = tc.indexTemplate
 
 			err := s.createSchema(context.Background(), testSchemaName)
 			require.ErrorIs(t, err, tc.wantErr)
@@ -851,13 +1108,66 @@ func TestStore_updateMapping(t *testing.T) {
 		"test": "mapping",
 	}
 
+	testAliasesLogEntry := &schemalog.LogEntry{
+		ID:         xid.New(),
+		SchemaName: testSchemaName,
+		Schema: schemalog.Schema{
+			Tables: []schemalog.Table{
+				{
+					Name:       "table-1",
+					PgstreamID: "t1",
+					Columns: []schemalog.Column{
+						{Name: "col-1", PgstreamID: "pgstreamid-1"},
+						{Name: "col-2", PgstreamID: "pgstreamid-2"},
+					},
+				},
+				{
+					Name:       "table-2-renamed",
+					PgstreamID: "t2",
+					Columns: []schemalog.Column{
+						{Name: "col-3", PgstreamID: "pgstreamid-3"},
+					},
+				},
+			},
+		},
+	}
+	testAliasesDiff := &schemalog.SchemaDiff{
+		ColumnsToAdd: []schemalog.Column{
+			{Name: "col-1", PgstreamID: "pgstreamid-1"},
+		},
+		TablesRenamed: []schemalog.TableRename{
+			{PgstreamID: "t2", OldName: "table-2", NewName: "table-2-renamed"},
+		},
+	}
+
+	testComment := `{"pgstream": {"search_mapping": {"type": "keyword", "ignore_above": 256}}}`
+	testOverridesLogEntry := &schemalog.LogEntry{
+		ID:         xid.New(),
+		SchemaName: testSchemaName,
+		Schema: schemalog.Schema{
+			Tables: []schemalog.Table{
+				{
+					Name:       "table-1",
+					PgstreamID: "t1",
+					Columns: []schemalog.Column{
+						{Name: "col-1", PgstreamID: "pgstreamid-1", Metadata: &testComment},
+						{Name: "col-2", PgstreamID: "pgstreamid-2"},
+					},
+				},
+			},
+		},
+	}
+
 	errTest := errors.New("oh noes")
 
 	tests := []struct {
-		name   string
-		client searchstore.Client
-		diff   *schemalog.SchemaDiff
-		mapper search.Mapper
+		name              string
+		client            searchstore.Client
+		diff              *schemalog.SchemaDiff
+		mapper            search.Mapper
+		logEntry          *schemalog.LogEntry
+		columnNameAliases bool
+		mappingOverrides  map[string]map[string]any
 
 		wantErr error
 	}{
@@ -915,6 +1225,49 @@ func TestStore_updateMapping(t *testing.T) {
 
 			wantErr: nil,
 		},
+		{
+			name: "ok - diff with columns to add and mapping overrides",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+					require.Equal(t, testIndexName, index)
+					require.Equal(t, map[string]any{
+						"properties": map[string]any{
+							"pgstreamid-1": map[string]any{
+								"test":         "mapping",
+								"type":         "keyword",
+								"ignore_above": float64(256),
+							},
+							"pgstreamid-2": map[string]any{
+								"test":  "mapping",
+								"index": false,
+							},
+						},
+					}, body)
+					return nil
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return nil
+				},
+			},
+			diff: &schemalog.SchemaDiff{
+				ColumnsToAdd: testOverridesLogEntry.Schema.Tables[0].Columns,
+			},
+			mapper: &searchmocks.Mapper{
+				ColumnToSearchMappingFn: func(column schemalog.Column) (map[string]any, error) {
+					return testMapping, nil
+				},
+			},
+			logEntry: testOverridesLogEntry,
+			mappingOverrides: map[string]map[string]any{
+				"test_schema.table-1.col-2": {"index": false},
+				"test_schema.table-2.col-2": {"type": "keyword"},
+			},
+
+			wantErr: nil,
+		},
 		{
 			name: "ok - diff with tables to remove",
 			client: &searchstoremocks.Client{
@@ -949,6 +1302,218 @@ func TestStore_updateMapping(t *testing.T) {
 
 			wantErr: nil,
 		},
+		{
+			name: "ok - diff with renamed columns",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+					require.Equal(t, testIndexName, index)
+					require.Equal(t, map[string]any{
+						"properties": map[string]any{
+							"table-1": map[string]any{
+								"properties": map[string]any{
+									"col-1-renamed": map[string]any{
+										"type": "alias",
+										"path": "pgstreamid-1",
+									},
+								},
+							},
+						},
+					}, body)
+					return nil
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return nil
+				},
+			},
+			diff: &schemalog.SchemaDiff{
+				ColumnsRenamed: []schemalog.ColumnChange{
+					{
+						TableName: "table-1",
+						Old:       schemalog.Column{Name: "col-1", PgstreamID: "pgstreamid-1"},
+						New:       schemalog.Column{Name: "col-1-renamed", PgstreamID: "pgstreamid-1"},
+					},
+				},
+			},
+
+			wantErr: nil,
+		},
+		{
+			name: "ok - conflict adding renamed column aliases",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+					return searchstore.ErrQueryInvalid{Cause: errTest}
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return nil
+				},
+			},
+			diff: &schemalog.SchemaDiff{
+				ColumnsRenamed: []schemalog.ColumnChange{
+					{
+						TableName: "table-1",
+						Old:       schemalog.Column{Name: "col-1", PgstreamID: "pgstreamid-1"},
+						New:       schemalog.Column{Name: "col-1-renamed", PgstreamID: "pgstreamid-1"},
+					},
+				},
+			},
+
+			wantErr: nil,
+		},
+		{
+			name: "ok - column name aliases",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+					require.Equal(t, testIndexName, index)
+					properties, ok := body["properties"].(map[string]any)
+					require.True(t, ok)
+					switch {
+					case properties["pgstreamid-1"] != nil:
+						require.Equal(t, map[string]any{"pgstreamid-1": testMapping}, properties)
+					case properties["_table_name"] != nil:
+						require.Equal(t, map[string]any{"_table_name": map[string]any{"type": "keyword"}}, properties)
+					default:
+						require.Equal(t, map[string]any{
+							"table-1": map[string]any{
+								"properties": map[string]any{
+									"col-1": map[string]any{"type": "alias", "path": "pgstreamid-1"},
+								},
+							},
+							"table-2-renamed": map[string]any{
+								"properties": map[string]any{
+									"col-3": map[string]any{"type": "alias", "path": "pgstreamid-3"},
+								},
+							},
+						}, properties)
+					}
+					return nil
+				},
+				UpdateByQueryFn: func(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+					require.Equal(t, []string{testIndexName}, req.Index)
+					require.Equal(t, map[string]any{
+						"query": map[string]any{
+							"term": map[string]any{"_table": "t2"},
+						},
+						"script": map[string]any{
+							"source": "ctx._source._table_name = params.name",
+							"lang":   "painless",
+							"params": map[string]any{
+								"name": "table-2-renamed",
+							},
+						},
+					}, req.Query)
+					return nil
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return nil
+				},
+			},
+			diff: testAliasesDiff,
+			mapper: &searchmocks.Mapper{
+				ColumnToSearchMappingFn: func(column schemalog.Column) (map[string]any, error) {
+					return testMapping, nil
+				},
+			},
+			logEntry:          testAliasesLogEntry,
+			columnNameAliases: true,
+
+			wantErr: nil,
+		},
+		{
+			name: "error - updating documents table name",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				PutIndexMappingsFn: func(ctx context.Context, index string, body map[string]any) error {
+					return nil
+				},
+				UpdateByQueryFn: func(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+					return errTest
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return errors.New("IndexWithIDFn: should not be called")
+				},
+			},
+			diff: testAliasesDiff,
+			mapper: &searchmocks.Mapper{
+				ColumnToSearchMappingFn: func(column schemalog.Column) (map[string]any, error) {
+					return testMapping, nil
+				},
+			},
+			logEntry:          testAliasesLogEntry,
+			columnNameAliases: true,
+
+			wantErr: errTest,
+		},
+		{
+			name: "ok - diff with columns removed",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return nil
+				},
+				UpdateByQueryFn: func(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+					require.Equal(t, []string{testIndexName}, req.Index)
+					require.Equal(t, map[string]any{
+						"query": map[string]any{
+							"bool": map[string]any{
+								"should": []any{
+									map[string]any{"exists": map[string]any{"field": "pgstreamid-1"}},
+								},
+								"minimum_should_match": 1,
+							},
+						},
+						"script": map[string]any{
+							"source": "for (String f : params.fields) { ctx._source.remove(f); }",
+							"lang":   "painless",
+							"params": map[string]any{
+								"fields": []string{"pgstreamid-1"},
+							},
+						},
+					}, req.Query)
+					return nil
+				},
+			},
+			diff: &schemalog.SchemaDiff{
+				ColumnsRemoved: []schemalog.Column{
+					{Name: "col-1", PgstreamID: "pgstreamid-1"},
+				},
+			},
+
+			wantErr: nil,
+		},
+		{
+			name: "error - removing columns",
+			client: &searchstoremocks.Client{
+				GetMapperFn: func() searchstore.Mapper {
+					return &searchstoremocks.Mapper{}
+				},
+				IndexWithIDFn: func(ctx context.Context, req *searchstore.IndexWithIDRequest) error {
+					return errors.New("IndexWithIDFn: should not be called")
+				},
+				UpdateByQueryFn: func(ctx context.Context, req *searchstore.UpdateByQueryRequest) error {
+					return errTest
+				},
+			},
+			diff: &schemalog.SchemaDiff{
+				ColumnsRemoved: []schemalog.Column{
+					{Name: "col-1", PgstreamID: "pgstreamid-1"},
+				},
+			},
+
+			wantErr: errTest,
+		},
 		{
 			name: "error - updating mapping",
 			client: &searchstoremocks.Client{
@@ -1025,11 +1590,18 @@ func TestStore_updateMapping(t *testing.T) {
 			t.Parallel()
 
 			s := NewStoreWithClient(tc.client)
+			s.columnNameAliases = tc.columnNameAliases
+			s.mappingOverrides = tc.mappingOverrides
 			if tc.mapper != nil {
 				s.mapper = tc.mapper
 			}
 
-			err := s.updateMapping(context.Background(), testSchemaName, testLogEntry, tc.diff)
+			logEntry := testLogEntry
+			if tc.logEntry != nil {
+				logEntry = tc.logEntry
+			}
+
+			err := s.updateMapping(context.Background(), testSchemaName, logEntry, tc.diff)
 			require.ErrorIs(t, err, tc.wantErr)
 		})
 	}
@@ -63,6 +63,24 @@ func newTestSchemaChangeEvent(action string) *wal.Event {
 	}
 }
 
+func newTestDDLEvent(diff *schemalog.SchemaDiff) *wal.Event {
+	logEntry := newTestLogEntry()
+	return &wal.Event{
+		Data: &wal.Data{
+			Action:   wal.SchemaChangeAction,
+			Schema:   testSchemaName,
+			Metadata: wal.Metadata{SchemaID: testSchemaID},
+			SchemaChange: &wal.SchemaChange{
+				SchemaName: testSchemaName,
+				SchemaID:   testSchemaID,
+				Version:    logEntry.Version,
+				Schema:     logEntry.Schema,
+				Diff:       diff,
+			},
+		},
+	}
+}
+
 func newTestDataEvent(action string) *wal.Event {
 	cols := []wal.Column{
 		{ID: "col-1", Name: "col-1", Type: "text", Value: "id-1"},
@@ -30,12 +30,16 @@ type Translator struct {
 	schemaLogStore       schemalog.Store
 	idFinder             columnFinder
 	versionFinder        columnFinder
+	emitSchemaChanges    bool
 }
 
 type walToLogEntryAdapter func(*wal.Data) (*schemalog.LogEntry, error)
 
 type Config struct {
 	Store schemalogpg.Config
+	// EmitSchemaChangeEvents enables a "DDL" wal event being sent to the
+	// processor after every acked schema log entry. Defaults to false.
+	EmitSchemaChangeEvents bool
 }
 
 // configurable filters that allow the user of this library to have flexibility
@@ -70,7 +74,8 @@ func New(cfg *Config, p processor.Processor, opts ...Option) (*Translator, error
 		skipDataEvent:   func(*wal.Data) bool { return false },
 		skipSchemaEvent: func(*schemalog.LogEntry) bool { return false },
 		// by default we look for the primary key to use as identity column
-		idFinder: primaryKeyFinder,
+		idFinder:          primaryKeyFinder,
+		emitSchemaChanges: cfg.EmitSchemaChangeEvents,
 	}
 
 	for _, opt := range opts {
@@ -131,6 +136,9 @@ func (t *Translator) ProcessWALEvent(ctx context.Context, event *wal.Event) erro
 	}
 
 	switch {
+	case data.IsSchemaChange():
+		// schema change events have already been translated (i.e. when
+		// consumed from kafka), pass them on as is
 	case isSchemaLogSchema(data.Schema):
 		// this happens when a write occurs to the `table_ids` table or if the
 		// schema log table rows are acked
@@ -146,9 +154,31 @@ func (t *Translator) ProcessWALEvent(ctx context.Context, event *wal.Event) erro
 			return nil
 		}
 
+		var previous *schemalog.LogEntry
+		if t.emitSchemaChanges {
+			// retrieve the previous acked entry before acking the new one, so
+			// that the schema change diff can be computed. The event is not
+			// processed if it can't be retrieved, since the diff would report
+			// the whole schema as added.
+			previous, err = t.fetchPreviousLogEntry(ctx, logEntry.SchemaName)
+			if err != nil {
+				return fmt.Errorf("fetching previous schema log for schema %s: %w", logEntry.SchemaName, err)
+			}
+		}
+
 		if err := t.schemaLogStore.Ack(ctx, logEntry); err != nil {
 			t.logger.Error(err, "ack schema log")
 		}
+
+		if err := t.processor.ProcessWALEvent(ctx, event); err != nil {
+			return err
+		}
+
+		if !t.emitSchemaChanges {
+			return nil
+		}
+
+		return t.processor.ProcessWALEvent(ctx, newSchemaChangeEvent(event, logEntry, previous))
 	default:
 		// by default, we translate columns and pass on the event. If we fail to
 		// translate, log a DATALOSS severity error and continue processing the
@@ -184,6 +214,17 @@ func (t *Translator) Close() error {
 	return t.schemaLogStore.Close()
 }
 
+func (t *Translator) fetchPreviousLogEntry(ctx context.Context, schemaName string) (*schemalog.LogEntry, error) {
+	previous, err := t.schemaLogStore.Fetch(ctx, schemaName, true)
+	if err != nil {
+		if errors.Is(err, schemalog.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return previous, nil
+}
+
 func (t *Translator) translate(ctx context.Context, data *wal.Data) error {
 	if data == nil {
 		return nil
@@ -260,6 +301,7 @@ func (t *Translator) translateColumnNames(event *wal.Data, schemaTable *schemalo
 			return fmt.Errorf("failed to find column in table %s: %w", schemaTable.Name, processor.ErrColumnNotFound)
 		}
 		event.Columns[i].ID = schemaCol.PgstreamID
+		event.Columns[i].TypeInfo = schemaCol.TypeInfo
 	}
 
 	for i, col := range event.Identity { // should only be filled if event.Type is "D" or "U"
@@ -268,10 +310,35 @@ func (t *Translator) translateColumnNames(event *wal.Data, schemaTable *schemalo
 			return fmt.Errorf("failed to find column in table: %s: %w", schemaTable.Name, processor.ErrColumnNotFound)
 		}
 		event.Identity[i].ID = schemaCol.PgstreamID
+		event.Identity[i].TypeInfo = schemaCol.TypeInfo
 	}
 	return nil
 }
 
+// newSchemaChangeEvent returns a "DDL" wal event for the acked log entry on
+// input. It shares the commit position of the original schema log event.
+func newSchemaChangeEvent(event *wal.Event, logEntry, previous *schemalog.LogEntry) *wal.Event {
+	return &wal.Event{
+		Data: &wal.Data{
+			Action:    wal.SchemaChangeAction,
+			Timestamp: event.Data.Timestamp,
+			LSN:       event.Data.LSN,
+			Schema:    logEntry.SchemaName,
+			Metadata: wal.Metadata{
+				SchemaID: logEntry.ID,
+			},
+			SchemaChange: &wal.SchemaChange{
+				SchemaName: logEntry.SchemaName,
+				SchemaID:   logEntry.ID,
+				Version:    logEntry.Version,
+				Schema:     logEntry.Schema,
+				Diff:       logEntry.Diff(previous),
+			},
+		},
+		CommitPosition: event.CommitPosition,
+	}
+}
+
 func isSchemaLogSchema(schema string) bool {
 	return schema == schemalog.SchemaName
 }
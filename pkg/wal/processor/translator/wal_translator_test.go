@@ -4,6 +4,7 @@ package translator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -30,6 +31,7 @@ func TestTranslator_ProcessWALEvent(t *testing.T) {
 		skipSchemaEvent schemaEventFilter
 		idFinder        columnFinder
 		processor       processor.Processor
+		emitSchema      bool
 
 		wantErr error
 	}{
@@ -81,6 +83,91 @@ func TestTranslator_ProcessWALEvent(t *testing.T) {
 
 			wantErr: nil,
 		},
+		{
+			name:       "ok - schema event with schema change event",
+			event:      newTestSchemaChangeEvent("I"),
+			emitSchema: true,
+			store: &schemalogmocks.Store{
+				FetchFn: func(ctx context.Context, schemaName string, ackedOnly bool) (*schemalog.LogEntry, error) {
+					require.Equal(t, testSchemaName, schemaName)
+					require.True(t, ackedOnly)
+					return nil, schemalog.ErrNoRows
+				},
+				AckFn: func(ctx context.Context, le *schemalog.LogEntry) error {
+					return nil
+				},
+			},
+			processor: &mocks.Processor{
+				ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+					if !walEvent.Data.IsSchemaChange() {
+						require.Equal(t, newTestSchemaChangeEvent("I"), walEvent)
+						return nil
+					}
+					require.Equal(t, newTestDDLEvent(&schemalog.SchemaDiff{
+						TablesAdded:  testLogEntry.Schema.Tables,
+						ColumnsToAdd: testLogEntry.Schema.Tables[0].Columns,
+					}), walEvent)
+					return nil
+				},
+			},
+
+			wantErr: nil,
+		},
+		{
+			name:       "error - schema event with schema change event and fetch error",
+			event:      newTestSchemaChangeEvent("I"),
+			emitSchema: true,
+			store: &schemalogmocks.Store{
+				FetchFn: func(ctx context.Context, schemaName string, ackedOnly bool) (*schemalog.LogEntry, error) {
+					return nil, errTest
+				},
+				AckFn: func(ctx context.Context, le *schemalog.LogEntry) error {
+					return errors.New("AckFn: should not be called")
+				},
+			},
+			processor: &mocks.Processor{
+				ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+					return fmt.Errorf("ProcessWALEventFn: should not be called with event %v", walEvent)
+				},
+			},
+
+			wantErr: errTest,
+		},
+		{
+			name:  "ok - ddl event",
+			event: newTestDDLEvent(&schemalog.SchemaDiff{}),
+			processor: &mocks.Processor{
+				ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+					require.Equal(t, newTestDDLEvent(&schemalog.SchemaDiff{}), walEvent)
+					return nil
+				},
+			},
+
+			wantErr: nil,
+		},
+		{
+			name:       "error - processing schema event with schema change event",
+			event:      newTestSchemaChangeEvent("I"),
+			emitSchema: true,
+			store: &schemalogmocks.Store{
+				FetchFn: func(ctx context.Context, schemaName string, ackedOnly bool) (*schemalog.LogEntry, error) {
+					return testLogEntry, nil
+				},
+				AckFn: func(ctx context.Context, le *schemalog.LogEntry) error {
+					return nil
+				},
+			},
+			processor: &mocks.Processor{
+				ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+					if walEvent.Data.IsSchemaChange() {
+						return errTest
+					}
+					return nil
+				},
+			},
+
+			wantErr: errTest,
+		},
 		{
 			name:  "ok - fail to ack schema event",
 			event: newTestSchemaChangeEvent("I"),
@@ -195,6 +282,7 @@ func TestTranslator_ProcessWALEvent(t *testing.T) {
 				idFinder:             func(c *schemalog.Column, _ *schemalog.Table) bool { return c.Name == "col-1" },
 				versionFinder:        func(c *schemalog.Column, _ *schemalog.Table) bool { return c.Name == "col-2" },
 				walToLogEntryAdapter: func(d *wal.Data) (*schemalog.LogEntry, error) { return testLogEntry, nil },
+				emitSchemaChanges:    tc.emitSchema,
 			}
 
 			if tc.idFinder != nil {
@@ -287,6 +375,27 @@ func TestTranslator_translate(t *testing.T) {
 			}(),
 			wantErr: nil,
 		},
+		{
+			name: "ok - user defined column type info",
+			store: &schemalogmocks.Store{
+				FetchFn: func(ctx context.Context, schemaName string, ackedOnly bool) (*schemalog.LogEntry, error) {
+					require.Equal(t, testSchemaName, schemaName)
+					l := newTestLogEntry()
+					l.Schema.Tables[0].Columns[1].TypeInfo = &schemalog.TypeInfo{Category: schemalog.TypeCategoryDomain, BaseType: "integer"}
+					return l, nil
+				},
+			},
+			data:          newTestDataEvent("I").Data,
+			idFinder:      primaryKeyFinder,
+			versionFinder: func(c *schemalog.Column, _ *schemalog.Table) bool { return c.Name == "col-2" },
+
+			wantData: func() *wal.Data {
+				d := newTestDataEventWithMetadata("I").Data
+				d.Columns[1].TypeInfo = &schemalog.TypeInfo{Category: schemalog.TypeCategoryDomain, BaseType: "integer"}
+				return d
+			}(),
+			wantErr: nil,
+		},
 		{
 			name: "error - fetching schema log entry",
 			store: &schemalogmocks.Store{
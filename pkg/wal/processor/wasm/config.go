@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import "time"
+
+type Config struct {
+	// Modules is the list of WASM modules used to transform the events. Each
+	// event is transformed by the first module matching its table, and events
+	// not matching any module are not transformed.
+	Modules []ModuleConfig
+	// Timeout is the max execution time of a transform call. Defaults to
+	// 100ms.
+	Timeout time.Duration
+	// MaxMemoryBytes is the max memory a module instance can use. It's
+	// rounded down to the 64KiB WASM page size. Defaults to 16MiB.
+	MaxMemoryBytes int64
+	// ReloadInterval is the interval at which the module files are checked
+	// for changes, in which case they're reloaded. Defaults to 5s.
+	ReloadInterval time.Duration
+}
+
+type ModuleConfig struct {
+	// Path to the WASM module file.
+	Path string
+	// Tables is the list of tables the module applies to, in the
+	// `<schema>.<table>` format. Wildcards are supported (i.e, `public.*`).
+	Tables []string
+}
+
+const (
+	defaultTimeout        = 100 * time.Millisecond
+	defaultMaxMemoryBytes = int64(16 * 1024 * 1024) // 16MiB
+	defaultReloadInterval = 5 * time.Second
+
+	wasmPageSize = 64 * 1024
+)
+
+func (c *Config) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+func (c *Config) maxMemoryPages() uint32 {
+	maxMemoryBytes := defaultMaxMemoryBytes
+	if c.MaxMemoryBytes > 0 {
+		maxMemoryBytes = c.MaxMemoryBytes
+	}
+	return uint32(max(maxMemoryBytes/wasmPageSize, 1))
+}
+
+func (c *Config) reloadInterval() time.Duration {
+	if c.ReloadInterval > 0 {
+		return c.ReloadInterval
+	}
+	return defaultReloadInterval
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// module is a compiled WASM module exporting the transform ABI:
+//
+//   - `memory`: the module memory.
+//   - `alloc(size i32) i32`: returns a pointer to a buffer of the size on
+//     input, where the event JSON is written.
+//   - `transform(ptr i32, len i32) i64`: transforms the event JSON at the
+//     pointer on input, and returns the pointer (high 32 bits) and length
+//     (low 32 bits) of the output JSON.
+//   - `dealloc(ptr i32, size i32)`: optional, called to release the input and
+//     output buffers once processed.
+//
+// A single instance is kept and reused across calls, and it's recreated after
+// a failed call, since its state can't be trusted anymore.
+type module struct {
+	path     string
+	tables   []string
+	modTime  time.Time
+	size     int64
+	compiled wazero.CompiledModule
+	instance api.Module
+}
+
+const (
+	memoryExport    = "memory"
+	allocExport     = "alloc"
+	transformExport = "transform"
+	deallocExport   = "dealloc"
+)
+
+var (
+	errMissingExport = errors.New("missing module export")
+	errMemoryAccess  = errors.New("module memory access out of range")
+)
+
+func loadModule(ctx context.Context, runtime wazero.Runtime, cfg ModuleConfig) (*module, error) {
+	info, err := os.Stat(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := compileModule(ctx, runtime, cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &module{
+		path:     cfg.Path,
+		tables:   cfg.Tables,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		compiled: compiled,
+	}, nil
+}
+
+func compileModule(ctx context.Context, runtime wazero.Runtime, path string) (wazero.CompiledModule, error) {
+	wasmBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := runtime.CompileModule(ctx, wasmBytes)
+	if err != nil {
+		return nil, fmt.Errorf("compiling module %s: %w", path, err)
+	}
+
+	if _, found := compiled.ExportedMemories()[memoryExport]; !found {
+		compiled.Close(ctx)
+		return nil, fmt.Errorf("%w: %s memory", errMissingExport, memoryExport)
+	}
+	exportedFunctions := compiled.ExportedFunctions()
+	for _, fn := range []string{allocExport, transformExport} {
+		if _, found := exportedFunctions[fn]; !found {
+			compiled.Close(ctx)
+			return nil, fmt.Errorf("%w: %s function", errMissingExport, fn)
+		}
+	}
+	return compiled, nil
+}
+
+// reload recompiles the module if the file has changed since it was loaded.
+// It returns true if the module was reloaded. The current module is kept if
+// the new one can't be loaded.
+func (m *module) reload(ctx context.Context, runtime wazero.Runtime) (bool, error) {
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(m.modTime) && info.Size() == m.size {
+		return false, nil
+	}
+
+	compiled, err := compileModule(ctx, runtime, m.path)
+	if err != nil {
+		return false, err
+	}
+
+	m.close(ctx)
+	m.compiled = compiled
+	m.modTime = info.ModTime()
+	m.size = info.Size()
+	return true, nil
+}
+
+// isFor returns true if the module applies to the schema table on input.
+func (m *module) isFor(schema, table string) bool {
+	for _, pattern := range m.tables {
+		schemaPattern, tablePattern, found := strings.Cut(pattern, ".")
+		if !found {
+			tablePattern = "*"
+		}
+		if match, _ := path.Match(schemaPattern, schema); !match {
+			continue
+		}
+		if match, _ := path.Match(tablePattern, table); match {
+			return true
+		}
+	}
+	return false
+}
+
+// transform calls the module transform function with the input on input, and
+// returns its output.
+func (m *module) transform(ctx context.Context, runtime wazero.Runtime, input []byte) (_ []byte, retErr error) {
+	if m.instance == nil {
+		instance, err := runtime.InstantiateModule(ctx, m.compiled, wazero.NewModuleConfig().
+			// reactor modules (i.e. built with wasi) need to be initialised,
+			// without running their main function
+			WithStartFunctions("_initialize").
+			// the instances are anonymous, so that the same module can be
+			// instantiated more than once
+			WithName(""))
+		if err != nil {
+			return nil, fmt.Errorf("instantiating module: %w", err)
+		}
+		m.instance = instance
+	}
+	defer func() {
+		if retErr != nil {
+			m.closeInstance(ctx)
+		}
+	}()
+
+	memory := m.instance.Memory()
+	res, err := m.instance.ExportedFunction(allocExport).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("allocating input: %w", err)
+	}
+	inputPtr := uint32(res[0])
+	if !memory.Write(inputPtr, input) {
+		return nil, fmt.Errorf("writing input: %w", errMemoryAccess)
+	}
+
+	res, err = m.instance.ExportedFunction(transformExport).Call(ctx, uint64(inputPtr), uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("calling transform: %w", err)
+	}
+	outputPtr, outputLen := uint32(res[0]>>32), uint32(res[0])
+
+	var output []byte
+	if outputLen > 0 {
+		view, ok := memory.Read(outputPtr, outputLen)
+		if !ok {
+			return nil, fmt.Errorf("reading output: %w", errMemoryAccess)
+		}
+		// the memory view is only valid until the next call
+		output = append([]byte{}, view...)
+	}
+
+	if dealloc := m.instance.ExportedFunction(deallocExport); dealloc != nil {
+		if _, err := dealloc.Call(ctx, uint64(inputPtr), uint64(len(input))); err != nil {
+			return nil, fmt.Errorf("releasing input: %w", err)
+		}
+		if outputLen > 0 {
+			if _, err := dealloc.Call(ctx, uint64(outputPtr), uint64(outputLen)); err != nil {
+				return nil, fmt.Errorf("releasing output: %w", err)
+			}
+		}
+	}
+
+	return output, nil
+}
+
+func (m *module) closeInstance(ctx context.Context) {
+	if m.instance != nil {
+		m.instance.Close(ctx)
+		m.instance = nil
+	}
+}
+
+func (m *module) close(ctx context.Context) {
+	m.closeInstance(ctx)
+	if m.compiled != nil {
+		m.compiled.Close(ctx)
+	}
+}
@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero"
+)
+
+// test module transform function bodies, with (ptr i32, len i32) params and an
+// i64 result
+var (
+	// returns the input unchanged
+	identityTransform = []byte{
+		0x20, 0x00, // local.get 0
+		0xad,       // i64.extend_i32_u
+		0x42, 0x20, // i64.const 32
+		0x86,       // i64.shl
+		0x20, 0x01, // local.get 1
+		0xad, // i64.extend_i32_u
+		0x84, // i64.or
+		0x0b, // end
+	}
+	// returns an empty output
+	dropTransform = []byte{0x42, 0x00, 0x0b}
+	// never returns
+	loopTransform = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b}
+	// traps
+	unreachableTransform = []byte{0x00, 0x0b}
+)
+
+const testDataOffset = 2048
+
+// constantTransform returns a transform body that outputs the data segment
+// of the module.
+func constantTransform(output string) []byte {
+	body := []byte{0x42}
+	body = appendSLEB128(body, int64(testDataOffset)<<32|int64(len(output)))
+	return append(body, 0x0b)
+}
+
+// buildTestModule assembles a WASM module exporting the transform ABI, with
+// an alloc function that always returns the same buffer, and the output on
+// input as data segment.
+func buildTestModule(memoryPages uint32, transformBody []byte, output string) []byte {
+	wasm := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+	// types: (i32) -> i32 and (i32, i32) -> i64
+	wasm = appendSection(wasm, 1, []byte{0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e})
+	// functions: alloc and transform
+	wasm = appendSection(wasm, 3, []byte{0x02, 0x00, 0x01})
+	// memory
+	wasm = appendSection(wasm, 5, appendULEB128([]byte{0x01, 0x00}, uint64(memoryPages)))
+	// exports
+	exports := []byte{0x03}
+	exports = appendName(exports, memoryExport)
+	exports = append(exports, 0x02, 0x00)
+	exports = appendName(exports, allocExport)
+	exports = append(exports, 0x00, 0x00)
+	exports = appendName(exports, transformExport)
+	exports = append(exports, 0x00, 0x01)
+	wasm = appendSection(wasm, 7, exports)
+	// code
+	allocBody := []byte{0x00, 0x41, 0x80, 0x08, 0x0b} // i32.const 1024
+	transform := append([]byte{0x00}, transformBody...)
+	code := []byte{0x02}
+	code = appendULEB128(code, uint64(len(allocBody)))
+	code = append(code, allocBody...)
+	code = appendULEB128(code, uint64(len(transform)))
+	code = append(code, transform...)
+	wasm = appendSection(wasm, 10, code)
+	// data
+	if output != "" {
+		data := []byte{0x01, 0x00, 0x41}
+		data = appendSLEB128(data, testDataOffset)
+		data = append(data, 0x0b)
+		data = appendULEB128(data, uint64(len(output)))
+		data = append(data, output...)
+		wasm = appendSection(wasm, 11, data)
+	}
+	return wasm
+}
+
+func appendSection(wasm []byte, id byte, content []byte) []byte {
+	wasm = append(wasm, id)
+	wasm = appendULEB128(wasm, uint64(len(content)))
+	return append(wasm, content...)
+}
+
+func appendName(b []byte, name string) []byte {
+	b = appendULEB128(b, uint64(len(name)))
+	return append(b, name...)
+}
+
+func appendULEB128(b []byte, v uint64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func appendSLEB128(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func writeTestModule(t *testing.T, dir, name string, wasm []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, wasm, 0o644))
+	return path
+}
+
+func newTestRuntime(t *testing.T, memoryPages uint32) wazero.Runtime {
+	t.Helper()
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(memoryPages).
+		WithCloseOnContextDone(true))
+	t.Cleanup(func() { runtime.Close(ctx) })
+	return runtime
+}
+
+func TestModule_transform(t *testing.T) {
+	t.Parallel()
+
+	input := []byte(`{"action":"I"}`)
+
+	tests := []struct {
+		name        string
+		wasm        []byte
+		memoryLimit uint32
+		timeout     time.Duration
+
+		wantOutput  []byte
+		wantLoadErr bool
+		wantErr     bool
+	}{
+		{
+			name: "ok - identity",
+			wasm: buildTestModule(1, identityTransform, ""),
+
+			wantOutput: input,
+		},
+		{
+			name: "ok - constant",
+			wasm: buildTestModule(1, constantTransform(`[{"action":"U"}]`), `[{"action":"U"}]`),
+
+			wantOutput: []byte(`[{"action":"U"}]`),
+		},
+		{
+			name: "ok - drop",
+			wasm: buildTestModule(1, dropTransform, ""),
+
+			wantOutput: nil,
+		},
+		{
+			name:    "error - timeout",
+			wasm:    buildTestModule(1, loopTransform, ""),
+			timeout: 50 * time.Millisecond,
+
+			wantErr: true,
+		},
+		{
+			name: "error - trap",
+			wasm: buildTestModule(1, unreachableTransform, ""),
+
+			wantErr: true,
+		},
+		{
+			name:        "error - memory over limit",
+			wasm:        buildTestModule(10, identityTransform, ""),
+			memoryLimit: 2,
+
+			wantLoadErr: true,
+		},
+		{
+			name: "error - invalid module",
+			wasm: []byte("not wasm"),
+
+			wantLoadErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			memoryLimit := uint32(16)
+			if tc.memoryLimit > 0 {
+				memoryLimit = tc.memoryLimit
+			}
+			runtime := newTestRuntime(t, memoryLimit)
+			path := writeTestModule(t, t.TempDir(), "module.wasm", tc.wasm)
+
+			ctx := context.Background()
+			m, err := loadModule(ctx, runtime, ModuleConfig{Path: path})
+			if tc.wantLoadErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			timeout := time.Second
+			if tc.timeout > 0 {
+				timeout = tc.timeout
+			}
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			output, err := m.transform(callCtx, runtime, input)
+			if tc.wantErr {
+				require.Error(t, err)
+				// the failed instance is discarded
+				require.Nil(t, m.instance)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantOutput, output)
+
+			// the instance is reused across calls
+			instance := m.instance
+			output, err = m.transform(ctx, runtime, input)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantOutput, output)
+			require.Equal(t, instance, m.instance)
+		})
+	}
+}
+
+func TestModule_isFor(t *testing.T) {
+	t.Parallel()
+
+	m := &module{tables: []string{"public.users", "audit.*", "reporting"}}
+
+	require.True(t, m.isFor("public", "users"))
+	require.False(t, m.isFor("public", "teams"))
+	require.True(t, m.isFor("audit", "logs"))
+	require.True(t, m.isFor("reporting", "daily"))
+	require.False(t, m.isFor("other", "users"))
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Transformer is a wrapper around a processor that transforms the wal events
+// with the WASM modules configured for their table before passing them on.
+// A transform can return zero, one or many events, as a JSON null, object or
+// array respectively.
+type Transformer struct {
+	processor processor.Processor
+	logger    loglib.Logger
+	runtime   wazero.Runtime
+	modules   []*module
+
+	timeout         time.Duration
+	reloadInterval  time.Duration
+	lastReloadCheck time.Time
+	losslessNumbers bool
+	now             func() time.Time
+}
+
+type Option func(t *Transformer)
+
+// New will return a transformer processor wrapper that applies the WASM
+// modules on input to the wal data events before passing them over to the
+// processor on input.
+func New(ctx context.Context, cfg *Config, p processor.Processor, opts ...Option) (*Transformer, error) {
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(cfg.maxMemoryPages()).
+		// makes sure the calls are interrupted once their timeout is reached
+		WithCloseOnContextDone(true))
+	// modules built with wasi support (i.e. tinygo or rust wasm32-wasi) need
+	// the wasi host functions
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("instantiating wasi: %w", err)
+	}
+
+	t := &Transformer{
+		processor:      p,
+		logger:         loglib.NewNoopLogger(),
+		runtime:        runtime,
+		timeout:        cfg.timeout(),
+		reloadInterval: cfg.reloadInterval(),
+		now:            time.Now,
+	}
+
+	for _, moduleCfg := range cfg.Modules {
+		m, err := loadModule(ctx, runtime, moduleCfg)
+		if err != nil {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("loading module %s: %w", moduleCfg.Path, err)
+		}
+		t.modules = append(t.modules, m)
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.lastReloadCheck = t.now()
+
+	return t, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(t *Transformer) {
+		t.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "wasm_transformer",
+		})
+	}
+}
+
+// WithLosslessNumbers decodes the numeric values of the transformed events
+// without precision loss, to be consistent with the listener decoding.
+func WithLosslessNumbers() Option {
+	return func(t *Transformer) {
+		t.losslessNumbers = true
+	}
+}
+
+// ProcessWALEvent transforms the wal event with the module for its table, if
+// any, and passes the resulting events to the wrapped processor. Only the
+// last resulting event carries the commit position, so that it's not
+// checkpointed before all of them have been processed. If the transform
+// returns no events, the position is passed on as a keep alive.
+func (t *Transformer) ProcessWALEvent(ctx context.Context, event *wal.Event) error {
+	if event.Data == nil || processor.IsSchemaLogEvent(event.Data) {
+		return t.processor.ProcessWALEvent(ctx, event)
+	}
+
+	t.reloadModules(ctx)
+
+	m := t.moduleFor(event.Data)
+	if m == nil {
+		return t.processor.ProcessWALEvent(ctx, event)
+	}
+
+	input, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("wasm transformer: marshalling event: %w", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	output, err := m.transform(callCtx, t.runtime, input)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("wasm transformer: module %s: %w", m.path, err)
+	}
+
+	transformed, err := t.unmarshalEvents(output)
+	if err != nil {
+		return fmt.Errorf("wasm transformer: module %s: unmarshalling output: %w", m.path, err)
+	}
+
+	if len(transformed) == 0 {
+		if event.CommitPosition == "" {
+			return nil
+		}
+		return t.processor.ProcessWALEvent(ctx, &wal.Event{CommitPosition: event.CommitPosition})
+	}
+
+	for i, data := range transformed {
+		transformedEvent := &wal.Event{Data: data}
+		if i == len(transformed)-1 {
+			transformedEvent.CommitPosition = event.CommitPosition
+		}
+		if err := t.processor.ProcessWALEvent(ctx, transformedEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Transformer) Name() string {
+	return t.processor.Name()
+}
+
+func (t *Transformer) Close() error {
+	return t.runtime.Close(context.Background())
+}
+
+func (t *Transformer) moduleFor(data *wal.Data) *module {
+	for _, m := range t.modules {
+		if m.isFor(data.Schema, data.Table) {
+			return m
+		}
+	}
+	return nil
+}
+
+// reloadModules reloads the modules whose file has changed, once the reload
+// interval has elapsed. Modules that fail to reload keep the previous
+// version.
+func (t *Transformer) reloadModules(ctx context.Context) {
+	if t.now().Sub(t.lastReloadCheck) < t.reloadInterval {
+		return
+	}
+	t.lastReloadCheck = t.now()
+
+	for _, m := range t.modules {
+		reloaded, err := m.reload(ctx, t.runtime)
+		if err != nil {
+			t.logger.Error(err, "wasm transformer: reloading module, keeping previous version", loglib.Fields{
+				"module": m.path,
+			})
+			continue
+		}
+		if reloaded {
+			t.logger.Info("wasm transformer: module reloaded", loglib.Fields{
+				"module": m.path,
+			})
+		}
+	}
+}
+
+// unmarshalEvents decodes the transform output, which can be a JSON object
+// for a single event, an array for multiple events, or null/empty for no
+// events.
+func (t *Transformer) unmarshalEvents(output []byte) ([]*wal.Data, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	if t.losslessNumbers {
+		decoder.UseNumber()
+	}
+
+	if output[0] == '[' {
+		decoded := []*wal.Data{}
+		if err := decoder.Decode(&decoded); err != nil {
+			return nil, err
+		}
+		events := make([]*wal.Data, 0, len(decoded))
+		for _, data := range decoded {
+			if data != nil {
+				events = append(events, data)
+			}
+		}
+		return events, nil
+	}
+
+	var data *wal.Data
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return []*wal.Data{data}, nil
+}
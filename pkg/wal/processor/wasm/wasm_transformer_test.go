@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformer_ProcessWALEvent(t *testing.T) {
+	t.Parallel()
+
+	newEvent := func(schema, table string) *wal.Event {
+		return &wal.Event{
+			CommitPosition: "0/1",
+			Data: &wal.Data{
+				Action: "I",
+				LSN:    "0/1",
+				Schema: schema,
+				Table:  table,
+				Columns: []wal.Column{
+					{ID: "c1", Name: "id", Type: "bigint", Value: json.Number("9007199254740993")},
+				},
+			},
+		}
+	}
+
+	const splitOutput = `[{"action":"I","schema":"public","table":"a"},null,{"action":"I","schema":"public","table":"b"}]`
+	errTest := errors.New("oh noes")
+
+	tests := []struct {
+		name      string
+		wasm      []byte
+		event     *wal.Event
+		processFn func(context.Context, *wal.Event) error
+
+		wantEvents []*wal.Event
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:  "ok - identity",
+			wasm:  buildTestModule(1, identityTransform, ""),
+			event: newEvent("public", "users"),
+
+			wantEvents: []*wal.Event{newEvent("public", "users")},
+		},
+		{
+			name:  "ok - no module for table",
+			wasm:  buildTestModule(1, unreachableTransform, ""),
+			event: newEvent("public", "teams"),
+
+			wantEvents: []*wal.Event{newEvent("public", "teams")},
+		},
+		{
+			name: "ok - schema log event",
+			wasm: buildTestModule(1, unreachableTransform, ""),
+			event: &wal.Event{Data: &wal.Data{
+				Action: "I",
+				Schema: schemalog.SchemaName,
+				Table:  schemalog.TableName,
+			}},
+
+			wantEvents: []*wal.Event{{Data: &wal.Data{
+				Action: "I",
+				Schema: schemalog.SchemaName,
+				Table:  schemalog.TableName,
+			}}},
+		},
+		{
+			name:  "ok - keep alive",
+			wasm:  buildTestModule(1, unreachableTransform, ""),
+			event: &wal.Event{CommitPosition: "0/1"},
+
+			wantEvents: []*wal.Event{{CommitPosition: "0/1"}},
+		},
+		{
+			name:  "ok - dropped",
+			wasm:  buildTestModule(1, dropTransform, ""),
+			event: newEvent("public", "users"),
+
+			wantEvents: []*wal.Event{{CommitPosition: "0/1"}},
+		},
+		{
+			name:  "ok - split",
+			wasm:  buildTestModule(1, constantTransform(splitOutput), splitOutput),
+			event: newEvent("public", "users"),
+
+			wantEvents: []*wal.Event{
+				{Data: &wal.Data{Action: "I", Schema: "public", Table: "a"}},
+				{Data: &wal.Data{Action: "I", Schema: "public", Table: "b"}, CommitPosition: "0/1"},
+			},
+		},
+		{
+			name:  "error - invalid output",
+			wasm:  buildTestModule(1, constantTransform(`{`), `{`),
+			event: newEvent("public", "users"),
+
+			wantEvents: []*wal.Event{},
+			wantAnyErr: true,
+		},
+		{
+			name:  "error - transform",
+			wasm:  buildTestModule(1, unreachableTransform, ""),
+			event: newEvent("public", "users"),
+
+			wantEvents: []*wal.Event{},
+			wantAnyErr: true,
+		},
+		{
+			name:  "error - processing event",
+			wasm:  buildTestModule(1, identityTransform, ""),
+			event: newEvent("public", "users"),
+			processFn: func(context.Context, *wal.Event) error {
+				return errTest
+			},
+
+			wantEvents: []*wal.Event{},
+			wantErr:    errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			path := writeTestModule(t, t.TempDir(), "module.wasm", tc.wasm)
+
+			events := []*wal.Event{}
+			p := &mocks.Processor{
+				ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+					if tc.processFn != nil {
+						return tc.processFn(ctx, walEvent)
+					}
+					events = append(events, walEvent)
+					return nil
+				},
+			}
+
+			transformer, err := New(ctx, &Config{
+				Modules: []ModuleConfig{{Path: path, Tables: []string{"public.users"}}},
+			}, p, WithLosslessNumbers())
+			require.NoError(t, err)
+			defer transformer.Close()
+
+			err = transformer.ProcessWALEvent(ctx, tc.event)
+			switch {
+			case tc.wantAnyErr:
+				require.Error(t, err)
+			default:
+				require.ErrorIs(t, err, tc.wantErr)
+			}
+			require.Equal(t, tc.wantEvents, events)
+		})
+	}
+}
+
+func TestTransformer_reload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := writeTestModule(t, dir, "module.wasm", buildTestModule(1, identityTransform, ""))
+
+	events := []*wal.Event{}
+	p := &mocks.Processor{
+		ProcessWALEventFn: func(ctx context.Context, walEvent *wal.Event) error {
+			events = append(events, walEvent)
+			return nil
+		},
+	}
+
+	now := time.Now()
+	transformer, err := New(ctx, &Config{
+		Modules:        []ModuleConfig{{Path: path, Tables: []string{"*"}}},
+		ReloadInterval: time.Minute,
+	}, p, func(t *Transformer) {
+		t.now = func() time.Time { return now }
+	})
+	require.NoError(t, err)
+	defer transformer.Close()
+
+	event := &wal.Event{CommitPosition: "0/1", Data: &wal.Data{Action: "I", Schema: "public", Table: "users"}}
+	require.NoError(t, transformer.ProcessWALEvent(ctx, event))
+	require.Equal(t, []*wal.Event{event}, events)
+
+	// the module is replaced, but it's not reloaded until the interval has
+	// elapsed
+	dropModule := buildTestModule(1, dropTransform, "")
+	require.NoError(t, os.WriteFile(path, dropModule, 0o644))
+	modTime := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	events = []*wal.Event{}
+	require.NoError(t, transformer.ProcessWALEvent(ctx, event))
+	require.Equal(t, []*wal.Event{event}, events)
+
+	now = now.Add(time.Minute)
+	events = []*wal.Event{}
+	require.NoError(t, transformer.ProcessWALEvent(ctx, event))
+	require.Equal(t, []*wal.Event{{CommitPosition: "0/1"}}, events)
+
+	// an invalid module keeps the previous version
+	require.NoError(t, os.WriteFile(path, []byte("not wasm"), 0o644))
+	modTime = modTime.Add(time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	now = now.Add(time.Minute)
+	events = []*wal.Event{}
+	require.NoError(t, transformer.ProcessWALEvent(ctx, event))
+	require.Equal(t, []*wal.Event{{CommitPosition: "0/1"}}, events)
+}
@@ -2,7 +2,11 @@
 
 package notifier
 
-import "time"
+import (
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/backoff"
+)
 
 type Config struct {
 	// MaxQueueBytes is the max memory used by the webhook notifier for inflight
@@ -16,10 +20,44 @@ type Config struct {
 	ClientTimeout time.Duration
 }
 
+// RetrierConfig configures the asynchronous retries of the webhook
+// deliveries stored in the outbox.
+type RetrierConfig struct {
+	// Backoff configuration for the retries of each delivery. Defaults to an
+	// exponential backoff with a 1s initial interval, for up to 1m and 5
+	// retries.
+	Backoff backoff.Config
+	// PollInterval is the interval at which the outbox is checked for pending
+	// deliveries. Defaults to 10s.
+	PollInterval time.Duration
+	// WorkerCount is the max number of deliveries retried concurrently.
+	// Defaults to 10.
+	WorkerCount uint
+	// DisableThreshold is the number of consecutive failed deliveries in the
+	// outbox for an endpoint url after which its subscriptions are disabled.
+	// Defaults to 10.
+	DisableThreshold uint
+	// DisableWindow is the period of time over which the failed deliveries
+	// are counted towards the disable threshold. Failures before the last
+	// successful delivery to the url are never counted. Defaults to 1h.
+	DisableWindow time.Duration
+	// ClientTimeout is the max time the retrier will wait for a response from
+	// a webhook url before it times out. Defaults to 10s.
+	ClientTimeout time.Duration
+}
+
 const (
 	defaultMaxQueueBytes  = int64(100 * 1024 * 1024) // 100MiB
 	defaultURLWorkerCount = 10
 	defaultClientTimeout  = 10 * time.Second
+
+	defaultRetryInitialInterval = time.Second
+	defaultRetryMaxInterval     = time.Minute
+	defaultRetryMaxRetries      = 5
+	defaultRetryPollInterval    = 10 * time.Second
+	defaultRetryWorkerCount     = 10
+	defaultDisableThreshold     = 10
+	defaultDisableWindow        = time.Hour
 )
 
 func (c *Config) maxQueueBytes() int64 {
@@ -45,3 +83,51 @@ func (c *Config) clientTimeout() time.Duration {
 
 	return defaultClientTimeout
 }
+
+func (c *RetrierConfig) backoffConfig() *backoff.Config {
+	if c.Backoff.Exponential != nil || c.Backoff.Constant != nil {
+		return &c.Backoff
+	}
+	return &backoff.Config{
+		Exponential: &backoff.ExponentialConfig{
+			InitialInterval: defaultRetryInitialInterval,
+			MaxInterval:     defaultRetryMaxInterval,
+			MaxRetries:      defaultRetryMaxRetries,
+		},
+	}
+}
+
+func (c *RetrierConfig) pollInterval() time.Duration {
+	if c.PollInterval > 0 {
+		return c.PollInterval
+	}
+	return defaultRetryPollInterval
+}
+
+func (c *RetrierConfig) workerCount() uint {
+	if c.WorkerCount > 0 {
+		return c.WorkerCount
+	}
+	return defaultRetryWorkerCount
+}
+
+func (c *RetrierConfig) disableThreshold() uint {
+	if c.DisableThreshold > 0 {
+		return c.DisableThreshold
+	}
+	return defaultDisableThreshold
+}
+
+func (c *RetrierConfig) disableWindow() time.Duration {
+	if c.DisableWindow > 0 {
+		return c.DisableWindow
+	}
+	return defaultDisableWindow
+}
+
+func (c *RetrierConfig) clientTimeout() time.Duration {
+	if c.ClientTimeout > 0 {
+		return c.ClientTimeout
+	}
+	return defaultClientTimeout
+}
@@ -24,8 +24,12 @@ func newTestSubscription(url, schema, table string, eventTypes []string) *subscr
 }
 
 func testNotifyMsg(urls []string, payload []byte) *notifyMsg {
+	subscriptions := make([]*subscription.Subscription, 0, len(urls))
+	for _, url := range urls {
+		subscriptions = append(subscriptions, newTestSubscription(url, "", "", nil))
+	}
 	return &notifyMsg{
-		urls:           urls,
+		subscriptions:  subscriptions,
 		payload:        payload,
 		commitPosition: testCommitPos,
 	}
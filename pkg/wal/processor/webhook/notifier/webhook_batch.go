@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notifier
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+)
+
+// batch accumulates the event payloads for a subscription with batched
+// deliveries, until any of its limits is reached. The payloads are sent as a
+// JSON array.
+type batch struct {
+	subscription *subscription.Subscription
+	payloads     [][]byte
+	// size is the size of the JSON array payload
+	size int
+	// firstSeq is the sequence of the oldest notify msg in the batch. The
+	// commit positions from that msg onwards are not checkpointed until the
+	// batch is delivered.
+	firstSeq  uint64
+	createdAt time.Time
+
+	maxEvents int
+	maxBytes  int
+	maxWait   time.Duration
+}
+
+const (
+	defaultBatchMaxEvents = 100
+	defaultBatchMaxBytes  = 1024 * 1024 // 1MiB
+	defaultBatchMaxWait   = time.Second
+)
+
+func newBatch(s *subscription.Subscription, seq uint64, now time.Time) *batch {
+	b := &batch{
+		subscription: s,
+		size:         len("[]"),
+		firstSeq:     seq,
+		createdAt:    now,
+		maxEvents:    defaultBatchMaxEvents,
+		maxBytes:     defaultBatchMaxBytes,
+		maxWait:      defaultBatchMaxWait,
+	}
+	if s.Batch != nil {
+		if s.Batch.MaxEvents > 0 {
+			b.maxEvents = s.Batch.MaxEvents
+		}
+		if s.Batch.MaxBytes > 0 {
+			b.maxBytes = s.Batch.MaxBytes
+		}
+		if s.Batch.MaxWaitMs > 0 {
+			b.maxWait = time.Duration(s.Batch.MaxWaitMs) * time.Millisecond
+		}
+	}
+	return b
+}
+
+func (b *batch) add(payload []byte) {
+	if len(b.payloads) > 0 {
+		b.size++ // separator
+	}
+	b.payloads = append(b.payloads, payload)
+	b.size += len(payload)
+}
+
+// fits returns true if the payload can be added to the batch without going
+// over the max bytes. Empty batches always fit the payload.
+func (b *batch) fits(payload []byte) bool {
+	return len(b.payloads) == 0 || b.size+len(payload)+1 <= b.maxBytes
+}
+
+func (b *batch) isFull() bool {
+	return len(b.payloads) >= b.maxEvents || b.size >= b.maxBytes
+}
+
+func (b *batch) isExpired(now time.Time) bool {
+	return now.Sub(b.createdAt) >= b.maxWait
+}
+
+func (b *batch) payload() []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, b.size))
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(b.payloads, []byte(",")))
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
@@ -6,11 +6,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"runtime/debug"
+	"slices"
 	"sync"
+	"time"
 
 	httplib "github.com/ApollosProject/pgstream-wal2json/internal/http"
 	synclib "github.com/ApollosProject/pgstream-wal2json/internal/sync"
@@ -18,6 +21,10 @@ import (
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxstore "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
 )
 
@@ -28,17 +35,66 @@ type Notifier struct {
 	logger            loglib.Logger
 	checkpointer      checkpointer.Checkpoint
 	subscriptionStore subscriptionRetriever
-	serialiser        serialiser
+	// outboxStore keeps the deliveries that failed, so that they can be
+	// retried asynchronously. Failed deliveries are dropped if not set.
+	outboxStore outboxstore.Store
+	// pendingOutbox keeps track of whether the subscriptions have pending
+	// deliveries in the outbox, by subscription key. New deliveries for
+	// those subscriptions are queued in the outbox behind the pending ones,
+	// so that they're delivered in order.
+	pendingOutboxMutex *sync.Mutex
+	pendingOutbox      map[string]bool
+	// stats keeps track of the delivery attempts per subscription. Optional.
+	stats      *subscription.StatsRegistry
+	serialiser serialiser
 	// queueBytesSema is used to limit the amount of memory used by the
 	// unbuffered msg channel, optimising the channel performance for variable
 	// size messages, while preventing the process from running oom
 	queueBytesSema synclib.WeightedSemaphore
 	notifyChan     chan *notifyMsg
 	workerCount    uint
+	now            func() time.Time
+
+	// batches are the pending batches of the subscriptions with batched
+	// deliveries, by subscription key. They're only accessed from the
+	// Notify loop.
+	batches map[string]*batch
+	// pendingPositions are the commit positions of the notified msgs that
+	// can't be checkpointed yet, since some of their events are in pending
+	// batches.
+	pendingPositions   []pendingPosition
+	msgSeq             uint64
+	batchCheckInterval time.Duration
+
+	// expressions are the compiled subscription expressions, by source.
+	// They're only accessed from ProcessWALEvent.
+	expressions map[string]*filter.Expression
 }
 
+type pendingPosition struct {
+	seq      uint64
+	position wal.CommitPosition
+}
+
+// webhookDelivery is a payload to be sent to a subscription, either for a
+// single event or for a batch of events.
+type webhookDelivery struct {
+	subscription *subscription.Subscription
+	payload      []byte
+}
+
+const (
+	defaultBatchCheckInterval = 100 * time.Millisecond
+	// maxCachedExpressions bounds the compiled expressions kept, since the
+	// ones from deleted subscriptions are never removed otherwise
+	maxCachedExpressions = 1000
+	// maxCachedOutboxStates bounds the subscriptions for which the pending
+	// outbox state is kept, for the same reason
+	maxCachedOutboxStates = 1000
+)
+
 type subscriptionRetriever interface {
-	GetSubscriptions(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error)
+	GetSubscriptions(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error)
 }
 
 type Option func(*Notifier)
@@ -49,10 +105,16 @@ func New(cfg *Config, store subscriptionRetriever, opts ...Option) *Notifier {
 		client: &http.Client{
 			Timeout: cfg.clientTimeout(),
 		},
-		subscriptionStore: store,
-		notifyChan:        make(chan *notifyMsg),
-		workerCount:       cfg.workerCount(),
-		serialiser:        json.Marshal,
+		subscriptionStore:  store,
+		notifyChan:         make(chan *notifyMsg),
+		workerCount:        cfg.workerCount(),
+		serialiser:         json.Marshal,
+		now:                time.Now,
+		batches:            map[string]*batch{},
+		batchCheckInterval: defaultBatchCheckInterval,
+		expressions:        map[string]*filter.Expression{},
+		pendingOutboxMutex: &sync.Mutex{},
+		pendingOutbox:      map[string]bool{},
 	}
 
 	// this allows us to bound and configure the memory used by the internal msg
@@ -74,6 +136,25 @@ func WithLogger(l loglib.Logger) Option {
 	}
 }
 
+// WithOutbox enables the delivery outbox. Deliveries that fail are stored in
+// the outbox instead of being dropped, and the events are only checkpointed
+// once they've been delivered or stored. The following deliveries for the
+// subscription are stored in the outbox as well until the pending ones have
+// been retried, so that they're delivered in order.
+func WithOutbox(store outboxstore.Store) Option {
+	return func(n *Notifier) {
+		n.outboxStore = store
+	}
+}
+
+// WithStats records the result of every delivery attempt in the stats
+// registry on input.
+func WithStats(stats *subscription.StatsRegistry) Option {
+	return func(n *Notifier) {
+		n.stats = stats
+	}
+}
+
 func WithCheckpoint(c checkpointer.Checkpoint) Option {
 	return func(n *Notifier) {
 		n.checkpointer = c
@@ -95,11 +176,19 @@ func (n *Notifier) ProcessWALEvent(ctx context.Context, walEvent *wal.Event) (er
 	subscriptions := []*subscription.Subscription{}
 	if walEvent.Data != nil {
 		data := walEvent.Data
-		subscriptions, err = n.subscriptionStore.GetSubscriptions(ctx, data.Action, data.Schema, data.Table)
+		row := subscription.NewRow(data)
+		subscriptions, err = n.subscriptionStore.GetSubscriptions(ctx, data.Action, data.Schema, data.Table, row)
 		if err != nil {
 			return fmt.Errorf("retrieving subscriptions: %w", err)
 		}
-		n.logger.Debug("matching subscriptions", loglib.Fields{"subscriptions": subscriptions})
+		// the stores can narrow down the subscriptions with looser value
+		// comparisons (i.e. jsonb containment), so the column filters are
+		// evaluated for all of them to keep consistent semantics. The
+		// expressions can't be evaluated by the stores.
+		subscriptions = slices.DeleteFunc(subscriptions, func(s *subscription.Subscription) bool {
+			return !s.IsForRow(row) || !n.matchesExpression(s, data)
+		})
+		n.logger.Debug("matching subscriptions", loglib.Fields{"subscriptions": subscriptionKeys(subscriptions)})
 	}
 
 	msg, err := newNotifyMsg(walEvent, subscriptions, n.serialiser)
@@ -123,17 +212,60 @@ func (n *Notifier) ProcessWALEvent(ctx context.Context, walEvent *wal.Event) (er
 	return nil
 }
 
+// matchesExpression returns true if the subscription has no expression or if
+// the wal data matches it. Expressions that can't be compiled or evaluated
+// don't match.
+func (n *Notifier) matchesExpression(s *subscription.Subscription, data *wal.Data) bool {
+	if s.Expression == "" {
+		return true
+	}
+
+	expr, found := n.expressions[s.Expression]
+	if !found {
+		var err error
+		if expr, err = filter.Compile(s.Expression); err != nil {
+			n.logger.Warn(err, "webhook notifier: invalid subscription expression", loglib.Fields{
+				"subscription": s.Key(),
+			})
+			return false
+		}
+		if len(n.expressions) >= maxCachedExpressions {
+			clear(n.expressions)
+		}
+		n.expressions[s.Expression] = expr
+	}
+
+	match, err := expr.Matches(data)
+	if err != nil {
+		n.logger.Trace("subscription expression not matched", loglib.Fields{
+			"subscription": s.Key(),
+			"error":        err.Error(),
+		})
+	}
+	return match
+}
+
 func (n *Notifier) Notify(ctx context.Context) error {
+	// the batches are checked periodically, so that they're sent once their
+	// max wait is reached even if no more events are received
+	ticker := time.NewTicker(n.batchCheckInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-ticker.C:
+			if err := n.flushExpiredBatches(ctx); err != nil {
+				n.logger.Error(err, "sending webhook batches")
+				return fmt.Errorf("sending webhook batches: %w", err)
+			}
 		case msg := <-n.notifyChan:
 			err := n.notify(ctx, msg)
 			n.queueBytesSema.Release(int64(msg.size()))
 			if err != nil {
 				n.logger.Error(err, "sending webhook event", loglib.Fields{
-					"urls":            msg.urls,
+					"urls":            msg.urls(),
 					"commit position": msg.commitPosition,
 					"payload":         string(msg.payload),
 				})
@@ -153,25 +285,146 @@ func (n *Notifier) Close() error {
 }
 
 func (n *Notifier) notify(ctx context.Context, msg *notifyMsg) error {
-	n.logger.Trace("notifying", loglib.Fields{"urls": msg.urls})
-	if len(msg.urls) > 0 {
-		urlChan := make(chan string, n.workerCount)
-		wg := &sync.WaitGroup{}
-		for i := 0; i < int(n.workerCount); i++ {
-			wg.Add(1)
-			go n.webhookWorker(ctx, wg, msg.payload, urlChan)
+	n.logger.Trace("notifying", loglib.Fields{"urls": msg.urls()})
+	n.msgSeq++
+	n.pendingPositions = append(n.pendingPositions, pendingPosition{seq: n.msgSeq, position: msg.commitPosition})
+
+	// deliveries are grouped by subscription, so that the ones for the same
+	// subscription are sent in order
+	deliveries := map[string][]*webhookDelivery{}
+	for _, s := range msg.subscriptions {
+		key := s.Key()
+		if s.Batch != nil {
+			deliveries[key] = append(deliveries[key], n.addToBatch(s, msg.payload)...)
+			continue
+		}
+		// send any pending batch first if batching has been disabled for
+		// the subscription
+		if _, found := n.batches[key]; found {
+			deliveries[key] = append(deliveries[key], n.closeBatch(key))
 		}
+		deliveries[key] = append(deliveries[key], &webhookDelivery{subscription: s, payload: msg.payload})
+	}
+	n.closeExpiredBatches(deliveries)
+
+	if err := n.deliver(ctx, deliveries); err != nil {
+		return err
+	}
+
+	return n.checkpoint(ctx)
+}
 
-		for _, url := range msg.urls {
-			urlChan <- url
+// addToBatch adds the payload to the pending batch of the subscription, and
+// returns the deliveries for the batches that are ready to be sent.
+func (n *Notifier) addToBatch(s *subscription.Subscription, payload []byte) []*webhookDelivery {
+	key := s.Key()
+	deliveries := []*webhookDelivery{}
+	b, found := n.batches[key]
+	if found && !b.fits(payload) {
+		deliveries = append(deliveries, n.closeBatch(key))
+		found = false
+	}
+	if !found {
+		b = newBatch(s, n.msgSeq, n.now())
+		n.batches[key] = b
+	}
+
+	// keep the latest subscription, so that the latest credentials are used
+	b.subscription = s
+	b.add(payload)
+	if b.isFull() {
+		deliveries = append(deliveries, n.closeBatch(key))
+	}
+	return deliveries
+}
+
+func (n *Notifier) closeBatch(key string) *webhookDelivery {
+	b := n.batches[key]
+	delete(n.batches, key)
+	return &webhookDelivery{subscription: b.subscription, payload: b.payload()}
+}
+
+func (n *Notifier) closeExpiredBatches(deliveries map[string][]*webhookDelivery) {
+	now := n.now()
+	for key, b := range n.batches {
+		if b.isExpired(now) {
+			deliveries[key] = append(deliveries[key], n.closeBatch(key))
 		}
+	}
+}
+
+func (n *Notifier) flushExpiredBatches(ctx context.Context) error {
+	deliveries := map[string][]*webhookDelivery{}
+	n.closeExpiredBatches(deliveries)
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := n.deliver(ctx, deliveries); err != nil {
+		return err
+	}
+
+	return n.checkpoint(ctx)
+}
+
+// deliver sends the deliveries in parallel across subscriptions, and in order
+// for each subscription.
+func (n *Notifier) deliver(ctx context.Context, deliveries map[string][]*webhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, d := range deliveries {
+		total += len(d)
+	}
 
-		close(urlChan)
-		wg.Wait()
+	deliveryChan := make(chan []*webhookDelivery, n.workerCount)
+	errChan := make(chan error, total)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < int(n.workerCount); i++ {
+		wg.Add(1)
+		go n.webhookWorker(ctx, wg, deliveryChan, errChan)
 	}
 
+	for _, d := range deliveries {
+		deliveryChan <- d
+	}
+
+	close(deliveryChan)
+	wg.Wait()
+	close(errChan)
+
+	// don't checkpoint the position if any of the failed deliveries
+	// couldn't be stored, since they'd be lost
+	errs := []error{}
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// checkpoint checkpoints the latest commit position for which all the events
+// up to it have been delivered. The positions of the events in pending
+// batches are held back until the batches are sent.
+func (n *Notifier) checkpoint(ctx context.Context) error {
+	oldestPending := n.msgSeq + 1
+	for _, b := range n.batches {
+		oldestPending = min(oldestPending, b.firstSeq)
+	}
+
+	i := 0
+	for i < len(n.pendingPositions) && n.pendingPositions[i].seq < oldestPending {
+		i++
+	}
+	if i == 0 {
+		return nil
+	}
+	position := n.pendingPositions[i-1].position
+	n.pendingPositions = n.pendingPositions[i:]
+
 	if n.checkpointer != nil {
-		if err := n.checkpointer(ctx, []wal.CommitPosition{msg.commitPosition}); err != nil {
+		if err := n.checkpointer(ctx, []wal.CommitPosition{position}); err != nil {
 			return fmt.Errorf("checkpointing commit position: %w", err)
 		}
 	}
@@ -179,39 +432,163 @@ func (n *Notifier) notify(ctx context.Context, msg *notifyMsg) error {
 	return nil
 }
 
-func (n *Notifier) webhookWorker(ctx context.Context, wg *sync.WaitGroup, payload []byte, urls <-chan string) {
+func (n *Notifier) webhookWorker(ctx context.Context, wg *sync.WaitGroup, deliveries <-chan []*webhookDelivery, errChan chan<- error) {
 	defer wg.Done()
-	for url := range urls {
-		if err := n.sendWebhook(ctx, payload, url); err != nil {
+	for subscriptionDeliveries := range deliveries {
+		if err := n.deliverInOrder(ctx, subscriptionDeliveries); err != nil {
+			errChan <- err
+		}
+	}
+}
+
+// deliverInOrder sends the deliveries for a subscription in order. Once the
+// subscription has pending deliveries in the outbox, the following ones are
+// queued behind them instead of being sent.
+func (n *Notifier) deliverInOrder(ctx context.Context, deliveries []*webhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	queued, err := n.hasPendingDeliveries(ctx, deliveries[0].subscription)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		if queued {
+			if err := n.storeDelivery(ctx, d.subscription, outbox.NewQueuedDelivery(d.subscription, d.payload)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := n.sendWebhook(ctx, d.payload, d.subscription); err != nil {
 			n.logger.Error(err, "sending webhook payload", loglib.Fields{
-				"payload": payload,
-				"url":     url,
+				"payload": d.payload,
+				"url":     d.subscription.URL,
 			})
-			continue
+			if err := n.storeFailedDelivery(ctx, d.payload, d.subscription, err); err != nil {
+				return err
+			}
+			queued = n.outboxStore != nil
 		}
 	}
+	return nil
 }
 
-func (n *Notifier) sendWebhook(ctx context.Context, payload []byte, url string) error {
-	n.logger.Trace("sending webhook", loglib.Fields{"url": url})
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payload))
+// hasPendingDeliveries returns true if the subscription has pending deliveries
+// in the outbox. The outbox is only checked again for subscriptions that had
+// pending deliveries, since new ones are only stored by the notifier.
+func (n *Notifier) hasPendingDeliveries(ctx context.Context, s *subscription.Subscription) (bool, error) {
+	if n.outboxStore == nil {
+		return false, nil
+	}
+
+	key := s.Key()
+	n.pendingOutboxMutex.Lock()
+	pending, found := n.pendingOutbox[key]
+	n.pendingOutboxMutex.Unlock()
+	if found && !pending {
+		return false, nil
+	}
+
+	count, err := n.outboxStore.CountDeliveries(ctx, &outbox.Filter{
+		Status:         outbox.StatusPending,
+		SubscriptionID: s.ID,
+		URL:            s.URL,
+		Schema:         s.Schema,
+		Table:          s.Table,
+	})
+	if err != nil {
+		return false, fmt.Errorf("checking pending webhook deliveries for %s: %w", s.URL, err)
+	}
+	n.setPendingDeliveries(key, count > 0)
+	return count > 0, nil
+}
+
+func (n *Notifier) setPendingDeliveries(key string, pending bool) {
+	n.pendingOutboxMutex.Lock()
+	defer n.pendingOutboxMutex.Unlock()
+	if _, found := n.pendingOutbox[key]; !found && len(n.pendingOutbox) >= maxCachedOutboxStates {
+		clear(n.pendingOutbox)
+	}
+	n.pendingOutbox[key] = pending
+}
+
+func (n *Notifier) storeFailedDelivery(ctx context.Context, payload []byte, s *subscription.Subscription, sendErr error) error {
+	if n.outboxStore == nil {
+		n.logger.Warn(sendErr, "webhook notifier: outbox disabled, failed delivery dropped", loglib.Fields{
+			"url": s.URL,
+		})
+		return nil
+	}
+	return n.storeDelivery(ctx, s, outbox.NewDelivery(s, payload, sendErr))
+}
+
+func (n *Notifier) storeDelivery(ctx context.Context, s *subscription.Subscription, d *outbox.Delivery) error {
+	if err := n.outboxStore.CreateDelivery(ctx, d); err != nil {
+		return fmt.Errorf("storing webhook delivery for %s: %w", s.URL, err)
+	}
+	n.setPendingDeliveries(s.Key(), true)
+	return nil
+}
+
+func (n *Notifier) sendWebhook(ctx context.Context, payload []byte, s *subscription.Subscription) error {
+	n.logger.Trace("sending webhook", loglib.Fields{"url": s.URL})
+	now := n.now()
+	err := sendWebhook(ctx, n.client, now, payload, s)
+	n.stats.RecordDelivery(s.ID, now, err)
+	return err
+}
+
+// sendWebhook posts the payload on input to the subscription url, signing it
+// with the timestamp on input if the subscription has a secret.
+func sendWebhook(ctx context.Context, client httplib.Client, timestamp time.Time, payload []byte, s *subscription.Subscription) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewBuffer(payload))
 	if err != nil {
 		return fmt.Errorf("building webhook payload request: %w", err)
 	}
+	setRequestHeaders(req, timestamp, payload, s)
 
-	resp, err := n.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("sending webhook payload request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	// any 2xx status is a successful delivery, since receivers can answer
+	// with 202 or 204 when they process the notifications asynchronously
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		return fmt.Errorf("error response from payload request, status code: %s, body: %v", resp.Status, getResponseBody(resp.Body))
 	}
 
 	return nil
 }
 
+// setRequestHeaders adds the subscription static headers and credentials to
+// the webhook request. The pgstream headers are set last so that they can't
+// be overwritten by the static headers.
+func setRequestHeaders(req *http.Request, timestamp time.Time, payload []byte, s *subscription.Subscription) {
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+	if s.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.BearerToken)
+	}
+	if s.Secret != "" {
+		webhook.SetSignatureHeaders(req.Header, s.Secret, timestamp, payload)
+	}
+}
+
+func subscriptionKeys(subscriptions []*subscription.Subscription) []string {
+	keys := make([]string, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		keys = append(keys, s.Key())
+	}
+	return keys
+}
+
 func getResponseBody(respBody io.ReadCloser) string {
 	bodyBytes, err := io.ReadAll(respBody)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"testing"
@@ -19,6 +20,8 @@ import (
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/checkpointer"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxmocks "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store/mocks"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store/mocks"
 	"github.com/stretchr/testify/require"
@@ -43,6 +46,18 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 	testPayload, err := json.Marshal(&webhook.Payload{Data: testEvent.Data})
 	require.NoError(t, err)
 
+	testRowEvent := &wal.Event{
+		Data: &wal.Data{
+			Action:  "I",
+			Schema:  "test_schema",
+			Table:   "test_table",
+			Columns: []wal.Column{{Name: "status", Value: "paid"}},
+		},
+		CommitPosition: testCommitPos,
+	}
+	testRowPayload, err := json.Marshal(&webhook.Payload{Data: testRowEvent.Data})
+	require.NoError(t, err)
+
 	tests := []struct {
 		name              string
 		store             subscriptionRetriever
@@ -56,7 +71,7 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 		{
 			name: "ok - no subscriptions for event",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return []*subscription.Subscription{}, nil
 				},
 			},
@@ -74,7 +89,7 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 		{
 			name: "ok - subscriptions for event",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return []*subscription.Subscription{
 						testSubscription("url-1"), testSubscription("url-2"),
 					}, nil
@@ -93,10 +108,78 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "ok - subscriptions filtered by row",
+			store: &mocks.Store{
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+					require.Equal(t, &subscription.Row{
+						ChangedColumns: []string{"status"},
+						NewValues:      map[string]any{"status": "paid"},
+						OldValues:      map[string]any{},
+					}, row)
+					filtered := testSubscription("url-2")
+					filtered.NewValues = map[string]any{"status": "pending"}
+					return []*subscription.Subscription{
+						testSubscription("url-1"), filtered,
+					}, nil
+				},
+			},
+			weightedSemaphore: &syncmocks.WeightedSemaphore{
+				TryAcquireFn: func(i int64) bool {
+					require.Equal(t, int64(len(testRowPayload)+len("url-1")), i)
+					return true
+				},
+			},
+			event: testRowEvent,
+
+			wantMsgs: []*notifyMsg{
+				testNotifyMsg([]string{"url-1"}, testRowPayload),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ok - subscriptions filtered by expression",
+			store: &mocks.Store{
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+					matching := testSubscription("url-1")
+					matching.Expression = `action == "I" && new.status == "paid"`
+					filtered := testSubscription("url-2")
+					filtered.Expression = `new.status == "pending"`
+					// evaluation errors don't match
+					missingColumn := testSubscription("url-3")
+					missingColumn.Expression = `old.status == "paid"`
+					invalid := testSubscription("url-4")
+					invalid.Expression = `new.status ==`
+					return []*subscription.Subscription{matching, filtered, missingColumn, invalid}, nil
+				},
+			},
+			weightedSemaphore: &syncmocks.WeightedSemaphore{
+				TryAcquireFn: func(i int64) bool {
+					require.Equal(t, int64(len(testRowPayload)+len("url-1")), i)
+					return true
+				},
+			},
+			event: testRowEvent,
+
+			wantMsgs: []*notifyMsg{
+				{
+					subscriptions: []*subscription.Subscription{
+						func() *subscription.Subscription {
+							s := testSubscription("url-1")
+							s.Expression = `action == "I" && new.status == "paid"`
+							return s
+						}(),
+					},
+					payload:        testRowPayload,
+					commitPosition: testCommitPos,
+				},
+			},
+			wantErr: nil,
+		},
 		{
 			name: "error - getting subscriptions",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return nil, errTest
 				},
 			},
@@ -108,7 +191,7 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 		{
 			name: "error - serialising payload",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return []*subscription.Subscription{
 						testSubscription("url-1"), testSubscription("url-2"),
 					}, nil
@@ -123,7 +206,7 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 		{
 			name: "error - acquiring semaphore",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return []*subscription.Subscription{
 						testSubscription("url-1"), testSubscription("url-2"),
 					}, nil
@@ -141,7 +224,7 @@ func TestNotifier_ProcessWALEvent(t *testing.T) {
 		{
 			name: "error - panic recovery",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					panic(errTest)
 				},
 			},
@@ -337,3 +420,323 @@ func TestNotifier_Notify(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifier_sendWebhook(t *testing.T) {
+	t.Parallel()
+
+	testPayload := []byte(`{"Data":{"action":"I"}}`)
+	testNow := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name         string
+		subscription *subscription.Subscription
+
+		wantHeaders http.Header
+	}{
+		{
+			name:         "no credentials",
+			subscription: newTestSubscription("url-1", "", "", nil),
+
+			wantHeaders: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+		},
+		{
+			name: "with credentials",
+			subscription: &subscription.Subscription{
+				URL:         "url-1",
+				Secret:      "test-secret",
+				BearerToken: "test-token",
+				Headers: map[string]string{
+					"X-Api-Key":     "test-key",
+					"Authorization": "overwritten",
+				},
+			},
+
+			wantHeaders: http.Header{
+				"Content-Type":          []string{"application/json"},
+				"X-Api-Key":             []string{"test-key"},
+				"Authorization":         []string{"Bearer test-token"},
+				webhook.TimestampHeader: []string{"1700000000"},
+				webhook.SignatureHeader: []string{webhook.Sign("test-secret", testNow, testPayload)},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := New(&Config{}, &mocks.Store{})
+			n.now = func() time.Time { return testNow }
+			n.client = &httpmocks.Client{
+				DoFn: func(r *http.Request) (*http.Response, error) {
+					require.Equal(t, tc.wantHeaders, r.Header)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       http.NoBody,
+					}, nil
+				},
+			}
+
+			err := n.sendWebhook(context.Background(), testPayload, tc.subscription)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNotifier_notify_outbox(t *testing.T) {
+	t.Parallel()
+
+	testPayload := []byte("test payload")
+
+	tests := []struct {
+		name             string
+		pendingCount     int
+		createDeliveryFn func(ctx context.Context, d *outbox.Delivery) error
+
+		wantSent       bool
+		wantCheckpoint bool
+		wantErr        error
+	}{
+		{
+			name: "ok - failed delivery stored",
+			createDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+				require.Equal(t, &outbox.Delivery{
+					URL:       "url-1",
+					Payload:   testPayload,
+					Status:    outbox.StatusPending,
+					Attempts:  1,
+					LastError: "sending webhook payload request: oh noes",
+				}, d)
+				return nil
+			},
+
+			wantSent:       true,
+			wantCheckpoint: true,
+			wantErr:        nil,
+		},
+		{
+			name:         "ok - delivery queued behind pending deliveries",
+			pendingCount: 1,
+			createDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+				require.Equal(t, &outbox.Delivery{
+					URL:     "url-1",
+					Payload: testPayload,
+					Status:  outbox.StatusPending,
+				}, d)
+				return nil
+			},
+
+			wantSent:       false,
+			wantCheckpoint: true,
+			wantErr:        nil,
+		},
+		{
+			name: "error - storing failed delivery",
+			createDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+				return errTest
+			},
+
+			wantSent:       true,
+			wantCheckpoint: false,
+			wantErr:        errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkpointed, sent := false, false
+			n := New(&Config{}, &mocks.Store{},
+				WithOutbox(&outboxmocks.Store{
+					CreateDeliveryFn: tc.createDeliveryFn,
+					CountDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) (int, error) {
+						require.Equal(t, &outbox.Filter{Status: outbox.StatusPending, URL: "url-1"}, filter)
+						return tc.pendingCount, nil
+					},
+				}),
+				WithCheckpoint(func(ctx context.Context, positions []wal.CommitPosition) error {
+					checkpointed = true
+					return nil
+				}))
+			n.client = &httpmocks.Client{
+				DoFn: func(r *http.Request) (*http.Response, error) {
+					sent = true
+					return nil, errTest
+				},
+			}
+
+			err := n.notify(context.Background(), testNotifyMsg([]string{"url-1"}, testPayload))
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantSent, sent)
+			require.Equal(t, tc.wantCheckpoint, checkpointed)
+		})
+	}
+}
+
+func TestNotifier_deliverInOrder(t *testing.T) {
+	t.Parallel()
+
+	testSubscription := newTestSubscription("url-1", "", "", nil)
+	testDeliveries := func() []*webhookDelivery {
+		return []*webhookDelivery{
+			{subscription: testSubscription, payload: []byte(`{"id":1}`)},
+			{subscription: testSubscription, payload: []byte(`{"id":2}`)},
+			{subscription: testSubscription, payload: []byte(`{"id":3}`)},
+		}
+	}
+
+	countCalls := 0
+	pendingCount := 0
+	var sent, stored []string
+	n := New(&Config{}, &mocks.Store{}, WithOutbox(&outboxmocks.Store{
+		CreateDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+			stored = append(stored, string(d.Payload))
+			return nil
+		},
+		CountDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) (int, error) {
+			countCalls++
+			return pendingCount, nil
+		},
+	}))
+	n.client = &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			sent = append(sent, string(body))
+			if string(body) == `{"id":2}` {
+				return nil, errTest
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+	ctx := context.Background()
+
+	// the deliveries following a failed one are queued behind it
+	require.NoError(t, n.deliverInOrder(ctx, testDeliveries()))
+	require.Equal(t, []string{`{"id":1}`, `{"id":2}`}, sent)
+	require.Equal(t, []string{`{"id":2}`, `{"id":3}`}, stored)
+	require.Equal(t, 1, countCalls)
+
+	// the outbox is checked again while the subscription has pending
+	// deliveries
+	pendingCount = 2
+	sent, stored = nil, nil
+	require.NoError(t, n.deliverInOrder(ctx, testDeliveries()))
+	require.Empty(t, sent)
+	require.Equal(t, []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}, stored)
+	require.Equal(t, 2, countCalls)
+
+	// deliveries are sent again once the pending ones have been retried
+	pendingCount = 0
+	sent, stored = nil, nil
+	n.client = &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			sent = append(sent, string(body))
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+	require.NoError(t, n.deliverInOrder(ctx, testDeliveries()))
+	require.Equal(t, []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}, sent)
+	require.Empty(t, stored)
+	require.Equal(t, 3, countCalls)
+
+	// subscriptions known to have no pending deliveries are not checked
+	sent = nil
+	require.NoError(t, n.deliverInOrder(ctx, testDeliveries()))
+	require.Len(t, sent, 3)
+	require.Equal(t, 3, countCalls)
+}
+
+func TestNotifier_notify_batches(t *testing.T) {
+	t.Parallel()
+
+	batchedSubscription := newTestSubscription("url-1", "", "", nil)
+	batchedSubscription.Batch = &subscription.Batch{MaxEvents: 2, MaxWaitMs: 1000}
+	unbatchedSubscription := newTestSubscription("url-2", "", "", nil)
+
+	testMsg := func(pos string, payload string) *notifyMsg {
+		return &notifyMsg{
+			subscriptions:  []*subscription.Subscription{batchedSubscription, unbatchedSubscription},
+			payload:        []byte(payload),
+			commitPosition: wal.CommitPosition(pos),
+		}
+	}
+
+	mutex := &sync.Mutex{}
+	requests := map[string][]string{}
+	checkpoints := []wal.CommitPosition{}
+	now := time.Unix(1700000000, 0)
+
+	n := New(&Config{}, &mocks.Store{},
+		WithCheckpoint(func(ctx context.Context, positions []wal.CommitPosition) error {
+			checkpoints = append(checkpoints, positions...)
+			return nil
+		}))
+	n.now = func() time.Time { return now }
+	n.client = &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			mutex.Lock()
+			defer mutex.Unlock()
+			requests[r.URL.Path] = append(requests[r.URL.Path], string(body))
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+	ctx := context.Background()
+
+	// the first event is held in the batch, so its position is not
+	// checkpointed
+	require.NoError(t, n.notify(ctx, testMsg("pos-1", `{"id":1}`)))
+	require.Equal(t, map[string][]string{"url-2": {`{"id":1}`}}, requests)
+	require.Empty(t, checkpoints)
+
+	// the batch is sent once it reaches the max events
+	require.NoError(t, n.notify(ctx, testMsg("pos-2", `{"id":2}`)))
+	require.Equal(t, []string{`[{"id":1},{"id":2}]`}, requests["url-1"])
+	require.Equal(t, []wal.CommitPosition{"pos-2"}, checkpoints)
+
+	// the batch is sent once it reaches the max wait
+	require.NoError(t, n.notify(ctx, testMsg("pos-3", `{"id":3}`)))
+	require.NoError(t, n.notify(ctx, &notifyMsg{commitPosition: "pos-4"}))
+	require.Equal(t, []wal.CommitPosition{"pos-2"}, checkpoints)
+
+	require.NoError(t, n.flushExpiredBatches(ctx))
+	require.Equal(t, []string{`[{"id":1},{"id":2}]`}, requests["url-1"])
+
+	now = now.Add(time.Second)
+	require.NoError(t, n.flushExpiredBatches(ctx))
+	require.Equal(t, []string{`[{"id":1},{"id":2}]`, `[{"id":3}]`}, requests["url-1"])
+	require.Equal(t, []wal.CommitPosition{"pos-2", "pos-4"}, checkpoints)
+	require.Empty(t, n.batches)
+	require.Empty(t, n.pendingPositions)
+}
+
+func TestNotifier_addToBatch(t *testing.T) {
+	t.Parallel()
+
+	testSubscription := newTestSubscription("url-1", "", "", nil)
+	testSubscription.Batch = &subscription.Batch{MaxEvents: 10, MaxBytes: 16}
+
+	n := New(&Config{}, &mocks.Store{})
+
+	// payloads that don't fit close the pending batch
+	require.Empty(t, n.addToBatch(testSubscription, []byte(`{"id":1}`)))
+	deliveries := n.addToBatch(testSubscription, []byte(`{"id":2}`))
+	require.Len(t, deliveries, 1)
+	require.Equal(t, `[{"id":1}]`, string(deliveries[0].payload))
+
+	// payloads bigger than the max bytes are sent on their own
+	deliveries = n.addToBatch(testSubscription, []byte(`{"id":3,"name":"big"}`))
+	require.Len(t, deliveries, 2)
+	require.Equal(t, `[{"id":2}]`, string(deliveries[0].payload))
+	require.Equal(t, `[{"id":3,"name":"big"}]`, string(deliveries[1].payload))
+	require.Empty(t, n.batches)
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type notifyMsg struct {
-	urls           []string
+	subscriptions  []*subscription.Subscription
 	payload        []byte
 	commitPosition wal.CommitPosition
 }
@@ -20,21 +20,16 @@ type serialiser func(any) ([]byte, error)
 
 func newNotifyMsg(event *wal.Event, subscriptions []*subscription.Subscription, serialiser serialiser) (*notifyMsg, error) {
 	var payload []byte
-	urls := make([]string, 0, len(subscriptions))
 	if len(subscriptions) > 0 {
 		var err error
 		payload, err = serialiser(&webhook.Payload{Data: event.Data})
 		if err != nil {
 			return nil, fmt.Errorf("serialising webhook payload: %w", err)
 		}
-
-		for _, s := range subscriptions {
-			urls = append(urls, s.URL)
-		}
 	}
 
 	return &notifyMsg{
-		urls:           urls,
+		subscriptions:  subscriptions,
 		payload:        payload,
 		commitPosition: event.CommitPosition,
 	}, nil
@@ -42,8 +37,16 @@ func newNotifyMsg(event *wal.Event, subscriptions []*subscription.Subscription,
 
 func (m *notifyMsg) size() int {
 	urlSize := 0
-	for _, url := range m.urls {
-		urlSize += len(url)
+	for _, s := range m.subscriptions {
+		urlSize += len(s.URL)
 	}
 	return len(m.payload) + urlSize
 }
+
+func (m *notifyMsg) urls() []string {
+	urls := make([]string, 0, len(m.subscriptions))
+	for _, s := range m.subscriptions {
+		urls = append(urls, s.URL)
+	}
+	return urls
+}
@@ -0,0 +1,353 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	httplib "github.com/ApollosProject/pgstream-wal2json/internal/http"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/backoff"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxstore "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
+)
+
+// Retrier retries the webhook deliveries stored in the outbox asynchronously,
+// so that failing endpoints don't block the processing of the WAL. The
+// deliveries of each subscription are retried one at a time in the order they
+// were stored, each with its own backoff, while different subscriptions are
+// retried concurrently. Deliveries that
+// exhaust their retries are marked as failed, and the subscriptions of an
+// endpoint url are disabled once it accumulates too many failed deliveries
+// since its last successful one, within the disable window.
+type Retrier struct {
+	client            httplib.Client
+	logger            loglib.Logger
+	outboxStore       outboxstore.Store
+	subscriptionStore subscriptionManager
+	stats             *subscription.StatsRegistry
+	backoffProvider   backoff.Provider
+	pollInterval      time.Duration
+	disableThreshold  int
+	disableWindow     time.Duration
+	now               func() time.Time
+
+	// lastSuccess keeps the time of the last successful retry per url, so
+	// that the failures before it don't count towards disabling the url
+	lastSuccessMutex *sync.Mutex
+	lastSuccess      map[string]time.Time
+
+	// workerSema bounds the number of deliveries retried concurrently
+	workerSema chan struct{}
+	// inflight keeps track of the subscriptions whose deliveries are being
+	// retried, by subscription key, so that they're not picked up again by
+	// the next poll
+	inflightMutex *sync.Mutex
+	inflight      map[string]struct{}
+	wg            *sync.WaitGroup
+}
+
+type subscriptionManager interface {
+	subscriptionRetriever
+	GetSubscription(ctx context.Context, id string) (*subscription.Subscription, error)
+	DisableSubscriptions(ctx context.Context, url string) error
+}
+
+type RetrierOption func(*Retrier)
+
+var errSubscriptionNotFound = errors.New("subscription not found or disabled")
+
+func NewRetrier(cfg *RetrierConfig, outboxStore outboxstore.Store, subscriptionStore subscriptionManager, opts ...RetrierOption) *Retrier {
+	r := &Retrier{
+		client: &http.Client{
+			Timeout: cfg.clientTimeout(),
+		},
+		logger:            loglib.NewNoopLogger(),
+		outboxStore:       outboxStore,
+		subscriptionStore: subscriptionStore,
+		backoffProvider:   backoff.NewProvider(cfg.backoffConfig()),
+		pollInterval:      cfg.pollInterval(),
+		disableThreshold:  int(cfg.disableThreshold()),
+		disableWindow:     cfg.disableWindow(),
+		now:               time.Now,
+		lastSuccessMutex:  &sync.Mutex{},
+		lastSuccess:       map[string]time.Time{},
+		workerSema:        make(chan struct{}, cfg.workerCount()),
+		inflightMutex:     &sync.Mutex{},
+		inflight:          map[string]struct{}{},
+		wg:                &sync.WaitGroup{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func WithRetrierLogger(l loglib.Logger) RetrierOption {
+	return func(r *Retrier) {
+		r.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "webhook_retrier",
+		})
+	}
+}
+
+// WithRetrierStats records the result of every retry attempt in the stats
+// registry on input.
+func WithRetrierStats(stats *subscription.StatsRegistry) RetrierOption {
+	return func(r *Retrier) {
+		r.stats = stats
+	}
+}
+
+// Run polls the outbox for pending deliveries on the configured interval
+// until the context is cancelled. This call is blocking.
+func (r *Retrier) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.retryPending(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			r.logger.Error(err, "retrying pending webhook deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			r.wg.Wait()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Retrier) retryPending(ctx context.Context) error {
+	deliveries, err := r.outboxStore.GetDeliveries(ctx, &outbox.Filter{
+		Status:                outbox.StatusPending,
+		OldestPerSubscription: true,
+		Limit:                 cap(r.workerSema) * 10,
+	})
+	if err != nil {
+		return fmt.Errorf("getting pending deliveries: %w", err)
+	}
+
+	for _, d := range deliveries {
+		key := d.SubscriptionKey()
+		if !r.markInflight(key) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			r.unmarkInflight(key)
+			return ctx.Err()
+		case r.workerSema <- struct{}{}:
+		}
+
+		r.wg.Add(1)
+		go func(d *outbox.Delivery) {
+			defer func() {
+				<-r.workerSema
+				r.unmarkInflight(key)
+				r.wg.Done()
+			}()
+			r.retryInOrder(ctx, d)
+		}(d)
+	}
+
+	return nil
+}
+
+// retryInOrder retries the delivery on input, followed by the next pending
+// deliveries of the same subscription, until there are none left or one of
+// them is kept pending.
+func (r *Retrier) retryInOrder(ctx context.Context, d *outbox.Delivery) {
+	for ctx.Err() == nil {
+		if err := r.retry(ctx, d); err != nil {
+			r.logger.Error(err, "retrying webhook delivery", loglib.Fields{
+				"delivery_id": d.ID,
+				"url":         d.URL,
+			})
+			return
+		}
+
+		next, err := r.outboxStore.GetDeliveries(ctx, &outbox.Filter{
+			Status:         outbox.StatusPending,
+			SubscriptionID: d.SubscriptionID,
+			URL:            d.URL,
+			Schema:         d.Schema,
+			Table:          d.Table,
+			Limit:          1,
+		})
+		if err != nil {
+			r.logger.Error(err, "getting next pending webhook delivery", loglib.Fields{"url": d.URL})
+			return
+		}
+		// the delivery is still pending if the subscription is paused
+		if len(next) == 0 || next[0].ID == d.ID || next[0].SubscriptionKey() != d.SubscriptionKey() {
+			return
+		}
+		d = next[0]
+	}
+}
+
+// retry sends the delivery with the configured backoff. The delivery is
+// removed from the outbox once it succeeds, and marked as failed if it
+// exhausts the retries.
+func (r *Retrier) retry(ctx context.Context, d *outbox.Delivery) error {
+	s, err := r.getSubscription(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	var sendErr error
+	switch {
+	case s == nil:
+		sendErr = errSubscriptionNotFound
+	case s.Paused:
+		// paused subscriptions keep their deliveries pending until they're
+		// resumed
+		r.logger.Debug("skipping delivery for paused subscription", loglib.Fields{"delivery_id": d.ID, "subscription_id": s.ID})
+		return nil
+	default:
+		sendErr = r.backoffProvider(ctx).RetryNotify(
+			func() error {
+				d.Attempts++
+				now := r.now()
+				err := sendWebhook(ctx, r.client, now, d.Payload, s)
+				r.stats.RecordDelivery(s.ID, now, err)
+				return err
+			},
+			func(err error, retryAfter time.Duration) {
+				r.logger.Debug("webhook delivery failed, retrying", loglib.Fields{
+					"url":         s.URL,
+					"error":       err.Error(),
+					"retry_after": retryAfter.String(),
+				})
+			})
+	}
+
+	if sendErr == nil {
+		r.logger.Debug("webhook delivery retried successfully", loglib.Fields{"delivery_id": d.ID, "url": d.URL})
+		r.setLastSuccess(d.URL, r.now())
+		return r.outboxStore.DeleteDelivery(ctx, d.ID)
+	}
+
+	d.LastError = sendErr.Error()
+	if ctx.Err() != nil {
+		// keep the delivery pending so that it's retried on restart
+		return r.outboxStore.UpdateDelivery(context.Background(), d)
+	}
+
+	d.Status = outbox.StatusFailed
+	if err := r.outboxStore.UpdateDelivery(ctx, d); err != nil {
+		return fmt.Errorf("marking delivery as failed: %w", err)
+	}
+	r.logger.Warn(sendErr, "webhook delivery failed", loglib.Fields{
+		"delivery_id": d.ID,
+		"url":         d.URL,
+		"attempts":    d.Attempts,
+	})
+
+	return r.disableFailingEndpoint(ctx, d.URL)
+}
+
+// getSubscription returns the subscription the delivery belongs to, or nil if
+// it no longer exists or has been disabled. The subscription is retrieved on
+// every retry, so that the latest credentials are used.
+func (r *Retrier) getSubscription(ctx context.Context, d *outbox.Delivery) (*subscription.Subscription, error) {
+	if d.SubscriptionID != "" {
+		s, err := r.subscriptionStore.GetSubscription(ctx, d.SubscriptionID)
+		switch {
+		case errors.Is(err, store.ErrSubscriptionNotFound):
+			return nil, nil
+		case err != nil:
+			return nil, fmt.Errorf("retrieving subscription: %w", err)
+		case s.Disabled:
+			return nil, nil
+		default:
+			return s, nil
+		}
+	}
+
+	// deliveries stored before subscriptions had ids are matched by their
+	// url, schema and table
+	subscriptions, err := r.subscriptionStore.GetSubscriptions(ctx, "", d.Schema, d.Table, nil)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving subscriptions: %w", err)
+	}
+	for _, s := range subscriptions {
+		if d.IsFor(s) {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// disableFailingEndpoint disables the subscriptions of the url on input if it
+// reached the disable threshold of failed deliveries since the later of its
+// last successful retry and the start of the disable window.
+func (r *Retrier) disableFailingEndpoint(ctx context.Context, url string) error {
+	since := r.now().Add(-r.disableWindow)
+	if lastSuccess, found := r.getLastSuccess(url); found && lastSuccess.After(since) {
+		since = lastSuccess
+	}
+
+	failed, err := r.outboxStore.CountDeliveries(ctx, &outbox.Filter{
+		Status:       outbox.StatusFailed,
+		URL:          url,
+		UpdatedAfter: since,
+	})
+	if err != nil {
+		return fmt.Errorf("counting failed deliveries: %w", err)
+	}
+	if failed < r.disableThreshold {
+		return nil
+	}
+
+	r.logger.Warn(nil, "disabling subscriptions for failing webhook endpoint", loglib.Fields{
+		"url":               url,
+		"failed_deliveries": failed,
+		"disable_threshold": r.disableThreshold,
+	})
+	if err := r.subscriptionStore.DisableSubscriptions(ctx, url); err != nil {
+		return fmt.Errorf("disabling subscriptions: %w", err)
+	}
+	return nil
+}
+
+func (r *Retrier) getLastSuccess(url string) (time.Time, bool) {
+	r.lastSuccessMutex.Lock()
+	defer r.lastSuccessMutex.Unlock()
+	t, found := r.lastSuccess[url]
+	return t, found
+}
+
+func (r *Retrier) setLastSuccess(url string, t time.Time) {
+	r.lastSuccessMutex.Lock()
+	defer r.lastSuccessMutex.Unlock()
+	r.lastSuccess[url] = t
+}
+
+func (r *Retrier) markInflight(key string) bool {
+	r.inflightMutex.Lock()
+	defer r.inflightMutex.Unlock()
+	if _, found := r.inflight[key]; found {
+		return false
+	}
+	r.inflight[key] = struct{}{}
+	return true
+}
+
+func (r *Retrier) unmarkInflight(key string) {
+	r.inflightMutex.Lock()
+	defer r.inflightMutex.Unlock()
+	delete(r.inflight, key)
+}
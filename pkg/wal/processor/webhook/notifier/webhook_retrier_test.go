@@ -0,0 +1,569 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	httpmocks "github.com/ApollosProject/pgstream-wal2json/internal/http/mocks"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/backoff"
+	backoffmocks "github.com/ApollosProject/pgstream-wal2json/pkg/backoff/mocks"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxmocks "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store/mocks"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrier_retry(t *testing.T) {
+	t.Parallel()
+
+	testDelivery := func() *outbox.Delivery {
+		return &outbox.Delivery{
+			ID:       1,
+			URL:      "url-1",
+			Schema:   "test_schema",
+			Table:    "test_table",
+			Payload:  []byte(`{"Data":null}`),
+			Status:   outbox.StatusPending,
+			Attempts: 1,
+		}
+	}
+	testSubscription := newTestSubscription("url-1", "test_schema", "test_table", nil)
+	testNow := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	subscriptionStore := func(disabled *bool) *mocks.Store {
+		return &mocks.Store{
+			GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+				require.Equal(t, "test_schema", schema)
+				require.Equal(t, "test_table", table)
+				return []*subscription.Subscription{
+					newTestSubscription("url-2", "test_schema", "test_table", nil),
+					testSubscription,
+				}, nil
+			},
+			DisableSubscriptionsFn: func(ctx context.Context, url string) error {
+				require.Equal(t, "url-1", url)
+				*disabled = true
+				return nil
+			},
+		}
+	}
+	okClient := &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+	noContentClient := &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+		},
+	}
+	redirectClient := &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusMovedPermanently, Status: "301 Moved Permanently", Body: http.NoBody}, nil
+		},
+	}
+	failingClient := &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			return nil, errTest
+		},
+	}
+
+	tests := []struct {
+		name              string
+		client            *httpmocks.Client
+		subscriptionStore func(disabled *bool) *mocks.Store
+		failedCount       int
+		lastSuccess       time.Time
+
+		wantFailedSince time.Time
+		wantDeleted     bool
+		wantDelivery    *outbox.Delivery
+		wantDisabled    bool
+		wantErr         error
+	}{
+		{
+			name:              "ok - delivered",
+			client:            okClient,
+			subscriptionStore: subscriptionStore,
+
+			wantDeleted: true,
+		},
+		{
+			name:              "ok - delivered with no content response",
+			client:            noContentClient,
+			subscriptionStore: subscriptionStore,
+
+			wantDeleted: true,
+		},
+		{
+			name:              "ok - retries exhausted with non 2xx response",
+			client:            redirectClient,
+			subscriptionStore: subscriptionStore,
+			failedCount:       1,
+
+			wantFailedSince: testNow.Add(-time.Hour),
+			wantDelivery: &outbox.Delivery{
+				ID:        1,
+				URL:       "url-1",
+				Schema:    "test_schema",
+				Table:     "test_table",
+				Payload:   []byte(`{"Data":null}`),
+				Status:    outbox.StatusFailed,
+				Attempts:  3,
+				LastError: "error response from payload request, status code: 301 Moved Permanently, body: ",
+			},
+		},
+		{
+			name:              "ok - retries exhausted",
+			client:            failingClient,
+			subscriptionStore: subscriptionStore,
+			failedCount:       1,
+
+			wantFailedSince: testNow.Add(-time.Hour),
+			wantDelivery: &outbox.Delivery{
+				ID:        1,
+				URL:       "url-1",
+				Schema:    "test_schema",
+				Table:     "test_table",
+				Payload:   []byte(`{"Data":null}`),
+				Status:    outbox.StatusFailed,
+				Attempts:  3,
+				LastError: "sending webhook payload request: oh noes",
+			},
+		},
+		{
+			name:              "ok - retries exhausted, endpoint disabled",
+			client:            failingClient,
+			subscriptionStore: subscriptionStore,
+			failedCount:       2,
+
+			wantFailedSince: testNow.Add(-time.Hour),
+			wantDelivery: &outbox.Delivery{
+				ID:        1,
+				URL:       "url-1",
+				Schema:    "test_schema",
+				Table:     "test_table",
+				Payload:   []byte(`{"Data":null}`),
+				Status:    outbox.StatusFailed,
+				Attempts:  3,
+				LastError: "sending webhook payload request: oh noes",
+			},
+			wantDisabled: true,
+		},
+		{
+			name:              "ok - retries exhausted, failures counted since last success",
+			client:            failingClient,
+			subscriptionStore: subscriptionStore,
+			failedCount:       1,
+			lastSuccess:       testNow.Add(-time.Minute),
+
+			wantFailedSince: testNow.Add(-time.Minute),
+			wantDelivery: &outbox.Delivery{
+				ID:        1,
+				URL:       "url-1",
+				Schema:    "test_schema",
+				Table:     "test_table",
+				Payload:   []byte(`{"Data":null}`),
+				Status:    outbox.StatusFailed,
+				Attempts:  3,
+				LastError: "sending webhook payload request: oh noes",
+			},
+		},
+		{
+			name:              "ok - retries exhausted, last success outside disable window",
+			client:            failingClient,
+			subscriptionStore: subscriptionStore,
+			failedCount:       1,
+			lastSuccess:       testNow.Add(-2 * time.Hour),
+
+			wantFailedSince: testNow.Add(-time.Hour),
+			wantDelivery: &outbox.Delivery{
+				ID:        1,
+				URL:       "url-1",
+				Schema:    "test_schema",
+				Table:     "test_table",
+				Payload:   []byte(`{"Data":null}`),
+				Status:    outbox.StatusFailed,
+				Attempts:  3,
+				LastError: "sending webhook payload request: oh noes",
+			},
+		},
+		{
+			name:   "ok - subscription not found",
+			client: okClient,
+			subscriptionStore: func(disabled *bool) *mocks.Store {
+				return &mocks.Store{
+					GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+						return []*subscription.Subscription{}, nil
+					},
+				}
+			},
+
+			wantDelivery: &outbox.Delivery{
+				ID:        1,
+				URL:       "url-1",
+				Schema:    "test_schema",
+				Table:     "test_table",
+				Payload:   []byte(`{"Data":null}`),
+				Status:    outbox.StatusFailed,
+				Attempts:  1,
+				LastError: errSubscriptionNotFound.Error(),
+			},
+			wantFailedSince: testNow.Add(-time.Hour),
+		},
+		{
+			name:   "error - retrieving subscriptions",
+			client: okClient,
+			subscriptionStore: func(disabled *bool) *mocks.Store {
+				return &mocks.Store{
+					GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+						return nil, errTest
+					},
+				}
+			},
+
+			wantErr: errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var deleted, disabled bool
+			var gotDelivery *outbox.Delivery
+			r := NewRetrier(&RetrierConfig{DisableThreshold: 2}, &outboxmocks.Store{
+				DeleteDeliveryFn: func(ctx context.Context, id int64) error {
+					require.Equal(t, int64(1), id)
+					deleted = true
+					return nil
+				},
+				UpdateDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+					gotDelivery = d
+					return nil
+				},
+				CountDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) (int, error) {
+					require.Equal(t, &outbox.Filter{Status: outbox.StatusFailed, URL: "url-1", UpdatedAfter: tc.wantFailedSince}, filter)
+					return tc.failedCount, nil
+				},
+			}, tc.subscriptionStore(&disabled))
+			r.logger = log.NewNoopLogger()
+			r.now = func() time.Time { return testNow }
+			if !tc.lastSuccess.IsZero() {
+				r.setLastSuccess("url-1", tc.lastSuccess)
+			}
+			r.client = tc.client
+			r.backoffProvider = func(ctx context.Context) backoff.Backoff {
+				return &backoffmocks.Backoff{
+					RetryNotifyFn: func(op backoff.Operation, notify backoff.Notify) error {
+						var err error
+						for i := 0; i < 2; i++ {
+							if err = op(); err == nil {
+								return nil
+							}
+							notify(err, time.Millisecond)
+						}
+						return err
+					},
+				}
+			}
+
+			err := r.retry(context.Background(), testDelivery())
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantDeleted, deleted)
+			require.Equal(t, tc.wantDelivery, gotDelivery)
+			require.Equal(t, tc.wantDisabled, disabled)
+			if tc.wantDeleted {
+				lastSuccess, found := r.getLastSuccess("url-1")
+				require.True(t, found)
+				require.Equal(t, testNow, lastSuccess)
+			}
+		})
+	}
+}
+
+func TestRetrier_retry_subscriptionID(t *testing.T) {
+	t.Parallel()
+
+	testSubscription := func(paused, disabled bool) *subscription.Subscription {
+		s := newTestSubscription("url-1", "test_schema", "test_table", nil)
+		s.ID = "id-1"
+		s.Paused = paused
+		s.Disabled = disabled
+		return s
+	}
+	testNow := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name         string
+		subscription *subscription.Subscription
+		getErr       error
+
+		wantDeleted bool
+		wantStatus  outbox.Status
+		wantStats   subscription.Stats
+		wantErr     error
+	}{
+		{
+			name:         "ok - delivered",
+			subscription: testSubscription(false, false),
+
+			wantDeleted: true,
+			wantStats:   subscription.Stats{Delivered: 1, LastDeliveredAt: &testNow},
+		},
+		{
+			name:         "ok - paused subscription",
+			subscription: testSubscription(true, false),
+
+			wantStatus: outbox.StatusPending,
+		},
+		{
+			name:         "ok - disabled subscription",
+			subscription: testSubscription(false, true),
+
+			wantStatus: outbox.StatusFailed,
+		},
+		{
+			name:   "ok - subscription not found",
+			getErr: store.ErrSubscriptionNotFound,
+
+			wantStatus: outbox.StatusFailed,
+		},
+		{
+			name:   "error - retrieving subscription",
+			getErr: errTest,
+
+			wantStatus: outbox.StatusPending,
+			wantErr:    errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var deleted bool
+			delivery := &outbox.Delivery{
+				ID:             1,
+				SubscriptionID: "id-1",
+				URL:            "url-1",
+				Payload:        []byte(`{"Data":null}`),
+				Status:         outbox.StatusPending,
+			}
+			stats := subscription.NewStatsRegistry()
+			r := NewRetrier(&RetrierConfig{DisableThreshold: 2}, &outboxmocks.Store{
+				DeleteDeliveryFn: func(ctx context.Context, id int64) error {
+					deleted = true
+					return nil
+				},
+				UpdateDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+					return nil
+				},
+				CountDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) (int, error) {
+					return 0, nil
+				},
+			}, &mocks.Store{
+				GetSubscriptionFn: func(ctx context.Context, id string) (*subscription.Subscription, error) {
+					require.Equal(t, "id-1", id)
+					return tc.subscription, tc.getErr
+				},
+			}, WithRetrierStats(stats))
+			r.now = func() time.Time { return testNow }
+			r.client = &httpmocks.Client{
+				DoFn: func(r *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+				},
+			}
+
+			err := r.retry(context.Background(), delivery)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantDeleted, deleted)
+			if !tc.wantDeleted {
+				require.Equal(t, tc.wantStatus, delivery.Status)
+			}
+			require.Equal(t, tc.wantStats, stats.Get("id-1"))
+		})
+	}
+}
+
+func TestRetrier_Run(t *testing.T) {
+	t.Parallel()
+
+	delivered := make(chan int64, 10)
+	r := NewRetrier(&RetrierConfig{PollInterval: time.Millisecond}, &outboxmocks.Store{
+		GetDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+			require.Equal(t, outbox.StatusPending, filter.Status)
+			if !filter.OldestPerSubscription {
+				// no more pending deliveries for the subscription
+				return []*outbox.Delivery{}, nil
+			}
+			return []*outbox.Delivery{
+				{ID: 1, URL: "url-1"},
+				{ID: 2, URL: "url-2"},
+			}, nil
+		},
+		DeleteDeliveryFn: func(ctx context.Context, id int64) error {
+			select {
+			case delivered <- id:
+			default:
+			}
+			return nil
+		},
+	}, &mocks.Store{
+		GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+			return []*subscription.Subscription{newTestSubscription("url-1", "", "", nil)}, nil
+		},
+	})
+	r.client = &httpmocks.Client{
+		DoFn: func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+	// subscriptions being retried are not picked up again by the next polls
+	r.markInflight("url-2//")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- r.Run(ctx)
+	}()
+
+	select {
+	case id := <-delivered:
+		require.Equal(t, int64(1), id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("test timeout waiting for delivery")
+	}
+
+	cancel()
+	require.True(t, errors.Is(<-errChan, context.Canceled))
+	close(delivered)
+	for id := range delivered {
+		require.Equal(t, int64(1), id)
+	}
+}
+
+func TestRetrier_retryInOrder(t *testing.T) {
+	t.Parallel()
+
+	testSubscription := newTestSubscription("url-1", "test_schema", "test_table", nil)
+	testDelivery := func(id int64) *outbox.Delivery {
+		return &outbox.Delivery{
+			ID:      id,
+			URL:     "url-1",
+			Schema:  "test_schema",
+			Table:   "test_table",
+			Payload: []byte(fmt.Sprintf(`{"id":%d}`, id)),
+			Status:  outbox.StatusPending,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		statusFn func(payload string) int
+
+		wantSent    []string
+		wantDeleted []int64
+		wantFailed  []int64
+	}{
+		{
+			name:     "ok - all pending deliveries retried in order",
+			statusFn: func(string) int { return http.StatusOK },
+
+			wantSent:    []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			wantDeleted: []int64{1, 2, 3},
+		},
+		{
+			name: "ok - failed delivery doesn't block the following ones",
+			statusFn: func(payload string) int {
+				if payload == `{"id":2}` {
+					return http.StatusInternalServerError
+				}
+				return http.StatusOK
+			},
+
+			wantSent:    []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			wantDeleted: []int64{1, 3},
+			wantFailed:  []int64{2},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pending := []*outbox.Delivery{testDelivery(1), testDelivery(2), testDelivery(3)}
+			removePending := func(id int64) {
+				pending = slices.DeleteFunc(pending, func(d *outbox.Delivery) bool { return d.ID == id })
+			}
+
+			var sent []string
+			var deleted, failed []int64
+			r := NewRetrier(&RetrierConfig{}, &outboxmocks.Store{
+				GetDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+					require.Equal(t, &outbox.Filter{
+						Status: outbox.StatusPending,
+						URL:    "url-1",
+						Schema: "test_schema",
+						Table:  "test_table",
+						Limit:  1,
+					}, filter)
+					return pending[:min(1, len(pending))], nil
+				},
+				DeleteDeliveryFn: func(ctx context.Context, id int64) error {
+					deleted = append(deleted, id)
+					removePending(id)
+					return nil
+				},
+				UpdateDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+					require.Equal(t, outbox.StatusFailed, d.Status)
+					failed = append(failed, d.ID)
+					removePending(d.ID)
+					return nil
+				},
+				CountDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) (int, error) {
+					return 0, nil
+				},
+			}, &mocks.Store{
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+					return []*subscription.Subscription{testSubscription}, nil
+				},
+			})
+			r.client = &httpmocks.Client{
+				DoFn: func(r *http.Request) (*http.Response, error) {
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					sent = append(sent, string(body))
+					return &http.Response{StatusCode: tc.statusFn(string(body)), Body: http.NoBody}, nil
+				},
+			}
+			r.backoffProvider = func(ctx context.Context) backoff.Backoff {
+				return &backoffmocks.Backoff{
+					RetryNotifyFn: func(op backoff.Operation, notify backoff.Notify) error {
+						return op()
+					},
+				}
+			}
+
+			r.retryInOrder(context.Background(), pending[0])
+			require.Equal(t, tc.wantSent, sent)
+			require.Equal(t, tc.wantDeleted, deleted)
+			require.Equal(t, tc.wantFailed, failed)
+			require.Empty(t, pending)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+)
+
+// Delivery is a webhook notification that couldn't be delivered to a
+// subscription, and is kept in the outbox until it's retried successfully.
+type Delivery struct {
+	ID int64 `json:"id"`
+	// SubscriptionID identifies the subscription the delivery is for. It's
+	// empty for deliveries stored before subscriptions had ids, which are
+	// matched by URL, Schema and Table instead.
+	SubscriptionID string          `json:"subscription_id,omitempty"`
+	URL            string          `json:"url"`
+	Schema         string          `json:"schema"`
+	Table          string          `json:"table"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         Status          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type Status string
+
+const (
+	// StatusPending deliveries are retried asynchronously.
+	StatusPending Status = "pending"
+	// StatusFailed deliveries have exhausted their retries, and are only
+	// retried when redelivered explicitly.
+	StatusFailed Status = "failed"
+)
+
+// Filter narrows down the deliveries retrieved from the outbox. Empty fields
+// are ignored.
+type Filter struct {
+	Status         Status
+	SubscriptionID string
+	URL            string
+	Schema         string
+	Table          string
+	// UpdatedAfter only matches the deliveries updated after the given time,
+	// when set.
+	UpdatedAfter time.Time
+	// OldestPerSubscription only retrieves the oldest delivery of each
+	// subscription.
+	OldestPerSubscription bool
+	// Limit is the max number of deliveries retrieved.
+	Limit int
+}
+
+// NewDelivery returns a pending delivery of the payload for the subscription
+// on input.
+func NewDelivery(s *subscription.Subscription, payload []byte, err error) *Delivery {
+	d := &Delivery{
+		SubscriptionID: s.ID,
+		URL:            s.URL,
+		Schema:         s.Schema,
+		Table:          s.Table,
+		Payload:        payload,
+		Status:         StatusPending,
+		Attempts:       1,
+	}
+	if err != nil {
+		d.LastError = err.Error()
+	}
+	return d
+}
+
+// NewQueuedDelivery returns a pending delivery of the payload for the
+// subscription on input that hasn't been attempted, since it's queued behind
+// the pending deliveries of the subscription.
+func NewQueuedDelivery(s *subscription.Subscription, payload []byte) *Delivery {
+	d := NewDelivery(s, payload, nil)
+	d.Attempts = 0
+	return d
+}
+
+// SubscriptionKey identifies the subscription the delivery is for. The
+// deliveries with the same key are retried in order.
+func (d *Delivery) SubscriptionKey() string {
+	if d.SubscriptionID != "" {
+		return d.SubscriptionID
+	}
+	return fmt.Sprintf("%s/%s/%s", d.URL, d.Schema, d.Table)
+}
+
+// IsFor returns true if the delivery belongs to the subscription on input.
+func (d *Delivery) IsFor(s *subscription.Subscription) bool {
+	return d.URL == s.URL && d.Schema == s.Schema && d.Table == s.Table
+}
+
+// Redeliver resets the delivery so that it's retried again.
+func (d *Delivery) Redeliver() {
+	d.Status = StatusPending
+	d.Attempts = 0
+	d.LastError = ""
+}
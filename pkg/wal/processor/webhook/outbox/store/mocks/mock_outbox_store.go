@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+)
+
+type Store struct {
+	CreateDeliveryFn  func(ctx context.Context, d *outbox.Delivery) error
+	UpdateDeliveryFn  func(ctx context.Context, d *outbox.Delivery) error
+	DeleteDeliveryFn  func(ctx context.Context, id int64) error
+	GetDeliveryFn     func(ctx context.Context, id int64) (*outbox.Delivery, error)
+	GetDeliveriesFn   func(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error)
+	CountDeliveriesFn func(ctx context.Context, filter *outbox.Filter) (int, error)
+}
+
+func (m *Store) CreateDelivery(ctx context.Context, d *outbox.Delivery) error {
+	return m.CreateDeliveryFn(ctx, d)
+}
+
+func (m *Store) UpdateDelivery(ctx context.Context, d *outbox.Delivery) error {
+	return m.UpdateDeliveryFn(ctx, d)
+}
+
+func (m *Store) DeleteDelivery(ctx context.Context, id int64) error {
+	return m.DeleteDeliveryFn(ctx, id)
+}
+
+func (m *Store) GetDelivery(ctx context.Context, id int64) (*outbox.Delivery, error) {
+	return m.GetDeliveryFn(ctx, id)
+}
+
+func (m *Store) GetDeliveries(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+	return m.GetDeliveriesFn(ctx, filter)
+}
+
+func (m *Store) CountDeliveries(ctx context.Context, filter *outbox.Filter) (int, error) {
+	return m.CountDeliveriesFn(ctx, filter)
+}
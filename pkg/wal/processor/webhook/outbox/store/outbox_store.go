@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+)
+
+type Store interface {
+	CreateDelivery(ctx context.Context, d *outbox.Delivery) error
+	UpdateDelivery(ctx context.Context, d *outbox.Delivery) error
+	DeleteDelivery(ctx context.Context, id int64) error
+	GetDelivery(ctx context.Context, id int64) (*outbox.Delivery, error)
+	GetDeliveries(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error)
+	CountDeliveries(ctx context.Context, filter *outbox.Filter) (int, error)
+}
+
+var ErrDeliveryNotFound = errors.New("delivery not found")
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	pglib "github.com/ApollosProject/pgstream-wal2json/internal/postgres"
+	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store"
+)
+
+// Store is a postgres implementation of the webhook delivery outbox. The
+// deliveries are stored in the pgstream schema so that they survive restarts.
+type Store struct {
+	conn   pglib.Querier
+	logger loglib.Logger
+}
+
+type Option func(*Store)
+
+const (
+	deliveriesTableName = "webhook_deliveries"
+	pgstreamSchema      = "pgstream"
+
+	deliveryColumns = "id, subscription_id, url, schema_name, table_name, payload, status, attempts, last_error, created_at, updated_at"
+)
+
+func NewOutboxStore(ctx context.Context, url string, opts ...Option) (*Store, error) {
+	pgpool, err := pglib.NewConnPool(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("create postgres connection pool: %w", err)
+	}
+	s := &Store{
+		conn:   pgpool,
+		logger: loglib.NewNoopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// create deliveries table if it doesn't exist
+	if err := s.createTable(ctx); err != nil {
+		return nil, fmt.Errorf("creating webhook deliveries table: %w", err)
+	}
+
+	return s, nil
+}
+
+func WithLogger(l loglib.Logger) Option {
+	return func(s *Store) {
+		s.logger = loglib.NewLogger(l).WithFields(loglib.Fields{
+			loglib.ServiceField: "webhook_outbox_store",
+		})
+	}
+}
+
+func (s *Store) CreateDelivery(ctx context.Context, d *outbox.Delivery) error {
+	query := fmt.Sprintf(`INSERT INTO %s(subscription_id, url, schema_name, table_name, payload, status, attempts, last_error)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`, deliveriesTable())
+	err := s.conn.QueryRow(ctx, query, d.SubscriptionID, d.URL, d.Schema, d.Table, []byte(d.Payload), d.Status, d.Attempts, d.LastError).
+		Scan(&d.ID, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) UpdateDelivery(ctx context.Context, d *outbox.Delivery) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, attempts = $2, last_error = $3, updated_at = now()
+	WHERE id = $4 RETURNING updated_at`, deliveriesTable())
+	err := s.conn.QueryRow(ctx, query, d.Status, d.Attempts, d.LastError, d.ID).Scan(&d.UpdatedAt)
+	if err != nil {
+		return mapError(fmt.Errorf("updating webhook delivery: %w", err))
+	}
+	return nil
+}
+
+func (s *Store) DeleteDelivery(ctx context.Context, id int64) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, deliveriesTable())
+	if _, err := s.conn.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("deleting webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetDelivery(ctx context.Context, id int64) (*outbox.Delivery, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, deliveryColumns, deliveriesTable())
+	d := &outbox.Delivery{}
+	if err := scanDelivery(s.conn.QueryRow(ctx, query, id), d); err != nil {
+		return nil, mapError(fmt.Errorf("getting webhook delivery: %w", err))
+	}
+	return d, nil
+}
+
+func (s *Store) GetDeliveries(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+	where, params := buildWhereClause(filter)
+	query := fmt.Sprintf(`SELECT %s FROM %s%s ORDER BY id`, deliveryColumns, deliveriesTable(), where)
+	if filter != nil && filter.OldestPerSubscription {
+		query = fmt.Sprintf(`SELECT %[1]s FROM (SELECT DISTINCT ON (subscription_id, url, schema_name, table_name) %[1]s FROM %[2]s%[3]s
+		ORDER BY subscription_id, url, schema_name, table_name, id) oldest ORDER BY id`, deliveryColumns, deliveriesTable(), where)
+	}
+	if filter != nil && filter.Limit > 0 {
+		query = fmt.Sprintf("%s LIMIT %d", query, filter.Limit)
+	}
+
+	s.logger.Trace("getting webhook deliveries", loglib.Fields{
+		"query":  query,
+		"params": params,
+	})
+	rows, err := s.conn.Query(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("querying webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*outbox.Delivery{}
+	for rows.Next() {
+		d := &outbox.Delivery{}
+		if err := scanDelivery(rows, d); err != nil {
+			return nil, fmt.Errorf("scanning webhook delivery row: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (s *Store) CountDeliveries(ctx context.Context, filter *outbox.Filter) (int, error) {
+	where, params := buildWhereClause(filter)
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s%s`, deliveriesTable(), where)
+	var count int
+	if err := s.conn.QueryRow(ctx, query, params...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting webhook deliveries: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) createTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s(
+	id BIGSERIAL PRIMARY KEY,
+	subscription_id TEXT NOT NULL DEFAULT '',
+	url TEXT NOT NULL,
+	schema_name TEXT NOT NULL,
+	table_name TEXT NOT NULL,
+	payload BYTEA NOT NULL,
+	status TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now())`, deliveriesTable())
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	// tables created by previous versions need the subscription id added
+	query = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS subscription_id TEXT NOT NULL DEFAULT ''`, deliveriesTable())
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_status_idx ON %s(status, id)`, deliveriesTableName, deliveriesTable())
+	_, err := s.conn.Exec(ctx, query)
+	return err
+}
+
+func buildWhereClause(filter *outbox.Filter) (string, []any) {
+	if filter == nil {
+		return "", nil
+	}
+
+	conditions := []struct {
+		column string
+		value  string
+	}{
+		{column: "status", value: string(filter.Status)},
+		{column: "subscription_id", value: filter.SubscriptionID},
+		{column: "url", value: filter.URL},
+		{column: "schema_name", value: filter.Schema},
+		{column: "table_name", value: filter.Table},
+	}
+
+	where := ""
+	var params []any
+	for _, c := range conditions {
+		if c.value == "" {
+			continue
+		}
+		separator := " AND"
+		if len(params) == 0 {
+			separator = " WHERE"
+		}
+		params = append(params, c.value)
+		where = fmt.Sprintf("%s%s %s = $%d", where, separator, c.column, len(params))
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		separator := " AND"
+		if len(params) == 0 {
+			separator = " WHERE"
+		}
+		params = append(params, filter.UpdatedAfter)
+		where = fmt.Sprintf("%s%s updated_at > $%d", where, separator, len(params))
+	}
+	return where, params
+}
+
+func scanDelivery(row pgx.Row, d *outbox.Delivery) error {
+	var payload []byte
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.URL, &d.Schema, &d.Table, &payload, &d.Status, &d.Attempts, &d.LastError, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return err
+	}
+	d.Payload = payload
+	return nil
+}
+
+func mapError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", store.ErrDeliveryNotFound, err)
+	}
+	return err
+}
+
+func deliveriesTable() string {
+	return fmt.Sprintf("%s.%s", pgstreamSchema, deliveriesTableName)
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWhereClause(t *testing.T) {
+	t.Parallel()
+
+	testTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter *outbox.Filter
+
+		wantWhere  string
+		wantParams []any
+	}{
+		{
+			name:   "no filter",
+			filter: nil,
+
+			wantWhere:  "",
+			wantParams: nil,
+		},
+		{
+			name:   "empty filter",
+			filter: &outbox.Filter{Limit: 10},
+
+			wantWhere:  "",
+			wantParams: nil,
+		},
+		{
+			name:   "status filter",
+			filter: &outbox.Filter{Status: outbox.StatusFailed},
+
+			wantWhere:  " WHERE status = $1",
+			wantParams: []any{"failed"},
+		},
+		{
+			name:   "updated after filter",
+			filter: &outbox.Filter{UpdatedAfter: testTime},
+
+			wantWhere:  " WHERE updated_at > $1",
+			wantParams: []any{testTime},
+		},
+		{
+			name: "all filters",
+			filter: &outbox.Filter{
+				Status:         outbox.StatusPending,
+				SubscriptionID: "id-1",
+				URL:            "url-1",
+				Schema:         "test_schema",
+				Table:          "test_table",
+				UpdatedAfter:   testTime,
+			},
+
+			wantWhere:  " WHERE status = $1 AND subscription_id = $2 AND url = $3 AND schema_name = $4 AND table_name = $5 AND updated_at > $6",
+			wantParams: []any{"pending", "id-1", "url-1", "test_schema", "test_table", testTime},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			where, params := buildWhereClause(tc.filter)
+			require.Equal(t, tc.wantWhere, where)
+			require.Equal(t, tc.wantParams, params)
+		})
+	}
+}
@@ -2,7 +2,11 @@
 
 package server
 
-import "time"
+import (
+	"time"
+
+	tlslib "github.com/ApollosProject/pgstream-wal2json/pkg/tls"
+)
 
 type Config struct {
 	// Address for the server to listen on. The format is "host:port". Defaults
@@ -15,6 +19,13 @@ type Config struct {
 	// response. It is reset whenever a new request's header is read. Defaults
 	// to 10s.
 	WriteTimeout time.Duration
+	// APIKeys are the keys accepted to authenticate the requests, sent either
+	// as a bearer token in the Authorization header or in the X-API-Key
+	// header. Authentication is disabled if empty.
+	APIKeys []string
+	// TLS enables HTTPS for the server, and mutual TLS authentication when a
+	// client CA is provided. Disabled if nil.
+	TLS *tlslib.ServerConfig
 }
 
 const (
@@ -12,14 +12,18 @@ import (
 
 	httplib "github.com/ApollosProject/pgstream-wal2json/internal/http"
 	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	outboxstore "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
 )
 
 type Server struct {
-	server  httplib.Server
-	logger  loglib.Logger
-	store   store.Store
+	server      httplib.Server
+	logger      loglib.Logger
+	store       store.Store
+	outboxStore outboxstore.Store
+	// stats keeps the delivery stats of the subscriptions. Optional.
+	stats   *subscription.StatsRegistry
 	address string
 }
 
@@ -38,16 +42,37 @@ func New(cfg *Config, store store.Store, opts ...Option) *Server {
 
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	if len(cfg.APIKeys) > 0 {
+		e.Use(httplib.APIKeyAuth(cfg.APIKeys))
+	}
 
 	e.POST("/webhooks/subscribe", s.subscribe)
 	e.POST("/webhooks/unsubscribe", s.unsubscribe)
 
+	e.GET("/webhooks/subscriptions", s.listSubscriptions)
+	e.POST("/webhooks/subscriptions", s.createSubscription)
+	e.GET("/webhooks/subscriptions/:id", s.getSubscription)
+	e.PUT("/webhooks/subscriptions/:id", s.updateSubscription)
+	e.DELETE("/webhooks/subscriptions/:id", s.deleteSubscription)
+	e.POST("/webhooks/subscriptions/:id/pause", s.pauseSubscription)
+	e.POST("/webhooks/subscriptions/:id/resume", s.resumeSubscription)
+	e.GET("/webhooks/subscriptions/:id/stats", s.getSubscriptionStats)
+
 	s.server = e
+	if cfg.TLS != nil {
+		s.server = &httplib.TLSServer{Echo: e, Config: cfg.TLS}
+	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
 
+	if s.outboxStore != nil {
+		e.GET("/webhooks/deliveries", s.getDeliveries)
+		e.GET("/webhooks/deliveries/:id", s.getDelivery)
+		e.POST("/webhooks/deliveries/:id/redeliver", s.redeliver)
+	}
+
 	return s
 }
 
@@ -59,6 +84,22 @@ func WithLogger(l loglib.Logger) Option {
 	}
 }
 
+// WithOutbox enables the endpoints to inspect and redeliver the webhook
+// deliveries stored in the outbox.
+func WithOutbox(store outboxstore.Store) Option {
+	return func(s *Server) {
+		s.outboxStore = store
+	}
+}
+
+// WithStats exposes the delivery stats of the subscriptions recorded in the
+// registry on input.
+func WithStats(stats *subscription.StatsRegistry) Option {
+	return func(s *Server) {
+		s.stats = stats
+	}
+}
+
 // Start will start the subscription server. This call is blocking.
 func (s *Server) Start() error {
 	s.logger.Info(fmt.Sprintf("subscription server listening on: %s...", s.address))
@@ -80,13 +121,16 @@ func (s *Server) subscribe(c echo.Context) error {
 	if err := c.Bind(subscription); err != nil {
 		return c.JSON(http.StatusBadRequest, err)
 	}
+	if err := subscription.ValidateExpression(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 
 	ctx := c.Request().Context()
 	if err := s.store.CreateSubscription(ctx, subscription); err != nil {
 		return c.JSON(http.StatusServiceUnavailable, err)
 	}
 
-	return c.JSON(http.StatusCreated, nil)
+	return c.JSON(http.StatusCreated, subscription.Redacted())
 }
 
 func (s *Server) unsubscribe(c echo.Context) error {
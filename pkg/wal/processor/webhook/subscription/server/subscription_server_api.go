@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
+)
+
+const (
+	defaultSubscriptionsLimit = 100
+	maxSubscriptionsLimit     = 1000
+)
+
+type listSubscriptionsResponse struct {
+	Subscriptions []*subscription.Subscription `json:"subscriptions"`
+	// NextCursor is the cursor to retrieve the next page of subscriptions.
+	// It's empty when there are no more subscriptions.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// listSubscriptions returns a page of subscriptions, filtered by the url,
+// schema and table query parameters. The credentials are redacted.
+func (s *Server) listSubscriptions(c echo.Context) error {
+	s.logger.Trace("request received on /subscriptions endpoint")
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	subscriptions, err := s.store.ListSubscriptions(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusServiceUnavailable, err.Error())
+	}
+
+	resp := &listSubscriptionsResponse{
+		Subscriptions: make([]*subscription.Subscription, 0, len(subscriptions)),
+	}
+	for _, sub := range subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, sub.Redacted())
+	}
+	if len(subscriptions) == filter.Limit {
+		resp.NextCursor = subscriptions[len(subscriptions)-1].ID
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// createSubscription creates the subscription on input. Unlike the legacy
+// subscribe endpoint, it doesn't replace an existing subscription for the same
+// url, schema and table, and returns a conflict instead.
+func (s *Server) createSubscription(c echo.Context) error {
+	s.logger.Trace("request received on POST /subscriptions endpoint")
+
+	sub := &subscription.Subscription{}
+	if err := c.Bind(sub); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	if err := sub.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	if err := s.store.InsertSubscription(c.Request().Context(), sub); err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, sub.Redacted())
+}
+
+func (s *Server) getSubscription(c echo.Context) error {
+	s.logger.Trace("request received on GET /subscriptions/:id endpoint")
+
+	sub, err := s.store.GetSubscription(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	return c.JSON(http.StatusOK, sub.Redacted())
+}
+
+// updateSubscription replaces the subscription with the one on input. The
+// credentials sent back redacted are kept as they are.
+func (s *Server) updateSubscription(c echo.Context) error {
+	s.logger.Trace("request received on PUT /subscriptions/:id endpoint")
+
+	sub := &subscription.Subscription{}
+	if err := c.Bind(sub); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	// the id in the path takes precedence over the one in the body
+	sub.ID = c.Param("id")
+	if err := sub.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	existing, err := s.store.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+	sub.RestoreRedacted(existing)
+
+	if err := s.store.UpdateSubscription(ctx, sub); err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	return c.JSON(http.StatusOK, sub.Redacted())
+}
+
+func (s *Server) deleteSubscription(c echo.Context) error {
+	s.logger.Trace("request received on DELETE /subscriptions/:id endpoint")
+
+	ctx := c.Request().Context()
+	sub, err := s.store.GetSubscription(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	if err := s.store.DeleteSubscription(ctx, sub); err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// pauseSubscription stops the notifications for the subscription until it's
+// resumed.
+func (s *Server) pauseSubscription(c echo.Context) error {
+	s.logger.Trace("request received on /subscriptions/:id/pause endpoint")
+
+	return s.setSubscriptionStatus(c, func(sub *subscription.Subscription) {
+		sub.Paused = true
+	})
+}
+
+// resumeSubscription notifies the subscription again. Subscriptions disabled
+// because of a failing endpoint are enabled again too.
+func (s *Server) resumeSubscription(c echo.Context) error {
+	s.logger.Trace("request received on /subscriptions/:id/resume endpoint")
+
+	return s.setSubscriptionStatus(c, func(sub *subscription.Subscription) {
+		sub.Paused = false
+		sub.Disabled = false
+	})
+}
+
+func (s *Server) setSubscriptionStatus(c echo.Context, update func(*subscription.Subscription)) error {
+	ctx := c.Request().Context()
+	sub, err := s.store.GetSubscription(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	update(sub)
+	if err := s.store.UpdateSubscription(ctx, sub); err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	return c.JSON(http.StatusOK, sub.Redacted())
+}
+
+// getSubscriptionStats returns the delivery stats of the subscription since
+// the process started, along with its deliveries in the outbox if enabled.
+func (s *Server) getSubscriptionStats(c echo.Context) error {
+	s.logger.Trace("request received on /subscriptions/:id/stats endpoint")
+
+	ctx := c.Request().Context()
+	sub, err := s.store.GetSubscription(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(subscriptionErrorStatus(err), err.Error())
+	}
+
+	stats := s.stats.Get(sub.ID)
+	if s.outboxStore != nil {
+		if stats.PendingDeliveries, err = s.countDeliveries(ctx, sub.ID, outbox.StatusPending); err != nil {
+			return c.JSON(http.StatusServiceUnavailable, err.Error())
+		}
+		if stats.FailedDeliveries, err = s.countDeliveries(ctx, sub.ID, outbox.StatusFailed); err != nil {
+			return c.JSON(http.StatusServiceUnavailable, err.Error())
+		}
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+func (s *Server) countDeliveries(ctx context.Context, subscriptionID string, status outbox.Status) (int, error) {
+	return s.outboxStore.CountDeliveries(ctx, &outbox.Filter{
+		SubscriptionID: subscriptionID,
+		Status:         status,
+	})
+}
+
+func parseListFilter(c echo.Context) (*subscription.ListFilter, error) {
+	filter := &subscription.ListFilter{
+		URL:    c.QueryParam("url"),
+		Schema: c.QueryParam("schema"),
+		Table:  c.QueryParam("table"),
+		Cursor: c.QueryParam("cursor"),
+		Limit:  defaultSubscriptionsLimit,
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 || filter.Limit > maxSubscriptionsLimit {
+			return nil, fmt.Errorf("invalid limit: %q, must be between 1 and %d", limit, maxSubscriptionsLimit)
+		}
+	}
+
+	return filter, nil
+}
+
+func subscriptionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, store.ErrSubscriptionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrSubscriptionConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	httplib "github.com/ApollosProject/pgstream-wal2json/internal/http"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxmocks "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store/mocks"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store/memory"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store/mocks"
+)
+
+const testAPIKey = "test-api-key"
+
+func newTestAPIServer(t *testing.T, opts ...Option) (*memory.Store, http.Handler) {
+	t.Helper()
+
+	store := memory.NewSubscriptionStore()
+	s := New(&Config{APIKeys: []string{testAPIKey}}, store, opts...)
+	e, ok := s.server.(*echo.Echo)
+	require.True(t, ok)
+	return store, e
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&payload).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func decodeResponse[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&v))
+	return v
+}
+
+func TestSubscriptionServer_API(t *testing.T) {
+	t.Parallel()
+
+	store, handler := newTestAPIServer(t)
+
+	// create
+	w := doRequest(t, handler, http.MethodPost, "/webhooks/subscriptions", &subscription.Subscription{
+		URL:         "url-1",
+		Schema:      "test_schema",
+		Table:       "test_table",
+		Secret:      "test-secret",
+		BearerToken: "test-token",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	created := decodeResponse[*subscription.Subscription](t, w)
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, "********", created.Secret)
+	require.Equal(t, "********", created.BearerToken)
+
+	w = doRequest(t, handler, http.MethodPost, "/webhooks/subscriptions", &subscription.Subscription{URL: "url-2"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	other := decodeResponse[*subscription.Subscription](t, w)
+
+	// creating a subscription for the same url, schema and table conflicts,
+	// and doesn't overwrite the existing one
+	w = doRequest(t, handler, http.MethodPost, "/webhooks/subscriptions", &subscription.Subscription{
+		URL:        "url-1",
+		Schema:     "test_schema",
+		Table:      "test_table",
+		EventTypes: []string{"D"},
+		Secret:     "other-secret",
+		Paused:     true,
+	})
+	require.Equal(t, http.StatusConflict, w.Code)
+	existing, err := store.GetSubscription(context.Background(), created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "test-secret", existing.Secret)
+	require.Equal(t, "test-token", existing.BearerToken)
+	require.Empty(t, existing.EventTypes)
+	require.False(t, existing.Paused)
+
+	// get
+	w = doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions/"+created.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	got := decodeResponse[*subscription.Subscription](t, w)
+	require.Equal(t, "url-1", got.URL)
+	require.Equal(t, "********", got.Secret)
+
+	// update keeps the redacted credentials
+	got.EventTypes = []string{"I"}
+	w = doRequest(t, handler, http.MethodPut, "/webhooks/subscriptions/"+created.ID, got)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// update to the url, schema and table of another subscription conflicts
+	w = doRequest(t, handler, http.MethodPut, "/webhooks/subscriptions/"+created.ID, &subscription.Subscription{URL: "url-2"})
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	// pause
+	w = doRequest(t, handler, http.MethodPost, "/webhooks/subscriptions/"+created.ID+"/pause", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, decodeResponse[*subscription.Subscription](t, w).Paused)
+
+	// resume
+	w = doRequest(t, handler, http.MethodPost, "/webhooks/subscriptions/"+created.ID+"/resume", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.False(t, decodeResponse[*subscription.Subscription](t, w).Paused)
+
+	// list with pagination
+	w = doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions?limit=1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	page := decodeResponse[*listSubscriptionsResponse](t, w)
+	require.Len(t, page.Subscriptions, 1)
+	require.NotEmpty(t, page.NextCursor)
+
+	w = doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions?limit=1&cursor="+page.NextCursor, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	nextPage := decodeResponse[*listSubscriptionsResponse](t, w)
+	require.Len(t, nextPage.Subscriptions, 1)
+	require.ElementsMatch(t, []string{created.ID, other.ID}, []string{page.Subscriptions[0].ID, nextPage.Subscriptions[0].ID})
+
+	// list with filters
+	w = doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions?url=url-1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	filtered := decodeResponse[*listSubscriptionsResponse](t, w)
+	require.Len(t, filtered.Subscriptions, 1)
+	require.Equal(t, created.ID, filtered.Subscriptions[0].ID)
+	require.Equal(t, []string{"I"}, filtered.Subscriptions[0].EventTypes)
+	require.Equal(t, "********", filtered.Subscriptions[0].Secret)
+	require.Empty(t, filtered.NextCursor)
+
+	// delete
+	w = doRequest(t, handler, http.MethodDelete, "/webhooks/subscriptions/"+created.ID, nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions/"+created.ID, nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSubscriptionServer_API_credentials(t *testing.T) {
+	t.Parallel()
+
+	store, handler := newTestAPIServer(t)
+
+	w := doRequest(t, handler, http.MethodPost, "/webhooks/subscriptions", &subscription.Subscription{
+		URL:     "url-1",
+		Secret:  "test-secret",
+		Headers: map[string]string{"X-Tenant": "tenant-1"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	created := decodeResponse[*subscription.Subscription](t, w)
+
+	created.BearerToken = "new-token"
+	created.Headers["X-Region"] = "eu"
+	w = doRequest(t, handler, http.MethodPut, "/webhooks/subscriptions/"+created.ID, created)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	stored, err := store.GetSubscription(context.Background(), created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "test-secret", stored.Secret)
+	require.Equal(t, "new-token", stored.BearerToken)
+	require.Equal(t, map[string]string{"X-Tenant": "tenant-1", "X-Region": "eu"}, stored.Headers)
+}
+
+func TestSubscriptionServer_API_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+
+		wantStatusCode int
+	}{
+		{
+			name:           "create - missing url",
+			method:         http.MethodPost,
+			path:           "/webhooks/subscriptions",
+			body:           &subscription.Subscription{Schema: "test_schema"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "create - invalid expression",
+			method:         http.MethodPost,
+			path:           "/webhooks/subscriptions",
+			body:           &subscription.Subscription{URL: "url-1", Expression: "new.status =="},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "list - invalid limit",
+			method:         http.MethodGet,
+			path:           "/webhooks/subscriptions?limit=1001",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "get - not found",
+			method:         http.MethodGet,
+			path:           "/webhooks/subscriptions/unknown",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "update - not found",
+			method:         http.MethodPut,
+			path:           "/webhooks/subscriptions/unknown",
+			body:           &subscription.Subscription{URL: "url-1"},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "pause - not found",
+			method:         http.MethodPost,
+			path:           "/webhooks/subscriptions/unknown/pause",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "delete - not found",
+			method:         http.MethodDelete,
+			path:           "/webhooks/subscriptions/unknown",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "stats - not found",
+			method:         http.MethodGet,
+			path:           "/webhooks/subscriptions/unknown/stats",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, handler := newTestAPIServer(t)
+			w := doRequest(t, handler, tc.method, tc.path, tc.body)
+			require.Equal(t, tc.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestSubscriptionServer_API_storeErrors(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("oh noes")
+	s := New(&Config{}, &mocks.Store{
+		ListSubscriptionsFn: func(ctx context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error) {
+			return nil, errTest
+		},
+		GetSubscriptionFn: func(ctx context.Context, id string) (*subscription.Subscription, error) {
+			return nil, errTest
+		},
+	})
+	handler := s.server.(*echo.Echo)
+
+	w := doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions", nil)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions/id-1", nil)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestSubscriptionServer_getSubscriptionStats(t *testing.T) {
+	t.Parallel()
+
+	testNow := time.Unix(1700000000, 0).UTC()
+	stats := subscription.NewStatsRegistry()
+	store, handler := newTestAPIServer(t, WithStats(stats), WithOutbox(&outboxmocks.Store{
+		CountDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) (int, error) {
+			require.NotEmpty(t, filter.SubscriptionID)
+			switch filter.Status {
+			case outbox.StatusPending:
+				return 2, nil
+			case outbox.StatusFailed:
+				return 1, nil
+			default:
+				return 0, errors.New("unexpected status")
+			}
+		},
+	}))
+
+	sub := &subscription.Subscription{URL: "url-1"}
+	require.NoError(t, store.CreateSubscription(context.Background(), sub))
+	stats.RecordDelivery(sub.ID, testNow, nil)
+	stats.RecordDelivery(sub.ID, testNow, errors.New("oh noes"))
+
+	w := doRequest(t, handler, http.MethodGet, "/webhooks/subscriptions/"+sub.ID+"/stats", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, subscription.Stats{
+		Delivered:         1,
+		Failed:            1,
+		LastDeliveredAt:   &testNow,
+		LastFailedAt:      &testNow,
+		LastError:         "oh noes",
+		PendingDeliveries: 2,
+		FailedDeliveries:  1,
+	}, decodeResponse[subscription.Stats](t, w))
+}
+
+func TestSubscriptionServer_apiKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	_, handler := newTestAPIServer(t)
+
+	header := func(key, value string) http.Header {
+		h := http.Header{}
+		h.Set(key, value)
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+
+		wantStatusCode int
+	}{
+		{
+			name:           "ok - bearer token",
+			header:         header(echo.HeaderAuthorization, "Bearer "+testAPIKey),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "ok - api key header",
+			header:         header(httplib.APIKeyHeader, testAPIKey),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "error - invalid key",
+			header:         header(httplib.APIKeyHeader, "invalid-key"),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "error - missing key",
+			header:         http.Header{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/subscriptions", nil)
+			req.Header = tc.header
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tc.wantStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxstore "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store"
+)
+
+const defaultDeliveriesLimit = 100
+
+// getDeliveries returns the deliveries in the outbox, filtered by the status,
+// subscription_id, url, schema and table query parameters.
+func (s *Server) getDeliveries(c echo.Context) error {
+	s.logger.Trace("request received on /deliveries endpoint")
+
+	filter, err := parseDeliveriesFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := s.outboxStore.GetDeliveries(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusServiceUnavailable, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+func (s *Server) getDelivery(c echo.Context) error {
+	s.logger.Trace("request received on /deliveries/:id endpoint")
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid delivery id: %v", err))
+	}
+
+	delivery, err := s.outboxStore.GetDelivery(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(deliveryErrorStatus(err), err.Error())
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// redeliver resets the delivery in the outbox so that it's retried again,
+// regardless of its status.
+func (s *Server) redeliver(c echo.Context) error {
+	s.logger.Trace("request received on /deliveries/:id/redeliver endpoint")
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid delivery id: %v", err))
+	}
+
+	ctx := c.Request().Context()
+	delivery, err := s.outboxStore.GetDelivery(ctx, id)
+	if err != nil {
+		return c.JSON(deliveryErrorStatus(err), err.Error())
+	}
+
+	delivery.Redeliver()
+	if err := s.outboxStore.UpdateDelivery(ctx, delivery); err != nil {
+		return c.JSON(deliveryErrorStatus(err), err.Error())
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+func parseDeliveriesFilter(c echo.Context) (*outbox.Filter, error) {
+	filter := &outbox.Filter{
+		Status:         outbox.Status(c.QueryParam("status")),
+		SubscriptionID: c.QueryParam("subscription_id"),
+		URL:            c.QueryParam("url"),
+		Schema:         c.QueryParam("schema"),
+		Table:          c.QueryParam("table"),
+		Limit:          defaultDeliveriesLimit,
+	}
+
+	switch filter.Status {
+	case "", outbox.StatusPending, outbox.StatusFailed:
+	default:
+		return nil, fmt.Errorf("invalid delivery status: %q", filter.Status)
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			return nil, fmt.Errorf("invalid limit: %q", limit)
+		}
+	}
+
+	return filter, nil
+}
+
+func deliveryErrorStatus(err error) int {
+	if errors.Is(err, outboxstore.ErrDeliveryNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusServiceUnavailable
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox"
+	outboxstore "github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/outbox/store/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionServer_getDeliveries(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("oh noes")
+
+	tests := []struct {
+		name  string
+		query string
+		store *mocks.Store
+
+		wantStatusCode int
+	}{
+		{
+			name:  "ok",
+			query: "?status=failed&subscription_id=id-1&url=url-1&limit=10",
+			store: &mocks.Store{
+				GetDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+					require.Equal(t, &outbox.Filter{Status: outbox.StatusFailed, SubscriptionID: "id-1", URL: "url-1", Limit: 10}, filter)
+					return []*outbox.Delivery{{ID: 1}}, nil
+				},
+			},
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:  "ok - default limit",
+			query: "",
+			store: &mocks.Store{
+				GetDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+					require.Equal(t, &outbox.Filter{Limit: defaultDeliveriesLimit}, filter)
+					return []*outbox.Delivery{}, nil
+				},
+			},
+
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:  "error - invalid status",
+			query: "?status=delivered",
+			store: &mocks.Store{},
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error - invalid limit",
+			query: "?limit=-1",
+			store: &mocks.Store{},
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error - getting deliveries",
+			query: "",
+			store: &mocks.Store{
+				GetDeliveriesFn: func(ctx context.Context, filter *outbox.Filter) ([]*outbox.Delivery, error) {
+					return nil, errTest
+				},
+			},
+
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &Server{
+				logger:      log.NewNoopLogger(),
+				outboxStore: tc.store,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/deliveries"+tc.query, nil)
+			w := httptest.NewRecorder()
+			echoCtx := echo.New().NewContext(req, w)
+
+			server.getDeliveries(echoCtx)
+			require.Equal(t, tc.wantStatusCode, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestSubscriptionServer_redeliver(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("oh noes")
+	testDelivery := func() *outbox.Delivery {
+		return &outbox.Delivery{
+			ID:        1,
+			URL:       "url-1",
+			Status:    outbox.StatusFailed,
+			Attempts:  5,
+			LastError: "oh noes",
+		}
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		store *mocks.Store
+
+		wantStatusCode int
+	}{
+		{
+			name: "ok",
+			id:   "1",
+			store: &mocks.Store{
+				GetDeliveryFn: func(ctx context.Context, id int64) (*outbox.Delivery, error) {
+					require.Equal(t, int64(1), id)
+					return testDelivery(), nil
+				},
+				UpdateDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+					require.Equal(t, &outbox.Delivery{
+						ID:     1,
+						URL:    "url-1",
+						Status: outbox.StatusPending,
+					}, d)
+					return nil
+				},
+			},
+
+			wantStatusCode: http.StatusAccepted,
+		},
+		{
+			name:  "error - invalid id",
+			id:    "one",
+			store: &mocks.Store{},
+
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error - delivery not found",
+			id:   "1",
+			store: &mocks.Store{
+				GetDeliveryFn: func(ctx context.Context, id int64) (*outbox.Delivery, error) {
+					return nil, outboxstore.ErrDeliveryNotFound
+				},
+			},
+
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "error - updating delivery",
+			id:   "1",
+			store: &mocks.Store{
+				GetDeliveryFn: func(ctx context.Context, id int64) (*outbox.Delivery, error) {
+					return testDelivery(), nil
+				},
+				UpdateDeliveryFn: func(ctx context.Context, d *outbox.Delivery) error {
+					return errTest
+				},
+			},
+
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &Server{
+				logger:      log.NewNoopLogger(),
+				outboxStore: tc.store,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			w := httptest.NewRecorder()
+			echoCtx := echo.New().NewContext(req, w)
+			echoCtx.SetPath("/webhooks/deliveries/:id/redeliver")
+			echoCtx.SetParamNames("id")
+			echoCtx.SetParamValues(tc.id)
+
+			server.redeliver(echoCtx)
+			require.Equal(t, tc.wantStatusCode, w.Result().StatusCode)
+		})
+	}
+}
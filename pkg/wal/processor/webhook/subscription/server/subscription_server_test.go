@@ -87,6 +87,17 @@ func TestSubscriptionServer_subscribe(t *testing.T) {
 			method:         http.MethodPost,
 			wantStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "error - invalid expression",
+			store: &mocks.Store{
+				CreateSubscriptionFn: func(ctx context.Context, s *subscription.Subscription) error {
+					return errors.New("CreateSubscriptionFn: should not be called")
+				},
+			},
+			payload:        bytes.NewBufferString(`{"url":"url-1","expression":"new.status =="}`),
+			method:         http.MethodPost,
+			wantStatusCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range tests {
@@ -70,17 +70,68 @@ func (s *Store) CreateSubscription(ctx context.Context, subscription *subscripti
 	return s.inner.CreateSubscription(ctx, subscription)
 }
 
+func (s *Store) InsertSubscription(ctx context.Context, subscription *subscription.Subscription) error {
+	return s.inner.InsertSubscription(ctx, subscription)
+}
+
+// UpdateSubscription updates the subscription in the wrapped store, and
+// replaces it in the cache straight away so that pausing or resuming it takes
+// effect without waiting for the next sync.
+func (s *Store) UpdateSubscription(ctx context.Context, subscription *subscription.Subscription) error {
+	if err := s.inner.UpdateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+	for key, cached := range s.cache {
+		if cached.ID == subscription.ID {
+			delete(s.cache, key)
+		}
+	}
+	if subscription.IsActive() {
+		s.cache[subscription.Key()] = subscription
+	}
+	return nil
+}
+
 func (s *Store) DeleteSubscription(ctx context.Context, subscription *subscription.Subscription) error {
 	return s.inner.DeleteSubscription(ctx, subscription)
 }
 
-func (s *Store) GetSubscriptions(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+// DisableSubscriptions disables the subscriptions in the wrapped store, and
+// removes them from the cache straight away so that they're no longer
+// notified.
+func (s *Store) DisableSubscriptions(ctx context.Context, url string) error {
+	if err := s.inner.DisableSubscriptions(ctx, url); err != nil {
+		return err
+	}
+
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+	for key, subscription := range s.cache {
+		if subscription.URL == url {
+			delete(s.cache, key)
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetSubscription(ctx context.Context, id string) (*subscription.Subscription, error) {
+	return s.inner.GetSubscription(ctx, id)
+}
+
+func (s *Store) ListSubscriptions(ctx context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error) {
+	return s.inner.ListSubscriptions(ctx, filter)
+}
+
+func (s *Store) GetSubscriptions(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 	s.cacheLock.RLock()
 	defer s.cacheLock.RUnlock()
 
 	subscriptions := make([]*subscription.Subscription, 0, len(s.cache))
 	for _, subscription := range s.cache {
-		if subscription.IsFor(action, schema, table) {
+		if subscription.IsActive() && subscription.IsFor(action, schema, table) && subscription.IsForRow(row) {
 			subscriptions = append(subscriptions, subscription)
 		}
 	}
@@ -106,7 +157,7 @@ func (s *Store) syncRefresh(ctx context.Context) {
 
 func (s *Store) refresh(ctx context.Context) error {
 	// get all subscriptions and populate the cache
-	subscriptions, err := s.inner.GetSubscriptions(ctx, "", "", "")
+	subscriptions, err := s.inner.GetSubscriptions(ctx, "", "", "", nil)
 	if err != nil {
 		return fmt.Errorf("retrieving subscriptions: %w", err)
 	}
@@ -115,13 +166,16 @@ func (s *Store) refresh(ctx context.Context) error {
 	defer s.cacheLock.Unlock()
 
 	s.cache = make(map[string]*subscription.Subscription, len(subscriptions))
+	// only the keys are logged to avoid leaking the subscription credentials
+	keys := make([]string, 0, len(subscriptions))
 	for _, subscription := range subscriptions {
 		s.cache[subscription.Key()] = subscription
+		keys = append(keys, subscription.Key())
 	}
 
 	s.logger.Debug("cache refreshed", loglib.Fields{
 		"subscription_total_count": len(s.cache),
-		"subscriptions":            s.cache,
+		"subscriptions":            keys,
 	})
 
 	return nil
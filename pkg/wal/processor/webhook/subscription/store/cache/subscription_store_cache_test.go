@@ -29,7 +29,7 @@ func TestSubscriptionStoreCache_NewSubscriptionStoreCache(t *testing.T) {
 		{
 			name: "ok",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return []*subscription.Subscription{subscription1, subscription2}, nil
 				},
 			},
@@ -42,7 +42,7 @@ func TestSubscriptionStoreCache_NewSubscriptionStoreCache(t *testing.T) {
 		{
 			name: "error - refreshing cache",
 			store: &mocks.Store{
-				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
+				GetSubscriptionsFn: func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
 					return nil, errTest
 				},
 			},
@@ -71,6 +71,7 @@ func TestSubscriptionStoreCache_GetSubscriptions(t *testing.T) {
 	testSubscription1 := newTestSubscription("test-url-1", "test_schema", "test_table", []string{"D"})
 	testSubscription2 := newTestSubscription("test-url-2", "test_schema", "test_table", []string{"I"})
 	testSubscription3 := newTestSubscription("test-url-3", "", "", []string{"I"})
+	testSubscription3.ChangedColumns = []string{"status"}
 
 	tests := []struct {
 		name   string
@@ -78,6 +79,7 @@ func TestSubscriptionStoreCache_GetSubscriptions(t *testing.T) {
 		action string
 		schema string
 		table  string
+		row    *subscription.Row
 
 		wantSubscriptions []*subscription.Subscription
 		wantErr           error
@@ -92,6 +94,18 @@ func TestSubscriptionStoreCache_GetSubscriptions(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name:   "ok - with row filters",
+			action: "I",
+			row: &subscription.Row{
+				ChangedColumns: []string{"name"},
+			},
+
+			wantSubscriptions: []*subscription.Subscription{
+				testSubscription2,
+			},
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range tests {
@@ -109,9 +123,119 @@ func TestSubscriptionStoreCache_GetSubscriptions(t *testing.T) {
 				},
 			}
 
-			subscriptions, err := cacheStore.GetSubscriptions(context.Background(), tc.action, tc.schema, tc.table)
+			subscriptions, err := cacheStore.GetSubscriptions(context.Background(), tc.action, tc.schema, tc.table, tc.row)
 			require.ErrorIs(t, err, tc.wantErr)
 			require.ElementsMatch(t, tc.wantSubscriptions, subscriptions)
 		})
 	}
 }
+
+func TestSubscriptionStoreCache_DisableSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	testSubscription1 := newTestSubscription("test-url-1", "test_schema", "test_table", nil)
+	testSubscription2 := newTestSubscription("test-url-1", "", "", nil)
+	testSubscription3 := newTestSubscription("test-url-2", "", "", nil)
+
+	cacheStore := &Store{
+		inner: &mocks.Store{
+			DisableSubscriptionsFn: func(ctx context.Context, url string) error {
+				require.Equal(t, "test-url-1", url)
+				return nil
+			},
+		},
+		cacheLock: &sync.RWMutex{},
+		cache: map[string]*subscription.Subscription{
+			testSubscription1.Key(): testSubscription1,
+			testSubscription2.Key(): testSubscription2,
+			testSubscription3.Key(): testSubscription3,
+		},
+	}
+
+	err := cacheStore.DisableSubscriptions(context.Background(), "test-url-1")
+	require.NoError(t, err)
+
+	subscriptions, err := cacheStore.GetSubscriptions(context.Background(), "", "", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []*subscription.Subscription{testSubscription3}, subscriptions)
+}
+
+func TestSubscriptionStoreCache_UpdateSubscription(t *testing.T) {
+	t.Parallel()
+
+	testSubscription := func(id, url string, paused bool) *subscription.Subscription {
+		s := newTestSubscription(url, "test_schema", "test_table", nil)
+		s.ID = id
+		s.Paused = paused
+		return s
+	}
+
+	tests := []struct {
+		name         string
+		subscription *subscription.Subscription
+		updateErr    error
+
+		wantSubscriptions []*subscription.Subscription
+		wantErr           error
+	}{
+		{
+			name:         "ok - url updated",
+			subscription: testSubscription("id-1", "test-url-3", false),
+
+			wantSubscriptions: []*subscription.Subscription{
+				testSubscription("id-1", "test-url-3", false),
+				testSubscription("id-2", "test-url-2", false),
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "ok - paused",
+			subscription: testSubscription("id-1", "test-url-1", true),
+
+			wantSubscriptions: []*subscription.Subscription{
+				testSubscription("id-2", "test-url-2", false),
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "error - updating subscription",
+			subscription: testSubscription("id-1", "test-url-1", true),
+			updateErr:    errTest,
+
+			wantSubscriptions: []*subscription.Subscription{
+				testSubscription("id-1", "test-url-1", false),
+				testSubscription("id-2", "test-url-2", false),
+			},
+			wantErr: errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testSubscription1 := testSubscription("id-1", "test-url-1", false)
+			testSubscription2 := testSubscription("id-2", "test-url-2", false)
+			cacheStore := &Store{
+				inner: &mocks.Store{
+					UpdateSubscriptionFn: func(ctx context.Context, s *subscription.Subscription) error {
+						return tc.updateErr
+					},
+				},
+				cacheLock: &sync.RWMutex{},
+				cache: map[string]*subscription.Subscription{
+					testSubscription1.Key(): testSubscription1,
+					testSubscription2.Key(): testSubscription2,
+				},
+			}
+
+			err := cacheStore.UpdateSubscription(context.Background(), tc.subscription)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			subscriptions, err := cacheStore.GetSubscriptions(context.Background(), "", "", "", nil)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.wantSubscriptions, subscriptions)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
+)
+
+// Store is an in memory implementation of the subscription store, with the
+// same semantics as the persistent stores. It is concurrency safe, and meant
+// for tests and local development, since subscriptions are lost on restart.
+type Store struct {
+	mutex         *sync.RWMutex
+	subscriptions map[string]*subscription.Subscription
+}
+
+func NewSubscriptionStore() *Store {
+	return &Store{
+		mutex:         &sync.RWMutex{},
+		subscriptions: map[string]*subscription.Subscription{},
+	}
+}
+
+func (s *Store) CreateSubscription(_ context.Context, sub *subscription.Subscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub.ID = uuid.NewString()
+	if existing := s.getByKey(sub.Key()); existing != nil {
+		sub.ID = existing.ID
+	}
+	s.subscriptions[sub.ID] = copySubscription(sub)
+	return nil
+}
+
+func (s *Store) InsertSubscription(_ context.Context, sub *subscription.Subscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if existing := s.getByKey(sub.Key()); existing != nil {
+		return store.ErrSubscriptionConflict
+	}
+	sub.ID = uuid.NewString()
+	s.subscriptions[sub.ID] = copySubscription(sub)
+	return nil
+}
+
+func (s *Store) UpdateSubscription(_ context.Context, sub *subscription.Subscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, found := s.subscriptions[sub.ID]; !found {
+		return store.ErrSubscriptionNotFound
+	}
+	if existing := s.getByKey(sub.Key()); existing != nil && existing.ID != sub.ID {
+		return store.ErrSubscriptionConflict
+	}
+	s.subscriptions[sub.ID] = copySubscription(sub)
+	return nil
+}
+
+func (s *Store) DeleteSubscription(_ context.Context, sub *subscription.Subscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if sub.ID != "" {
+		delete(s.subscriptions, sub.ID)
+		return nil
+	}
+	if existing := s.getByKey(sub.Key()); existing != nil {
+		delete(s.subscriptions, existing.ID)
+	}
+	return nil
+}
+
+func (s *Store) GetSubscription(_ context.Context, id string) (*subscription.Subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	sub, found := s.subscriptions[id]
+	if !found {
+		return nil, store.ErrSubscriptionNotFound
+	}
+	return copySubscription(sub), nil
+}
+
+func (s *Store) ListSubscriptions(_ context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	subscriptions := []*subscription.Subscription{}
+	for _, sub := range s.subscriptions {
+		if filter != nil {
+			if (filter.URL != "" && sub.URL != filter.URL) ||
+				(filter.Schema != "" && sub.Schema != filter.Schema) ||
+				(filter.Table != "" && sub.Table != filter.Table) ||
+				(filter.Cursor != "" && sub.ID <= filter.Cursor) {
+				continue
+			}
+		}
+		subscriptions = append(subscriptions, copySubscription(sub))
+	}
+
+	slices.SortFunc(subscriptions, func(a, b *subscription.Subscription) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	if filter != nil && filter.Limit > 0 && len(subscriptions) > filter.Limit {
+		subscriptions = subscriptions[:filter.Limit]
+	}
+	return subscriptions, nil
+}
+
+func (s *Store) GetSubscriptions(_ context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	subscriptions := []*subscription.Subscription{}
+	for _, sub := range s.subscriptions {
+		if sub.IsActive() && sub.IsFor(action, schema, table) && sub.IsForRow(row) {
+			subscriptions = append(subscriptions, copySubscription(sub))
+		}
+	}
+	return subscriptions, nil
+}
+
+func (s *Store) DisableSubscriptions(_ context.Context, url string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, sub := range s.subscriptions {
+		if sub.URL == url {
+			sub.Disabled = true
+		}
+	}
+	return nil
+}
+
+func (s *Store) getByKey(key string) *subscription.Subscription {
+	for _, sub := range s.subscriptions {
+		if sub.Key() == key {
+			return sub
+		}
+	}
+	return nil
+}
+
+// copySubscription returns a copy of the subscription, so that callers can't
+// modify the stored subscriptions. Nested filter values are not copied.
+func copySubscription(sub *subscription.Subscription) *subscription.Subscription {
+	c := *sub
+	c.EventTypes = slices.Clone(sub.EventTypes)
+	c.ChangedColumns = slices.Clone(sub.ChangedColumns)
+	c.NewValues = maps.Clone(sub.NewValues)
+	c.OldValues = maps.Clone(sub.OldValues)
+	c.Headers = maps.Clone(sub.Headers)
+	if sub.Batch != nil {
+		batch := *sub.Batch
+		c.Batch = &batch
+	}
+	return &c
+}
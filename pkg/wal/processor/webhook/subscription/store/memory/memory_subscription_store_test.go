@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewSubscriptionStore()
+
+	sub1 := &subscription.Subscription{URL: "url-1", Schema: "test_schema", Table: "test_table"}
+	require.NoError(t, s.CreateSubscription(ctx, sub1))
+	require.NotEmpty(t, sub1.ID)
+
+	sub2 := &subscription.Subscription{URL: "url-2"}
+	require.NoError(t, s.CreateSubscription(ctx, sub2))
+
+	// recreating a subscription keeps its id
+	recreated := &subscription.Subscription{URL: "url-1", Schema: "test_schema", Table: "test_table", EventTypes: []string{"I"}}
+	require.NoError(t, s.CreateSubscription(ctx, recreated))
+	require.Equal(t, sub1.ID, recreated.ID)
+
+	got, err := s.GetSubscription(ctx, sub1.ID)
+	require.NoError(t, err)
+	require.Equal(t, recreated, got)
+
+	// inserting an existing subscription conflicts and keeps the existing one
+	require.ErrorIs(t, s.InsertSubscription(ctx, &subscription.Subscription{URL: "url-1", Schema: "test_schema", Table: "test_table"}), store.ErrSubscriptionConflict)
+	got, err = s.GetSubscription(ctx, sub1.ID)
+	require.NoError(t, err)
+	require.Equal(t, recreated, got)
+
+	inserted := &subscription.Subscription{URL: "url-3"}
+	require.NoError(t, s.InsertSubscription(ctx, inserted))
+	require.NotEmpty(t, inserted.ID)
+	require.NoError(t, s.DeleteSubscription(ctx, inserted))
+
+	// updates
+	got.Paused = true
+	require.NoError(t, s.UpdateSubscription(ctx, got))
+	require.ErrorIs(t, s.UpdateSubscription(ctx, &subscription.Subscription{ID: sub1.ID, URL: "url-2"}), store.ErrSubscriptionConflict)
+	require.ErrorIs(t, s.UpdateSubscription(ctx, &subscription.Subscription{ID: "unknown", URL: "url-3"}), store.ErrSubscriptionNotFound)
+
+	// paused and disabled subscriptions are not active
+	active, err := s.GetSubscriptions(ctx, "I", "test_schema", "test_table", nil)
+	require.NoError(t, err)
+	require.Equal(t, []*subscription.Subscription{sub2}, active)
+
+	require.NoError(t, s.DisableSubscriptions(ctx, "url-2"))
+	active, err = s.GetSubscriptions(ctx, "", "", "", nil)
+	require.NoError(t, err)
+	require.Empty(t, active)
+
+	// list
+	all, err := s.ListSubscriptions(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	page, err := s.ListSubscriptions(ctx, &subscription.ListFilter{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []*subscription.Subscription{all[0]}, page)
+
+	page, err = s.ListSubscriptions(ctx, &subscription.ListFilter{Limit: 1, Cursor: all[0].ID})
+	require.NoError(t, err)
+	require.Equal(t, []*subscription.Subscription{all[1]}, page)
+
+	filtered, err := s.ListSubscriptions(ctx, &subscription.ListFilter{URL: "url-2"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, sub2.ID, filtered[0].ID)
+
+	// delete by id and by url, schema and table
+	require.NoError(t, s.DeleteSubscription(ctx, &subscription.Subscription{ID: sub1.ID}))
+	_, err = s.GetSubscription(ctx, sub1.ID)
+	require.ErrorIs(t, err, store.ErrSubscriptionNotFound)
+
+	require.NoError(t, s.DeleteSubscription(ctx, &subscription.Subscription{URL: "url-2"}))
+	all, err = s.ListSubscriptions(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, all)
+}
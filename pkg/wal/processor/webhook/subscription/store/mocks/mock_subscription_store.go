@@ -9,19 +9,44 @@ import (
 )
 
 type Store struct {
-	CreateSubscriptionFn func(ctx context.Context, s *subscription.Subscription) error
-	DeleteSubscriptionFn func(ctx context.Context, s *subscription.Subscription) error
-	GetSubscriptionsFn   func(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error)
+	CreateSubscriptionFn   func(ctx context.Context, s *subscription.Subscription) error
+	InsertSubscriptionFn   func(ctx context.Context, s *subscription.Subscription) error
+	UpdateSubscriptionFn   func(ctx context.Context, s *subscription.Subscription) error
+	DeleteSubscriptionFn   func(ctx context.Context, s *subscription.Subscription) error
+	GetSubscriptionFn      func(ctx context.Context, id string) (*subscription.Subscription, error)
+	ListSubscriptionsFn    func(ctx context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error)
+	GetSubscriptionsFn     func(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error)
+	DisableSubscriptionsFn func(ctx context.Context, url string) error
 }
 
 func (m *Store) CreateSubscription(ctx context.Context, s *subscription.Subscription) error {
 	return m.CreateSubscriptionFn(ctx, s)
 }
 
+func (m *Store) InsertSubscription(ctx context.Context, s *subscription.Subscription) error {
+	return m.InsertSubscriptionFn(ctx, s)
+}
+
+func (m *Store) UpdateSubscription(ctx context.Context, s *subscription.Subscription) error {
+	return m.UpdateSubscriptionFn(ctx, s)
+}
+
 func (m *Store) DeleteSubscription(ctx context.Context, s *subscription.Subscription) error {
 	return m.DeleteSubscriptionFn(ctx, s)
 }
 
-func (m *Store) GetSubscriptions(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
-	return m.GetSubscriptionsFn(ctx, action, schema, table)
+func (m *Store) GetSubscription(ctx context.Context, id string) (*subscription.Subscription, error) {
+	return m.GetSubscriptionFn(ctx, id)
+}
+
+func (m *Store) ListSubscriptions(ctx context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error) {
+	return m.ListSubscriptionsFn(ctx, filter)
+}
+
+func (m *Store) GetSubscriptions(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+	return m.GetSubscriptionsFn(ctx, action, schema, table, row)
+}
+
+func (m *Store) DisableSubscriptions(ctx context.Context, url string) error {
+	return m.DisableSubscriptionsFn(ctx, url)
 }
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+)
+
+// credentials contains the subscription fields that are stored encrypted at
+// rest.
+type credentials struct {
+	Secret      string            `json:"secret,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// encrypter uses AES-GCM to encrypt the subscription credentials. The nonce
+// is prepended to the ciphertext.
+type encrypter struct {
+	aead cipher.AEAD
+}
+
+var (
+	errEncryptionKeyRequired = errors.New("encryption key required for subscription credentials")
+	errInvalidCiphertext     = errors.New("invalid credentials ciphertext")
+)
+
+// newEncrypter returns an encrypter for the base64 encoded key on input. The
+// key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func newEncrypter(encodedKey string) (*encrypter, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating encryption cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating encryption cipher: %w", err)
+	}
+	return &encrypter{aead: aead}, nil
+}
+
+func (e *encrypter) encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return e.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (e *encrypter) decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := e.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errInvalidCiphertext
+	}
+	plaintext, err := e.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidCiphertext, err)
+	}
+	return plaintext, nil
+}
+
+// encryptCredentials returns the encrypted credentials of the subscription,
+// or nil if it has none.
+func (s *Store) encryptCredentials(sub *subscription.Subscription) ([]byte, error) {
+	if !sub.HasCredentials() {
+		return nil, nil
+	}
+	if s.encrypter == nil {
+		return nil, errEncryptionKeyRequired
+	}
+
+	plaintext, err := json.Marshal(&credentials{
+		Secret:      sub.Secret,
+		BearerToken: sub.BearerToken,
+		Headers:     sub.Headers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling credentials: %w", err)
+	}
+	return s.encrypter.encrypt(plaintext)
+}
+
+// decryptCredentials sets the decrypted credentials on the subscription.
+func (s *Store) decryptCredentials(sub *subscription.Subscription, ciphertext []byte) error {
+	if len(ciphertext) == 0 {
+		return nil
+	}
+	if s.encrypter == nil {
+		return errEncryptionKeyRequired
+	}
+
+	plaintext, err := s.encrypter.decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	creds := &credentials{}
+	if err := json.Unmarshal(plaintext, creds); err != nil {
+		return fmt.Errorf("unmarshalling credentials: %w", err)
+	}
+	sub.Secret = creds.Secret
+	sub.BearerToken = creds.BearerToken
+	sub.Headers = creds.Headers
+	return nil
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_credentials(t *testing.T) {
+	t.Parallel()
+
+	testKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	testEncrypter := func(t *testing.T, key string) *encrypter {
+		e, err := newEncrypter(key)
+		require.NoError(t, err)
+		return e
+	}
+
+	tests := []struct {
+		name         string
+		encrypter    func(t *testing.T) *encrypter
+		subscription *subscription.Subscription
+
+		wantErr error
+	}{
+		{
+			name:      "ok",
+			encrypter: func(t *testing.T) *encrypter { return testEncrypter(t, testKey) },
+			subscription: &subscription.Subscription{
+				URL:         "url-1",
+				Secret:      "test-secret",
+				BearerToken: "test-token",
+				Headers:     map[string]string{"X-Api-Key": "test-key"},
+			},
+
+			wantErr: nil,
+		},
+		{
+			name:         "ok - no credentials",
+			encrypter:    func(t *testing.T) *encrypter { return nil },
+			subscription: &subscription.Subscription{URL: "url-1"},
+
+			wantErr: nil,
+		},
+		{
+			name:      "error - no encryption key",
+			encrypter: func(t *testing.T) *encrypter { return nil },
+			subscription: &subscription.Subscription{
+				URL:    "url-1",
+				Secret: "test-secret",
+			},
+
+			wantErr: errEncryptionKeyRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Store{encrypter: tc.encrypter(t)}
+			ciphertext, err := s.encryptCredentials(tc.subscription)
+			require.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			if tc.subscription.Secret != "" {
+				require.NotContains(t, string(ciphertext), tc.subscription.Secret)
+			}
+
+			got := &subscription.Subscription{URL: tc.subscription.URL}
+			err = s.decryptCredentials(got, ciphertext)
+			require.NoError(t, err)
+			require.Equal(t, tc.subscription, got)
+		})
+	}
+}
+
+func TestStore_decryptCredentials_wrongKey(t *testing.T) {
+	t.Parallel()
+
+	encrypter, err := newEncrypter(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+	require.NoError(t, err)
+	otherEncrypter, err := newEncrypter(base64.StdEncoding.EncodeToString([]byte("fedcba9876543210")))
+	require.NoError(t, err)
+
+	ciphertext, err := (&Store{encrypter: encrypter}).encryptCredentials(&subscription.Subscription{Secret: "test-secret"})
+	require.NoError(t, err)
+
+	err = (&Store{encrypter: otherEncrypter}).decryptCredentials(&subscription.Subscription{}, ciphertext)
+	require.ErrorIs(t, err, errInvalidCiphertext)
+}
+
+func TestNewEncrypter(t *testing.T) {
+	t.Parallel()
+
+	_, err := newEncrypter("not base64!")
+	require.Error(t, err)
+
+	_, err = newEncrypter(base64.StdEncoding.EncodeToString([]byte("short")))
+	require.Error(t, err)
+}
@@ -3,17 +3,29 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
 	pglib "github.com/ApollosProject/pgstream-wal2json/internal/postgres"
 	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription/store"
 )
 
 type Store struct {
-	conn   pglib.Querier
-	logger loglib.Logger
+	conn          pglib.Querier
+	logger        loglib.Logger
+	encryptionKey string
+	encrypter     *encrypter
 }
 
 type Option func(*Store)
@@ -21,6 +33,8 @@ type Option func(*Store)
 const (
 	subscriptionsTableName = "webhook_subscriptions"
 	pgstreamSchema         = "pgstream"
+
+	subscriptionColumns = "id, url, schema_name, table_name, event_types, changed_columns, new_values, old_values, credentials, disabled, paused, batch, expression"
 )
 
 func NewSubscriptionStore(ctx context.Context, url string, opts ...Option) (*Store, error) {
@@ -36,6 +50,13 @@ func NewSubscriptionStore(ctx context.Context, url string, opts ...Option) (*Sto
 		opt(ss)
 	}
 
+	if ss.encryptionKey != "" {
+		ss.encrypter, err = newEncrypter(ss.encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// create subscriptions table if it doesn't exist
 	if err := ss.createTable(ctx); err != nil {
 		return nil, fmt.Errorf("creating subscriptions table: %w", err)
@@ -52,22 +73,124 @@ func WithLogger(l loglib.Logger) Option {
 	}
 }
 
+// WithEncryptionKey sets the base64 encoded AES key used to encrypt the
+// subscription credentials at rest. Subscriptions with credentials can't be
+// stored or retrieved without it.
+func WithEncryptionKey(key string) Option {
+	return func(ss *Store) {
+		ss.encryptionKey = key
+	}
+}
+
 func (s *Store) CreateSubscription(ctx context.Context, subscription *subscription.Subscription) error {
+	credentials, err := s.encryptCredentials(subscription)
+	if err != nil {
+		return fmt.Errorf("encrypting subscription credentials: %w", err)
+	}
+	batch, err := marshalBatch(subscription.Batch)
+	if err != nil {
+		return err
+	}
+
+	// the existing subscription keeps its ID when it's recreated
+	query := fmt.Sprintf(`
+	INSERT INTO %s(id, url, schema_name, table_name, event_types, changed_columns, new_values, old_values, credentials, disabled, paused, batch, expression) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	ON CONFLICT (url,schema_name,table_name) DO UPDATE SET event_types = EXCLUDED.event_types, changed_columns = EXCLUDED.changed_columns,
+	new_values = EXCLUDED.new_values, old_values = EXCLUDED.old_values, credentials = EXCLUDED.credentials, disabled = EXCLUDED.disabled,
+	paused = EXCLUDED.paused, batch = EXCLUDED.batch, expression = EXCLUDED.expression RETURNING id;`, subscriptionsTable())
+	return s.conn.QueryRow(ctx, query, uuid.NewString(), subscription.URL, subscription.Schema, subscription.Table, subscription.EventTypes,
+		nilIfEmpty(subscription.ChangedColumns), nilIfEmpty(subscription.NewValues), nilIfEmpty(subscription.OldValues), credentials,
+		subscription.Disabled, subscription.Paused, batch, subscription.Expression).Scan(&subscription.ID)
+}
+
+func (s *Store) InsertSubscription(ctx context.Context, subscription *subscription.Subscription) error {
+	credentials, err := s.encryptCredentials(subscription)
+	if err != nil {
+		return fmt.Errorf("encrypting subscription credentials: %w", err)
+	}
+	batch, err := marshalBatch(subscription.Batch)
+	if err != nil {
+		return err
+	}
+
+	id := uuid.NewString()
 	query := fmt.Sprintf(`
-	INSERT INTO %s(url, schema_name, table_name, event_types) VALUES($1, $2, $3, $4)
-	ON CONFLICT (url,schema_name,table_name) DO UPDATE SET event_types = EXCLUDED.event_types;`, subscriptionsTable())
-	_, err := s.conn.Exec(ctx, query, subscription.URL, subscription.Schema, subscription.Table, subscription.EventTypes)
+	INSERT INTO %s(id, url, schema_name, table_name, event_types, changed_columns, new_values, old_values, credentials, disabled, paused, batch, expression)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`, subscriptionsTable())
+	if _, err := s.conn.Exec(ctx, query, id, subscription.URL, subscription.Schema, subscription.Table, subscription.EventTypes,
+		nilIfEmpty(subscription.ChangedColumns), nilIfEmpty(subscription.NewValues), nilIfEmpty(subscription.OldValues), credentials,
+		subscription.Disabled, subscription.Paused, batch, subscription.Expression); err != nil {
+		return mapError(err)
+	}
+	subscription.ID = id
+	return nil
+}
+
+func (s *Store) UpdateSubscription(ctx context.Context, subscription *subscription.Subscription) error {
+	credentials, err := s.encryptCredentials(subscription)
+	if err != nil {
+		return fmt.Errorf("encrypting subscription credentials: %w", err)
+	}
+	batch, err := marshalBatch(subscription.Batch)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET url = $2, schema_name = $3, table_name = $4, event_types = $5, changed_columns = $6,
+	new_values = $7, old_values = $8, credentials = $9, disabled = $10, paused = $11, batch = $12, expression = $13 WHERE id = $1`, subscriptionsTable())
+	tag, err := s.conn.Exec(ctx, query, subscription.ID, subscription.URL, subscription.Schema, subscription.Table, subscription.EventTypes,
+		nilIfEmpty(subscription.ChangedColumns), nilIfEmpty(subscription.NewValues), nilIfEmpty(subscription.OldValues), credentials,
+		subscription.Disabled, subscription.Paused, batch, subscription.Expression)
+	if err != nil {
+		return mapError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (s *Store) DisableSubscriptions(ctx context.Context, url string) error {
+	query := fmt.Sprintf(`UPDATE %s SET disabled = true WHERE url=$1;`, subscriptionsTable())
+	_, err := s.conn.Exec(ctx, query, url)
 	return err
 }
 
 func (s *Store) DeleteSubscription(ctx context.Context, subscription *subscription.Subscription) error {
-	query := fmt.Sprintf(`DELETE FROM %s WHERE url=$1 AND schema_name=$2 AND table=$3;`, subscriptionsTable())
+	if subscription.ID != "" {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE id=$1;`, subscriptionsTable())
+		_, err := s.conn.Exec(ctx, query, subscription.ID)
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE url=$1 AND schema_name=$2 AND table_name=$3;`, subscriptionsTable())
 	_, err := s.conn.Exec(ctx, query, subscription.URL, subscription.Schema, subscription.Table)
 	return err
 }
 
-func (s *Store) GetSubscriptions(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error) {
-	query, params := s.buildGetQuery(action, schema, table)
+func (s *Store) GetSubscription(ctx context.Context, id string) (*subscription.Subscription, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id=$1`, subscriptionColumns, subscriptionsTable())
+	subscription, err := s.scanSubscription(s.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return subscription, nil
+}
+
+func (s *Store) ListSubscriptions(ctx context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error) {
+	query, params := buildListQuery(filter)
+	return s.querySubscriptions(ctx, query, params)
+}
+
+func (s *Store) GetSubscriptions(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error) {
+	query, params, err := s.buildGetQuery(action, schema, table, row)
+	if err != nil {
+		return nil, fmt.Errorf("building subscriptions query: %w", err)
+	}
+	return s.querySubscriptions(ctx, query, params)
+}
+
+func (s *Store) querySubscriptions(ctx context.Context, query string, params []any) ([]*subscription.Subscription, error) {
 	s.logger.Trace("getting subscriptions", loglib.Fields{
 		"query":  query,
 		"params": params,
@@ -80,52 +203,209 @@ func (s *Store) GetSubscriptions(ctx context.Context, action, schema, table stri
 
 	subscriptions := []*subscription.Subscription{}
 	for rows.Next() {
-		subscription := &subscription.Subscription{}
-		if err := rows.Scan(&subscription.URL, &subscription.Schema, &subscription.Table, &subscription.EventTypes); err != nil {
-			return nil, fmt.Errorf("scanning subscription row: %w", err)
+		subscription, err := s.scanSubscription(rows)
+		if err != nil {
+			return nil, err
 		}
-
 		subscriptions = append(subscriptions, subscription)
 	}
 
-	return subscriptions, nil
+	return subscriptions, rows.Err()
+}
+
+func (s *Store) scanSubscription(row pglib.Row) (*subscription.Subscription, error) {
+	subscription := &subscription.Subscription{}
+	var newValues, oldValues, credentials, batch []byte
+	if err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Schema, &subscription.Table, &subscription.EventTypes,
+		&subscription.ChangedColumns, &newValues, &oldValues, &credentials,
+		&subscription.Disabled, &subscription.Paused, &batch, &subscription.Expression); err != nil {
+		return nil, fmt.Errorf("scanning subscription row: %w", err)
+	}
+	var err error
+	if subscription.NewValues, err = unmarshalValues(newValues); err != nil {
+		return nil, fmt.Errorf("unmarshalling subscription new values: %w", err)
+	}
+	if subscription.OldValues, err = unmarshalValues(oldValues); err != nil {
+		return nil, fmt.Errorf("unmarshalling subscription old values: %w", err)
+	}
+	if batch != nil {
+		if err := json.Unmarshal(batch, &subscription.Batch); err != nil {
+			return nil, fmt.Errorf("unmarshalling subscription batch: %w", err)
+		}
+	}
+	if err := s.decryptCredentials(subscription, credentials); err != nil {
+		return nil, fmt.Errorf("decrypting subscription credentials: %w", err)
+	}
+	return subscription, nil
+}
+
+// unmarshalValues decodes the column values filter, keeping the numbers as
+// json numbers so that they can be compared without losing precision.
+func unmarshalValues(data []byte) (map[string]any, error) {
+	if data == nil {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	values := map[string]any{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
 
 func (s *Store) createTable(ctx context.Context) error {
 	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s(
+	id TEXT,
 	url TEXT,
 	schema_name TEXT,
 	table_name TEXT,
 	event_types TEXT[],
+	changed_columns TEXT[],
+	new_values JSONB,
+	old_values JSONB,
+	credentials BYTEA,
+	disabled BOOLEAN NOT NULL DEFAULT false,
+	paused BOOLEAN NOT NULL DEFAULT false,
+	batch JSONB,
+	expression TEXT NOT NULL DEFAULT '',
 	PRIMARY KEY(url,schema_name,table_name))`, subscriptionsTable())
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	// tables created by previous versions need the column filters,
+	// credentials, status, ids, batch settings and expressions added
+	query = fmt.Sprintf(`ALTER TABLE %s
+	ADD COLUMN IF NOT EXISTS changed_columns TEXT[],
+	ADD COLUMN IF NOT EXISTS new_values JSONB,
+	ADD COLUMN IF NOT EXISTS old_values JSONB,
+	ADD COLUMN IF NOT EXISTS credentials BYTEA,
+	ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS paused BOOLEAN NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS id TEXT,
+	ADD COLUMN IF NOT EXISTS batch JSONB,
+	ADD COLUMN IF NOT EXISTS expression TEXT NOT NULL DEFAULT ''`, subscriptionsTable())
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET id = md5(random()::text || clock_timestamp()::text)::uuid::text WHERE id IS NULL`, subscriptionsTable())
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s_id_idx ON %s(id)`, subscriptionsTableName, subscriptionsTable())
 	_, err := s.conn.Exec(ctx, query)
 	return err
 }
 
-func (s *Store) buildGetQuery(action, schema, table string) (string, []any) {
-	query := fmt.Sprintf(`SELECT url, schema_name, table_name, event_types FROM %s`, subscriptionsTable())
-
-	separator := func(params []any) string {
-		if len(params) == 0 {
-			return "WHERE"
-		}
-		return "AND"
-	}
+func (s *Store) buildGetQuery(action, schema, table string, row *subscription.Row) (string, []any, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE NOT disabled AND NOT paused`, subscriptionColumns, subscriptionsTable())
 	var params []any
 	if schema != "" {
-		query = fmt.Sprintf("%s %s (schema_name=$%d OR schema_name='')", query, separator(params), len(params)+1)
+		query = fmt.Sprintf("%s AND (schema_name=$%d OR schema_name='')", query, len(params)+1)
 		params = append(params, schema)
 	}
 	if table != "" {
-		query = fmt.Sprintf("%s %s (table_name=$%d OR table_name='')", query, separator(params), len(params)+1)
+		query = fmt.Sprintf("%s AND (table_name=$%d OR table_name='')", query, len(params)+1)
 		params = append(params, table)
 	}
 	if action != "" {
-		query = fmt.Sprintf("%s %s ($%d=ANY(event_types) OR event_types IS NULL)", query, separator(params), len(params)+1)
+		query = fmt.Sprintf("%s AND ($%d=ANY(event_types) OR event_types IS NULL)", query, len(params)+1)
 		params = append(params, action)
 	}
+	// schema change events only match the subscriptions not scoped to a table
+	if action == wal.SchemaChangeAction {
+		query = fmt.Sprintf("%s AND table_name=''", query)
+	}
+	if row != nil {
+		newValues, err := json.Marshal(row.NewValues)
+		if err != nil {
+			return "", nil, fmt.Errorf("marshalling new values: %w", err)
+		}
+		oldValues, err := json.Marshal(row.OldValues)
+		if err != nil {
+			return "", nil, fmt.Errorf("marshalling old values: %w", err)
+		}
+		query = fmt.Sprintf("%s AND (changed_columns IS NULL OR changed_columns && $%d)", query, len(params)+1)
+		params = append(params, row.ChangedColumns)
+		query = fmt.Sprintf("%s AND (new_values IS NULL OR new_values <@ $%d::jsonb)", query, len(params)+1)
+		params = append(params, string(newValues))
+		query = fmt.Sprintf("%s AND (old_values IS NULL OR old_values <@ $%d::jsonb)", query, len(params)+1)
+		params = append(params, string(oldValues))
+	}
+
+	return fmt.Sprintf("%s LIMIT 1000", query), params, nil
+}
+
+func buildListQuery(filter *subscription.ListFilter) (string, []any) {
+	query := fmt.Sprintf(`SELECT %s FROM %s`, subscriptionColumns, subscriptionsTable())
+	if filter == nil {
+		return fmt.Sprintf("%s ORDER BY id", query), nil
+	}
 
-	return fmt.Sprintf("%s LIMIT 1000", query), params
+	conditions := []struct {
+		condition string
+		value     string
+	}{
+		{condition: "url = $%d", value: filter.URL},
+		{condition: "schema_name = $%d", value: filter.Schema},
+		{condition: "table_name = $%d", value: filter.Table},
+		{condition: "id > $%d", value: filter.Cursor},
+	}
+
+	var params []any
+	for _, c := range conditions {
+		if c.value == "" {
+			continue
+		}
+		separator := "AND"
+		if len(params) == 0 {
+			separator = "WHERE"
+		}
+		params = append(params, c.value)
+		query = fmt.Sprintf("%s %s %s", query, separator, fmt.Sprintf(c.condition, len(params)))
+	}
+
+	query = fmt.Sprintf("%s ORDER BY id", query)
+	if filter.Limit > 0 {
+		query = fmt.Sprintf("%s LIMIT %d", query, filter.Limit)
+	}
+	return query, params
+}
+
+func mapError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", store.ErrSubscriptionNotFound, err)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		return fmt.Errorf("%w: %w", store.ErrSubscriptionConflict, err)
+	}
+	return err
+}
+
+// marshalBatch returns the batch settings to be stored in the batch column,
+// or nil if the subscription deliveries are not batched.
+func marshalBatch(batch *subscription.Batch) ([]byte, error) {
+	if batch == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling subscription batch: %w", err)
+	}
+	return b, nil
+}
+
+// nilIfEmpty stores the empty column filters as NULL, so that they're not
+// evaluated.
+func nilIfEmpty[T []string | map[string]any](v T) any {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
 }
 
 func subscriptionsTable() string {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,31 +18,39 @@ func TestStore_buildGetQuery(t *testing.T) {
 		action string
 		schema string
 		table  string
+		row    *subscription.Row
 
 		wantQuery  string
 		wantParams []any
 	}{
 		{
 			name:       "no filters",
-			wantQuery:  fmt.Sprintf(`SELECT url, schema_name, table_name, event_types FROM %s LIMIT 1000`, subscriptionsTable()),
+			wantQuery:  fmt.Sprintf(`SELECT %s FROM %s WHERE NOT disabled AND NOT paused LIMIT 1000`, subscriptionColumns, subscriptionsTable()),
 			wantParams: nil,
 		},
 		{
 			name:       "with action filter",
 			action:     "I",
-			wantQuery:  fmt.Sprintf(`SELECT url, schema_name, table_name, event_types FROM %s WHERE ($1=ANY(event_types) OR event_types IS NULL) LIMIT 1000`, subscriptionsTable()),
+			wantQuery:  fmt.Sprintf(`SELECT %s FROM %s WHERE NOT disabled AND NOT paused AND ($1=ANY(event_types) OR event_types IS NULL) LIMIT 1000`, subscriptionColumns, subscriptionsTable()),
 			wantParams: []any{"I"},
 		},
+		{
+			name:       "with schema change action filter",
+			action:     "DDL",
+			schema:     "test_schema",
+			wantQuery:  fmt.Sprintf(`SELECT %s FROM %s WHERE NOT disabled AND NOT paused AND (schema_name=$1 OR schema_name='') AND ($2=ANY(event_types) OR event_types IS NULL) AND table_name='' LIMIT 1000`, subscriptionColumns, subscriptionsTable()),
+			wantParams: []any{"test_schema", "DDL"},
+		},
 		{
 			name:       "with schema filter",
 			schema:     "test_schema",
-			wantQuery:  fmt.Sprintf(`SELECT url, schema_name, table_name, event_types FROM %s WHERE (schema_name=$1 OR schema_name='') LIMIT 1000`, subscriptionsTable()),
+			wantQuery:  fmt.Sprintf(`SELECT %s FROM %s WHERE NOT disabled AND NOT paused AND (schema_name=$1 OR schema_name='') LIMIT 1000`, subscriptionColumns, subscriptionsTable()),
 			wantParams: []any{"test_schema"},
 		},
 		{
 			name:       "with table filter",
 			table:      "test_table",
-			wantQuery:  fmt.Sprintf(`SELECT url, schema_name, table_name, event_types FROM %s WHERE (table_name=$1 OR table_name='') LIMIT 1000`, subscriptionsTable()),
+			wantQuery:  fmt.Sprintf(`SELECT %s FROM %s WHERE NOT disabled AND NOT paused AND (table_name=$1 OR table_name='') LIMIT 1000`, subscriptionColumns, subscriptionsTable()),
 			wantParams: []any{"test_table"},
 		},
 		{
@@ -49,12 +58,27 @@ func TestStore_buildGetQuery(t *testing.T) {
 			action: "I",
 			schema: "test_schema",
 			table:  "test_table",
-			wantQuery: fmt.Sprintf(`SELECT url, schema_name, table_name, event_types FROM %s `, subscriptionsTable()) +
-				"WHERE (schema_name=$1 OR schema_name='') " +
+			wantQuery: fmt.Sprintf(`SELECT %s FROM %s `, subscriptionColumns, subscriptionsTable()) +
+				"WHERE NOT disabled AND NOT paused AND (schema_name=$1 OR schema_name='') " +
 				"AND (table_name=$2 OR table_name='') " +
 				"AND ($3=ANY(event_types) OR event_types IS NULL) LIMIT 1000",
 			wantParams: []any{"test_schema", "test_table", "I"},
 		},
+		{
+			name:   "with row filters",
+			action: "U",
+			row: &subscription.Row{
+				ChangedColumns: []string{"status"},
+				NewValues:      map[string]any{"id": 1, "status": "paid"},
+				OldValues:      map[string]any{"id": 1},
+			},
+			wantQuery: fmt.Sprintf(`SELECT %s FROM %s `, subscriptionColumns, subscriptionsTable()) +
+				"WHERE NOT disabled AND NOT paused AND ($1=ANY(event_types) OR event_types IS NULL) " +
+				"AND (changed_columns IS NULL OR changed_columns && $2) " +
+				"AND (new_values IS NULL OR new_values <@ $3::jsonb) " +
+				"AND (old_values IS NULL OR old_values <@ $4::jsonb) LIMIT 1000",
+			wantParams: []any{"U", []string{"status"}, `{"id":1,"status":"paid"}`, `{"id":1}`},
+		},
 	}
 
 	for _, tc := range tests {
@@ -63,7 +87,64 @@ func TestStore_buildGetQuery(t *testing.T) {
 			t.Parallel()
 
 			s := &Store{}
-			query, params := s.buildGetQuery(tc.action, tc.schema, tc.table)
+			query, params, err := s.buildGetQuery(tc.action, tc.schema, tc.table, tc.row)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantQuery, query)
+			require.Equal(t, tc.wantParams, params)
+		})
+	}
+}
+
+func TestStore_buildListQuery(t *testing.T) {
+	t.Parallel()
+
+	selectQuery := fmt.Sprintf(`SELECT %s FROM %s`, subscriptionColumns, subscriptionsTable())
+
+	tests := []struct {
+		name   string
+		filter *subscription.ListFilter
+
+		wantQuery  string
+		wantParams []any
+	}{
+		{
+			name:       "no filter",
+			filter:     nil,
+			wantQuery:  selectQuery + " ORDER BY id",
+			wantParams: nil,
+		},
+		{
+			name:       "with limit",
+			filter:     &subscription.ListFilter{Limit: 10},
+			wantQuery:  selectQuery + " ORDER BY id LIMIT 10",
+			wantParams: nil,
+		},
+		{
+			name: "with all filters",
+			filter: &subscription.ListFilter{
+				URL:    "test-url",
+				Schema: "test_schema",
+				Table:  "test_table",
+				Cursor: "test-id",
+				Limit:  10,
+			},
+			wantQuery:  selectQuery + " WHERE url = $1 AND schema_name = $2 AND table_name = $3 AND id > $4 ORDER BY id LIMIT 10",
+			wantParams: []any{"test-url", "test_schema", "test_table", "test-id"},
+		},
+		{
+			name:       "with cursor",
+			filter:     &subscription.ListFilter{Cursor: "test-id"},
+			wantQuery:  selectQuery + " WHERE id > $1 ORDER BY id",
+			wantParams: []any{"test-id"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, params := buildListQuery(tc.filter)
 			require.Equal(t, tc.wantQuery, query)
 			require.Equal(t, tc.wantParams, params)
 		})
@@ -4,12 +4,40 @@ package store
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook/subscription"
 )
 
 type Store interface {
+	// CreateSubscription creates the subscription on input, or updates the
+	// existing subscription for the same url, schema and table. The
+	// subscription ID is set on input.
 	CreateSubscription(ctx context.Context, s *subscription.Subscription) error
+	// InsertSubscription creates the subscription on input. It returns
+	// ErrSubscriptionConflict if a subscription already exists for the same
+	// url, schema and table. The subscription ID is set on input.
+	InsertSubscription(ctx context.Context, s *subscription.Subscription) error
+	// UpdateSubscription updates the subscription with the same ID as the
+	// one on input.
+	UpdateSubscription(ctx context.Context, s *subscription.Subscription) error
+	// DeleteSubscription deletes the subscription with the same ID as the
+	// one on input, or with the same url, schema and table if it has no ID.
 	DeleteSubscription(ctx context.Context, s *subscription.Subscription) error
-	GetSubscriptions(ctx context.Context, action, schema, table string) ([]*subscription.Subscription, error)
+	GetSubscription(ctx context.Context, id string) (*subscription.Subscription, error)
+	ListSubscriptions(ctx context.Context, filter *subscription.ListFilter) ([]*subscription.Subscription, error)
+	// GetSubscriptions returns the active subscriptions that match the
+	// event on input.
+	GetSubscriptions(ctx context.Context, action, schema, table string, row *subscription.Row) ([]*subscription.Subscription, error)
+	// DisableSubscriptions disables all the subscriptions for the url on
+	// input, so that they're no longer returned when retrieving
+	// subscriptions.
+	DisableSubscriptions(ctx context.Context, url string) error
 }
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionConflict is returned when a subscription already exists
+	// for the same url, schema and table.
+	ErrSubscriptionConflict = errors.New("subscription already exists")
+)
@@ -3,22 +3,177 @@
 package subscription
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/filter"
 )
 
 type Subscription struct {
+	// ID is the stable identifier of the subscription, generated when it's
+	// created.
+	ID         string   `json:"id,omitempty"`
 	URL        string   `json:"url"`
 	EventTypes []string `json:"event_types"`
 	Schema     string   `json:"schema"`
 	Table      string   `json:"table"`
+	// ChangedColumns restricts the notifications to the events where at
+	// least one of these columns changed. Columns missing from the old
+	// values are considered changed, so updates can only be filtered
+	// accurately on tables with REPLICA IDENTITY FULL.
+	ChangedColumns []string `json:"changed_columns,omitempty"`
+	// NewValues restricts the notifications to the events where the new
+	// column values are equal to these.
+	NewValues map[string]any `json:"new_values,omitempty"`
+	// OldValues restricts the notifications to the events where the old
+	// identity values are equal to these.
+	OldValues map[string]any `json:"old_values,omitempty"`
+	// Expression is a CEL expression the events need to match to be
+	// notified, with the same variables as the filter and routing
+	// expressions (i.e, `new.status != old.status`).
+	Expression string `json:"expression,omitempty"`
+	// Secret is used to sign the webhook requests with an HMAC-SHA256
+	// signature, so that the receiver can verify their origin.
+	Secret string `json:"secret,omitempty"`
+	// BearerToken is sent in the Authorization header of the webhook
+	// requests.
+	BearerToken string `json:"bearer_token,omitempty"`
+	// Headers are static headers added to the webhook requests.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Disabled subscriptions are not notified. Subscriptions are disabled
+	// automatically when their endpoint keeps failing, and are enabled again
+	// when they're recreated.
+	Disabled bool `json:"disabled,omitempty"`
+	// Paused subscriptions are not notified until they're resumed. Their
+	// pending deliveries are kept in the outbox.
+	Paused bool `json:"paused,omitempty"`
+	// Batch enables the batched deliveries for the subscription. The events
+	// are sent one per request if not set.
+	Batch *Batch `json:"batch,omitempty"`
+}
+
+// Batch configures the batched deliveries of a subscription. The events are
+// grouped into a JSON array, sent once any of the limits is reached.
+type Batch struct {
+	// MaxEvents is the max number of events per batch. Defaults to 100.
+	MaxEvents int `json:"max_events,omitempty"`
+	// MaxBytes is the max size of the batch payload. Events bigger than this
+	// are sent on their own. Defaults to 1MiB.
+	MaxBytes int `json:"max_bytes,omitempty"`
+	// MaxWaitMs is the max time in milliseconds an event is kept in the
+	// batch before it's sent. Defaults to 1000.
+	MaxWaitMs int `json:"max_wait_ms,omitempty"`
+}
+
+// ListFilter narrows down the subscriptions listed. Empty fields are
+// ignored. Subscriptions are listed in ID order, and the Cursor is the ID of
+// the last subscription of the previous page.
+type ListFilter struct {
+	URL    string
+	Schema string
+	Table  string
+	Limit  int
+	Cursor string
+}
+
+const redactedValue = "********"
+
+// UnmarshalJSON decodes the subscription keeping the numbers in the column
+// values filters as json numbers, so that they can be compared without
+// losing precision.
+func (s *Subscription) UnmarshalJSON(data []byte) error {
+	type subscriptionAlias Subscription
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode((*subscriptionAlias)(s))
+}
+
+var (
+	errMissingURL        = errors.New("subscription url is required")
+	errInvalidBatch      = errors.New("subscription batch limits can't be negative")
+	errInvalidExpression = errors.New("invalid subscription expression")
+)
+
+// Validate returns an error if the subscription is missing required fields.
+func (s *Subscription) Validate() error {
+	if s.URL == "" {
+		return errMissingURL
+	}
+	if s.Batch != nil && (s.Batch.MaxEvents < 0 || s.Batch.MaxBytes < 0 || s.Batch.MaxWaitMs < 0) {
+		return errInvalidBatch
+	}
+	return s.ValidateExpression()
+}
+
+// ValidateExpression returns an error if the subscription expression is not
+// a valid CEL expression.
+func (s *Subscription) ValidateExpression() error {
+	if s.Expression == "" {
+		return nil
+	}
+	if _, err := filter.Compile(s.Expression); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidExpression, err)
+	}
+	return nil
 }
 
+// IsActive returns true if the subscription is neither paused nor disabled.
+func (s *Subscription) IsActive() bool {
+	return !s.Paused && !s.Disabled
+}
+
+// Redacted returns a copy of the subscription with the credentials masked, so
+// that it can be returned by the API or logged.
+func (s *Subscription) Redacted() *Subscription {
+	redacted := *s
+	if redacted.Secret != "" {
+		redacted.Secret = redactedValue
+	}
+	if redacted.BearerToken != "" {
+		redacted.BearerToken = redactedValue
+	}
+	if len(s.Headers) > 0 {
+		redacted.Headers = make(map[string]string, len(s.Headers))
+		for key := range s.Headers {
+			redacted.Headers[key] = redactedValue
+		}
+	}
+	return &redacted
+}
+
+// RestoreRedacted replaces the redacted credentials of the subscription with
+// the ones from the existing subscription on input, so that a redacted
+// subscription can be sent back on update without losing its credentials.
+func (s *Subscription) RestoreRedacted(existing *Subscription) {
+	if s.Secret == redactedValue {
+		s.Secret = existing.Secret
+	}
+	if s.BearerToken == redactedValue {
+		s.BearerToken = existing.BearerToken
+	}
+	for key, value := range s.Headers {
+		if value == redactedValue {
+			s.Headers[key] = existing.Headers[key]
+		}
+	}
+}
+
+// IsFor returns true if the subscription is for events with the action,
+// schema and table on input. Schema change events don't have a table, so they
+// only match the subscriptions that are not scoped to a table.
 func (s *Subscription) IsFor(action, schema, table string) bool {
 	if action == "" && schema == "" && table == "" {
 		return true
 	}
 
+	if action == wal.SchemaChangeAction && s.Table != "" {
+		return false
+	}
+
 	if action != "" && len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, action) {
 		return false
 	}
@@ -34,6 +189,12 @@ func (s *Subscription) IsFor(action, schema, table string) bool {
 	return true
 }
 
+// HasCredentials returns true if the subscription has any secret, token or
+// static headers configured.
+func (s *Subscription) HasCredentials() bool {
+	return s.Secret != "" || s.BearerToken != "" || len(s.Headers) > 0
+}
+
 func (s *Subscription) Key() string {
 	return fmt.Sprintf("%s/%s/%s", s.URL, s.Schema, s.Table)
 }
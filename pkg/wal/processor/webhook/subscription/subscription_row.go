@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package subscription
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"slices"
+
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
+)
+
+// Row contains the column values of a wal data event, used to match the
+// subscriptions column filters.
+type Row struct {
+	// ChangedColumns are the columns whose value differs between the old
+	// identity values and the new column values. Columns that are only
+	// present on one side are considered changed.
+	ChangedColumns []string
+	NewValues      map[string]any
+	OldValues      map[string]any
+}
+
+// NewRow returns the row for the wal data on input. It returns nil if the
+// event has no column values (i.e. truncates or schema changes), since the
+// column filters can't be evaluated.
+func NewRow(data *wal.Data) *Row {
+	if data == nil || (len(data.Columns) == 0 && len(data.Identity) == 0) {
+		return nil
+	}
+
+	row := &Row{
+		ChangedColumns: []string{},
+		NewValues:      make(map[string]any, len(data.Columns)),
+		OldValues:      make(map[string]any, len(data.Identity)),
+	}
+	for _, col := range data.Columns {
+		row.NewValues[col.Name] = col.Value
+	}
+	for _, col := range data.Identity {
+		row.OldValues[col.Name] = col.Value
+	}
+
+	for _, col := range data.Columns {
+		oldValue, found := row.OldValues[col.Name]
+		if !found || !valuesEqual(col.Value, oldValue) {
+			row.ChangedColumns = append(row.ChangedColumns, col.Name)
+		}
+	}
+	for _, col := range data.Identity {
+		if _, found := row.NewValues[col.Name]; !found {
+			row.ChangedColumns = append(row.ChangedColumns, col.Name)
+		}
+	}
+
+	return row
+}
+
+// IsForRow returns true if the row on input matches the subscription column
+// filters. A nil row matches all subscriptions.
+func (s *Subscription) IsForRow(row *Row) bool {
+	if row == nil {
+		return true
+	}
+
+	if len(s.ChangedColumns) > 0 && !containsAny(row.ChangedColumns, s.ChangedColumns) {
+		return false
+	}
+
+	return containsValues(row.NewValues, s.NewValues) && containsValues(row.OldValues, s.OldValues)
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, w := range wanted {
+		if slices.Contains(values, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsValues returns true if all the wanted column values are present
+// and equal in the values on input.
+func containsValues(values, wanted map[string]any) bool {
+	for column, wantedValue := range wanted {
+		value, found := values[column]
+		if !found || !valuesEqual(value, wantedValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares the column values on input, treating all numeric
+// types as equal if they represent the same number, since values can be
+// decoded as json numbers or float64 depending on their origin. Json numbers
+// and integers are compared exactly, and are only converted to float64 when
+// compared to a float, so that large or precise numbers don't lose precision.
+func valuesEqual(a, b any) bool {
+	switch v := a.(type) {
+	case map[string]any:
+		other, ok := b.(map[string]any)
+		if !ok || len(v) != len(other) {
+			return false
+		}
+		for k, val := range v {
+			otherVal, found := other[k]
+			if !found || !valuesEqual(val, otherVal) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		other, ok := b.([]any)
+		if !ok || len(v) != len(other) {
+			return false
+		}
+		for i := range v {
+			if !valuesEqual(v[i], other[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if isFloat(a) || isFloat(b) {
+		fa, okA := toFloat(a)
+		fb, okB := toFloat(b)
+		if okA && okB {
+			return fa == fb
+		}
+	}
+	ra, okA := toRat(a)
+	rb, okB := toRat(b)
+	if okA && okB {
+		return ra.Cmp(rb) == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func isFloat(value any) bool {
+	switch value.(type) {
+	case float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// toRat returns the exact value of the json numbers and integers.
+func toRat(value any) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(v.String())
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(v)), true
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	default:
+		return nil, false
+	}
+}
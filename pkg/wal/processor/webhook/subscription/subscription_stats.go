@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package subscription
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats contains the delivery stats of a subscription.
+type Stats struct {
+	// Delivered and Failed are the number of successful and failed delivery
+	// attempts since the process started.
+	Delivered       uint64     `json:"delivered"`
+	Failed          uint64     `json:"failed"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty"`
+	LastFailedAt    *time.Time `json:"last_failed_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	// PendingDeliveries and FailedDeliveries are the number of deliveries
+	// for the subscription in the outbox, when enabled.
+	PendingDeliveries int `json:"pending_deliveries"`
+	FailedDeliveries  int `json:"failed_deliveries"`
+}
+
+// StatsRegistry keeps the in memory delivery stats of the subscriptions. It
+// is concurrency safe.
+type StatsRegistry struct {
+	mutex *sync.RWMutex
+	stats map[string]*Stats
+}
+
+func NewStatsRegistry() *StatsRegistry {
+	return &StatsRegistry{
+		mutex: &sync.RWMutex{},
+		stats: map[string]*Stats{},
+	}
+}
+
+// RecordDelivery records the result of a delivery attempt for the
+// subscription id on input.
+func (r *StatsRegistry) RecordDelivery(id string, at time.Time, err error) {
+	if r == nil || id == "" {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stats, found := r.stats[id]
+	if !found {
+		stats = &Stats{}
+		r.stats[id] = stats
+	}
+
+	if err != nil {
+		stats.Failed++
+		stats.LastFailedAt = &at
+		stats.LastError = err.Error()
+		return
+	}
+	stats.Delivered++
+	stats.LastDeliveredAt = &at
+}
+
+// Get returns a copy of the stats for the subscription id on input.
+func (r *StatsRegistry) Get(id string) Stats {
+	if r == nil {
+		return Stats{}
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if stats, found := r.stats[id]; found {
+		return *stats
+	}
+	return Stats{}
+}
@@ -3,8 +3,10 @@
 package subscription
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,6 +64,20 @@ func TestSubscription_IsFor(t *testing.T) {
 			table:        "another_table",
 			wantMatch:    false,
 		},
+		{
+			name:         "schema change event, schema subscription matched",
+			subscription: newTestSubscription("url-1", "test_schema", "", []string{}),
+			action:       "DDL",
+			schema:       "test_schema",
+			wantMatch:    true,
+		},
+		{
+			name:         "schema change event, table subscription not matched",
+			subscription: newTestSubscription("url-1", "test_schema", "test_table", []string{}),
+			action:       "DDL",
+			schema:       "test_schema",
+			wantMatch:    false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -83,3 +99,300 @@ func newTestSubscription(url, schema, table string, eventTypes []string) *Subscr
 		EventTypes: eventTypes,
 	}
 }
+
+func TestNewRow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data *wal.Data
+
+		wantRow *Row
+	}{
+		{
+			name: "insert",
+			data: &wal.Data{
+				Action:  "I",
+				Columns: []wal.Column{{Name: "id", Value: 1}, {Name: "status", Value: "paid"}},
+			},
+
+			wantRow: &Row{
+				ChangedColumns: []string{"id", "status"},
+				NewValues:      map[string]any{"id": 1, "status": "paid"},
+				OldValues:      map[string]any{},
+			},
+		},
+		{
+			name: "update",
+			data: &wal.Data{
+				Action: "U",
+				Columns: []wal.Column{
+					{Name: "id", Value: json.Number("1")},
+					{Name: "status", Value: "paid"},
+					{Name: "total", Value: float64(10)},
+				},
+				Identity: []wal.Column{
+					{Name: "id", Value: float64(1)},
+					{Name: "status", Value: "pending"},
+				},
+			},
+
+			wantRow: &Row{
+				ChangedColumns: []string{"status", "total"},
+				NewValues:      map[string]any{"id": json.Number("1"), "status": "paid", "total": float64(10)},
+				OldValues:      map[string]any{"id": float64(1), "status": "pending"},
+			},
+		},
+		{
+			name: "delete",
+			data: &wal.Data{
+				Action:   "D",
+				Identity: []wal.Column{{Name: "id", Value: 1}},
+			},
+
+			wantRow: &Row{
+				ChangedColumns: []string{"id"},
+				NewValues:      map[string]any{},
+				OldValues:      map[string]any{"id": 1},
+			},
+		},
+		{
+			name: "truncate",
+			data: &wal.Data{Action: "T"},
+
+			wantRow: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.wantRow, NewRow(tc.data))
+		})
+	}
+}
+
+func TestSubscription_IsForRow(t *testing.T) {
+	t.Parallel()
+
+	testRow := &Row{
+		ChangedColumns: []string{"status"},
+		NewValues:      map[string]any{"id": json.Number("1"), "status": "paid", "tags": []any{"a"}},
+		OldValues:      map[string]any{"id": float64(1), "status": "pending"},
+	}
+
+	tests := []struct {
+		name         string
+		subscription *Subscription
+		row          *Row
+
+		wantMatch bool
+	}{
+		{
+			name:         "no row",
+			subscription: &Subscription{ChangedColumns: []string{"status"}},
+			row:          nil,
+
+			wantMatch: true,
+		},
+		{
+			name:         "no column filters",
+			subscription: &Subscription{},
+			row:          testRow,
+
+			wantMatch: true,
+		},
+		{
+			name:         "changed columns matched",
+			subscription: &Subscription{ChangedColumns: []string{"name", "status"}},
+			row:          testRow,
+
+			wantMatch: true,
+		},
+		{
+			name:         "changed columns not matched",
+			subscription: &Subscription{ChangedColumns: []string{"name"}},
+			row:          testRow,
+
+			wantMatch: false,
+		},
+		{
+			name: "values matched",
+			subscription: &Subscription{
+				NewValues: map[string]any{"id": 1, "status": "paid", "tags": []any{"a"}},
+				OldValues: map[string]any{"status": "pending"},
+			},
+			row: testRow,
+
+			wantMatch: true,
+		},
+		{
+			name: "new values not matched",
+			subscription: &Subscription{
+				NewValues: map[string]any{"status": "pending"},
+			},
+			row: testRow,
+
+			wantMatch: false,
+		},
+		{
+			name: "big integers compared exactly",
+			subscription: &Subscription{
+				NewValues: map[string]any{"id": json.Number("9007199254740993")},
+			},
+			row: &Row{NewValues: map[string]any{"id": json.Number("9007199254740992")}},
+
+			wantMatch: false,
+		},
+		{
+			name: "decimals compared exactly",
+			subscription: &Subscription{
+				NewValues: map[string]any{"total": json.Number("0.10000000000000000001")},
+			},
+			row: &Row{NewValues: map[string]any{"total": json.Number("0.1")}},
+
+			wantMatch: false,
+		},
+		{
+			name: "decimals with different representations matched",
+			subscription: &Subscription{
+				NewValues: map[string]any{"total": json.Number("1.50"), "id": json.Number("1e2")},
+			},
+			row: &Row{NewValues: map[string]any{"total": json.Number("1.5"), "id": int64(100)}},
+
+			wantMatch: true,
+		},
+		{
+			name: "json numbers compared to floats",
+			subscription: &Subscription{
+				NewValues: map[string]any{"total": float64(1.1), "values": map[string]any{"a": float64(2)}},
+			},
+			row: &Row{NewValues: map[string]any{"total": json.Number("1.1"), "values": map[string]any{"a": json.Number("2")}}},
+
+			wantMatch: true,
+		},
+		{
+			name: "old values not matched",
+			subscription: &Subscription{
+				OldValues: map[string]any{"missing": "value"},
+			},
+			row: testRow,
+
+			wantMatch: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.wantMatch, tc.subscription.IsForRow(tc.row))
+		})
+	}
+}
+
+func TestSubscription_RestoreRedacted(t *testing.T) {
+	t.Parallel()
+
+	existing := &Subscription{
+		URL:         "url-1",
+		Secret:      "test-secret",
+		BearerToken: "test-token",
+		Headers:     map[string]string{"X-Tenant": "tenant-1", "X-Region": "eu"},
+	}
+
+	redacted := existing.Redacted()
+	require.Equal(t, &Subscription{
+		URL:         "url-1",
+		Secret:      redactedValue,
+		BearerToken: redactedValue,
+		Headers:     map[string]string{"X-Tenant": redactedValue, "X-Region": redactedValue},
+	}, redacted)
+	// the existing subscription is not modified
+	require.Equal(t, "test-secret", existing.Secret)
+	require.Equal(t, "tenant-1", existing.Headers["X-Tenant"])
+
+	redacted.BearerToken = ""
+	redacted.Headers["X-Region"] = "us"
+	redacted.RestoreRedacted(existing)
+	require.Equal(t, &Subscription{
+		URL:     "url-1",
+		Secret:  "test-secret",
+		Headers: map[string]string{"X-Tenant": "tenant-1", "X-Region": "us"},
+	}, redacted)
+}
+
+func TestSubscription_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		subscription *Subscription
+
+		wantErr error
+	}{
+		{
+			name:         "ok",
+			subscription: &Subscription{URL: "url-1"},
+			wantErr:      nil,
+		},
+		{
+			name:         "ok - with batch",
+			subscription: &Subscription{URL: "url-1", Batch: &Batch{MaxEvents: 10}},
+			wantErr:      nil,
+		},
+		{
+			name:         "ok - with expression",
+			subscription: &Subscription{URL: "url-1", Expression: `new.status != old.status`},
+			wantErr:      nil,
+		},
+		{
+			name:         "error - missing url",
+			subscription: &Subscription{Schema: "test_schema"},
+			wantErr:      errMissingURL,
+		},
+		{
+			name:         "error - invalid batch",
+			subscription: &Subscription{URL: "url-1", Batch: &Batch{MaxWaitMs: -1}},
+			wantErr:      errInvalidBatch,
+		},
+		{
+			name:         "error - invalid expression",
+			subscription: &Subscription{URL: "url-1", Expression: `new.status ==`},
+			wantErr:      errInvalidExpression,
+		},
+		{
+			name:         "error - non bool expression",
+			subscription: &Subscription{URL: "url-1", Expression: `new.status`},
+			wantErr:      errInvalidExpression,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.subscription.Validate()
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSubscription_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	s := &Subscription{}
+	err := json.Unmarshal([]byte(`{"url":"url-1","new_values":{"id":9007199254740993},"batch":{"max_events":10}}`), s)
+	require.NoError(t, err)
+	require.Equal(t, &Subscription{
+		URL:       "url-1",
+		NewValues: map[string]any{"id": json.Number("9007199254740993")},
+		Batch:     &Batch{MaxEvents: 10},
+	}, s)
+	require.True(t, s.IsForRow(&Row{NewValues: map[string]any{"id": json.Number("9007199254740993")}}))
+	require.False(t, s.IsForRow(&Row{NewValues: map[string]any{"id": json.Number("9007199254740992")}}))
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the webhook
+	// request, in the `v1=<hex signature>` format.
+	SignatureHeader = "X-Pgstream-Signature"
+	// TimestampHeader contains the unix timestamp (in seconds) at which the
+	// webhook request was signed. It's part of the signed content, so that
+	// receivers can reject replayed requests.
+	TimestampHeader = "X-Pgstream-Timestamp"
+
+	signatureVersion = "v1"
+	// DefaultSignatureTolerance is the recommended max age of a signed request
+	DefaultSignatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature   = errors.New("missing webhook signature")
+	ErrInvalidSignature   = errors.New("invalid webhook signature")
+	ErrSignatureTimestamp = errors.New("webhook signature timestamp outside of tolerance")
+)
+
+// Sign returns the signature of the payload on input for the given secret
+// and timestamp. The signed content is `<unix timestamp>.<payload>`.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	return fmt.Sprintf("%s=%s", signatureVersion, hex.EncodeToString(computeSignature(secret, timestamp.Unix(), payload)))
+}
+
+// SetSignatureHeaders signs the payload on input and sets the signature and
+// timestamp headers on the request headers.
+func SetSignatureHeaders(header http.Header, secret string, timestamp time.Time, payload []byte) {
+	header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(SignatureHeader, Sign(secret, timestamp, payload))
+}
+
+// Verify checks the signature headers of a webhook request against the
+// payload on input. It returns an error if the signature doesn't match, or
+// if the request timestamp differs from now by more than the tolerance.
+func Verify(header http.Header, secret string, payload []byte, tolerance time.Duration, now time.Time) error {
+	signatureHeader := header.Get(SignatureHeader)
+	timestampHeader := header.Get(TimestampHeader)
+	if signatureHeader == "" || timestampHeader == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp: %w", ErrInvalidSignature, err)
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureTimestamp
+	}
+
+	version, encodedSignature, found := strings.Cut(signatureHeader, "=")
+	if !found || version != signatureVersion {
+		return fmt.Errorf("%w: unsupported format", ErrInvalidSignature)
+	}
+	signature, err := hex.DecodeString(encodedSignature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	if !hmac.Equal(signature, computeSignature(secret, timestamp, payload)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func computeSignature(secret string, timestamp int64, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
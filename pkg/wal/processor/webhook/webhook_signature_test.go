@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	testSecret := "test-secret"
+	testPayload := []byte(`{"Data":{"action":"I"}}`)
+	testNow := time.Unix(1700000000, 0)
+
+	signedHeader := func(secret string, timestamp time.Time) http.Header {
+		header := http.Header{}
+		SetSignatureHeaders(header, secret, timestamp, testPayload)
+		return header
+	}
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		payload []byte
+
+		wantErr error
+	}{
+		{
+			name:    "ok",
+			header:  signedHeader(testSecret, testNow),
+			payload: testPayload,
+
+			wantErr: nil,
+		},
+		{
+			name:    "ok - within tolerance",
+			header:  signedHeader(testSecret, testNow.Add(-time.Minute)),
+			payload: testPayload,
+
+			wantErr: nil,
+		},
+		{
+			name:    "error - missing headers",
+			header:  http.Header{},
+			payload: testPayload,
+
+			wantErr: ErrMissingSignature,
+		},
+		{
+			name:    "error - different secret",
+			header:  signedHeader("another-secret", testNow),
+			payload: testPayload,
+
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "error - tampered payload",
+			header:  signedHeader(testSecret, testNow),
+			payload: []byte(`{"Data":{"action":"D"}}`),
+
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "error - replayed request",
+			header:  signedHeader(testSecret, testNow.Add(-time.Hour)),
+			payload: testPayload,
+
+			wantErr: ErrSignatureTimestamp,
+		},
+		{
+			name: "error - invalid signature format",
+			header: http.Header{
+				SignatureHeader: []string{"v2=abc"},
+				TimestampHeader: []string{"1700000000"},
+			},
+			payload: testPayload,
+
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "error - invalid timestamp",
+			header: http.Header{
+				SignatureHeader: []string{Sign(testSecret, testNow, testPayload)},
+				TimestampHeader: []string{"now"},
+			},
+			payload: testPayload,
+
+			wantErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Verify(tc.header, testSecret, tc.payload, DefaultSignatureTolerance, testNow)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
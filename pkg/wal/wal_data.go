@@ -6,6 +6,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/ApollosProject/pgstream-wal2json/pkg/schemalog"
 	"github.com/rs/xid"
 )
 
@@ -19,7 +20,7 @@ type Event struct {
 
 // Data contains the wal data properties identifying the table operation.
 type Data struct {
-	Action    string   `json:"action"`    // "I" -- insert, "U" -- update, "D" -- delete, "T" -- truncate
+	Action    string   `json:"action"`    // "I" -- insert, "U" -- update, "D" -- delete, "T" -- truncate, "DDL" -- schema change
 	Timestamp string   `json:"timestamp"` // ISO8601, i.e. 2019-12-29 04:58:34.806671
 	LSN       string   `json:"lsn"`
 	Schema    string   `json:"schema"`
@@ -27,8 +28,24 @@ type Data struct {
 	Columns   []Column `json:"columns"`
 	Identity  []Column `json:"identity"`
 	Metadata  Metadata `json:"metadata"` // pgstream specific metadata
+	// SchemaChange is only populated for "DDL" events, emitted once a schema
+	// log entry has been acked.
+	SchemaChange *SchemaChange `json:"schema_change,omitempty"`
 }
 
+// SchemaChange describes an acked schema change for a given schema, including
+// the full new schema and the diff against the previously acked version.
+type SchemaChange struct {
+	SchemaName string                `json:"schema_name"`
+	SchemaID   xid.ID                `json:"schema_id"`
+	Version    int64                 `json:"version"`
+	Schema     schemalog.Schema      `json:"schema"`
+	Diff       *schemalog.SchemaDiff `json:"diff"`
+}
+
+// SchemaChangeAction is the action used for schema change events.
+const SchemaChangeAction = "DDL"
+
 // Metadata is pgstream specific properties to help identify the id/version
 // within the wal event as well as some pgstream unique immutable ids for the
 // schema and the table it relates to.
@@ -49,6 +66,8 @@ type Column struct {
 	Name  string `json:"name"`
 	Type  string `json:"type"`
 	Value any    `json:"value"`
+	// TypeInfo is populated by the translator for user defined types
+	TypeInfo *schemalog.TypeInfo `json:"type_info,omitempty"`
 }
 
 const iso8601Format = "2006-01-02 15:04:05.999999+00"
@@ -65,6 +84,10 @@ func (d *Data) IsInsert() bool {
 	return d.Action == "I"
 }
 
+func (d *Data) IsSchemaChange() bool {
+	return d.Action == SchemaChangeAction
+}
+
 // IsEmpty returns true if the pgstream metadata hasn't been populated, false
 // otherwise.
 func (m Metadata) IsEmpty() bool {
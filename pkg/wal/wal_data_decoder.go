@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UnmarshalLossless decodes the JSON on input into v without losing numeric
+// precision. Numbers are decoded as json.Number instead of float64, which is
+// serialised unchanged by the encoding/json package. When v is a wal Data
+// value, the numeric values of integer columns are converted into int64, using
+// the column type.
+func UnmarshalLossless(b []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if d, ok := v.(*Data); ok {
+		return d.convertIntegerColumns()
+	}
+	return nil
+}
+
+func (d *Data) convertIntegerColumns() error {
+	for _, columns := range [][]Column{d.Columns, d.Identity} {
+		for i := range columns {
+			if err := columns[i].convertInteger(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertInteger converts the column value into an int64 if the column is an
+// integer type and the value has been decoded as a json.Number. Any other
+// numeric values (numeric, float) are kept as json.Number to keep their exact
+// representation.
+func (c *Column) convertInteger() error {
+	n, ok := c.Value.(json.Number)
+	if !ok || !isIntegerType(c.Type) {
+		return nil
+	}
+	i, err := n.Int64()
+	if err != nil {
+		return fmt.Errorf("parsing integer value for column %s: %w", c.Name, err)
+	}
+	c.Value = i
+	return nil
+}
+
+func isIntegerType(typeName string) bool {
+	switch strings.ToLower(typeName) {
+	case "int2", "int4", "int8", "smallint", "integer", "bigint", "smallserial", "serial", "bigserial":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package wal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalLossless(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+
+		wantData *Data
+		wantErr  error
+	}{
+		{
+			name:  "ok - integer and numeric columns",
+			input: `{"action":"I","schema":"public","table":"test","columns":[{"name":"id","type":"bigint","value":9007199254740993},{"name":"amount","type":"numeric(20,4)","value":12345678901234567.1234},{"name":"ratio","type":"double precision","value":0.1},{"name":"name","type":"text","value":"a"}],"identity":[{"name":"id","type":"int8","value":9007199254740993}]}`,
+
+			wantData: &Data{
+				Action: "I",
+				Schema: "public",
+				Table:  "test",
+				Columns: []Column{
+					{Name: "id", Type: "bigint", Value: int64(9007199254740993)},
+					{Name: "amount", Type: "numeric(20,4)", Value: json.Number("12345678901234567.1234")},
+					{Name: "ratio", Type: "double precision", Value: json.Number("0.1")},
+					{Name: "name", Type: "text", Value: "a"},
+				},
+				Identity: []Column{
+					{Name: "id", Type: "int8", Value: int64(9007199254740993)},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "error - invalid integer value",
+			input: `{"action":"I","columns":[{"name":"id","type":"integer","value":1.5}]}`,
+
+			wantData: &Data{
+				Action:  "I",
+				Columns: []Column{{Name: "id", Type: "integer", Value: json.Number("1.5")}},
+			},
+			wantErr: errors.New("parsing integer value for column id: strconv.ParseInt: parsing \"1.5\": invalid syntax"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := &Data{}
+			err := UnmarshalLossless([]byte(tc.input), data)
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantData, data)
+
+			if tc.wantErr == nil {
+				// the values are serialised without precision loss
+				out, err := json.Marshal(data.Columns)
+				require.NoError(t, err)
+				require.Contains(t, string(out), `"value":9007199254740993`)
+				require.Contains(t, string(out), `"value":12345678901234567.1234`)
+			}
+		})
+	}
+}
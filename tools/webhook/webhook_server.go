@@ -14,13 +14,22 @@ import (
 
 	"github.com/ApollosProject/pgstream-wal2json/internal/log/zerolog"
 	loglib "github.com/ApollosProject/pgstream-wal2json/pkg/log"
+	"github.com/ApollosProject/pgstream-wal2json/pkg/wal/processor/webhook"
 )
 
-var logger loglib.Logger
+var (
+	logger loglib.Logger
+	// secret is used to verify the webhook request signatures. Verification
+	// is skipped if it's not set.
+	secret    string
+	tolerance time.Duration
+)
 
 func main() {
 	address := flag.String("address", ":9910", "Webhook server address")
 	logLevel := flag.String("log-level", "debug", "Webhook server log level")
+	flag.StringVar(&secret, "secret", "", "Subscription secret used to verify the webhook request signatures")
+	flag.DurationVar(&tolerance, "tolerance", webhook.DefaultSignatureTolerance, "Max age of the signed webhook requests")
 	flag.Parse()
 
 	logger = zerolog.NewStdLogger(zerolog.NewLogger(&zerolog.Config{
@@ -59,6 +68,15 @@ func processWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	if secret != "" {
+		if err := webhook.Verify(r.Header, secret, bodyBytes, tolerance, time.Now()); err != nil {
+			logger.Error(err, "verifying webhook signature")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		logger.Debug("webhook signature verified")
+	}
+
 	var prettyJSON bytes.Buffer
 	if err = json.Indent(&prettyJSON, bodyBytes, "", "    "); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)